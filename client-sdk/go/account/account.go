@@ -0,0 +1,125 @@
+// Package account provides an Account abstraction bundling a runtime client with a signer, so
+// that components making transactions on behalf of a single account (e.g. schedulers and bots)
+// don't need to manage nonces, fees and signing themselves.
+package account
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Option is an option for configuring an account.
+type Option func(*options)
+
+type options struct {
+	fee        *types.Fee
+	middleware []*Middleware
+}
+
+// WithFee sets the fee paid by each transaction of the account.
+func WithFee(fee *types.Fee) Option {
+	return func(opts *options) {
+		opts.fee = fee
+	}
+}
+
+// Account is a runtime account controlled by a signer.
+//
+// Transactions submitted through the same Account are serialized, so that each is signed with the
+// correct nonce. The account must not be used by other means at the same time.
+type Account struct {
+	rc     client.RuntimeClient
+	ac     accounts.V1
+	signer signature.Signer
+	spec   types.SignatureAddressSpec
+	opts   options
+
+	l sync.Mutex
+}
+
+// New creates a new account controlled by the given signer.
+func New(rc client.RuntimeClient, signer signature.Signer, spec types.SignatureAddressSpec, opts ...Option) *Account {
+	a := &Account{
+		rc:     rc,
+		ac:     accounts.NewV1(rc),
+		signer: signer,
+		spec:   spec,
+	}
+	for _, opt := range opts {
+		opt(&a.opts)
+	}
+	return a
+}
+
+// Address returns the address of the account.
+func (a *Account) Address() types.Address {
+	return types.NewAddress(a.spec)
+}
+
+// Client returns the runtime client of the account.
+func (a *Account) Client() client.RuntimeClient {
+	return a.rc
+}
+
+// Balance returns the balance of the account in the given denomination at the latest round.
+func (a *Account) Balance(ctx context.Context, denomination types.Denomination) (*types.Quantity, error) {
+	balances, err := a.ac.Balances(ctx, client.RoundLatest, a.Address())
+	if err != nil {
+		return nil, fmt.Errorf("account: failed to query balances: %w", err)
+	}
+	balance := balances.Balances[denomination]
+	return &balance, nil
+}
+
+// Transfer transfers the given amount to the given address.
+func (a *Account) Transfer(ctx context.Context, to types.Address, amount types.BaseUnits) (*client.TransactionMeta, error) {
+	return a.Submit(ctx, a.ac.Transfer(to, amount), nil)
+}
+
+// Submit signs the transaction of the given builder with the next nonce of the account, submits it
+// and waits for its execution, decoding the result into rsp (if non-nil).
+//
+// Transactions rejected by the transaction checks fail with the classified check error (see
+// client.Classify) and a nil meta. Registered middleware is called before signing and after the
+// outcome is known (see Middleware).
+func (a *Account) Submit(ctx context.Context, tb *client.TransactionBuilder, rsp interface{}) (*client.TransactionMeta, error) {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	nonce, err := a.ac.Nonce(ctx, client.RoundLatest, a.Address())
+	if err != nil {
+		return nil, fmt.Errorf("account: failed to query nonce: %w", err)
+	}
+	if a.opts.fee != nil {
+		tb.SetFeeAmount(a.opts.fee.Amount)
+		tb.SetFeeGas(a.opts.fee.Gas)
+	}
+	tb.AppendAuthSignature(a.spec, nonce)
+	tx := tb.GetTransaction()
+	if err = a.preSign(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err = tb.AppendSign(ctx, a.signer); err != nil {
+		return nil, fmt.Errorf("account: failed to sign transaction: %w", err)
+	}
+
+	meta, err := tb.SubmitTxMeta(ctx, rsp)
+	if meta != nil && meta.CheckTxError != nil {
+		err = client.Classify(types.FailedCallResult{
+			Module:  meta.CheckTxError.Module,
+			Code:    meta.CheckTxError.Code,
+			Message: meta.CheckTxError.Message,
+		})
+	}
+	a.postConfirm(ctx, tx, meta, err)
+	if meta != nil && meta.CheckTxError != nil {
+		return nil, err
+	}
+	return meta, err
+}
@@ -0,0 +1,114 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// fakeChain executes transactions with the expected nonce and rejects all others.
+type fakeChain struct {
+	client.RuntimeClient
+
+	nonce uint64
+	round uint64
+
+	// stale makes nonce queries return a stale nonce.
+	stale bool
+}
+
+func (c *fakeChain) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: signature.Context("test chain")}, nil
+}
+
+func (c *fakeChain) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*uint64) = c.nonce
+	if c.stale {
+		*rsp.(*uint64) = c.nonce - 1
+	}
+	return nil
+}
+
+func (c *fakeChain) SubmitTxRawMeta(ctx context.Context, utx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	tx, err := utx.Verify(signature.Context("test chain"))
+	if err != nil {
+		return nil, err
+	}
+
+	var meta client.SubmitTxRawMeta
+	if tx.AuthInfo.SignerInfo[0].Nonce != c.nonce {
+		meta.CheckTxError = &client.CheckTxError{Module: "core", Code: 4, Message: "invalid nonce"}
+		return &meta, nil
+	}
+	c.nonce++
+	c.round++
+	meta.Round = c.round
+	meta.Result.Ok = cbor.Marshal(nil)
+	return &meta, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	chain := &fakeChain{nonce: 5, round: 10}
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)
+	errLimit := errors.New("spending limit exceeded")
+
+	var calls []string
+	var outcomes []string
+	acct := New(chain, sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec, WithMiddleware(&Middleware{
+		PreSign: func(ctx context.Context, tx *types.Transaction) error {
+			calls = append(calls, "limit")
+			var body accounts.Transfer
+			if err := cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				return err
+			}
+			if body.Amount.Amount.Cmp(quantity.NewFromUint64(1000)) > 0 {
+				return errLimit
+			}
+			return nil
+		},
+	}))
+	acct.Use(&Middleware{
+		PreSign: func(ctx context.Context, tx *types.Transaction) error {
+			calls = append(calls, "validity")
+			notAfter := uint64(20)
+			tx.AuthInfo.NotAfter = &notAfter
+			return nil
+		},
+		PostConfirm: func(ctx context.Context, tx *types.Transaction, meta *client.TransactionMeta, err error) {
+			require.EqualValues(20, *tx.AuthInfo.NotAfter, "amendments should be visible")
+			outcomes = append(outcomes, Outcome(meta, err))
+		},
+	}, Metrics())
+
+	meta, err := acct.Transfer(ctx, sdkTesting.Bob.Address, amount)
+	require.NoError(err, "Transfer")
+	require.EqualValues(11, meta.Round)
+	require.Equal([]string{"limit", "validity"}, calls)
+	require.Equal([]string{OutcomeExecuted}, outcomes)
+
+	_, err = acct.Transfer(ctx, sdkTesting.Bob.Address, types.NewBaseUnits(*quantity.NewFromUint64(5000), types.NativeDenomination))
+	require.ErrorIs(err, errLimit, "transactions should be aborted by pre-sign hooks")
+	require.Equal([]string{"limit", "validity", "limit"}, calls, "hooks should stop at the first error")
+	require.Len(outcomes, 1, "aborted transactions should not be confirmed")
+	require.EqualValues(6, chain.nonce)
+
+	chain.stale = true
+	meta, err = acct.Transfer(ctx, sdkTesting.Bob.Address, amount)
+	require.Error(err, "rejected transactions should fail")
+	require.Nil(meta)
+	require.Equal([]string{OutcomeExecuted, OutcomeRejected}, outcomes)
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Middleware hooks into the transactions submitted through an account, so that business rules
+// (e.g. spending limits or audit logging) are enforced where transactions are made. Either hook may
+// be nil.
+type Middleware struct {
+	// PreSign is called with each transaction before it is signed, after its nonce and fee are set.
+	// It may amend the transaction, e.g. to set its validity rounds. Returning an error aborts the
+	// transaction, in which case Submit fails with the error.
+	PreSign func(ctx context.Context, tx *types.Transaction) error
+
+	// PostConfirm is called with the outcome of each signed transaction, i.e. the meta and error
+	// returned by the runtime client. The meta is nil in case the outcome is unknown (e.g. the
+	// connection was lost) and has CheckTxError set in case the transaction was rejected by the
+	// transaction checks.
+	PostConfirm func(ctx context.Context, tx *types.Transaction, meta *client.TransactionMeta, err error)
+}
+
+// WithMiddleware registers the given middleware, see Account.Use.
+func WithMiddleware(mw ...*Middleware) Option {
+	return func(opts *options) {
+		opts.middleware = append(opts.middleware, mw...)
+	}
+}
+
+// Use registers the given middleware. The PreSign hooks are called in registration order, stopping
+// at the first error, and the PostConfirm hooks in reverse registration order.
+func (a *Account) Use(mw ...*Middleware) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.opts.middleware = append(a.opts.middleware, mw...)
+}
+
+// preSign calls the PreSign hooks of the registered middleware.
+func (a *Account) preSign(ctx context.Context, tx *types.Transaction) error {
+	for _, mw := range a.opts.middleware {
+		if mw.PreSign == nil {
+			continue
+		}
+		if err := mw.PreSign(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postConfirm calls the PostConfirm hooks of the registered middleware.
+func (a *Account) postConfirm(ctx context.Context, tx *types.Transaction, meta *client.TransactionMeta, err error) {
+	for i := len(a.opts.middleware) - 1; i >= 0; i-- {
+		if fn := a.opts.middleware[i].PostConfirm; fn != nil {
+			fn(ctx, tx, meta, err)
+		}
+	}
+}
+
+// Transaction outcomes reported by the Metrics middleware.
+const (
+	OutcomeExecuted = "executed"
+	OutcomeFailed   = "failed"
+	OutcomeRejected = "rejected"
+	OutcomeUnknown  = "unknown"
+)
+
+var (
+	metricsOnce sync.Once
+
+	transactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_sdk_account_transactions",
+			Help: "Number of transactions submitted through accounts by method and outcome.",
+		},
+		[]string{"method", "outcome"},
+	)
+)
+
+// Outcome classifies the outcome of a transaction as passed to PostConfirm.
+func Outcome(meta *client.TransactionMeta, err error) string {
+	switch {
+	case meta == nil:
+		return OutcomeUnknown
+	case meta.CheckTxError != nil:
+		return OutcomeRejected
+	case err != nil:
+		return OutcomeFailed
+	default:
+		return OutcomeExecuted
+	}
+}
+
+// Metrics returns a middleware counting transactions by method and outcome in the
+// oasis_sdk_account_transactions metric.
+func Metrics() *Middleware {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(transactions)
+	})
+	return &Middleware{
+		PostConfirm: func(ctx context.Context, tx *types.Transaction, meta *client.TransactionMeta, err error) {
+			transactions.WithLabelValues(tx.Call.Method, Outcome(meta, err)).Inc()
+		},
+	}
+}
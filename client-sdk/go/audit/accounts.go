@@ -0,0 +1,150 @@
+// Package audit implements periodic hygiene checks and verification of on-chain state.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// AccountsSource is the source of account information, usually the accounts module client.
+type AccountsSource interface {
+	Role(ctx context.Context, round uint64, address types.Address) (types.Role, error)
+	RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error)
+	Nonce(ctx context.Context, round uint64, address types.Address) (uint64, error)
+	Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error)
+}
+
+var _ AccountsSource = (accounts.V1)(nil)
+
+// FindingKind is the kind of an audit finding.
+type FindingKind string
+
+const (
+	// FindingZeroBalance is reported for role holders without any balance.
+	FindingZeroBalance FindingKind = "zero_balance"
+	// FindingInactive is reported for role holders that have never submitted a transaction.
+	FindingInactive FindingKind = "inactive"
+	// FindingBlacklistedBalance is reported for blacklisted addresses still holding a balance,
+	// which they can no longer move.
+	FindingBlacklistedBalance FindingKind = "blacklisted_balance"
+	// FindingRoleMismatch is reported for addresses listed as role holders whose role does not
+	// match the role they are listed under.
+	FindingRoleMismatch FindingKind = "role_mismatch"
+)
+
+// Finding is an anomaly found during an audit.
+type Finding struct {
+	Kind    FindingKind   `json:"kind"`
+	Address types.Address `json:"address"`
+	Role    types.Role    `json:"role"`
+	Detail  string        `json:"detail,omitempty"`
+}
+
+// AccountsReport is the result of an accounts audit.
+type AccountsReport struct {
+	// Round is the round at which the audit was performed.
+	Round uint64 `json:"round"`
+	// Scanned is the number of scanned addresses.
+	Scanned int `json:"scanned"`
+	// Findings are the found anomalies, ordered by address.
+	Findings []Finding `json:"findings"`
+}
+
+// governanceRoles are the roles that grant governance privileges.
+var governanceRoles = []types.Role{
+	types.Admin,
+	types.MintProposer,
+	types.MintVoter,
+	types.BurnProposer,
+	types.BurnVoter,
+	types.WhitelistProposer,
+	types.WhitelistVoter,
+	types.BlacklistProposer,
+	types.BlacklistVoter,
+}
+
+// AuditAccounts scans all role assignments at the given round, cross-references them with
+// balances and activity and reports any anomalies.
+//
+// A concrete round should be passed so that all queries observe the same state.
+func AuditAccounts(ctx context.Context, src AccountsSource, round uint64) (*AccountsReport, error) {
+	report := AccountsReport{Round: round}
+	roles := append([]types.Role{types.BlacklistedUser}, governanceRoles...)
+	for _, role := range roles {
+		holders, err := src.RolesTeam(ctx, round, role)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to query %s role holders: %w", role, err)
+		}
+		for _, addr := range holders {
+			findings, err := auditRoleHolder(ctx, src, round, addr, role)
+			if err != nil {
+				return nil, err
+			}
+			report.Findings = append(report.Findings, findings...)
+			report.Scanned++
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Address.String() < report.Findings[j].Address.String()
+	})
+	return &report, nil
+}
+
+func auditRoleHolder(
+	ctx context.Context,
+	src AccountsSource,
+	round uint64,
+	addr types.Address,
+	role types.Role,
+) ([]Finding, error) {
+	var findings []Finding
+	report := func(kind FindingKind, detail string) {
+		findings = append(findings, Finding{Kind: kind, Address: addr, Role: role, Detail: detail})
+	}
+
+	actual, err := src.Role(ctx, round, addr)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to query role of %s: %w", addr, err)
+	}
+	if actual != role {
+		report(FindingRoleMismatch, fmt.Sprintf("listed as %s but has role %s", role, actual))
+	}
+
+	balances, err := src.Balances(ctx, round, addr)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to query balances of %s: %w", addr, err)
+	}
+	var funded bool
+	for _, amount := range balances.Balances {
+		if !amount.IsZero() {
+			funded = true
+			break
+		}
+	}
+
+	// Each address holds a single role, so blacklisted addresses can't hold governance roles.
+	if role == types.BlacklistedUser {
+		if funded {
+			report(FindingBlacklistedBalance, "")
+		}
+		return findings, nil
+	}
+
+	if !funded {
+		report(FindingZeroBalance, "")
+	}
+
+	nonce, err := src.Nonce(ctx, round, addr)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to query nonce of %s: %w", addr, err)
+	}
+	if nonce == 0 {
+		report(FindingInactive, "")
+	}
+	return findings, nil
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeAccount struct {
+	role    types.Role
+	nonce   uint64
+	balance uint64
+}
+
+type fakeSource struct {
+	accounts map[types.Address]*fakeAccount
+	teams    map[types.Role][]types.Address
+}
+
+func (s *fakeSource) account(addr types.Address) *fakeAccount {
+	if acct, ok := s.accounts[addr]; ok {
+		return acct
+	}
+	return &fakeAccount{role: types.User}
+}
+
+func (s *fakeSource) Role(ctx context.Context, round uint64, address types.Address) (types.Role, error) {
+	return s.account(address).role, nil
+}
+
+func (s *fakeSource) RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error) {
+	return s.teams[role], nil
+}
+
+func (s *fakeSource) Nonce(ctx context.Context, round uint64, address types.Address) (uint64, error) {
+	return s.account(address).nonce, nil
+}
+
+func (s *fakeSource) Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error) {
+	return &accounts.AccountBalances{Balances: map[types.Denomination]types.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(s.account(address).balance),
+	}}, nil
+}
+
+func TestAuditAccounts(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie, dave := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address, sdkTesting.Dave.Address
+	erin := sdkTesting.Erin.Address
+	src := &fakeSource{
+		accounts: map[types.Address]*fakeAccount{
+			alice:   {role: types.Admin, nonce: 5, balance: 100},
+			bob:     {role: types.MintProposer, nonce: 0, balance: 0},
+			charlie: {role: types.User, nonce: 1, balance: 10},
+			dave:    {role: types.BlacklistedUser, nonce: 1, balance: 10},
+			erin:    {role: types.BlacklistedUser, nonce: 1},
+		},
+		teams: map[types.Role][]types.Address{
+			types.Admin:           {alice},
+			types.MintProposer:    {bob},
+			types.BurnVoter:       {charlie},
+			types.BlacklistedUser: {dave, erin},
+		},
+	}
+
+	report, err := AuditAccounts(context.Background(), src, 10)
+	require.NoError(err, "AuditAccounts")
+	require.EqualValues(10, report.Round)
+	require.Equal(5, report.Scanned)
+
+	kinds := make(map[types.Address][]FindingKind)
+	for _, f := range report.Findings {
+		kinds[f.Address] = append(kinds[f.Address], f.Kind)
+	}
+	require.Empty(kinds[alice])
+	require.ElementsMatch([]FindingKind{FindingZeroBalance, FindingInactive}, kinds[bob])
+	require.ElementsMatch([]FindingKind{FindingRoleMismatch}, kinds[charlie])
+	require.ElementsMatch([]FindingKind{FindingBlacklistedBalance}, kinds[dave], "blacklisted addresses with balances should be reported")
+	require.Empty(kinds[erin], "blacklisted addresses should not be checked for activity")
+}
@@ -0,0 +1,365 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// StateSource is the source of the account state compared by Diff, usually the accounts module
+// client.
+type StateSource interface {
+	Parameters(ctx context.Context, round uint64) (*accounts.Parameters, error)
+	Quorums(ctx context.Context, round uint64, action types.Action) (uint8, error)
+	RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error)
+	Addresses(ctx context.Context, round uint64, denomination types.Denomination) (accounts.Addresses, error)
+	Role(ctx context.Context, round uint64, address types.Address) (types.Role, error)
+	Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error)
+}
+
+var _ StateSource = (accounts.V1)(nil)
+
+// governanceActions are the actions whose quorums are part of a snapshot.
+var governanceActions = []types.Action{
+	types.SetRoles,
+	types.Mint,
+	types.Burn,
+	types.Whitelist,
+	types.Blacklist,
+	types.Config,
+}
+
+// AccountState is the state of a single account. Blacklisted accounts are those holding the
+// BlacklistedUser role.
+type AccountState struct {
+	Role        types.Role                            `json:"role"`
+	Blacklisted bool                                  `json:"blacklisted,omitempty"`
+	Balances    map[types.Denomination]types.Quantity `json:"balances,omitempty"`
+}
+
+// Snapshot is the account state at a given round. It is serializable to JSON so that snapshots
+// can be stored and compared later on.
+type Snapshot struct {
+	Round      uint64                          `json:"round"`
+	Parameters *accounts.Parameters            `json:"parameters"`
+	Quorums    map[types.Action]uint8          `json:"quorums"`
+	Accounts   map[types.Address]*AccountState `json:"accounts"`
+}
+
+// SnapshotOptions are the snapshot options.
+type SnapshotOptions struct {
+	// Denominations are the denominations whose holders are included in the snapshot in addition
+	// to all role holders. Holders of the native denomination are included if empty.
+	Denominations []types.Denomination
+	// Addresses are additional addresses to include, e.g. accounts which neither hold a role nor
+	// a balance.
+	Addresses []types.Address
+}
+
+// TakeSnapshot takes a snapshot of the account state at the given round.
+//
+// A concrete round should be passed so that all queries observe the same state.
+func TakeSnapshot(ctx context.Context, src StateSource, round uint64, opts *SnapshotOptions) (*Snapshot, error) {
+	addrs, err := snapshotAddresses(ctx, src, round, opts)
+	if err != nil {
+		return nil, err
+	}
+	return takeSnapshot(ctx, src, round, addrs)
+}
+
+// snapshotAddresses returns the addresses of the accounts included in a snapshot.
+func snapshotAddresses(ctx context.Context, src StateSource, round uint64, opts *SnapshotOptions) (map[types.Address]struct{}, error) {
+	if opts == nil {
+		opts = &SnapshotOptions{}
+	}
+	addrs := make(map[types.Address]struct{})
+	for _, addr := range opts.Addresses {
+		addrs[addr] = struct{}{}
+	}
+	roles := append([]types.Role{types.BlacklistedUser}, governanceRoles...)
+	for _, role := range roles {
+		holders, err := src.RolesTeam(ctx, round, role)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to query %s role holders: %w", role, err)
+		}
+		for _, addr := range holders {
+			addrs[addr] = struct{}{}
+		}
+	}
+	denominations := opts.Denominations
+	if len(denominations) == 0 {
+		denominations = []types.Denomination{types.NativeDenomination}
+	}
+	for _, denomination := range denominations {
+		holders, err := src.Addresses(ctx, round, denomination)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to query %s holders: %w", denomination, err)
+		}
+		for _, addr := range holders {
+			addrs[addr] = struct{}{}
+		}
+	}
+	return addrs, nil
+}
+
+// takeSnapshot takes a snapshot of the given accounts at the given round.
+func takeSnapshot(ctx context.Context, src StateSource, round uint64, addrs map[types.Address]struct{}) (*Snapshot, error) {
+	params, err := src.Parameters(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to query parameters: %w", err)
+	}
+	snapshot := Snapshot{
+		Round:      round,
+		Parameters: params,
+		Quorums:    make(map[types.Action]uint8, len(governanceActions)),
+		Accounts:   make(map[types.Address]*AccountState, len(addrs)),
+	}
+	for _, action := range governanceActions {
+		if snapshot.Quorums[action], err = src.Quorums(ctx, round, action); err != nil {
+			return nil, fmt.Errorf("audit: failed to query %s quorum: %w", action, err)
+		}
+	}
+	for addr := range addrs {
+		var state AccountState
+		if state.Role, err = src.Role(ctx, round, addr); err != nil {
+			return nil, fmt.Errorf("audit: failed to query role of %s: %w", addr, err)
+		}
+		state.Blacklisted = state.Role == types.BlacklistedUser
+		balances, err := src.Balances(ctx, round, addr)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to query balances of %s: %w", addr, err)
+		}
+		state.Balances = balances.Balances
+		snapshot.Accounts[addr] = &state
+	}
+	return &snapshot, nil
+}
+
+// ChangeKind is the kind of a state change.
+type ChangeKind string
+
+const (
+	// ChangeParameter is reported for changed accounts module parameters.
+	ChangeParameter ChangeKind = "parameter"
+	// ChangeQuorum is reported for changed governance quorums.
+	ChangeQuorum ChangeKind = "quorum"
+	// ChangeRole is reported for accounts whose role changed.
+	ChangeRole ChangeKind = "role"
+	// ChangeBlacklist is reported for accounts whose blacklist status changed, in addition to the
+	// corresponding role change.
+	ChangeBlacklist ChangeKind = "blacklist"
+	// ChangeBalance is reported for changed account balances.
+	ChangeBalance ChangeKind = "balance"
+)
+
+// Change is a single change of the account state between two snapshots.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Address is the affected account for account changes.
+	Address *types.Address `json:"address,omitempty"`
+	// Key identifies the changed value: the parameter path (e.g. "gas_costs.tx_transfer") for
+	// parameter changes, the action for quorum changes and the denomination for balance changes.
+	Key string `json:"key,omitempty"`
+	// Old is the value in the first snapshot.
+	Old string `json:"old"`
+	// New is the value in the second snapshot.
+	New string `json:"new"`
+}
+
+// Diff returns the changes between the given snapshots. Parameter and quorum changes come first,
+// followed by the account changes ordered by address.
+//
+// Accounts only present in one of the snapshots are reported with empty values on the other
+// side. Use DiffRounds to compare the same set of accounts at both rounds.
+func Diff(from, to *Snapshot) ([]Change, error) {
+	var changes []Change
+
+	oldParams, err := flattenParameters(from.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	newParams, err := flattenParameters(to.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(oldParams, newParams) {
+		if oldParams[key] != newParams[key] {
+			changes = append(changes, Change{Kind: ChangeParameter, Key: key, Old: oldParams[key], New: newParams[key]})
+		}
+	}
+
+	for _, action := range governanceActions {
+		oldQuorum, oldOk := from.Quorums[action]
+		newQuorum, newOk := to.Quorums[action]
+		if oldQuorum != newQuorum || oldOk != newOk {
+			changes = append(changes, Change{
+				Kind: ChangeQuorum,
+				Key:  action.String(),
+				Old:  formatQuorum(oldQuorum, oldOk),
+				New:  formatQuorum(newQuorum, newOk),
+			})
+		}
+	}
+
+	addrs := make([]types.Address, 0, len(from.Accounts)+len(to.Accounts))
+	for addr := range from.Accounts {
+		addrs = append(addrs, addr)
+	}
+	for addr := range to.Accounts {
+		if _, ok := from.Accounts[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+	for i := range addrs {
+		changes = append(changes, diffAccount(&addrs[i], from.Accounts[addrs[i]], to.Accounts[addrs[i]])...)
+	}
+	return changes, nil
+}
+
+// DiffRounds takes snapshots of the same set of accounts at both rounds and returns the changes
+// between them, e.g. to verify the effects of a runtime upgrade.
+func DiffRounds(ctx context.Context, src StateSource, from, to uint64, opts *SnapshotOptions) ([]Change, error) {
+	addrs, err := snapshotAddresses(ctx, src, from, opts)
+	if err != nil {
+		return nil, err
+	}
+	toAddrs, err := snapshotAddresses(ctx, src, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	for addr := range toAddrs {
+		addrs[addr] = struct{}{}
+	}
+
+	fromSnapshot, err := takeSnapshot(ctx, src, from, addrs)
+	if err != nil {
+		return nil, err
+	}
+	toSnapshot, err := takeSnapshot(ctx, src, to, addrs)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(fromSnapshot, toSnapshot)
+}
+
+// diffAccount returns the changes of the given account. Either state may be nil.
+func diffAccount(addr *types.Address, from, to *AccountState) []Change {
+	var changes []Change
+	role := func(s *AccountState) string {
+		if s == nil {
+			return ""
+		}
+		return s.Role.String()
+	}
+	if oldRole, newRole := role(from), role(to); oldRole != newRole {
+		changes = append(changes, Change{Kind: ChangeRole, Address: addr, Old: oldRole, New: newRole})
+	}
+
+	blacklisted := func(s *AccountState) string {
+		if s == nil {
+			return ""
+		}
+		return strconv.FormatBool(s.Blacklisted)
+	}
+	if oldBlacklisted, newBlacklisted := blacklisted(from), blacklisted(to); oldBlacklisted != newBlacklisted {
+		changes = append(changes, Change{Kind: ChangeBlacklist, Address: addr, Old: oldBlacklisted, New: newBlacklisted})
+	}
+
+	balances := func(s *AccountState) map[string]string {
+		m := make(map[string]string)
+		if s == nil {
+			return m
+		}
+		for denomination, amount := range s.Balances {
+			if !amount.IsZero() {
+				m[denomination.String()] = amount.String()
+			}
+		}
+		return m
+	}
+	oldBalances, newBalances := balances(from), balances(to)
+	for _, denomination := range sortedKeys(oldBalances, newBalances) {
+		oldAmount, newAmount := oldBalances[denomination], newBalances[denomination]
+		if oldAmount == newAmount {
+			continue
+		}
+		// Missing balances are zero, unless the whole account is missing.
+		if oldAmount == "" && from != nil {
+			oldAmount = "0"
+		}
+		if newAmount == "" && to != nil {
+			newAmount = "0"
+		}
+		changes = append(changes, Change{Kind: ChangeBalance, Address: addr, Key: denomination, Old: oldAmount, New: newAmount})
+	}
+	return changes
+}
+
+// flattenParameters flattens the JSON encoding of the given parameters into a map from dotted
+// field paths to JSON-encoded values.
+func flattenParameters(params *accounts.Parameters) (map[string]string, error) {
+	flat := make(map[string]string)
+	if params == nil {
+		return flat, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to marshal parameters: %w", err)
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("audit: failed to unmarshal parameters: %w", err)
+	}
+	flatten(flat, "", v)
+	return flat, nil
+}
+
+func flatten(flat map[string]string, prefix string, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		data, _ := json.Marshal(v)
+		flat[prefix] = string(data)
+		return
+	}
+	for key, item := range m {
+		if key == "" {
+			// The native denomination in denomination infos.
+			key = types.NativeDenomination.String()
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flatten(flat, key, item)
+	}
+}
+
+// formatQuorum formats a quorum, returning an empty string in case it is unknown.
+func formatQuorum(quorum uint8, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(quorum), 10)
+}
+
+// sortedKeys returns the sorted union of the keys of the given maps.
+func sortedKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
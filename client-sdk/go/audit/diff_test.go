@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeState struct {
+	params   accounts.Parameters
+	quorums  map[types.Action]uint8
+	teams    map[types.Role][]types.Address
+	roles    map[types.Address]types.Role
+	balances map[types.Address]uint64
+}
+
+// fakeStateSource serves the given state at each round.
+type fakeStateSource map[uint64]*fakeState
+
+func (s fakeStateSource) Parameters(ctx context.Context, round uint64) (*accounts.Parameters, error) {
+	params := s[round].params
+	return &params, nil
+}
+
+func (s fakeStateSource) Quorums(ctx context.Context, round uint64, action types.Action) (uint8, error) {
+	return s[round].quorums[action], nil
+}
+
+func (s fakeStateSource) RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error) {
+	return s[round].teams[role], nil
+}
+
+func (s fakeStateSource) Addresses(ctx context.Context, round uint64, denomination types.Denomination) (accounts.Addresses, error) {
+	var addrs accounts.Addresses
+	for addr, balance := range s[round].balances {
+		if balance > 0 {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+func (s fakeStateSource) Role(ctx context.Context, round uint64, address types.Address) (types.Role, error) {
+	if role, ok := s[round].roles[address]; ok {
+		return role, nil
+	}
+	return types.User, nil
+}
+
+func (s fakeStateSource) Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error) {
+	return &accounts.AccountBalances{Balances: map[types.Denomination]types.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(s[round].balances[address]),
+	}}, nil
+}
+
+func TestDiff(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	src := fakeStateSource{
+		10: {
+			params:   accounts.Parameters{GasCosts: accounts.GasCosts{TxTransfer: 1000}},
+			quorums:  map[types.Action]uint8{types.Mint: 50},
+			teams:    map[types.Role][]types.Address{types.Admin: {alice}},
+			roles:    map[types.Address]types.Role{alice: types.Admin},
+			balances: map[types.Address]uint64{alice: 100, bob: 10},
+		},
+		20: {
+			params:  accounts.Parameters{GasCosts: accounts.GasCosts{TxTransfer: 2000}},
+			quorums: map[types.Action]uint8{types.Mint: 50},
+			teams: map[types.Role][]types.Address{
+				types.Admin:           {alice},
+				types.MintProposer:    {charlie},
+				types.BlacklistedUser: {bob},
+			},
+			roles:    map[types.Address]types.Role{alice: types.Admin, bob: types.BlacklistedUser, charlie: types.MintProposer},
+			balances: map[types.Address]uint64{alice: 100},
+		},
+	}
+	ctx := context.Background()
+
+	changes, err := DiffRounds(ctx, src, 10, 20, nil)
+	require.NoError(err, "DiffRounds")
+
+	bobChanges := []Change{
+		{Kind: ChangeRole, Address: &bob, Old: types.User.String(), New: types.BlacklistedUser.String()},
+		{Kind: ChangeBlacklist, Address: &bob, Old: "false", New: "true"},
+		{Kind: ChangeBalance, Address: &bob, Key: "<native>", Old: "10", New: "0"},
+	}
+	charlieChanges := []Change{
+		{Kind: ChangeRole, Address: &charlie, Old: types.User.String(), New: types.MintProposer.String()},
+	}
+	expected := []Change{
+		{Kind: ChangeParameter, Key: "gas_costs.tx_transfer", Old: "1000", New: "2000"},
+	}
+	if bob.String() < charlie.String() {
+		expected = append(append(expected, bobChanges...), charlieChanges...)
+	} else {
+		expected = append(append(expected, charlieChanges...), bobChanges...)
+	}
+	require.Equal(expected, changes)
+
+	// Stored snapshots can be compared as well.
+	from, err := TakeSnapshot(ctx, src, 10, nil)
+	require.NoError(err, "TakeSnapshot")
+	data, err := json.Marshal(from)
+	require.NoError(err, "json.Marshal")
+	var stored Snapshot
+	require.NoError(json.Unmarshal(data, &stored), "json.Unmarshal")
+	require.Equal(from, &stored)
+
+	to, err := TakeSnapshot(ctx, src, 20, nil)
+	require.NoError(err, "TakeSnapshot")
+	changes, err = Diff(&stored, to)
+	require.NoError(err, "Diff")
+	require.Contains(changes, Change{Kind: ChangeRole, Address: &charlie, Old: "", New: types.MintProposer.String()},
+		"accounts missing from a snapshot should be reported with empty values")
+
+	changes, err = Diff(from, from)
+	require.NoError(err, "Diff")
+	require.Empty(changes)
+}
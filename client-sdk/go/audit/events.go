@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// StoredEvent is a raw event together with the form it was decoded into when it was stored, e.g.
+// by an indexer.
+type StoredEvent struct {
+	// Round is the round in which the event was emitted.
+	Round uint64
+	// Raw is the raw event.
+	Raw types.Event
+	// Decoded is the JSON encoding of the list of events the raw event was decoded into.
+	Decoded json.RawMessage
+}
+
+// EventStore is a store of previously decoded events.
+type EventStore interface {
+	// ForEach calls the given function for all stored events. Iteration stops at the first error.
+	ForEach(ctx context.Context, fn func(*StoredEvent) error) error
+}
+
+// EventMismatch is a stored event whose decoding changed.
+type EventMismatch struct {
+	// Event is the stored event.
+	Event *StoredEvent
+	// Current is the JSON encoding of the events the raw event is decoded into now.
+	Current json.RawMessage
+	// Err is the decoding error (if any).
+	Err error
+}
+
+// EventReplayReport is the result of an event replay check.
+type EventReplayReport struct {
+	// Checked is the number of checked events.
+	Checked int
+	// Mismatches are the events whose decoding changed.
+	Mismatches []EventMismatch
+}
+
+// CheckEventReplay re-decodes all stored raw events using the given decoders and reports the
+// events whose decoded form differs from the stored one. This can be used to catch changes in
+// decoding behavior before upgrading the SDK used by an indexer.
+func CheckEventReplay(ctx context.Context, store EventStore, decoders []client.EventDecoder) (*EventReplayReport, error) {
+	var report EventReplayReport
+	err := store.ForEach(ctx, func(ev *StoredEvent) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report.Checked++
+
+		// Decoders may depend on the round (e.g. schema upgrades), which is stored separately.
+		raw := ev.Raw
+		raw.Round = ev.Round
+		current, err := decodeEvent(&raw, decoders)
+		if err != nil {
+			report.Mismatches = append(report.Mismatches, EventMismatch{Event: ev, Err: err})
+			return nil
+		}
+
+		stored, err := normalizeJSON(ev.Decoded)
+		if err != nil {
+			return fmt.Errorf("audit: malformed stored event (round %d): %w", ev.Round, err)
+		}
+		if !bytes.Equal(stored, current) {
+			report.Mismatches = append(report.Mismatches, EventMismatch{Event: ev, Current: current})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// decodeEvent decodes the given raw event using the first decoder that recognizes it and returns
+// the normalized JSON encoding of the result.
+func decodeEvent(ev *types.Event, decoders []client.EventDecoder) (json.RawMessage, error) {
+	evs := []client.DecodedEvent{}
+	for _, decoder := range decoders {
+		decoded, err := decoder.DecodeEvent(ev)
+		if err != nil {
+			return nil, err
+		}
+		if decoded != nil {
+			evs = decoded
+			break
+		}
+	}
+
+	raw, err := json.Marshal(evs)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeJSON(raw)
+}
+
+// normalizeJSON re-encodes the given JSON document so that equivalent documents compare equal.
+func normalizeJSON(raw json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
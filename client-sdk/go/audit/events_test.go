@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type memoryEventStore []*StoredEvent
+
+func (s memoryEventStore) ForEach(ctx context.Context, fn func(*StoredEvent) error) error {
+	for _, ev := range s {
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type decoderFunc func(*types.Event) ([]client.DecodedEvent, error)
+
+func (f decoderFunc) DecodeEvent(ev *types.Event) ([]client.DecodedEvent, error) {
+	return f(ev)
+}
+
+func TestCheckEventReplay(t *testing.T) {
+	require := require.New(t)
+
+	raw := types.Event{
+		Module: accounts.ModuleName,
+		Code:   accounts.MintEventCode,
+		Value: cbor.Marshal([]*accounts.MintEvent{{
+			Owner:  sdkTesting.Alice.Address,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		}}),
+	}
+	// Stored events do not necessarily carry their round in the raw event.
+	decodeAt := func(round uint64) json.RawMessage {
+		ev := raw
+		ev.Round = round
+		decoded, err := accounts.DecodeEvent(&ev)
+		require.NoError(err, "DecodeEvent")
+		good, err := json.Marshal(decoded)
+		require.NoError(err, "Marshal")
+		return good
+	}
+
+	store := memoryEventStore{
+		{Round: 1, Raw: raw, Decoded: decodeAt(1)},
+		{Round: 2, Raw: raw, Decoded: json.RawMessage(`[]`)},
+	}
+	decoders := []client.EventDecoder{decoderFunc(accounts.DecodeEvent)}
+
+	report, err := CheckEventReplay(context.Background(), store, decoders)
+	require.NoError(err, "CheckEventReplay")
+	require.Equal(2, report.Checked)
+	require.Len(report.Mismatches, 1)
+	require.EqualValues(2, report.Mismatches[0].Event.Round)
+
+	failing := decoderFunc(func(*types.Event) ([]client.DecodedEvent, error) {
+		return nil, fmt.Errorf("decoding failed")
+	})
+	report, err = CheckEventReplay(context.Background(), store[:1], []client.EventDecoder{failing})
+	require.NoError(err, "CheckEventReplay")
+	require.Len(report.Mismatches, 1)
+	require.Error(report.Mismatches[0].Err)
+
+	// Events should be decoded as of the round they were emitted in.
+	upgraded := decoderFunc(func(ev *types.Event) ([]client.DecodedEvent, error) {
+		if ev.Round < 5 {
+			return nil, fmt.Errorf("legacy schema")
+		}
+		return accounts.DecodeEvent(ev)
+	})
+	store = memoryEventStore{{Round: 10, Raw: raw, Decoded: decodeAt(10)}}
+	report, err = CheckEventReplay(context.Background(), store, []client.EventDecoder{upgraded})
+	require.NoError(err, "CheckEventReplay")
+	require.Empty(report.Mismatches)
+	require.Zero(store[0].Raw.Round, "stored events should not be modified")
+}
@@ -0,0 +1,226 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// methodVoteST is the accounts method whose execution may pass a mint or burn proposal.
+const methodVoteST = "accounts.VoteST"
+
+// TransactionSource is the source of executed transactions, usually the runtime client.
+type TransactionSource interface {
+	GetTransactionsWithResults(ctx context.Context, round uint64) ([]*client.TransactionWithResults, error)
+}
+
+// ProposalSource is the source of governance proposals, usually the accounts module client.
+type ProposalSource interface {
+	ProposalInfo(ctx context.Context, round uint64, id uint32) (*accounts.ProposalOutput, error)
+}
+
+var (
+	_ TransactionSource = (client.RuntimeClient)(nil)
+	_ ProposalSource    = (accounts.V1)(nil)
+)
+
+// SupplyChange is a single mint or burn together with the proposal that authorized it.
+type SupplyChange struct {
+	// Round is the round in which the supply change happened.
+	Round uint64 `json:"round"`
+	// TxHash is the hash of the transaction that caused the supply change.
+	TxHash string `json:"tx_hash"`
+	// Method is the method of the transaction that caused the supply change.
+	Method string `json:"method"`
+	// Action is either types.Mint or types.Burn.
+	Action types.Action `json:"action"`
+	// Owner is the address whose balance changed.
+	Owner types.Address `json:"owner"`
+	// Amount is the minted or burned amount.
+	Amount types.BaseUnits `json:"amount"`
+
+	// ProposalID is the identifier of the originating proposal. It is zero for unpaired changes.
+	ProposalID uint32 `json:"proposal_id,omitempty"`
+	// Submitter is the submitter of the originating proposal.
+	Submitter *types.Address `json:"submitter,omitempty"`
+	// Votes are the votes cast on the originating proposal.
+	Votes map[types.Address]types.Vote `json:"votes,omitempty"`
+}
+
+// IsPaired returns true iff the supply change was matched with its originating proposal.
+func (sc *SupplyChange) IsPaired() bool {
+	return sc.ProposalID != 0
+}
+
+// SupplyReport is the result of a mint/burn reconciliation over a range of rounds.
+type SupplyReport struct {
+	// FromRound is the first round included in the report.
+	FromRound uint64 `json:"from_round"`
+	// ToRound is the last round included in the report.
+	ToRound uint64 `json:"to_round"`
+	// Changes are all supply changes in the round range, in execution order.
+	Changes []SupplyChange `json:"changes"`
+}
+
+// Unpaired returns the supply changes that could not be matched with an originating proposal,
+// e.g. because they were caused by direct MintST/BurnST calls of the chain initiator.
+func (r *SupplyReport) Unpaired() []SupplyChange {
+	var unpaired []SupplyChange
+	for _, sc := range r.Changes {
+		if !sc.IsPaired() {
+			unpaired = append(unpaired, sc)
+		}
+	}
+	return unpaired
+}
+
+// ReconcileSupply pairs every mint and burn in rounds [from, to] with the governance proposal that
+// authorized it.
+//
+// Mints and burns authorized by governance are executed by the VoteST transaction that passes the
+// proposal, so the proposal is looked up as of the round of that transaction. Supply changes that
+// were not caused by passing a matching proposal are reported as unpaired.
+func ReconcileSupply(ctx context.Context, txs TransactionSource, proposals ProposalSource, from, to uint64) (*SupplyReport, error) {
+	if from > to {
+		return nil, fmt.Errorf("audit: invalid round range [%d, %d]", from, to)
+	}
+
+	report := SupplyReport{
+		FromRound: from,
+		ToRound:   to,
+	}
+	for round := from; round <= to; round++ {
+		results, err := txs.GetTransactionsWithResults(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to fetch transactions at round %d: %w", round, err)
+		}
+		for _, twr := range results {
+			changes, err := reconcileTx(ctx, proposals, round, twr)
+			if err != nil {
+				return nil, err
+			}
+			report.Changes = append(report.Changes, changes...)
+		}
+	}
+	return &report, nil
+}
+
+// reconcileTx extracts the supply changes caused by the given transaction and pairs them with
+// the proposal passed by it (if any).
+func reconcileTx(ctx context.Context, proposals ProposalSource, round uint64, twr *client.TransactionWithResults) ([]SupplyChange, error) {
+	if !twr.Result.IsSuccess() {
+		return nil, nil
+	}
+
+	txHash := twr.Tx.Hash().String()
+	var changes []SupplyChange
+	for _, rawEv := range twr.Events {
+		evs, err := accounts.DecodeEvent(rawEv)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to decode event in tx %s: %w", txHash, err)
+		}
+		for _, ev := range evs {
+			ae := ev.(*accounts.Event)
+			switch {
+			case ae.Mint != nil:
+				changes = append(changes, SupplyChange{Action: types.Mint, Owner: ae.Mint.Owner, Amount: ae.Mint.Amount})
+			case ae.Burn != nil:
+				changes = append(changes, SupplyChange{Action: types.Burn, Owner: ae.Burn.Owner, Amount: ae.Burn.Amount})
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	var tx types.Transaction
+	if err := cbor.Unmarshal(twr.Tx.Body, &tx); err != nil {
+		return nil, fmt.Errorf("audit: malformed transaction %s: %w", txHash, err)
+	}
+
+	var proposal *accounts.ProposalOutput
+	if tx.Call.Method == methodVoteST {
+		var vote accounts.VoteProposal
+		if err := cbor.Unmarshal(tx.Call.Body, &vote); err != nil {
+			return nil, fmt.Errorf("audit: malformed vote in tx %s: %w", txHash, err)
+		}
+		p, err := proposals.ProposalInfo(ctx, round, vote.ID)
+		if err != nil {
+			return nil, fmt.Errorf("audit: failed to query proposal %d: %w", vote.ID, err)
+		}
+		if p.State == types.Passed {
+			proposal = p
+		}
+	}
+
+	for i := range changes {
+		sc := &changes[i]
+		sc.Round = round
+		sc.TxHash = txHash
+		sc.Method = tx.Call.Method
+		if proposal != nil && proposalAuthorizes(proposal, sc) {
+			submitter := proposal.Submitter
+			sc.ProposalID = proposal.ID
+			sc.Submitter = &submitter
+			sc.Votes = proposal.VoteOption
+		}
+	}
+	return changes, nil
+}
+
+// proposalAuthorizes checks whether the given proposal authorizes the given supply change.
+func proposalAuthorizes(p *accounts.ProposalOutput, sc *SupplyChange) bool {
+	data := p.Content.Data
+	if p.Content.Action != sc.Action || data.Address == nil || data.Amount == nil {
+		return false
+	}
+	return data.Address.Equal(sc.Owner) &&
+		data.Amount.Denomination == sc.Amount.Denomination &&
+		data.Amount.Amount.Cmp(&sc.Amount.Amount) == 0
+}
+
+// SignedSupplyReport is a supply report signed by the attesting party.
+type SignedSupplyReport struct {
+	// Body is the CBOR-encoded SupplyReport.
+	Body []byte `json:"body"`
+	// PublicKey is the public key of the signer.
+	PublicKey types.PublicKey `json:"public_key"`
+	// Signature is the signature over the body.
+	Signature []byte `json:"signature"`
+}
+
+// Sign signs the supply report for the given chain domain separation context.
+func (r *SupplyReport) Sign(ctx signature.Context, signer signature.Signer) (*SignedSupplyReport, error) {
+	body := cbor.Marshal(r)
+	sig, err := types.SignMessage(ctx, signer, body)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to sign supply report: %w", err)
+	}
+	return &SignedSupplyReport{
+		Body:      body,
+		PublicKey: types.PublicKey{PublicKey: signer.Public()},
+		Signature: sig,
+	}, nil
+}
+
+// Open verifies the signed supply report for the given chain domain separation context and
+// returns the report.
+func (sr *SignedSupplyReport) Open(ctx signature.Context) (*SupplyReport, error) {
+	if sr.PublicKey.PublicKey == nil {
+		return nil, fmt.Errorf("audit: missing public key")
+	}
+	if err := types.VerifyMessage(ctx, sr.PublicKey.PublicKey, sr.Body, sr.Signature); err != nil {
+		return nil, fmt.Errorf("audit: supply report signature verification failed: %w", err)
+	}
+	var report SupplyReport
+	if err := cbor.Unmarshal(sr.Body, &report); err != nil {
+		return nil, fmt.Errorf("audit: malformed supply report: %w", err)
+	}
+	return &report, nil
+}
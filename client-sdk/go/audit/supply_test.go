@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeTxSource map[uint64][]*client.TransactionWithResults
+
+func (s fakeTxSource) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*client.TransactionWithResults, error) {
+	return s[round], nil
+}
+
+type fakeProposalSource map[uint32]*accounts.ProposalOutput
+
+func (s fakeProposalSource) ProposalInfo(ctx context.Context, round uint64, id uint32) (*accounts.ProposalOutput, error) {
+	return s[id], nil
+}
+
+func supplyTx(method string, body interface{}, code uint32, ev interface{}) *client.TransactionWithResults {
+	tx := types.NewTransaction(nil, method, body)
+	return &client.TransactionWithResults{
+		Tx: types.UnverifiedTransaction{Body: cbor.Marshal(tx)},
+		Events: []*types.Event{
+			{Module: accounts.ModuleName, Code: code, Value: cbor.Marshal([]interface{}{ev})},
+		},
+	}
+}
+
+func TestReconcileSupply(t *testing.T) {
+	require := require.New(t)
+
+	owner := sdkTesting.Alice.Address
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)
+	other := types.NewBaseUnits(*quantity.NewFromUint64(5), types.NativeDenomination)
+
+	proposals := fakeProposalSource{
+		1: {
+			ID:        1,
+			Submitter: sdkTesting.Bob.Address,
+			State:     types.Passed,
+			Content: accounts.ProposalContent{
+				Action: types.Mint,
+				Data:   types.ProposalData{Address: &owner, Amount: &amount},
+			},
+			VoteOption: map[types.Address]types.Vote{sdkTesting.Charlie.Address: types.VoteYes},
+		},
+	}
+	txs := fakeTxSource{
+		10: {
+			supplyTx("accounts.VoteST", &accounts.VoteProposal{ID: 1, Option: types.VoteYes},
+				accounts.MintEventCode, &accounts.MintEvent{Owner: owner, Amount: amount}),
+		},
+		12: {
+			supplyTx("accounts.BurnST", &accounts.BurnST{Amount: other},
+				accounts.BurnEventCode, &accounts.BurnEvent{Owner: owner, Amount: other}),
+		},
+	}
+
+	_, err := ReconcileSupply(context.Background(), txs, proposals, 12, 10)
+	require.Error(err, "invalid round range should fail")
+
+	report, err := ReconcileSupply(context.Background(), txs, proposals, 10, 12)
+	require.NoError(err, "ReconcileSupply")
+	require.Len(report.Changes, 2)
+
+	mint := report.Changes[0]
+	require.True(mint.IsPaired())
+	require.EqualValues(10, mint.Round)
+	require.Equal(types.Mint, mint.Action)
+	require.EqualValues(1, mint.ProposalID)
+	require.Equal(sdkTesting.Bob.Address, *mint.Submitter)
+	require.Equal(types.VoteYes, mint.Votes[sdkTesting.Charlie.Address])
+
+	unpaired := report.Unpaired()
+	require.Len(unpaired, 1)
+	require.Equal(types.Burn, unpaired[0].Action)
+	require.Equal("accounts.BurnST", unpaired[0].Method)
+
+	chainCtx := signature.Context("test chain")
+	signed, err := report.Sign(chainCtx, sdkTesting.Alice.Signer)
+	require.NoError(err, "Sign")
+	opened, err := signed.Open(chainCtx)
+	require.NoError(err, "Open")
+	require.Equal(report.Changes[0].ProposalID, opened.Changes[0].ProposalID)
+
+	_, err = signed.Open(signature.Context("other chain"))
+	require.Error(err, "signature for a different chain should not verify")
+}
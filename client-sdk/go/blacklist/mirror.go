@@ -0,0 +1,119 @@
+// Package blacklist implements a local mirror of the on-chain blacklist.
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Source is the source of blacklist information, usually the accounts module client.
+type Source interface {
+	RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error)
+	Role(ctx context.Context, round uint64, address types.Address) (types.Role, error)
+}
+
+var _ Source = (accounts.V1)(nil)
+
+// Option is a mirror option.
+type Option func(*Mirror)
+
+// WithVerification makes the mirror confirm every change by querying the role of the affected
+// address at the refresh round before applying it.
+//
+// The runtime does not expose storage proofs so verification relies on the queried node.
+func WithVerification() Option {
+	return func(m *Mirror) {
+		m.verify = true
+	}
+}
+
+// Mirror is a local mirror of the on-chain blacklist which allows O(1) offline screening of
+// addresses. It is safe for concurrent use.
+//
+// Addresses are blacklisted by holding the BlacklistedUser role, which can be granted or revoked
+// by Blacklist, Whitelist, SetRoles and InitOwners proposals. Instead of replaying proposals, the
+// mirror is therefore rebuilt from the addresses holding the role at the refresh round.
+type Mirror struct {
+	src    Source
+	verify bool
+
+	l           sync.RWMutex
+	blacklisted map[types.Address]struct{}
+}
+
+// NewMirror creates a new empty blacklist mirror.
+func NewMirror(src Source, opts ...Option) *Mirror {
+	m := &Mirror{
+		src:         src,
+		blacklisted: make(map[types.Address]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// IsBlacklisted checks whether the given address is blacklisted according to the last refresh.
+func (m *Mirror) IsBlacklisted(address types.Address) bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	_, ok := m.blacklisted[address]
+	return ok
+}
+
+// Len returns the number of blacklisted addresses.
+func (m *Mirror) Len() int {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	return len(m.blacklisted)
+}
+
+// Refresh updates the mirror to the blacklist at the given round.
+//
+// In case the refresh fails, the mirror is left unchanged and the refresh can be retried.
+func (m *Mirror) Refresh(ctx context.Context, round uint64) error {
+	addresses, err := m.src.RolesTeam(ctx, round, types.BlacklistedUser)
+	if err != nil {
+		return fmt.Errorf("blacklist: failed to query blacklisted addresses: %w", err)
+	}
+	blacklisted := make(map[types.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		blacklisted[address] = struct{}{}
+	}
+
+	if m.verify {
+		// Only addresses whose status changed since the last refresh need to be confirmed.
+		var changed []types.Address
+		m.l.RLock()
+		for address := range blacklisted {
+			if _, ok := m.blacklisted[address]; !ok {
+				changed = append(changed, address)
+			}
+		}
+		for address := range m.blacklisted {
+			if _, ok := blacklisted[address]; !ok {
+				changed = append(changed, address)
+			}
+		}
+		m.l.RUnlock()
+
+		for _, address := range changed {
+			role, err := m.src.Role(ctx, round, address)
+			if err != nil {
+				return fmt.Errorf("blacklist: failed to verify %s: %w", address, err)
+			}
+			if _, listed := blacklisted[address]; listed != (role == types.BlacklistedUser) {
+				return fmt.Errorf("blacklist: role of %s (%s) does not match blacklisted addresses", address, role)
+			}
+		}
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.blacklisted = blacklisted
+	return nil
+}
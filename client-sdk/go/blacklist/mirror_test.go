@@ -0,0 +1,93 @@
+package blacklist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeSource struct {
+	roles   map[types.Address]types.Role
+	team    []types.Address
+	queried []types.Address
+}
+
+func (s *fakeSource) RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error) {
+	if s.team != nil {
+		return s.team, nil
+	}
+	var addresses []types.Address
+	for address, r := range s.roles {
+		if r == role {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+func (s *fakeSource) Role(ctx context.Context, round uint64, address types.Address) (types.Role, error) {
+	s.queried = append(s.queried, address)
+	if role, ok := s.roles[address]; ok {
+		return role, nil
+	}
+	return types.User, nil
+}
+
+func TestMirror(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	src := &fakeSource{roles: map[types.Address]types.Role{
+		alice:   types.BlacklistedUser,
+		bob:     types.User,
+		charlie: types.MintProposer,
+	}}
+
+	m := NewMirror(src)
+	require.NoError(m.Refresh(ctx, 1))
+	require.True(m.IsBlacklisted(alice))
+	require.False(m.IsBlacklisted(bob))
+	require.False(m.IsBlacklisted(charlie))
+	require.Equal(1, m.Len())
+
+	// Roles may change through any kind of proposal (e.g. SetRoles), so the mirror follows them.
+	src.roles[alice] = types.User
+	src.roles[bob] = types.BlacklistedUser
+	require.NoError(m.Refresh(ctx, 2))
+	require.False(m.IsBlacklisted(alice), "unblacklisted address should be removed")
+	require.True(m.IsBlacklisted(bob))
+	require.Empty(src.queried, "addresses should only be verified when enabled")
+}
+
+func TestMirrorVerification(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	src := &fakeSource{roles: map[types.Address]types.Role{alice: types.BlacklistedUser, bob: types.BlacklistedUser}}
+
+	m := NewMirror(src, WithVerification())
+	require.NoError(m.Refresh(ctx, 1))
+	require.ElementsMatch([]types.Address{alice, bob}, src.queried)
+	require.Equal(2, m.Len())
+
+	// Only changes should be verified.
+	src.roles[bob] = types.User
+	src.roles[charlie] = types.BlacklistedUser
+	src.queried = nil
+	require.NoError(m.Refresh(ctx, 2))
+	require.ElementsMatch([]types.Address{bob, charlie}, src.queried)
+	require.True(m.IsBlacklisted(alice))
+	require.False(m.IsBlacklisted(bob))
+	require.True(m.IsBlacklisted(charlie))
+
+	// Unconfirmed changes should fail the refresh and leave the mirror unchanged.
+	src.team = []types.Address{alice, bob, charlie}
+	require.ErrorContains(m.Refresh(ctx, 3), "does not match")
+	require.False(m.IsBlacklisted(bob))
+}
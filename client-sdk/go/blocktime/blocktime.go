@@ -0,0 +1,202 @@
+// Package blocktime maps between rounds and block timestamps, so that reporting code can select
+// the rounds of a time period (e.g. "all rounds in 2024-05") instead of approximating time with
+// round arithmetic, which drifts with variable block times.
+package blocktime
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// DefaultCacheSize is the default maximum number of cached block timestamps.
+const DefaultCacheSize = 100_000
+
+// ErrNoRounds is the error returned when no available round falls into the requested period.
+var ErrNoRounds = errors.New("blocktime: no rounds in period")
+
+// Option is a resolver option.
+type Option func(*Resolver)
+
+// WithCacheSize configures the maximum number of cached block timestamps.
+func WithCacheSize(size int) Option {
+	return func(r *Resolver) {
+		r.cacheSize = size
+	}
+}
+
+// Resolver maps between rounds and block timestamps. Timestamps of looked up blocks are cached, as
+// they never change.
+type Resolver struct {
+	rc        client.RuntimeClient
+	cacheSize int
+
+	l     sync.Mutex
+	cache map[uint64]*list.Element
+	order *list.List
+}
+
+type cacheEntry struct {
+	round     uint64
+	timestamp time.Time
+}
+
+// NewResolver creates a new resolver using the given runtime client.
+func NewResolver(rc client.RuntimeClient, opts ...Option) *Resolver {
+	r := &Resolver{
+		rc:        rc,
+		cacheSize: DefaultCacheSize,
+		cache:     make(map[uint64]*list.Element),
+		order:     list.New(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Timestamp returns the timestamp of the block at the given round.
+func (r *Resolver) Timestamp(ctx context.Context, round uint64) (time.Time, error) {
+	if round != client.RoundLatest {
+		if ts, ok := r.cached(round); ok {
+			return ts, nil
+		}
+	}
+	blk, err := r.rc.GetBlock(ctx, round)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("blocktime: failed to fetch block %d: %w", round, err)
+	}
+	ts := time.Unix(int64(blk.Header.Timestamp), 0).UTC()
+	r.store(blk.Header.Round, ts)
+	return ts, nil
+}
+
+func (r *Resolver) cached(round uint64) (time.Time, bool) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	elem, ok := r.cache[round]
+	if !ok {
+		return time.Time{}, false
+	}
+	r.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).timestamp, true
+}
+
+func (r *Resolver) store(round uint64, ts time.Time) {
+	if r.cacheSize <= 0 {
+		return
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if _, ok := r.cache[round]; ok {
+		return
+	}
+	r.cache[round] = r.order.PushFront(&cacheEntry{round: round, timestamp: ts})
+	for r.order.Len() > r.cacheSize {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.cache, oldest.Value.(*cacheEntry).round)
+	}
+}
+
+// bounds returns the first and last available rounds.
+func (r *Resolver) bounds(ctx context.Context) (uint64, uint64, error) {
+	first, err := r.rc.GetLastRetainedBlock(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("blocktime: failed to fetch last retained block: %w", err)
+	}
+	latest, err := r.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("blocktime: failed to fetch latest block: %w", err)
+	}
+	r.store(first.Header.Round, time.Unix(int64(first.Header.Timestamp), 0).UTC())
+	r.store(latest.Header.Round, time.Unix(int64(latest.Header.Timestamp), 0).UTC())
+	return first.Header.Round, latest.Header.Round, nil
+}
+
+// search returns the first round in [first, last] whose block timestamp satisfies the given
+// predicate, or last+1 in case there is none. The predicate must be monotonic in time.
+func (r *Resolver) search(ctx context.Context, first, last uint64, pred func(time.Time) bool) (uint64, error) {
+	var err error
+	n := sort.Search(int(last-first+1), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var ts time.Time
+		ts, err = r.Timestamp(ctx, first+uint64(i))
+		return pred(ts)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return first + uint64(n), nil
+}
+
+// RoundAt returns the last round whose block timestamp is not after the given time.
+//
+// Returns ErrNoRounds in case the time is before the first available round.
+func (r *Resolver) RoundAt(ctx context.Context, t time.Time) (uint64, error) {
+	first, last, err := r.bounds(ctx)
+	if err != nil {
+		return 0, err
+	}
+	next, err := r.search(ctx, first, last, func(ts time.Time) bool { return ts.After(t) })
+	if err != nil {
+		return 0, err
+	}
+	if next == first {
+		return 0, ErrNoRounds
+	}
+	return next - 1, nil
+}
+
+// Range returns the first and last rounds whose block timestamps fall into [start, end).
+//
+// Returns ErrNoRounds in case no available round falls into the period.
+func (r *Resolver) Range(ctx context.Context, start, end time.Time) (uint64, uint64, error) {
+	first, last, err := r.bounds(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	from, err := r.search(ctx, first, last, func(ts time.Time) bool { return !ts.Before(start) })
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := r.search(ctx, from, last, func(ts time.Time) bool { return !ts.Before(end) })
+	if err != nil {
+		return 0, 0, err
+	}
+	if to == from {
+		return 0, 0, ErrNoRounds
+	}
+	return from, to - 1, nil
+}
+
+// Day returns the first and last rounds of the given calendar day in the given location (UTC if
+// nil).
+func (r *Resolver) Day(ctx context.Context, year int, month time.Month, day int, loc *time.Location) (uint64, uint64, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return r.Range(ctx, start, start.AddDate(0, 0, 1))
+}
+
+// Month returns the first and last rounds of the given calendar month in the given location (UTC
+// if nil).
+func (r *Resolver) Month(ctx context.Context, year int, month time.Month, loc *time.Location) (uint64, uint64, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return r.Range(ctx, start, start.AddDate(0, 1, 0))
+}
@@ -0,0 +1,87 @@
+package blocktime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// start is the timestamp of the first retained block.
+var start = time.Date(2024, 4, 30, 23, 0, 0, 0, time.UTC)
+
+// blocksRuntimeClient serves blocks from round 100 to 1000 produced every 6 seconds, except for a
+// gap of a day after round 500.
+type blocksRuntimeClient struct {
+	client.RuntimeClient
+
+	fetches int
+}
+
+func (rc *blocksRuntimeClient) timestamp(round uint64) time.Time {
+	ts := start.Add(time.Duration(round-100) * 6 * time.Second)
+	if round > 500 {
+		ts = ts.Add(24 * time.Hour)
+	}
+	return ts
+}
+
+func (rc *blocksRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	rc.fetches++
+	if round == client.RoundLatest {
+		round = 1000
+	}
+	var blk block.Block
+	blk.Header.Round = round
+	blk.Header.Timestamp = block.Timestamp(rc.timestamp(round).Unix())
+	return &blk, nil
+}
+
+func (rc *blocksRuntimeClient) GetLastRetainedBlock(ctx context.Context) (*block.Block, error) {
+	return rc.GetBlock(ctx, 100)
+}
+
+func TestResolver(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &blocksRuntimeClient{}
+	r := NewResolver(rc)
+
+	ts, err := r.Timestamp(ctx, 110)
+	require.NoError(err)
+	require.Equal(start.Add(time.Minute), ts)
+	fetches := rc.fetches
+	_, err = r.Timestamp(ctx, 110)
+	require.NoError(err)
+	require.Equal(fetches, rc.fetches, "timestamps should be cached")
+
+	round, err := r.RoundAt(ctx, start.Add(time.Minute+3*time.Second))
+	require.NoError(err)
+	require.EqualValues(110, round)
+	round, err = r.RoundAt(ctx, start.Add(time.Minute))
+	require.NoError(err)
+	require.EqualValues(110, round)
+	_, err = r.RoundAt(ctx, start.Add(-time.Second))
+	require.ErrorIs(err, ErrNoRounds)
+
+	check := func(from, to uint64, err error) func(uint64, uint64) {
+		require.NoError(err)
+		return func(expectedFrom, expectedTo uint64) {
+			require.EqualValues(expectedFrom, from)
+			require.EqualValues(expectedTo, to)
+		}
+	}
+	check(r.Month(ctx, 2024, time.April, nil))(100, 500)
+	check(r.Month(ctx, 2024, time.May, nil))(501, 1000)
+	check(r.Day(ctx, 2024, time.May, 1, nil))(501, 699)
+	cet := time.FixedZone("CET", 3600)
+	check(r.Day(ctx, 2024, time.May, 1, cet))(100, 500)
+	_, _, err = r.Month(ctx, 2024, time.June, nil)
+	require.ErrorIs(err, ErrNoRounds)
+}
@@ -0,0 +1,435 @@
+// Package bridge implements exactly-once deposits into and withdrawals from a runtime, e.g. for
+// exchange integrations.
+//
+// Deposits and withdrawals complete asynchronously: the runtime transaction only emits a
+// consensus layer message and the outcome is reported by a DepositEvent or WithdrawEvent in a
+// later round. A Tracker records each operation in a journal file before submitting it and
+// matches the events to operations by the nonce of the submitting transaction. After a crash,
+// Resume picks up all incomplete operations: transactions that didn't execute are submitted again
+// with the same nonce (so they can't execute twice) and executed ones are matched to their events.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrConflict is the error returned when an operation with the same identifier but different
+// parameters was already recorded.
+var ErrConflict = errors.New("bridge: conflicting operation")
+
+// Kind is the kind of a bridge operation.
+type Kind string
+
+const (
+	// KindDeposit is a deposit from the consensus layer into the runtime.
+	KindDeposit Kind = "deposit"
+	// KindWithdraw is a withdrawal from the runtime into the consensus layer.
+	KindWithdraw Kind = "withdraw"
+)
+
+// Status is the status of a bridge operation.
+type Status string
+
+const (
+	// StatusPending means that the transaction of the operation may not have executed yet.
+	StatusPending Status = "pending"
+	// StatusSubmitted means that the transaction executed and the operation awaits the outcome
+	// of the consensus layer message.
+	StatusSubmitted Status = "submitted"
+	// StatusCompleted means that the operation succeeded.
+	StatusCompleted Status = "completed"
+	// StatusFailed means that the operation failed.
+	StatusFailed Status = "failed"
+)
+
+// Done checks whether the status is final.
+func (s Status) Done() bool {
+	return s == StatusCompleted || s == StatusFailed
+}
+
+// Operation is a bridge operation.
+type Operation struct {
+	// ID is the caller chosen identifier of the operation, e.g. the identifier of the exchange
+	// withdrawal request.
+	ID string `json:"id"`
+	// Kind is the kind of the operation.
+	Kind Kind `json:"kind"`
+	// To is the recipient of the operation (the signer's account if nil).
+	To *types.Address `json:"to,omitempty"`
+	// Amount is the bridged amount.
+	Amount types.BaseUnits `json:"amount"`
+	// Nonce is the nonce of the transaction submitting the operation.
+	Nonce uint64 `json:"nonce"`
+	// Status is the status of the operation.
+	Status Status `json:"status"`
+	// Round is the first round that may contain the event reporting the outcome.
+	Round uint64 `json:"round"`
+	// Error is the reason the operation failed.
+	Error string `json:"error,omitempty"`
+	// Created is the time the operation was recorded.
+	Created time.Time `json:"created"`
+	// Updated is the time the operation was last updated.
+	Updated time.Time `json:"updated"`
+}
+
+func (op *Operation) sameAs(other *Operation) bool {
+	sameTo := (op.To == nil && other.To == nil) || (op.To != nil && other.To != nil && op.To.Equal(*other.To))
+	return op.Kind == other.Kind && sameTo && op.Amount.String() == other.Amount.String()
+}
+
+type options struct {
+	fee *types.Fee
+	now func() time.Time
+}
+
+// Option is an option for configuring the tracker.
+type Option func(*options)
+
+// WithFee sets the fee paid by each operation's transaction.
+func WithFee(fee *types.Fee) Option {
+	return func(opts *options) {
+		opts.fee = fee
+	}
+}
+
+// Tracker submits bridge operations and tracks them until their outcome is known.
+type Tracker struct {
+	rc     client.RuntimeClient
+	ac     accounts.V1
+	ca     consensusaccounts.V1
+	signer signature.Signer
+	spec   types.SignatureAddressSpec
+	path   string
+	opts   options
+
+	l   sync.Mutex
+	ops map[string]*Operation
+}
+
+// NewTracker creates a new tracker for operations signed by the given signer, journaled in the
+// given file. Operations recorded in an existing journal are loaded; call Resume to complete them.
+//
+// The tracker manages the nonces of the signer's account, so the account must not be used for
+// anything else.
+func NewTracker(
+	rc client.RuntimeClient,
+	signer signature.Signer,
+	spec types.SignatureAddressSpec,
+	path string,
+	opts ...Option,
+) (*Tracker, error) {
+	t := &Tracker{
+		rc:     rc,
+		ac:     accounts.NewV1(rc),
+		ca:     consensusaccounts.NewV1(rc),
+		signer: signer,
+		spec:   spec,
+		path:   path,
+		opts:   options{now: time.Now},
+		ops:    make(map[string]*Operation),
+	}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("bridge: failed to read journal: %w", err)
+	default:
+		var ops []*Operation
+		if err = json.Unmarshal(data, &ops); err != nil {
+			return nil, fmt.Errorf("bridge: malformed journal: %w", err)
+		}
+		for _, op := range ops {
+			t.ops[op.ID] = op
+		}
+	}
+	return t, nil
+}
+
+// Deposit deposits the given amount from the signer's consensus account into the given runtime
+// account. The runtime must have an allowance for the signer's consensus account.
+//
+// Calling Deposit again with the same identifier doesn't submit the operation again but returns
+// the recorded operation.
+func (t *Tracker) Deposit(ctx context.Context, id string, to *types.Address, amount types.BaseUnits) (*Operation, error) {
+	return t.submit(ctx, &Operation{ID: id, Kind: KindDeposit, To: to, Amount: amount})
+}
+
+// Withdraw withdraws the given amount from the signer's runtime account into the given consensus
+// account.
+//
+// Calling Withdraw again with the same identifier doesn't submit the operation again but returns
+// the recorded operation.
+func (t *Tracker) Withdraw(ctx context.Context, id string, to *types.Address, amount types.BaseUnits) (*Operation, error) {
+	return t.submit(ctx, &Operation{ID: id, Kind: KindWithdraw, To: to, Amount: amount})
+}
+
+// Operation returns the operation with the given identifier.
+func (t *Tracker) Operation(id string) (*Operation, bool) {
+	t.l.Lock()
+	defer t.l.Unlock()
+	op, ok := t.ops[id]
+	if !ok {
+		return nil, false
+	}
+	cp := *op
+	return &cp, true
+}
+
+// Incomplete returns all operations whose outcome is not known yet in order of nonces.
+func (t *Tracker) Incomplete() []*Operation {
+	t.l.Lock()
+	defer t.l.Unlock()
+	return t.incomplete()
+}
+
+// Resume picks up all incomplete operations: operations whose transaction didn't execute are
+// submitted again and the events of executed ones are looked up. It returns the operations that
+// are still incomplete afterwards, i.e. those still awaiting the consensus layer.
+//
+// Resume should be called after a restart and can be called periodically to track submitted
+// operations.
+func (t *Tracker) Resume(ctx context.Context) ([]*Operation, error) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	for _, op := range t.incomplete() {
+		if op.Status == StatusPending {
+			nonce, err := t.ac.Nonce(ctx, client.RoundLatest, types.NewAddress(t.spec))
+			if err != nil {
+				return nil, fmt.Errorf("bridge: failed to query nonce: %w", err)
+			}
+			if nonce <= op.Nonce {
+				// The transaction didn't execute, submit it again. In case an earlier operation
+				// failed without consuming its nonce, take over that nonce.
+				t.ops[op.ID].Nonce = nonce
+				if err = t.send(ctx, t.ops[op.ID]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			// The transaction executed in an unknown round, but no earlier than the recorded
+			// round.
+			t.update(t.ops[op.ID], StatusSubmitted, "")
+			if err = t.save(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := t.matchEvents(ctx); err != nil {
+		return nil, err
+	}
+	return t.incomplete(), nil
+}
+
+// submit records the given operation and submits it.
+func (t *Tracker) submit(ctx context.Context, op *Operation) (*Operation, error) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	if existing, ok := t.ops[op.ID]; ok {
+		if !existing.sameAs(op) {
+			return nil, fmt.Errorf("%w: %s", ErrConflict, op.ID)
+		}
+		cp := *existing
+		return &cp, nil
+	}
+
+	nonce, err := t.ac.Nonce(ctx, client.RoundLatest, types.NewAddress(t.spec))
+	if err != nil {
+		return nil, fmt.Errorf("bridge: failed to query nonce: %w", err)
+	}
+	for _, other := range t.ops {
+		if !other.Status.Done() && other.Nonce >= nonce {
+			nonce = other.Nonce + 1
+		}
+	}
+	blk, err := t.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("bridge: failed to fetch latest block: %w", err)
+	}
+
+	now := t.opts.now()
+	op.Nonce = nonce
+	op.Status = StatusPending
+	op.Round = blk.Header.Round + 1
+	op.Created, op.Updated = now, now
+	t.ops[op.ID] = op
+	// The operation must be journaled before it is submitted.
+	if err = t.save(); err != nil {
+		delete(t.ops, op.ID)
+		return nil, err
+	}
+
+	err = t.send(ctx, op)
+	cp := *op
+	return &cp, err
+}
+
+// send submits the transaction of the given operation. In case the outcome of the transaction is
+// unknown, the operation remains pending and an error is returned.
+func (t *Tracker) send(ctx context.Context, op *Operation) error {
+	var tb *client.TransactionBuilder
+	switch op.Kind {
+	case KindDeposit:
+		tb = t.ca.Deposit(op.To, op.Amount)
+	case KindWithdraw:
+		tb = t.ca.Withdraw(op.To, op.Amount)
+	default:
+		return fmt.Errorf("bridge: unknown operation kind: %s", op.Kind)
+	}
+	if t.opts.fee != nil {
+		tb.SetFeeAmount(t.opts.fee.Amount)
+		tb.SetFeeGas(t.opts.fee.Gas)
+	}
+	tb.SetFeeConsensusMessages(1)
+	tb.AppendAuthSignature(t.spec, op.Nonce)
+	if err := tb.AppendSign(ctx, t.signer); err != nil {
+		return fmt.Errorf("bridge: failed to sign operation %s: %w", op.ID, err)
+	}
+
+	meta, err := tb.SubmitTxMeta(ctx, nil)
+	switch {
+	case meta == nil:
+		return fmt.Errorf("bridge: failed to submit operation %s: %w", op.ID, err)
+	case meta.CheckTxError != nil:
+		checkErr := types.FailedCallResult{
+			Module:  meta.CheckTxError.Module,
+			Code:    meta.CheckTxError.Code,
+			Message: meta.CheckTxError.Message,
+		}
+		if client.IsTransient(checkErr) {
+			return fmt.Errorf("bridge: operation %s check failed: %w", op.ID, client.Classify(checkErr))
+		}
+		// The nonce was not consumed, so it may be reused by later operations.
+		t.update(op, StatusFailed, checkErr.Error())
+	case err != nil:
+		t.update(op, StatusFailed, err.Error())
+	default:
+		op.Round = meta.Round
+		t.update(op, StatusSubmitted, "")
+	}
+	return t.save()
+}
+
+// matchEvents looks up the events reporting the outcome of submitted operations.
+func (t *Tracker) matchEvents(ctx context.Context) error {
+	byNonce := make(map[uint64]*Operation)
+	from := uint64(0)
+	for _, op := range t.ops {
+		if op.Status != StatusSubmitted {
+			continue
+		}
+		byNonce[op.Nonce] = op
+		if len(byNonce) == 1 || op.Round < from {
+			from = op.Round
+		}
+	}
+	if len(byNonce) == 0 {
+		return nil
+	}
+
+	blk, err := t.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to fetch latest block: %w", err)
+	}
+	signer := types.NewAddress(t.spec)
+	for round := from; round <= blk.Header.Round && len(byNonce) > 0; round++ {
+		evs, err := t.ca.GetEvents(ctx, round)
+		if err != nil {
+			return fmt.Errorf("bridge: failed to fetch events of round %d: %w", round, err)
+		}
+		for _, ev := range evs {
+			var (
+				kind   Kind
+				evFrom types.Address
+				nonce  uint64
+				cErr   *consensusaccounts.ConsensusError
+			)
+			switch {
+			case ev.Deposit != nil:
+				kind, evFrom, nonce, cErr = KindDeposit, ev.Deposit.From, ev.Deposit.Nonce, ev.Deposit.Error
+			case ev.Withdraw != nil:
+				kind, evFrom, nonce, cErr = KindWithdraw, ev.Withdraw.From, ev.Withdraw.Nonce, ev.Withdraw.Error
+			default:
+				continue
+			}
+			op, ok := byNonce[nonce]
+			if !ok || op.Kind != kind || !evFrom.Equal(signer) {
+				continue
+			}
+			if cErr != nil {
+				t.update(op, StatusFailed, fmt.Sprintf("consensus error: module: %s code: %d", cErr.Module, cErr.Code))
+			} else {
+				t.update(op, StatusCompleted, "")
+			}
+			delete(byNonce, nonce)
+		}
+	}
+
+	// Don't scan the same rounds again.
+	for _, op := range byNonce {
+		op.Round = blk.Header.Round + 1
+	}
+	return t.save()
+}
+
+// update sets the status of the given operation.
+func (t *Tracker) update(op *Operation, status Status, reason string) {
+	op.Status = status
+	op.Error = reason
+	op.Updated = t.opts.now()
+}
+
+// incomplete returns copies of all incomplete operations in order of nonces.
+func (t *Tracker) incomplete() []*Operation {
+	var ops []*Operation
+	for _, op := range t.ops {
+		if op.Status.Done() {
+			continue
+		}
+		cp := *op
+		ops = append(ops, &cp)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Nonce < ops[j].Nonce })
+	return ops
+}
+
+// save atomically stores the journal.
+func (t *Tracker) save() error {
+	ops := make([]*Operation, 0, len(t.ops))
+	for _, op := range t.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].ID < ops[j].ID })
+
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to marshal journal: %w", err)
+	}
+	tmpPath := t.path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("bridge: failed to write journal: %w", err)
+	}
+	if err = os.Rename(tmpPath, t.path); err != nil {
+		return fmt.Errorf("bridge: failed to write journal: %w", err)
+	}
+	return nil
+}
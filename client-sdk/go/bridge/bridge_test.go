@@ -0,0 +1,171 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// fakeChain executes deposits and withdrawals, reporting their outcome in the next round.
+// Deposits of more than 100 fail in the consensus layer.
+type fakeChain struct {
+	client.RuntimeClient
+
+	nonce    uint64
+	round    uint64
+	events   map[uint64][]*types.Event
+	executed []string
+
+	// loseBefore loses the connection before executing the next transaction.
+	loseBefore bool
+	// loseAfter loses the connection after executing the next transaction.
+	loseAfter bool
+}
+
+func (c *fakeChain) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: signature.Context("test chain")}, nil
+}
+
+func (c *fakeChain) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = c.round
+	return &blk, nil
+}
+
+func (c *fakeChain) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*uint64) = c.nonce
+	return nil
+}
+
+func (c *fakeChain) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	return c.events[round], nil
+}
+
+func (c *fakeChain) SubmitTxRawMeta(ctx context.Context, utx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	tx, err := utx.Verify(signature.Context("test chain"))
+	if err != nil {
+		return nil, err
+	}
+	if c.loseBefore {
+		c.loseBefore = false
+		return nil, errors.New("connection lost")
+	}
+
+	var meta client.SubmitTxRawMeta
+	nonce := tx.AuthInfo.SignerInfo[0].Nonce
+	if nonce != c.nonce {
+		meta.CheckTxError = &client.CheckTxError{Module: "core", Code: 4, Message: "invalid nonce"}
+		return &meta, nil
+	}
+
+	from := sdkTesting.Alice.Address
+	var ev *types.Event
+	switch tx.Call.Method {
+	case "consensus.Deposit":
+		var body consensusaccounts.Deposit
+		if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+			return nil, err
+		}
+		dev := &consensusaccounts.DepositEvent{From: from, Nonce: nonce, Amount: body.Amount}
+		if body.Amount.Amount.Cmp(quantity.NewFromUint64(100)) > 0 {
+			dev.Error = &consensusaccounts.ConsensusError{Module: "staking", Code: 5}
+		}
+		ev = &types.Event{Module: consensusaccounts.ModuleName, Code: consensusaccounts.DepositEventCode, Value: cbor.Marshal([]*consensusaccounts.DepositEvent{dev})}
+	case "consensus.Withdraw":
+		var body consensusaccounts.Withdraw
+		if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+			return nil, err
+		}
+		wev := &consensusaccounts.WithdrawEvent{From: from, Nonce: nonce, Amount: body.Amount}
+		ev = &types.Event{Module: consensusaccounts.ModuleName, Code: consensusaccounts.WithdrawEventCode, Value: cbor.Marshal([]*consensusaccounts.WithdrawEvent{wev})}
+	}
+
+	c.nonce++
+	c.round++
+	c.executed = append(c.executed, tx.Call.Method)
+	c.events[c.round+1] = append(c.events[c.round+1], ev)
+	meta.Round = c.round
+	meta.Result.Ok = cbor.Marshal(nil)
+	if c.loseAfter {
+		c.loseAfter = false
+		return nil, errors.New("connection lost")
+	}
+	return &meta, nil
+}
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	bob := sdkTesting.Bob.Address
+	amount := func(v uint64) types.BaseUnits {
+		return types.NewBaseUnits(*quantity.NewFromUint64(v), types.NativeDenomination)
+	}
+	chain := &fakeChain{nonce: 3, round: 10, events: make(map[uint64][]*types.Event)}
+	path := filepath.Join(t.TempDir(), "bridge.json")
+
+	tr, err := NewTracker(chain, sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec, path)
+	require.NoError(err, "NewTracker")
+
+	op, err := tr.Withdraw(ctx, "w1", &bob, amount(10))
+	require.NoError(err, "Withdraw")
+	require.Equal(StatusSubmitted, op.Status)
+	require.EqualValues(3, op.Nonce)
+
+	op, err = tr.Withdraw(ctx, "w1", &bob, amount(10))
+	require.NoError(err, "repeated operations should return the recorded operation")
+	require.EqualValues(3, op.Nonce)
+	require.Len(chain.executed, 1, "repeated operations should not be submitted again")
+	_, err = tr.Withdraw(ctx, "w1", &bob, amount(20))
+	require.ErrorIs(err, ErrConflict)
+
+	chain.loseAfter = true
+	_, err = tr.Deposit(ctx, "d1", nil, amount(500))
+	require.Error(err, "lost connections should be reported")
+	chain.loseBefore = true
+	_, err = tr.Withdraw(ctx, "w2", nil, amount(30))
+	require.Error(err, "lost connections should be reported")
+	require.Len(chain.executed, 2)
+
+	// Restart.
+	chain.round++
+	tr, err = NewTracker(chain, sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec, path)
+	require.NoError(err, "NewTracker")
+	require.Len(tr.Incomplete(), 3)
+
+	incomplete, err := tr.Resume(ctx)
+	require.NoError(err, "Resume")
+	require.Len(incomplete, 1, "the resubmitted withdrawal should await its event")
+	require.Equal("w2", incomplete[0].ID)
+	require.EqualValues(5, incomplete[0].Nonce)
+	require.Equal([]string{"consensus.Withdraw", "consensus.Deposit", "consensus.Withdraw"}, chain.executed,
+		"only operations that didn't execute should be submitted again")
+
+	op, ok := tr.Operation("w1")
+	require.True(ok)
+	require.Equal(StatusCompleted, op.Status)
+	op, ok = tr.Operation("d1")
+	require.True(ok)
+	require.Equal(StatusFailed, op.Status, "consensus errors should fail the operation")
+
+	chain.round++
+	incomplete, err = tr.Resume(ctx)
+	require.NoError(err, "Resume")
+	require.Empty(incomplete)
+	op, _ = tr.Operation("w2")
+	require.Equal(StatusCompleted, op.Status)
+	require.Len(chain.executed, 3)
+}
@@ -0,0 +1,315 @@
+// Package broker shares a single event subscription to a node among many in-process consumers.
+//
+// Services with dozens of watchers would otherwise open one block subscription (and fetch the
+// events of every block once) per watcher. A Broker instead maintains a single subscription and
+// fans the events out to its subscribers, each with its own cursor and buffer.
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+var (
+	// ErrSlowConsumer is the error a subscription is closed with when it falls too far behind
+	// and the broker is configured with the Disconnect policy.
+	ErrSlowConsumer = errors.New("broker: subscriber too slow")
+	// ErrStopped is the error subscriptions are closed with when the broker stops.
+	ErrStopped = errors.New("broker: stopped")
+)
+
+// Policy is the policy applied to subscribers whose buffer is full.
+type Policy int
+
+const (
+	// Block makes the broker wait for slow subscribers, slowing down all other subscribers and
+	// the node subscription.
+	Block Policy = iota
+	// Disconnect closes the subscriptions of slow subscribers with ErrSlowConsumer.
+	Disconnect
+)
+
+// defaultBufferSize is the default number of blocks buffered per subscriber.
+const defaultBufferSize = 16
+
+// Option is a broker option.
+type Option func(*options)
+
+type options struct {
+	bufferSize       int
+	policy           Policy
+	includeUndecoded bool
+}
+
+// WithBufferSize configures the number of blocks buffered per subscriber.
+func WithBufferSize(size int) Option {
+	return func(o *options) {
+		if size < 1 {
+			size = 1
+		}
+		o.bufferSize = size
+	}
+}
+
+// WithPolicy configures the policy applied to subscribers whose buffer is full (Block by
+// default).
+func WithPolicy(policy Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithUndecodedEvents makes the broker also deliver events none of the decoders recognized.
+func WithUndecodedEvents() Option {
+	return func(o *options) {
+		o.includeUndecoded = true
+	}
+}
+
+// Broker maintains a single event subscription and fans the events out to its subscribers.
+type Broker struct {
+	rc       client.RuntimeClient
+	decoders []client.EventDecoder
+	opts     options
+
+	l    sync.Mutex
+	subs map[*Subscription]struct{}
+	err  error
+}
+
+// New creates a new broker decoding events using the given decoders. Call Run to start it.
+func New(rc client.RuntimeClient, decoders []client.EventDecoder, opts ...Option) *Broker {
+	o := options{bufferSize: defaultBufferSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Broker{
+		rc:       rc,
+		decoders: decoders,
+		opts:     o,
+		subs:     make(map[*Subscription]struct{}),
+	}
+}
+
+// Run subscribes to the events of new blocks and fans them out until the context is cancelled or
+// the node subscription ends. All subscriptions are closed when Run returns.
+//
+// To survive node restarts, use a runtime client with watch resumption enabled (see
+// client.WithWatchResumption).
+func (b *Broker) Run(ctx context.Context) error {
+	ch, err := b.rc.WatchEvents(ctx, b.decoders, b.opts.includeUndecoded)
+	if err != nil {
+		b.stop(err)
+		return fmt.Errorf("broker: failed to subscribe to events: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.stop(ErrStopped)
+			return ctx.Err()
+		case blk, ok := <-ch:
+			if !ok {
+				b.stop(ErrStopped)
+				return ErrStopped
+			}
+			b.publish(ctx, blk)
+		}
+	}
+}
+
+// Subscribe creates a new subscription receiving the events of all rounds starting at the given
+// round. Rounds preceding the first block received by the broker are fetched from the node. In
+// case from is client.RoundLatest, the subscription only receives new blocks.
+//
+// The subscription must be closed once no longer needed.
+func (b *Broker) Subscribe(from uint64) (*Subscription, error) {
+	sub := &Subscription{
+		b:     b,
+		queue: make(chan *client.BlockEvents, b.opts.bufferSize),
+		ch:    make(chan *client.BlockEvents),
+		done:  make(chan struct{}),
+	}
+	if from != client.RoundLatest {
+		sub.next, sub.started = from, true
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	b.subs[sub] = struct{}{}
+	go sub.pump()
+	return sub, nil
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Broker) Subscribers() int {
+	b.l.Lock()
+	defer b.l.Unlock()
+	return len(b.subs)
+}
+
+// publish delivers the given block to all subscribers, applying the configured policy to slow
+// subscribers.
+func (b *Broker) publish(ctx context.Context, blk *client.BlockEvents) {
+	b.l.Lock()
+	subs := make([]*Subscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.l.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.queue <- blk:
+			continue
+		case <-sub.done:
+			continue
+		default:
+		}
+
+		switch b.opts.policy {
+		case Disconnect:
+			sub.fail(ErrSlowConsumer)
+		default:
+			select {
+			case sub.queue <- blk:
+			case <-sub.done:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// stop closes all subscriptions with the given error and rejects new ones.
+func (b *Broker) stop(err error) {
+	b.l.Lock()
+	b.err = err
+	subs := b.subs
+	b.subs = make(map[*Subscription]struct{})
+	b.l.Unlock()
+
+	for sub := range subs {
+		sub.fail(err)
+	}
+}
+
+// remove removes the given subscription.
+func (b *Broker) remove(sub *Subscription) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	delete(b.subs, sub)
+}
+
+// Subscription is a subscription to the events of a broker.
+type Subscription struct {
+	b *Broker
+
+	queue chan *client.BlockEvents
+	ch    chan *client.BlockEvents
+	done  chan struct{}
+
+	// next is the next round to deliver.
+	next uint64
+	// started is true once next is known, i.e. after the first block in case the subscription
+	// only receives new blocks.
+	started bool
+
+	closeOnce sync.Once
+	err       error
+}
+
+// Events returns the channel the events of each block are delivered on in order of rounds. The
+// channel is closed once the subscription is closed.
+func (s *Subscription) Events() <-chan *client.BlockEvents {
+	return s.ch
+}
+
+// Err returns the reason the subscription was closed, e.g. ErrSlowConsumer. It returns nil in
+// case the subscription was closed via Close or is still active.
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close closes the subscription.
+func (s *Subscription) Close() {
+	s.fail(nil)
+}
+
+func (s *Subscription) fail(err error) {
+	s.closeOnce.Do(func() {
+		s.err = err
+		close(s.done)
+		s.b.remove(s)
+	})
+}
+
+// pump delivers the queued blocks to the subscriber, first fetching the events of any rounds
+// between the cursor and the queued block.
+func (s *Subscription) pump() {
+	defer close(s.ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		var blk *client.BlockEvents
+		select {
+		case <-s.done:
+			return
+		case blk = <-s.queue:
+		}
+
+		if !s.started {
+			s.next, s.started = blk.Round, true
+		}
+		for ; s.next < blk.Round; s.next++ {
+			events, err := s.b.rc.GetEvents(ctx, s.next, s.b.decoders, s.b.opts.includeUndecoded)
+			if err != nil {
+				s.fail(fmt.Errorf("broker: failed to fetch events of round %d: %w", s.next, err))
+				return
+			}
+			if !s.deliver(&client.BlockEvents{Round: s.next, Events: events}) {
+				return
+			}
+		}
+		if blk.Round < s.next {
+			// Already delivered.
+			continue
+		}
+		if !s.deliver(blk) {
+			return
+		}
+		s.next = blk.Round + 1
+	}
+}
+
+// deliver delivers the given block to the subscriber, returning false in case the subscription
+// was closed.
+func (s *Subscription) deliver(blk *client.BlockEvents) bool {
+	select {
+	case s.ch <- blk:
+		return true
+	case <-s.done:
+		return false
+	}
+}
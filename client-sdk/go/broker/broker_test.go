@@ -0,0 +1,105 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+type streamRuntimeClient struct {
+	client.RuntimeClient
+
+	ch         chan *client.BlockEvents
+	watches    int
+	backfilled []uint64
+}
+
+func (s *streamRuntimeClient) WatchEvents(ctx context.Context, decoders []client.EventDecoder, includeUndecoded bool) (<-chan *client.BlockEvents, error) {
+	s.watches++
+	return s.ch, nil
+}
+
+func (s *streamRuntimeClient) GetEvents(ctx context.Context, round uint64, decoders []client.EventDecoder, includeUndecoded bool) ([]client.DecodedEvent, error) {
+	s.backfilled = append(s.backfilled, round)
+	return []client.DecodedEvent{round}, nil
+}
+
+func receive(t *testing.T, sub *Subscription) *client.BlockEvents {
+	select {
+	case blk := <-sub.Events():
+		return blk
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for events")
+		return nil
+	}
+}
+
+func TestBroker(t *testing.T) {
+	require := require.New(t)
+
+	rc := &streamRuntimeClient{ch: make(chan *client.BlockEvents)}
+	b := New(rc, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	live, err := b.Subscribe(client.RoundLatest)
+	require.NoError(err)
+	historical, err := b.Subscribe(8)
+	require.NoError(err)
+	require.Equal(2, b.Subscribers())
+
+	done := make(chan error)
+	go func() { done <- b.Run(ctx) }()
+
+	rc.ch <- &client.BlockEvents{Round: 10}
+	require.EqualValues(10, receive(t, live).Round)
+	for round := uint64(8); round <= 10; round++ {
+		require.EqualValues(round, receive(t, historical).Round, "rounds before the first block should be backfilled")
+	}
+	require.Equal([]uint64{8, 9}, rc.backfilled)
+
+	rc.ch <- &client.BlockEvents{Round: 11}
+	require.EqualValues(11, receive(t, live).Round)
+	require.EqualValues(11, receive(t, historical).Round)
+	require.Equal(1, rc.watches, "subscribers should share a single node subscription")
+
+	historical.Close()
+	_, ok := <-historical.Events()
+	require.False(ok, "closed subscriptions should close their channel")
+	require.NoError(historical.Err())
+
+	cancel()
+	require.ErrorIs(<-done, context.Canceled)
+	_, ok = <-live.Events()
+	require.False(ok)
+	require.ErrorIs(live.Err(), ErrStopped)
+	_, err = b.Subscribe(client.RoundLatest)
+	require.ErrorIs(err, ErrStopped)
+}
+
+func TestBrokerSlowConsumer(t *testing.T) {
+	require := require.New(t)
+
+	rc := &streamRuntimeClient{ch: make(chan *client.BlockEvents)}
+	b := New(rc, nil, WithBufferSize(1), WithPolicy(Disconnect))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow, err := b.Subscribe(client.RoundLatest)
+	require.NoError(err)
+	fast, err := b.Subscribe(client.RoundLatest)
+	require.NoError(err)
+	go func() { _ = b.Run(ctx) }()
+
+	// The slow subscriber never receives, so one block is held by its pump and one is buffered.
+	for round := uint64(1); round <= 3; round++ {
+		rc.ch <- &client.BlockEvents{Round: round}
+		require.EqualValues(round, receive(t, fast).Round)
+	}
+	require.ErrorIs(slow.Err(), ErrSlowConsumer)
+	require.Equal(1, b.Subscribers())
+}
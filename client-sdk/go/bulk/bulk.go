@@ -0,0 +1,341 @@
+// Package bulk implements executing large numbers of transfers from a single account, e.g. for
+// airdrops and payroll jobs.
+//
+// The runtime has no batch transfer method, so every entry is sent as its own accounts.Transfer
+// transaction. Entries are processed sequentially in chunks, throttled to the configured number
+// of transactions per second. After each chunk the progress is reported and a checkpoint is
+// stored, so that an interrupted job can be resumed without paying anyone twice.
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// DefaultChunkSize is the default number of entries processed between checkpoints.
+const DefaultChunkSize = 100
+
+// Entry is a single transfer of a bulk job.
+type Entry struct {
+	// To is the recipient of the transfer.
+	To types.Address `json:"to"`
+	// Amount is the transferred amount.
+	Amount types.BaseUnits `json:"amount"`
+}
+
+// Failure is a transfer that failed.
+type Failure struct {
+	// Index is the index of the failed entry.
+	Index int `json:"index"`
+	// Error is the reason the transfer failed.
+	Error string `json:"error"`
+}
+
+// Checkpoint is the state of a bulk job.
+type Checkpoint struct {
+	// Next is the index of the next entry to process.
+	Next int `json:"next"`
+	// Nonce is the nonce the next transfer is going to be sent with.
+	Nonce uint64 `json:"nonce"`
+	// Failures are the transfers that failed so far.
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// Save atomically stores the checkpoint in the given file.
+func (cp *Checkpoint) Save(path string) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("bulk: failed to marshal checkpoint: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("bulk: failed to write checkpoint: %w", err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("bulk: failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint loads a checkpoint previously stored using Save.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bulk: failed to read checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("bulk: malformed checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Progress is the progress of a bulk job.
+type Progress struct {
+	// Done is the number of processed entries, including failed ones.
+	Done int
+	// Failed is the number of failed entries.
+	Failed int
+	// Total is the total number of entries.
+	Total int
+	// Round is the round the last transfer was executed in.
+	Round uint64
+}
+
+type options struct {
+	tps            float64
+	chunkSize      int
+	fee            *types.Fee
+	checkpointPath string
+	onProgress     func(*Progress)
+}
+
+// Option is an option for configuring the bulk executor.
+type Option func(*options)
+
+// WithTPS limits the number of transactions submitted per second. By default transactions are
+// submitted as fast as they are executed.
+func WithTPS(tps float64) Option {
+	return func(opts *options) {
+		opts.tps = tps
+	}
+}
+
+// WithChunkSize sets the number of entries processed between checkpoints and progress reports.
+func WithChunkSize(size int) Option {
+	return func(opts *options) {
+		opts.chunkSize = size
+	}
+}
+
+// WithFee sets the fee paid by each transfer.
+func WithFee(fee *types.Fee) Option {
+	return func(opts *options) {
+		opts.fee = fee
+	}
+}
+
+// WithCheckpointPath makes the executor store checkpoints in the given file.
+func WithCheckpointPath(path string) Option {
+	return func(opts *options) {
+		opts.checkpointPath = path
+	}
+}
+
+// WithProgress sets the function called with the progress after each chunk.
+func WithProgress(fn func(*Progress)) Option {
+	return func(opts *options) {
+		opts.onProgress = fn
+	}
+}
+
+// Executor executes bulk transfers.
+type Executor struct {
+	ac     accounts.V1
+	signer signature.Signer
+	spec   types.SignatureAddressSpec
+	opts   options
+}
+
+// NewExecutor creates a new executor sending transfers signed by the given signer.
+//
+// The executor manages the nonces of the signer's account, so the account must not be used for
+// anything else while a job is running.
+func NewExecutor(rc client.RuntimeClient, signer signature.Signer, spec types.SignatureAddressSpec, opts ...Option) *Executor {
+	e := &Executor{
+		ac:     accounts.NewV1(rc),
+		signer: signer,
+		spec:   spec,
+		opts: options{
+			chunkSize: DefaultChunkSize,
+		},
+	}
+	for _, opt := range opts {
+		opt(&e.opts)
+	}
+	return e
+}
+
+// Run executes the given entries, starting at the given checkpoint (nil to start from the
+// beginning). It returns the final checkpoint, which is also returned together with any error so
+// that the job can be resumed later.
+//
+// Transfers that fail are recorded in the checkpoint and do not stop the job. Errors that leave
+// the outcome of a transfer unknown (e.g. a lost connection) stop the job; when resuming, the
+// signer's nonce is used to determine whether the transfer was executed after all.
+func (e *Executor) Run(ctx context.Context, entries []Entry, cp *Checkpoint) (*Checkpoint, error) {
+	nonce, err := e.ac.Nonce(ctx, client.RoundLatest, types.NewAddress(e.spec))
+	if err != nil {
+		return cp, fmt.Errorf("bulk: failed to query nonce: %w", err)
+	}
+	if cp == nil {
+		cp = &Checkpoint{Nonce: nonce}
+	} else {
+		cp = cp.clone()
+		if err = cp.resync(nonce); err != nil {
+			return cp, err
+		}
+	}
+
+	th := newThrottle(e.opts.tps)
+	var round uint64
+	for cp.Next < len(entries) {
+		end := cp.Next + e.opts.chunkSize
+		if end > len(entries) || e.opts.chunkSize <= 0 {
+			end = len(entries)
+		}
+		for cp.Next < end {
+			if err = th.wait(ctx); err != nil {
+				return cp, e.finishChunk(cp, len(entries), round, err)
+			}
+			var out *outcome
+			if out, err = e.transfer(ctx, &entries[cp.Next], cp.Nonce); err != nil {
+				return cp, e.finishChunk(cp, len(entries), round, err)
+			}
+			if out.meta != nil {
+				round = out.meta.Round
+			}
+			if out.consumed {
+				cp.Nonce++
+			}
+			if out.failure != nil {
+				cp.Failures = append(cp.Failures, Failure{Index: cp.Next, Error: out.failure.Error()})
+			}
+			cp.Next++
+			if out.failure != nil && !out.consumed {
+				// The nonce was not consumed, so store the failure right away to keep the
+				// mapping between nonces and entries used when resuming intact.
+				if err = e.save(cp); err != nil {
+					return cp, err
+				}
+			}
+		}
+		if err = e.finishChunk(cp, len(entries), round, nil); err != nil {
+			return cp, err
+		}
+	}
+	return cp, nil
+}
+
+// outcome is the outcome of a single transfer.
+type outcome struct {
+	meta     *client.TransactionMeta
+	consumed bool
+	failure  error
+}
+
+// transfer sends a single transfer with the given nonce. An error is only returned in case the
+// outcome of the transfer is unknown.
+func (e *Executor) transfer(ctx context.Context, entry *Entry, nonce uint64) (*outcome, error) {
+	tb := e.ac.Transfer(entry.To, entry.Amount)
+	if e.opts.fee != nil {
+		tb.SetFeeAmount(e.opts.fee.Amount)
+		tb.SetFeeGas(e.opts.fee.Gas)
+	}
+	tb.AppendAuthSignature(e.spec, nonce)
+	if err := tb.AppendSign(ctx, e.signer); err != nil {
+		return nil, fmt.Errorf("bulk: failed to sign transfer: %w", err)
+	}
+
+	meta, err := tb.SubmitTxMeta(ctx, nil)
+	switch {
+	case meta == nil:
+		return nil, fmt.Errorf("bulk: failed to submit transfer: %w", err)
+	case meta.CheckTxError != nil:
+		checkErr := types.FailedCallResult{
+			Module:  meta.CheckTxError.Module,
+			Code:    meta.CheckTxError.Code,
+			Message: meta.CheckTxError.Message,
+		}
+		if client.IsTransient(checkErr) {
+			// E.g. an invalid nonce caused by a concurrent use of the account.
+			return nil, fmt.Errorf("bulk: transfer check failed: %w", client.Classify(checkErr))
+		}
+		return &outcome{meta: meta, failure: checkErr}, nil
+	default:
+		// Executed transactions consume the nonce even if they failed.
+		return &outcome{meta: meta, consumed: true, failure: err}, nil
+	}
+}
+
+// finishChunk stores the checkpoint and reports the progress. The given error is returned in
+// case it is non-nil.
+func (e *Executor) finishChunk(cp *Checkpoint, total int, round uint64, err error) error {
+	if saveErr := e.save(cp); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	if e.opts.onProgress != nil {
+		e.opts.onProgress(&Progress{
+			Done:   cp.Next,
+			Failed: len(cp.Failures),
+			Total:  total,
+			Round:  round,
+		})
+	}
+	return err
+}
+
+// save stores the checkpoint in case a checkpoint path is configured.
+func (e *Executor) save(cp *Checkpoint) error {
+	if e.opts.checkpointPath == "" {
+		return nil
+	}
+	return cp.Save(e.opts.checkpointPath)
+}
+
+// clone returns a copy of the checkpoint.
+func (cp *Checkpoint) clone() *Checkpoint {
+	c := *cp
+	c.Failures = append([]Failure(nil), cp.Failures...)
+	return &c
+}
+
+// resync brings the checkpoint in line with the current nonce of the account. Transfers executed
+// after the checkpoint was stored have consumed nonces, so their entries are skipped.
+func (cp *Checkpoint) resync(nonce uint64) error {
+	if nonce < cp.Nonce {
+		return fmt.Errorf("bulk: account nonce %d is behind checkpoint nonce %d", nonce, cp.Nonce)
+	}
+	cp.Next += int(nonce - cp.Nonce)
+	cp.Nonce = nonce
+	return nil
+}
+
+// throttle limits the rate of transactions.
+type throttle struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newThrottle(tps float64) *throttle {
+	if tps <= 0 {
+		return &throttle{}
+	}
+	return &throttle{interval: time.Duration(float64(time.Second) / tps)}
+}
+
+// wait waits until the next transaction may be submitted.
+func (t *throttle) wait(ctx context.Context) error {
+	if t.interval == 0 {
+		return ctx.Err()
+	}
+	now := time.Now()
+	if delay := t.next.Sub(now); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		now = t.next
+	}
+	t.next = now.Add(t.interval)
+	return nil
+}
@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// fakeChain executes transfers, failing those to Bob and rejecting those to Dave in the check.
+type fakeChain struct {
+	client.RuntimeClient
+
+	nonce     uint64
+	paid      []types.Address
+	lostAfter int
+}
+
+func (c *fakeChain) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: signature.Context("test chain")}, nil
+}
+
+func (c *fakeChain) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*uint64) = c.nonce
+	return nil
+}
+
+func (c *fakeChain) SubmitTxRawMeta(ctx context.Context, utx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	tx, err := utx.Verify(signature.Context("test chain"))
+	if err != nil {
+		return nil, err
+	}
+	var body accounts.Transfer
+	if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+		return nil, err
+	}
+
+	var meta client.SubmitTxRawMeta
+	switch {
+	case tx.AuthInfo.SignerInfo[0].Nonce != c.nonce:
+		meta.CheckTxError = &client.CheckTxError{Module: "core", Code: 4, Message: "invalid nonce"}
+		return &meta, nil
+	case body.To.Equal(sdkTesting.Dave.Address):
+		meta.CheckTxError = &client.CheckTxError{Module: "core", Code: 5, Message: "insufficient balance"}
+		return &meta, nil
+	}
+
+	c.nonce++
+	meta.Round = c.nonce
+	if body.To.Equal(sdkTesting.Bob.Address) {
+		meta.Result.Failed = &types.FailedCallResult{Module: "accounts", Code: 2, Message: "forbidden"}
+	} else {
+		c.paid = append(c.paid, body.To)
+		meta.Result.Ok = cbor.Marshal(nil)
+	}
+	if c.lostAfter > 0 && len(c.paid) == c.lostAfter {
+		c.lostAfter = 0
+		return nil, errors.New("connection lost")
+	}
+	return &meta, nil
+}
+
+func TestExecutor(t *testing.T) {
+	require := require.New(t)
+
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination)
+	recipients := []types.Address{
+		sdkTesting.Charlie.Address,
+		sdkTesting.Bob.Address,
+		sdkTesting.Dave.Address,
+		sdkTesting.Charlie.Address,
+		sdkTesting.Frank.Address,
+	}
+	var entries []Entry
+	for _, to := range recipients {
+		entries = append(entries, Entry{To: to, Amount: amount})
+	}
+
+	chain := &fakeChain{nonce: 7, lostAfter: 2}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	var progress []Progress
+	e := NewExecutor(chain, sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec,
+		WithChunkSize(2),
+		WithTPS(1000),
+		WithCheckpointPath(path),
+		WithProgress(func(p *Progress) { progress = append(progress, *p) }),
+	)
+
+	cp, err := e.Run(context.Background(), entries, nil)
+	require.Error(err, "lost connections should stop the job")
+	require.Equal(3, cp.Next, "the entry with an unknown outcome should not be marked as done")
+	require.Len(chain.paid, 2)
+
+	stored, err := LoadCheckpoint(path)
+	require.NoError(err, "LoadCheckpoint")
+	require.Equal(cp, stored)
+	require.Len(stored.Failures, 2)
+	require.Equal(1, stored.Failures[0].Index, "executed failures should be recorded")
+	require.Equal(2, stored.Failures[1].Index, "check failures should be recorded")
+
+	cp, err = e.Run(context.Background(), entries, stored)
+	require.NoError(err, "resuming should succeed")
+	require.Equal(len(entries), cp.Next)
+	require.Equal(chain.nonce, cp.Nonce)
+	require.Equal([]types.Address{
+		sdkTesting.Charlie.Address,
+		sdkTesting.Charlie.Address,
+		sdkTesting.Frank.Address,
+	}, chain.paid, "transfers executed before the interruption should not be repeated")
+
+	last := progress[len(progress)-1]
+	require.Equal(Progress{Done: 5, Failed: 2, Total: 5, Round: chain.nonce}, last)
+
+	chain.nonce = 0
+	_, err = e.Run(context.Background(), entries, cp)
+	require.Error(err, "checkpoints ahead of the account should be rejected")
+}
@@ -3,16 +3,21 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
@@ -67,6 +72,11 @@ type RuntimeClient interface {
 	// GetLastRetainedBlock returns the last retained block.
 	GetLastRetainedBlock(ctx context.Context) (*block.Block, error)
 
+	// CheckRetained checks whether state at the given round is still available, either on the
+	// node or on the configured archive nodes (see WithArchiveNodes). Otherwise it fails with a
+	// *PrunedError. Use it as a preflight before scanning historical rounds.
+	CheckRetained(ctx context.Context, round uint64) error
+
 	// GetTransactions returns all transactions that are part of a given block.
 	GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error)
 
@@ -80,6 +90,26 @@ type RuntimeClient interface {
 	// GetEvents returns and decodes events emitted in a given block with the provided decoders.
 	GetEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool) ([]DecodedEvent, error)
 
+	// GetEventsForModule returns the events emitted by the given module in a given block, decoded
+	// with the module's registered decoder (see RegisterModule). Events emitted by other modules
+	// are skipped before decoding.
+	//
+	// The node has no support for filtering events, so the block's raw events are still fetched
+	// at once.
+	GetEventsForModule(ctx context.Context, round uint64, module string) ([]DecodedEvent, error)
+
+	// StreamEventsRaw calls fn with the events emitted in a given block in chunks of at most
+	// chunkSize events, stopping at the first error returned by fn.
+	//
+	// The node has no support for retrieving event ranges, so the block's raw events are still
+	// fetched at once, but they are only unmarshalled chunk by chunk so that blocks with many
+	// events don't need to be fully materialized before processing.
+	StreamEventsRaw(ctx context.Context, round uint64, chunkSize int, fn func([]*types.Event) error) error
+
+	// StreamEvents is like StreamEventsRaw, but decodes the events with the provided decoders.
+	// Chunks contain the events decoded from at most chunkSize raw events.
+	StreamEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool, chunkSize int, fn func([]DecodedEvent) error) error
+
 	// WatchBlocks subscribes to blocks for a specific runtimes.
 	WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
 
@@ -87,7 +117,14 @@ type RuntimeClient interface {
 	WatchEvents(ctx context.Context, decoders []EventDecoder, includeUndecoded bool) (<-chan *BlockEvents, error)
 
 	// Query makes a runtime-specific query.
+	//
+	// Use WithResultMeta on the passed context to obtain the round and timestamp the result was
+	// computed at.
 	Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error
+
+	// QueryRaw makes a runtime-specific query and returns the raw CBOR-encoded response without
+	// decoding it. This is useful for probing methods not yet wrapped by the SDK, see CheckShape.
+	QueryRaw(ctx context.Context, round uint64, method string, args interface{}) (cbor.RawMessage, error)
 }
 
 // EventDecoder is an event decoder interface.
@@ -96,9 +133,28 @@ type EventDecoder interface {
 	DecodeEvent(*types.Event) ([]DecodedEvent, error)
 }
 
+// ContextEventDecoder is an event decoder that supports cancellation, e.g. because decoding an
+// event is expensive. It is used instead of DecodeEvent whenever a context is available.
+type ContextEventDecoder interface {
+	EventDecoder
+
+	// DecodeEventContext decodes an event, aborting in case the context is cancelled.
+	DecodeEventContext(context.Context, *types.Event) ([]DecodedEvent, error)
+}
+
 // DecodedEvent is a decoded event.
 type DecodedEvent interface{}
 
+// EventMetadata returns the metadata of the given decoded event. Events decoded by the runtime
+// module decoders as well as undecoded events carry metadata.
+func EventMetadata(ev DecodedEvent) (types.EventMeta, bool) {
+	m, ok := ev.(interface{ Metadata() types.EventMeta })
+	if !ok {
+		return types.EventMeta{}, false
+	}
+	return m.Metadata(), true
+}
+
 // BlockEvents are the events emitted in a block.
 type BlockEvents struct {
 	// Round is the round of the block.
@@ -155,6 +211,11 @@ type runtimeClient struct {
 
 	runtimeID   common.Namespace
 	runtimeInfo *types.RuntimeInfo
+
+	opts       options
+	queryGroup singleflight.Group
+	rounds     roundTracker
+	methods    preferredNames
 }
 
 // Implements RuntimeClient.
@@ -165,7 +226,7 @@ func (rc *runtimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error
 
 	chainCtx, err := rc.cs.GetChainContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch consensus layer chain context: %w", err)
+		return nil, Classify(fmt.Errorf("failed to fetch consensus layer chain context: %w", err))
 	}
 
 	rc.runtimeInfo = &types.RuntimeInfo{
@@ -177,7 +238,10 @@ func (rc *runtimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTransaction) (*types.CallResult, error) {
-	raw, err := rc.cc.SubmitTx(ctx, &coreClient.SubmitTxRequest{
+	if err := rc.chargeQuota(ctx, QuotaSubmissions); err != nil {
+		return nil, err
+	}
+	raw, err := rc.runtime(ctx, PrioritySubmit).SubmitTx(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
 	})
@@ -194,7 +258,10 @@ func (rc *runtimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTr
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxRawMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*SubmitTxRawMeta, error) {
-	meta, err := rc.cc.SubmitTxMeta(ctx, &coreClient.SubmitTxRequest{
+	if err := rc.chargeQuota(ctx, QuotaSubmissions); err != nil {
+		return nil, err
+	}
+	meta, err := rc.runtime(ctx, PrioritySubmit).SubmitTxMeta(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
 	})
@@ -236,11 +303,11 @@ func (rc *runtimeClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTrans
 	}
 	switch {
 	case result.IsUnknown():
-		return nil, fmt.Errorf("got unknown result, use SubmitTxRaw to retrieve")
+		return nil, Classify(fmt.Errorf("got unknown result, use SubmitTxRaw to retrieve"))
 	case result.IsSuccess():
 		return result.Ok, nil
 	default:
-		return nil, result.Failed
+		return nil, Classify(result.Failed)
 	}
 }
 
@@ -258,20 +325,23 @@ func (rc *runtimeClient) SubmitTxMeta(ctx context.Context, tx *types.UnverifiedT
 
 	switch {
 	case meta.Result.IsUnknown():
-		return nil, fmt.Errorf("got unknown result, use SubmitTxRawMeta to retrieve")
+		return nil, Classify(fmt.Errorf("got unknown result, use SubmitTxRawMeta to retrieve"))
 	case meta.Result.IsSuccess():
 		return &SubmitTxMeta{
 			Result:          meta.Result.Ok,
 			TransactionMeta: meta.TransactionMeta,
 		}, nil
 	default:
-		return &SubmitTxMeta{TransactionMeta: meta.TransactionMeta}, meta.Result.Failed
+		return &SubmitTxMeta{TransactionMeta: meta.TransactionMeta}, Classify(meta.Result.Failed)
 	}
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
-	return rc.cc.SubmitTxNoWait(ctx, &coreClient.SubmitTxRequest{
+	if err := rc.chargeQuota(ctx, QuotaSubmissions); err != nil {
+		return err
+	}
+	return rc.runtime(ctx, PrioritySubmit).SubmitTxNoWait(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
 	})
@@ -279,17 +349,21 @@ func (rc *runtimeClient) SubmitTxNoWait(ctx context.Context, tx *types.Unverifie
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
-	return rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	return rc.runtime(ctx, PriorityInteractive).WatchBlocks(ctx, rc.runtimeID)
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
-	return rc.cc.GetGenesisBlock(ctx, rc.runtimeID)
+	return rc.runtime(ctx, PriorityInteractive).GetGenesisBlock(ctx, rc.runtimeID)
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
-	return rc.cc.GetBlock(ctx, &coreClient.GetBlockRequest{
+	ctx, round, err := rc.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	return rc.runtime(ctx, PriorityInteractive).GetBlock(ctx, &coreClient.GetBlockRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
 	})
@@ -297,12 +371,44 @@ func (rc *runtimeClient) GetBlock(ctx context.Context, round uint64) (*block.Blo
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetLastRetainedBlock(ctx context.Context) (*block.Block, error) {
-	return rc.cc.GetLastRetainedBlock(ctx, rc.runtimeID)
+	return rc.runtime(ctx, PriorityInteractive).GetLastRetainedBlock(ctx, rc.runtimeID)
+}
+
+// Implements RuntimeClient.
+func (rc *runtimeClient) CheckRetained(ctx context.Context, round uint64) error {
+	if round == RoundLatest {
+		return nil
+	}
+	blk, err := rc.GetLastRetainedBlock(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch last retained block: %w", err)
+	}
+	if round >= blk.Header.Round {
+		return nil
+	}
+	if rc.opts.archive != nil {
+		archive := &wrappedClient{RuntimeClient: rc.opts.archive.client(), timeouts: rc.opts.timeouts}
+		ablk, err := archive.GetLastRetainedBlock(ctx, rc.runtimeID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch last retained block from archive node: %w", err)
+		}
+		if round >= ablk.Header.Round {
+			return nil
+		}
+	}
+	return &classifiedError{
+		class: ErrNotFound,
+		err:   &PrunedError{Round: round, LastRetainedRound: blk.Header.Round},
+	}
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
-	rawTxs, err := rc.cc.GetTransactions(ctx, &coreClient.GetTransactionsRequest{
+	ctx, round, err := rc.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	rawTxs, err := rc.runtime(ctx, PriorityInteractive).GetTransactions(ctx, &coreClient.GetTransactionsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
 	})
@@ -321,7 +427,11 @@ func (rc *runtimeClient) GetTransactions(ctx context.Context, round uint64) ([]*
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*TransactionWithResults, error) {
-	rawTxs, err := rc.cc.GetTransactionsWithResults(ctx, &coreClient.GetTransactionsRequest{
+	ctx, round, err := rc.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	rawTxs, err := rc.runtime(ctx, PriorityInteractive).GetTransactionsWithResults(ctx, &coreClient.GetTransactionsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
 	})
@@ -335,11 +445,14 @@ func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round u
 		_ = cbor.Unmarshal(raw.Tx, &tx.Tx) // Ignore errors as there can be invalid transactions.
 		_ = cbor.Unmarshal(raw.Result, &tx.Result)
 
+		txHash := hash.NewFromBytes(raw.Tx)
+		txIndex := uint32(i)
 		for _, rawEv := range raw.Events {
 			var ev types.Event
-			if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value, nil); err != nil {
+			if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value, &txHash); err != nil {
 				continue
 			}
+			ev.TxIndex = &txIndex
 
 			tx.Events = append(tx.Events, &ev)
 		}
@@ -351,66 +464,194 @@ func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round u
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
-	rawEvs, err := rc.cc.GetEvents(ctx, &coreClient.GetEventsRequest{
-		RuntimeID: rc.runtimeID,
-		Round:     round,
+	var evs []*types.Event
+	err := rc.StreamEventsRaw(ctx, round, 0, func(chunk []*types.Event) error {
+		evs = chunk
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	if evs == nil {
+		evs = []*types.Event{}
+	}
+	return evs, nil
+}
 
-	evs := make([]*types.Event, len(rawEvs))
-	for i, rawEv := range rawEvs {
-		var ev types.Event
-		if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value, &rawEv.TxHash); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal event '%v': %w", rawEv, err)
-		}
-		evs[i] = &ev
+// Implements RuntimeClient.
+func (rc *runtimeClient) GetEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool) ([]DecodedEvent, error) {
+	evs := make([]DecodedEvent, 0)
+	err := rc.StreamEvents(ctx, round, decoders, includeUndecoded, 0, func(chunk []DecodedEvent) error {
+		evs = chunk
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// Implements RuntimeClient.
+func (rc *runtimeClient) GetEventsForModule(ctx context.Context, round uint64, module string) ([]DecodedEvent, error) {
+	desc, ok := LookupModule(module)
+	if !ok || desc.Events == nil {
+		return nil, fmt.Errorf("no event decoder registered for module '%s'", module)
 	}
 
+	evs := make([]DecodedEvent, 0)
+	err := rc.StreamEventsRaw(ctx, round, 0, func(chunk []*types.Event) error {
+		for _, ev := range chunk {
+			// Modules may emit events under sub-modules, e.g. "contracts.<code>".
+			if ev.Module != module && !strings.HasPrefix(ev.Module, module+".") {
+				continue
+			}
+			decoded, err := decodeEvent(ctx, desc.Events, ev)
+			if err != nil {
+				return fmt.Errorf("failed to decode event: %w", err)
+			}
+			evs = append(evs, decoded...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return evs, nil
 }
 
 // Implements RuntimeClient.
-func (rc *runtimeClient) GetEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool) ([]DecodedEvent, error) {
-	rawEvs, err := rc.cc.GetEvents(ctx, &coreClient.GetEventsRequest{
+func (rc *runtimeClient) StreamEventsRaw(ctx context.Context, round uint64, chunkSize int, fn func([]*types.Event) error) error {
+	ctx, round, err := rc.resolveRound(ctx, round)
+	if err != nil {
+		return err
+	}
+	rawEvs, err := rc.runtime(ctx, PriorityInteractive).GetEvents(ctx, &coreClient.GetEventsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
 	})
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if limit := rc.opts.limiter.Limits().MaxEventsPerBlock; limit > 0 && uint64(len(rawEvs)) > limit {
+		rc.opts.limiter.ReportTruncation(&TruncationNotice{
+			Kind:   LimitEventsPerBlock,
+			Limit:  limit,
+			Total:  uint64(len(rawEvs)),
+			Round:  round,
+			Source: "GetEvents",
+		})
+		rawEvs = rawEvs[:limit]
+	}
+	var txIndices map[hash.Hash]uint32
+	if rc.opts.txIndices {
+		if txIndices, err = rc.txIndices(ctx, round, rawEvs); err != nil {
+			return err
+		}
+	}
+	if chunkSize <= 0 || chunkSize > len(rawEvs) {
+		chunkSize = len(rawEvs)
 	}
 
-	evs := make([]DecodedEvent, 0)
-OUTER:
-	for _, rawEv := range rawEvs {
-		var ev types.Event
-		if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value, &rawEv.TxHash); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal event '%v': %w", rawEv, err)
+	for start := 0; start < len(rawEvs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(rawEvs) {
+			end = len(rawEvs)
 		}
-		for _, decoder := range decoders {
-			decoded, err := decoder.DecodeEvent(&ev)
-			if err != nil {
-				return nil, fmt.Errorf("failed to decode event: %w", err)
+		evs := make([]*types.Event, 0, end-start)
+		for i := start; i < end; i++ {
+			// Stop mid-block in case the request was cancelled.
+			if err := ctx.Err(); err != nil {
+				return err
 			}
-			if decoded != nil {
-				evs = append(evs, decoded...)
-				continue OUTER
+			rawEv := rawEvs[i]
+			var ev types.Event
+			if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value, &rawEv.TxHash); err != nil {
+				return fmt.Errorf("failed to unmarshal event %d %s: %w", i, types.RedactPayload(rawEv.Value), err)
+			}
+			ev.Round = round
+			ev.Index = uint32(i)
+			if idx, ok := txIndices[rawEv.TxHash]; ok {
+				ev.TxIndex = &idx
 			}
-		}
-		if includeUndecoded {
 			evs = append(evs, &ev)
+			// Allow processed raw events to be garbage collected.
+			rawEvs[i] = nil
+		}
+		if err := fn(evs); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return evs, nil
+// txIndices returns the indices of the transactions of the given round by transaction hash. The
+// transactions are only fetched in case any of the given events was emitted by a transaction.
+func (rc *runtimeClient) txIndices(ctx context.Context, round uint64, rawEvs []*coreClient.Event) (map[hash.Hash]uint32, error) {
+	var needed bool
+	for _, rawEv := range rawEvs {
+		if !rawEv.TxHash.Equal(&transaction.TagBlockTxHash) {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil, nil
+	}
+
+	rawTxs, err := rc.runtime(ctx, PriorityInteractive).GetTransactions(ctx, &coreClient.GetTransactionsRequest{
+		RuntimeID: rc.runtimeID,
+		Round:     round,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	indices := make(map[hash.Hash]uint32, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		indices[hash.NewFromBytes(rawTx)] = uint32(i)
+	}
+	return indices, nil
+}
+
+// Implements RuntimeClient.
+func (rc *runtimeClient) StreamEvents(
+	ctx context.Context,
+	round uint64,
+	decoders []EventDecoder,
+	includeUndecoded bool,
+	chunkSize int,
+	fn func([]DecodedEvent) error,
+) error {
+	return rc.StreamEventsRaw(ctx, round, chunkSize, func(chunk []*types.Event) error {
+		evs := make([]DecodedEvent, 0, len(chunk))
+	OUTER:
+		for _, ev := range chunk {
+			for _, decoder := range decoders {
+				decoded, err := decodeEvent(ctx, decoder, ev)
+				if err != nil {
+					return fmt.Errorf("failed to decode event: %w", err)
+				}
+				if decoded != nil {
+					evs = append(evs, decoded...)
+					continue OUTER
+				}
+			}
+			if includeUndecoded {
+				evs = append(evs, ev)
+			}
+		}
+		return fn(evs)
+	})
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) WatchEvents(ctx context.Context, decoders []EventDecoder, includeUndecoded bool) (<-chan *BlockEvents, error) {
+	if rc.opts.watchMaxBackoff > 0 {
+		return rc.watchEventsResumable(ctx, decoders, includeUndecoded), nil
+	}
+
 	ch := make(chan *BlockEvents)
 
-	blkCh, blkSub, err := rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	blkCh, blkSub, err := rc.runtime(ctx, PriorityInteractive).WatchBlocks(ctx, rc.runtimeID)
 	if err != nil {
 		return nil, err
 	}
@@ -419,10 +660,18 @@ func (rc *runtimeClient) WatchEvents(ctx context.Context, decoders []EventDecode
 		defer blkSub.Close()
 		defer close(ch)
 
+		var stallCh <-chan time.Time
 		for {
+			if rc.opts.timeouts.Watch > 0 {
+				stallCh = time.After(rc.opts.timeouts.Watch)
+			}
+
 			select {
 			case <-ctx.Done():
 				return
+			case <-stallCh:
+				// No new blocks for too long, consider the subscription stalled.
+				return
 			case blk, ok := <-blkCh:
 				if !ok {
 					return
@@ -443,32 +692,263 @@ func (rc *runtimeClient) WatchEvents(ctx context.Context, decoders []EventDecode
 	return ch, nil
 }
 
+// watchEventsResumable is WatchEvents that resubscribes after the block subscription fails (see
+// WithWatchResumption).
+func (rc *runtimeClient) watchEventsResumable(ctx context.Context, decoders []EventDecoder, includeUndecoded bool) <-chan *BlockEvents {
+	ch := make(chan *BlockEvents)
+
+	go func() {
+		defer close(ch)
+
+		var cursor watchCursor
+		backoff := watchMinBackoff
+		for {
+			if rc.watchEventsFrom(ctx, ch, decoders, includeUndecoded, &cursor) {
+				backoff = watchMinBackoff
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if backoff *= 2; backoff > rc.opts.watchMaxBackoff {
+				backoff = rc.opts.watchMaxBackoff
+			}
+		}
+	}()
+
+	return ch
+}
+
+// watchCursor tracks the progress of a resumable watch.
+type watchCursor struct {
+	// next is the next round to send.
+	next uint64
+	// started is true once the first block was received.
+	started bool
+}
+
+// watchEventsFrom subscribes to blocks and sends the events of each round starting at the cursor,
+// so that rounds missed since a previous subscription are backfilled. It returns once the
+// subscription fails and reports whether any round was sent.
+func (rc *runtimeClient) watchEventsFrom(
+	ctx context.Context,
+	ch chan<- *BlockEvents,
+	decoders []EventDecoder,
+	includeUndecoded bool,
+	cursor *watchCursor,
+) bool {
+	blkCh, blkSub, err := rc.runtime(ctx, PriorityInteractive).WatchBlocks(ctx, rc.runtimeID)
+	if err != nil {
+		return false
+	}
+	defer blkSub.Close()
+
+	var sent bool
+	var stallCh <-chan time.Time
+	for {
+		if rc.opts.timeouts.Watch > 0 {
+			stallCh = time.After(rc.opts.timeouts.Watch)
+		}
+
+		select {
+		case <-ctx.Done():
+			return sent
+		case <-stallCh:
+			// No new blocks for too long, consider the subscription stalled.
+			return sent
+		case blk, ok := <-blkCh:
+			if !ok {
+				return sent
+			}
+
+			round := blk.Block.Header.Round
+			if !cursor.started {
+				cursor.next, cursor.started = round, true
+			}
+			for ; cursor.next <= round; cursor.next++ {
+				events, err := rc.GetEvents(ctx, cursor.next, decoders, includeUndecoded)
+				if err != nil {
+					return sent
+				}
+				select {
+				case ch <- &BlockEvents{Round: cursor.next, Events: events}:
+					sent = true
+				case <-ctx.Done():
+					return sent
+				}
+			}
+		}
+	}
+}
+
 // Implements RuntimeClient.
 func (rc *runtimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
 	// fmt.Printf("gbtest: args before is: %s \n", args)
-	raw, err := rc.cc.Query(ctx, &coreClient.QueryRequest{
-		RuntimeID: rc.runtimeID,
-		Round:     round,
-		Method:    method,
-		Args:      cbor.Marshal(args),
-	})
+	data, err := rc.QueryRaw(ctx, round, method, args)
 	if err != nil {
 		return err
 	}
 	if rsp != nil {
 		// fmt.Printf("gbtest: raw.Data is: %s \n", raw.Data)
-		if err = cbor.Unmarshal(raw.Data, rsp); err != nil {
+		if data, err = rc.opts.codec.decodeResponse(data, rsp); err != nil {
+			return fmt.Errorf("failed to transcode response: %w", err)
+		}
+		switch rc.opts.drift {
+		case nil:
+			err = unmarshalResponse(method, data, rsp)
+		default:
+			err = decodeWithDriftDetection(ctx, method, data, rsp, rc.opts.drift)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
 	}
 	return nil
 }
 
+// Implements RuntimeClient.
+func (rc *runtimeClient) QueryRaw(ctx context.Context, round uint64, method string, args interface{}) (cbor.RawMessage, error) {
+	if err := rc.chargeQuota(ctx, QuotaQueries); err != nil {
+		return nil, err
+	}
+	ctx, round, err := rc.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	if meta := resultMetaFromContext(ctx); meta != nil {
+		// Pin the query to a concrete round so that the metadata matches the result.
+		blk, err := rc.GetBlock(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch block for result metadata: %w", err)
+		}
+		round = blk.Header.Round
+		meta.record(blk.Header.Round, time.Unix(int64(blk.Header.Timestamp), 0))
+	}
+	rawArgs, err := rc.opts.codec.encodeQueryArgs(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query arguments: %w", err)
+	}
+	req := &coreClient.QueryRequest{
+		RuntimeID: rc.runtimeID,
+		Round:     round,
+		Method:    rc.methods.resolve(method),
+		Args:      rawArgs,
+	}
+	data, err := rc.query(ctx, req)
+	if isUnknownMethod(err) {
+		// The method may be known to the node under an alias, e.g. after being renamed.
+		if alias, ok := aliases.alias(req.Method); ok {
+			req.Method = alias
+			if data, err = rc.query(ctx, req); err == nil {
+				rc.methods.prefer(method, alias)
+				if alias != method {
+					aliases.warn(ctx, &DeprecationWarning{Method: method, Used: alias})
+				}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return aliases.rewriteFields(ctx, method, data)
+}
+
+// query performs the given query request, coalescing it with identical in-flight requests if
+// query deduplication is enabled.
+func (rc *runtimeClient) query(ctx context.Context, req *coreClient.QueryRequest) ([]byte, error) {
+	if !rc.opts.dedupQueries {
+		rsp, err := rc.runtime(ctx, PriorityInteractive).Query(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return rsp.Data, nil
+	}
+
+	key := fmt.Sprintf("%s/%d/%x", req.Method, req.Round, req.Args)
+	ch := rc.queryGroup.DoChan(key, func() (interface{}, error) {
+		// The shared request must not fail when the caller that happened to start it goes away.
+		timeout := rc.opts.timeouts.Query
+		if timeout <= 0 {
+			timeout = sharedQueryTimeout
+		}
+		ctx, cancel := withDefaultTimeout(detachedContext{ctx}, timeout)
+		defer cancel()
+
+		rsp, err := rc.runtime(ctx, PriorityInteractive).Query(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return rsp.Data, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
+	}
+}
+
+// node returns the node to use for an operation issued with the given context.
+func (rc *runtimeClient) node(ctx context.Context, def Priority) coreClient.RuntimeClient {
+	if cc, ok := ctx.Value(pinnedNodeKey{}).(coreClient.RuntimeClient); ok && def != PrioritySubmit {
+		return cc
+	}
+	switch {
+	case rc.opts.nodes != nil:
+		// Routing is based on the kind of operation, not on the priority set by the caller.
+		return coreClient.NewRuntimeClient(rc.opts.nodes.Conn(def))
+	case rc.opts.pool != nil:
+		if conn := rc.opts.pool.Conn(PriorityFromContext(ctx, def)); conn != nil {
+			return coreClient.NewRuntimeClient(conn)
+		}
+	}
+	return rc.cc
+}
+
+// runtime returns the runtime client to use for an operation issued with the given context.
+func (rc *runtimeClient) runtime(ctx context.Context, def Priority) coreClient.RuntimeClient {
+	return rc.wrap(rc.node(ctx, def))
+}
+
+// wrap wraps the given node into the runtime client used for operations.
+func (rc *runtimeClient) wrap(cc coreClient.RuntimeClient) coreClient.RuntimeClient {
+	return &pruningClient{
+		RuntimeClient: &wrappedClient{RuntimeClient: cc, timeouts: rc.opts.timeouts},
+		runtimeID:     rc.runtimeID,
+		archive:       rc.opts.archive,
+		timeouts:      rc.opts.timeouts,
+	}
+}
+
+// resolveRound resolves special round numbers that are not understood by the node into concrete
+// rounds. RoundLatest is passed through as the node handles it directly, unless monotonic reads
+// are enabled.
+//
+// The returned context should be used for the operation at the resolved round as it may pin the
+// operation to the node the round was resolved with.
+func (rc *runtimeClient) resolveRound(ctx context.Context, round uint64) (context.Context, uint64, error) {
+	if round != RoundLatest || rc.opts.monotonicAttempts == 0 {
+		return ctx, round, nil
+	}
+	return rc.resolveLatestMonotonic(ctx)
+}
+
 // New creates a new runtime client for the specified runtime.
-func New(conn *grpc.ClientConn, runtimeID common.Namespace) RuntimeClient {
-	return &runtimeClient{
+func New(conn *grpc.ClientConn, runtimeID common.Namespace, opts ...Option) RuntimeClient {
+	rc := &runtimeClient{
 		cs:        consensus.NewConsensusClient(conn),
 		cc:        coreClient.NewRuntimeClient(conn),
 		runtimeID: runtimeID,
 	}
+	for _, opt := range opts {
+		opt(&rc.opts)
+	}
+	return rc
 }
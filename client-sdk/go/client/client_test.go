@@ -0,0 +1,376 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// manyEventsRuntimeClient returns the given number of events for every block.
+type manyEventsRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	count int
+}
+
+func (m *manyEventsRuntimeClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	evs := make([]*coreClient.Event, m.count)
+	for i := range evs {
+		evs[i] = &coreClient.Event{
+			Key:   types.NewEventKey("test", uint32(i%2)),
+			Value: cbor.Marshal(i),
+		}
+	}
+	return evs, nil
+}
+
+// txEventsRuntimeClient returns a block with two transactions, each emitting one event, followed by
+// an event emitted by the block itself.
+type txEventsRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	txs [][]byte
+}
+
+func (m *txEventsRuntimeClient) GetTransactions(ctx context.Context, request *coreClient.GetTransactionsRequest) ([][]byte, error) {
+	return m.txs, nil
+}
+
+func (m *txEventsRuntimeClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	evs := make([]*coreClient.Event, 0, len(m.txs)+1)
+	for i := len(m.txs) - 1; i >= 0; i-- {
+		evs = append(evs, &coreClient.Event{
+			Key:    types.NewEventKey("test", 1),
+			Value:  cbor.Marshal(i),
+			TxHash: hash.NewFromBytes(m.txs[i]),
+		})
+	}
+	evs = append(evs, &coreClient.Event{Key: types.NewEventKey("test", 2)})
+	return evs, nil
+}
+
+// mixedEventsRuntimeClient returns events emitted by different modules.
+type mixedEventsRuntimeClient struct {
+	coreClient.RuntimeClient
+}
+
+func (m *mixedEventsRuntimeClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	var evs []*coreClient.Event
+	for i, module := range []string{"test.mixed", "other", "test.mixed.1", "test.mixedup"} {
+		evs = append(evs, &coreClient.Event{
+			Key:   types.NewEventKey(module, 1),
+			Value: cbor.Marshal(i),
+		})
+	}
+	return evs, nil
+}
+
+func TestStreamEvents(t *testing.T) {
+	require := require.New(t)
+
+	rc := &runtimeClient{cc: &manyEventsRuntimeClient{count: 10}}
+	ctx := context.Background()
+
+	var sizes []int
+	err := rc.StreamEventsRaw(ctx, 1, 4, func(chunk []*types.Event) error {
+		sizes = append(sizes, len(chunk))
+		return nil
+	})
+	require.NoError(err, "StreamEventsRaw")
+	require.Equal([]int{4, 4, 2}, sizes)
+
+	// Only decode events with code 1.
+	decoder := EventDecoderFunc(func(ev *types.Event) ([]DecodedEvent, error) {
+		if ev.Code != 1 {
+			return nil, nil
+		}
+		return []DecodedEvent{ev.Value}, nil
+	})
+	var decoded []DecodedEvent
+	err = rc.StreamEvents(ctx, 1, []EventDecoder{decoder}, false, 3, func(chunk []DecodedEvent) error {
+		require.LessOrEqual(len(chunk), 3)
+		decoded = append(decoded, chunk...)
+		return nil
+	})
+	require.NoError(err, "StreamEvents")
+	require.Len(decoded, 5)
+
+	all, err := rc.GetEvents(ctx, 1, []EventDecoder{decoder}, false)
+	require.NoError(err, "GetEvents")
+	require.Equal(decoded, all, "GetEvents should match the streamed events")
+
+	raw, err := rc.GetEventsRaw(ctx, 1)
+	require.NoError(err, "GetEventsRaw")
+	require.Len(raw, 10)
+	for i, ev := range raw {
+		require.EqualValues(1, ev.Round)
+		require.EqualValues(i, ev.Index, "events should be indexed by their position in the block")
+	}
+
+	// Undecoded events carry their identity as well.
+	ids := make(map[string]bool)
+	all, err = rc.GetEvents(ctx, 1, []EventDecoder{decoder}, true)
+	require.NoError(err, "GetEvents")
+	for _, ev := range all {
+		meta, ok := EventMetadata(ev)
+		if !ok {
+			continue
+		}
+		ids[meta.ID.String()] = true
+	}
+	require.Len(ids, 5, "undecoded events should have distinct identities")
+
+	stop := fmt.Errorf("stop")
+	var chunks int
+	err = rc.StreamEventsRaw(ctx, 1, 2, func([]*types.Event) error {
+		chunks++
+		return stop
+	})
+	require.ErrorIs(err, stop, "errors returned by the callback should stop streaming")
+	require.Equal(1, chunks)
+
+	rc.cc = &manyEventsRuntimeClient{}
+	raw, err = rc.GetEventsRaw(ctx, 1)
+	require.NoError(err, "GetEventsRaw")
+	require.NotNil(raw)
+	require.Empty(raw)
+}
+
+// cancellingDecoder cancels the decoding context after the given number of events.
+type cancellingDecoder struct {
+	EventDecoder
+
+	cancel  context.CancelFunc
+	after   int
+	decoded int
+}
+
+func (d *cancellingDecoder) DecodeEventContext(ctx context.Context, ev *types.Event) ([]DecodedEvent, error) {
+	if d.decoded++; d.decoded == d.after {
+		d.cancel()
+	}
+	return []DecodedEvent{ev}, nil
+}
+
+func TestStreamEventsCancellation(t *testing.T) {
+	require := require.New(t)
+
+	rc := &runtimeClient{cc: &manyEventsRuntimeClient{count: 10}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	decoder := &cancellingDecoder{cancel: cancel, after: 3}
+	err := rc.StreamEvents(ctx, 1, []EventDecoder{decoder}, false, 0, func([]DecodedEvent) error {
+		require.Fail("cancelled blocks should not be delivered")
+		return nil
+	})
+	require.ErrorIs(err, context.Canceled)
+	require.Equal(3, decoder.decoded, "decoding should stop mid-block")
+
+	_, err = rc.GetEventsRaw(ctx, 1)
+	require.ErrorIs(err, context.Canceled)
+}
+
+func TestEventTxCorrelation(t *testing.T) {
+	require := require.New(t)
+
+	cc := &txEventsRuntimeClient{txs: [][]byte{{0x01}, {0x02}}}
+	rc := &runtimeClient{cc: cc}
+	ctx := context.Background()
+
+	evs, err := rc.GetEvents(ctx, 1, nil, true)
+	require.NoError(err, "GetEvents")
+	require.Len(evs, 3)
+	for i, ev := range evs[:2] {
+		meta, ok := EventMetadata(ev)
+		require.True(ok)
+		require.NotNil(meta.TxHash, "events should carry the hash of their transaction")
+		require.Equal(hash.NewFromBytes(cc.txs[1-i]), *meta.TxHash)
+		require.Nil(meta.TxIndex, "transaction indices should only be resolved when enabled")
+	}
+
+	rc.opts.txIndices = true
+	evs, err = rc.GetEvents(ctx, 1, nil, true)
+	require.NoError(err, "GetEvents")
+	for i, ev := range evs[:2] {
+		meta, _ := EventMetadata(ev)
+		require.NotNil(meta.TxIndex)
+		require.EqualValues(1-i, *meta.TxIndex)
+	}
+	meta, _ := EventMetadata(evs[2])
+	require.Nil(meta.TxHash, "block events should not be linked to a transaction")
+	require.Nil(meta.TxIndex, "block events should not be linked to a transaction")
+}
+
+func TestGetEventsForModule(t *testing.T) {
+	require := require.New(t)
+
+	var decodedModules []string
+	MustRegisterModule(&ModuleDescriptor{
+		Name: "test.mixed",
+		Events: EventDecoderFunc(func(ev *types.Event) ([]DecodedEvent, error) {
+			decodedModules = append(decodedModules, ev.Module)
+			return []DecodedEvent{ev.Module}, nil
+		}),
+	})
+
+	rc := &runtimeClient{cc: &mixedEventsRuntimeClient{}}
+	ctx := context.Background()
+
+	evs, err := rc.GetEventsForModule(ctx, 1, "test.mixed")
+	require.NoError(err, "GetEventsForModule")
+	require.Equal([]DecodedEvent{"test.mixed", "test.mixed.1"}, evs)
+	require.Equal([]string{"test.mixed", "test.mixed.1"}, decodedModules, "events of other modules should not be decoded")
+
+	_, err = rc.GetEventsForModule(ctx, 1, "test.unknown")
+	require.Error(err, "GetEventsForModule should fail for unregistered modules")
+}
+
+// flakyBlocksRuntimeClient serves each given sequence of rounds over a separate block subscription,
+// which fails after the last round.
+type flakyBlocksRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	subscriptions [][]uint64
+}
+
+func (f *flakyBlocksRuntimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	if len(f.subscriptions) == 0 {
+		return nil, nil, fmt.Errorf("node unreachable")
+	}
+	rounds := f.subscriptions[0]
+	f.subscriptions = f.subscriptions[1:]
+
+	ch := make(chan *roothash.AnnotatedBlock, len(rounds))
+	for _, round := range rounds {
+		var blk block.Block
+		blk.Header.Round = round
+		ch <- &roothash.AnnotatedBlock{Block: &blk}
+	}
+	close(ch)
+	return ch, pubsub.NewBroker(false).Subscribe(), nil
+}
+
+func (f *flakyBlocksRuntimeClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	return nil, nil
+}
+
+func TestWatchEventsResumption(t *testing.T) {
+	require := require.New(t)
+
+	cc := &flakyBlocksRuntimeClient{subscriptions: [][]uint64{{5, 6}, {9}, {10}}}
+	rc := &runtimeClient{cc: cc, opts: options{watchMaxBackoff: watchMinBackoff}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := rc.WatchEvents(ctx, nil, false)
+	require.NoError(err, "WatchEvents")
+	for expected := uint64(5); expected <= 10; expected++ {
+		select {
+		case evs := <-ch:
+			require.EqualValues(expected, evs.Round, "missed rounds should be backfilled")
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for events", "round %d", expected)
+		}
+	}
+
+	// Failed subscriptions should be retried until the context is cancelled.
+	cancel()
+	for range ch {
+	}
+}
+
+// sharedQueryRuntimeClient blocks queries until released, failing those whose context is done.
+type sharedQueryRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	started     chan struct{}
+	startedOnce sync.Once
+	release     chan struct{}
+	calls       int32
+	deadline    bool
+}
+
+func (s *sharedQueryRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	if atomic.AddInt32(&s.calls, 1) == 1 {
+		_, s.deadline = ctx.Deadline()
+	}
+	s.startedOnce.Do(func() { close(s.started) })
+	<-s.release
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &coreClient.QueryResponse{Data: cbor.Marshal(uint64(42))}, nil
+}
+
+// waitingContext is a context counting the callers waiting for it to be done.
+type waitingContext struct {
+	context.Context
+
+	waiting *int32
+}
+
+func (c waitingContext) Done() <-chan struct{} {
+	atomic.AddInt32(c.waiting, 1)
+	return c.Context.Done()
+}
+
+func TestQueryDeduplicationCancellation(t *testing.T) {
+	require := require.New(t)
+
+	cc := &sharedQueryRuntimeClient{started: make(chan struct{}), release: make(chan struct{})}
+	rc := &runtimeClient{cc: cc, opts: options{dedupQueries: true}}
+
+	// The first caller gives up while the shared query is in flight.
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := rc.QueryRaw(firstCtx, 10, "test.Query", nil)
+		firstErr <- err
+	}()
+	<-cc.started
+
+	var (
+		wg      sync.WaitGroup
+		waiting int32
+	)
+	ctx := waitingContext{Context: context.Background(), waiting: &waiting}
+	results := make([]cbor.RawMessage, 3)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = rc.QueryRaw(ctx, 10, "test.Query", nil)
+		}(i)
+	}
+	// The other callers wait for the shared query once they joined it.
+	require.Eventually(func() bool { return atomic.LoadInt32(&waiting) == int32(len(results)) }, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(<-firstErr, context.Canceled)
+	close(cc.release)
+	wg.Wait()
+
+	for i := range results {
+		require.NoError(errs[i], "other callers should not fail when the first caller gives up")
+		require.Equal(cbor.RawMessage(cbor.Marshal(uint64(42))), results[i])
+	}
+	require.EqualValues(1, atomic.LoadInt32(&cc.calls), "identical queries should be shared")
+	require.True(cc.deadline, "the shared query should be subject to a timeout")
+}
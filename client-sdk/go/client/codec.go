@@ -0,0 +1,324 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// CodecMode determines how pre-encoded call bodies (cbor.RawMessage) are handled.
+type CodecMode uint8
+
+const (
+	// CodecModePassthrough submits pre-encoded call bodies unchanged.
+	CodecModePassthrough CodecMode = iota
+	// CodecModeCanonical rejects pre-encoded call bodies that are not canonically encoded, e.g.
+	// because they were produced by a different CBOR library.
+	CodecModeCanonical
+	// CodecModeCompatibility re-encodes pre-encoded call bodies canonically.
+	CodecModeCompatibility
+)
+
+// CodecConfig configures how the client encodes call bodies and query arguments and decodes query
+// responses, so that the SDK can be matched to the encoding expected by the runtime. The zero
+// value is the default encoding.
+type CodecConfig struct {
+	// Mode determines how pre-encoded call bodies are handled.
+	Mode CodecMode `json:"mode"`
+	// IntegerRoles encodes roles and actions (see types.Role and types.Action) as CBOR integers
+	// instead of single byte strings. Runtimes with the accounts module of this SDK version expect
+	// byte strings, as oasis-cbor does not handle uint8 values by default.
+	IntegerRoles bool `json:"integer_roles"`
+}
+
+// WithCodec makes the client encode call bodies and query arguments and decode query responses
+// using the given codec configuration. Use CodecConfig.Verify to check the configuration against
+// the encoding vectors published for the runtime (e.g. obtained from LoadCodecVectors) first.
+func WithCodec(cfg CodecConfig) Option {
+	return func(o *options) {
+		o.codec = cfg
+	}
+}
+
+// CodecProvider is implemented by runtime clients configured with a codec (see WithCodec).
+type CodecProvider interface {
+	// Codec returns the codec configuration of the client.
+	Codec() CodecConfig
+}
+
+// CodecOf returns the codec configuration of the given runtime client, i.e. the default
+// configuration unless the client implements CodecProvider.
+func CodecOf(rc RuntimeClient) CodecConfig {
+	if cp, ok := rc.(CodecProvider); ok {
+		return cp.Codec()
+	}
+	return CodecConfig{}
+}
+
+// Implements CodecProvider.
+func (rc *runtimeClient) Codec() CodecConfig {
+	return rc.opts.codec
+}
+
+// EncodeCallBody encodes the body of a call to the given method using the codec configuration.
+func (cfg CodecConfig) EncodeCallBody(method string, body interface{}) (cbor.RawMessage, error) {
+	var bodyType reflect.Type
+	if m, ok := LookupMethod(method); ok {
+		bodyType = m.BodyType
+	}
+
+	var data []byte
+	switch raw := body.(type) {
+	case cbor.RawMessage:
+		data = raw
+		if cfg.Mode == CodecModePassthrough {
+			break
+		}
+		var v interface{}
+		if err := cbor.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("client: malformed body of %s: %w", method, err)
+		}
+		canonical := cbor.Marshal(v)
+		if bytes.Equal(canonical, raw) {
+			break
+		}
+		if cfg.Mode == CodecModeCanonical {
+			return nil, fmt.Errorf("client: body of %s is not canonically encoded", method)
+		}
+		data = canonical
+	default:
+		data = cbor.Marshal(body)
+		if bodyType == nil && body != nil {
+			bodyType = reflect.TypeOf(body)
+		}
+	}
+
+	if !cfg.IntegerRoles {
+		return data, nil
+	}
+	data, err := transcodeRoles(data, bodyType, true)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to transcode body of %s: %w", method, err)
+	}
+	return data, nil
+}
+
+// encodeQueryArgs encodes the given query arguments using the codec configuration.
+func (cfg CodecConfig) encodeQueryArgs(args interface{}) ([]byte, error) {
+	data := cbor.Marshal(args)
+	if !cfg.IntegerRoles || args == nil {
+		return data, nil
+	}
+	return transcodeRoles(data, reflect.TypeOf(args), true)
+}
+
+// decodeResponse converts the given query response into the default encoding expected by the
+// response types.
+func (cfg CodecConfig) decodeResponse(data []byte, rsp interface{}) ([]byte, error) {
+	if !cfg.IntegerRoles || rsp == nil {
+		return data, nil
+	}
+	return transcodeRoles(data, reflect.TypeOf(rsp), false)
+}
+
+var (
+	roleType   = reflect.TypeOf(types.Role(0))
+	actionType = reflect.TypeOf(types.Action(0))
+)
+
+// transcodeRoles rewrites all roles and actions in the given CBOR-encoded value of the given type
+// to integers in case toInteger is true and to single byte strings otherwise.
+func transcodeRoles(data []byte, t reflect.Type, toInteger bool) ([]byte, error) {
+	if t == nil || len(data) == 0 || !containsRoles(t, make(map[reflect.Type]bool)) {
+		return data, nil
+	}
+	var v interface{}
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, changed := transcodeValue(v, t, toInteger)
+	if !changed {
+		return data, nil
+	}
+	return cbor.Marshal(v), nil
+}
+
+// containsRoles checks whether values of the given type may contain roles or actions.
+func containsRoles(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == roleType || t == actionType {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, ft := range structFields(t) {
+			if containsRoles(ft, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		// Slices of uint8-based types (including roles) are encoded as byte strings.
+		return t.Elem().Kind() != reflect.Uint8 && containsRoles(t.Elem(), seen)
+	case reflect.Map:
+		return containsRoles(t.Elem(), seen)
+	}
+	return false
+}
+
+// transcodeValue rewrites all roles and actions in the generically decoded CBOR value of the given
+// type and reports whether anything was changed.
+func transcodeValue(v interface{}, t reflect.Type, toInteger bool) (interface{}, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == roleType || t == actionType {
+		switch x := v.(type) {
+		case []byte:
+			if toInteger && len(x) == 1 {
+				return uint64(x[0]), true
+			}
+		case uint64:
+			if !toInteger && x <= math.MaxUint8 {
+				return []byte{byte(x)}, true
+			}
+		}
+		return v, false
+	}
+	// Types with custom encoding don't contain roles.
+	ptr := reflect.PtrTo(t)
+	if ptr.Implements(cborUnmarshalerType) || ptr.Implements(binaryUnmarshalerType) {
+		return v, false
+	}
+
+	var changed bool
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[interface{}]interface{})
+		if !ok {
+			return v, false
+		}
+		known := structFields(t)
+		for key, item := range m {
+			name, ok := key.(string)
+			if !ok {
+				continue
+			}
+			ft, ok := known.lookup(name)
+			if !ok {
+				continue
+			}
+			if item, ok = transcodeValue(item, ft, toInteger); ok {
+				m[key] = item
+				changed = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return v, false
+		}
+		for i, item := range arr {
+			if item, ok = transcodeValue(item, t.Elem(), toInteger); ok {
+				arr[i] = item
+				changed = true
+			}
+		}
+	case reflect.Map:
+		m, ok := v.(map[interface{}]interface{})
+		if !ok {
+			return v, false
+		}
+		for key, item := range m {
+			if item, ok = transcodeValue(item, t.Elem(), toInteger); ok {
+				m[key] = item
+				changed = true
+			}
+		}
+	}
+	return v, changed
+}
+
+// CodecVector is an encoding vector, i.e. the expected encoding of a call body.
+type CodecVector struct {
+	// Method is the name of the called method.
+	Method string `json:"method"`
+	// Body is the JSON representation of the call body.
+	Body json.RawMessage `json:"body"`
+	// Encoded is the hex-encoded CBOR encoding of the call body expected by the runtime.
+	Encoded string `json:"encoded"`
+}
+
+// LoadCodecVectors downloads encoding vectors published for a runtime from the given URL, which
+// must serve a JSON array of vectors.
+func LoadCodecVectors(ctx context.Context, url string) ([]CodecVector, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("client: bad codec vectors URL: %w", err)
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to download codec vectors: %w", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("client: failed to download codec vectors: %s", rsp.Status)
+	}
+
+	var vectors []CodecVector
+	if err = json.NewDecoder(rsp.Body).Decode(&vectors); err != nil {
+		return nil, fmt.Errorf("client: malformed codec vectors: %w", err)
+	}
+	return vectors, nil
+}
+
+// Verify checks that the codec configuration is valid and reproduces the given encoding vectors.
+// The bodies of the vectors are decoded into the body types of the registered methods.
+func (cfg CodecConfig) Verify(vectors []CodecVector) error {
+	if cfg.Mode > CodecModeCompatibility {
+		return fmt.Errorf("client: unknown codec mode %d", cfg.Mode)
+	}
+	for i, vec := range vectors {
+		m, ok := LookupMethod(vec.Method)
+		if !ok {
+			return fmt.Errorf("client: codec vector %d: unknown method %s", i, vec.Method)
+		}
+		var body interface{}
+		if m.BodyType != nil {
+			bodyPtr := reflect.New(m.BodyType)
+			if err := json.Unmarshal(vec.Body, bodyPtr.Interface()); err != nil {
+				return fmt.Errorf("client: codec vector %d: malformed body of %s: %w", i, vec.Method, err)
+			}
+			body = bodyPtr.Elem().Interface()
+		}
+		expected, err := hex.DecodeString(vec.Encoded)
+		if err != nil {
+			return fmt.Errorf("client: codec vector %d: malformed encoding: %w", i, err)
+		}
+
+		encoded, err := cfg.EncodeCallBody(vec.Method, body)
+		if err != nil {
+			return fmt.Errorf("client: codec vector %d: %w", i, err)
+		}
+		if !bytes.Equal(encoded, expected) {
+			return fmt.Errorf("client: codec vector %d: encoding mismatch for %s (expected: %s got: %x)",
+				i, vec.Method, vec.Encoded, []byte(encoded))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type testRoleEntry struct {
+	Name string     `json:"name"`
+	Role types.Role `json:"role"`
+}
+
+type testRoleBody struct {
+	Action  types.Action    `json:"action"`
+	Entries []testRoleEntry `json:"entries"`
+	Owners  []types.Role    `json:"owners"`
+}
+
+// testIntegerRoleEntry and testIntegerRoleBody are encoded like the role types would be with
+// integer roles.
+type testIntegerRoleEntry struct {
+	Name string `json:"name"`
+	Role uint8  `json:"role"`
+}
+
+type testIntegerRoleBody struct {
+	Action  uint8                  `json:"action"`
+	Entries []testIntegerRoleEntry `json:"entries"`
+	Owners  []types.Role           `json:"owners"`
+}
+
+func TestCodecIntegerRoles(t *testing.T) {
+	require := require.New(t)
+
+	body := testRoleBody{
+		Action:  types.Mint,
+		Entries: []testRoleEntry{{Name: "a", Role: types.MintProposer}, {Name: "b", Role: types.Admin}},
+		Owners:  []types.Role{types.Admin, types.User},
+	}
+	integer := cbor.Marshal(&testIntegerRoleBody{
+		Action:  uint8(types.Mint),
+		Entries: []testIntegerRoleEntry{{Name: "a", Role: uint8(types.MintProposer)}, {Name: "b"}},
+		Owners:  []types.Role{types.Admin, types.User},
+	})
+
+	cfg := CodecConfig{IntegerRoles: true}
+	encoded, err := cfg.EncodeCallBody("test.codec.Unregistered", body)
+	require.NoError(err, "EncodeCallBody")
+	require.EqualValues(integer, encoded)
+
+	decoded, err := cfg.decodeResponse(encoded, &testRoleBody{})
+	require.NoError(err, "decodeResponse")
+	require.EqualValues(cbor.Marshal(body), decoded, "responses should be transcoded back")
+	var rsp testRoleBody
+	require.NoError(cbor.Unmarshal(decoded, &rsp))
+	require.Equal(body, rsp)
+
+	encoded, err = CodecConfig{}.EncodeCallBody("test.codec.Unregistered", body)
+	require.NoError(err, "EncodeCallBody")
+	require.EqualValues(cbor.Marshal(body), encoded, "the default should encode roles as byte strings")
+
+	// The codec configuration is per client.
+	integerClient := &runtimeClient{}
+	WithCodec(cfg)(&integerClient.opts)
+	defaultClient := &runtimeClient{}
+	require.Equal(cfg, CodecOf(integerClient))
+	require.Equal(CodecConfig{}, CodecOf(defaultClient))
+	require.EqualValues(integer, NewTransactionBuilder(integerClient, "test.codec.Unregistered", body).GetTransaction().Call.Body)
+	require.EqualValues(cbor.Marshal(body), NewTransactionBuilder(defaultClient, "test.codec.Unregistered", body).GetTransaction().Call.Body,
+		"other clients should not be affected")
+}
+
+func TestCodecModes(t *testing.T) {
+	require := require.New(t)
+
+	// {"b": 1, "a": 2} with keys not in canonical order.
+	raw := cbor.RawMessage{0xa2, 0x61, 0x62, 0x01, 0x61, 0x61, 0x02}
+
+	encoded, err := CodecConfig{}.EncodeCallBody("test.codec.Raw", raw)
+	require.NoError(err)
+	require.EqualValues(raw, encoded, "bodies should be passed through by default")
+
+	_, err = CodecConfig{Mode: CodecModeCanonical}.EncodeCallBody("test.codec.Raw", raw)
+	require.ErrorContains(err, "not canonically encoded")
+
+	encoded, err = CodecConfig{Mode: CodecModeCompatibility}.EncodeCallBody("test.codec.Raw", raw)
+	require.NoError(err)
+	require.EqualValues(cbor.Marshal(map[string]uint64{"a": 2, "b": 1}), encoded)
+
+	canonical := cbor.Marshal(map[string]uint64{"a": 2, "b": 1})
+	encoded, err = CodecConfig{Mode: CodecModeCanonical}.EncodeCallBody("test.codec.Raw", cbor.RawMessage(canonical))
+	require.NoError(err)
+	require.EqualValues(canonical, encoded)
+}
+
+func TestCodecVectors(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(RegisterModule(&ModuleDescriptor{
+		Name:    "test.codec",
+		Methods: map[string]interface{}{"test.codec.Set": testRoleBody{}},
+	}))
+
+	body := testRoleBody{Action: types.Burn, Entries: []testRoleEntry{{Name: "a", Role: types.BurnVoter}}}
+	rawBody, err := json.Marshal(body)
+	require.NoError(err)
+	vectors := []CodecVector{{
+		Method:  "test.codec.Set",
+		Body:    rawBody,
+		Encoded: hex.EncodeToString(cbor.Marshal(body)),
+	}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(vectors)
+	}))
+	defer srv.Close()
+	loaded, err := LoadCodecVectors(context.Background(), srv.URL)
+	require.NoError(err, "LoadCodecVectors")
+	require.Len(loaded, 1)
+
+	require.NoError(CodecConfig{}.Verify(loaded))
+	err = CodecConfig{IntegerRoles: true}.Verify(loaded)
+	require.ErrorContains(err, "encoding mismatch for test.codec.Set")
+	require.ErrorContains(CodecConfig{Mode: 42}.Verify(nil), "unknown codec mode")
+
+	err = CodecConfig{}.Verify([]CodecVector{{Method: "test.codec.Unknown"}})
+	require.ErrorContains(err, "unknown method")
+}
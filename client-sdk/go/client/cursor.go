@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// EventCursor remembers the position of the last processed event so that event processing can
+// resume exactly where it left off, without the need for a full indexer.
+//
+// A cursor can be scoped to events of a single module and/or events involving a single address.
+// It is serializable to JSON so it can be persisted between runs (see Save and LoadEventCursor).
+type EventCursor struct {
+	// Module restricts the cursor to events emitted by the given module (if set).
+	Module string `json:"module,omitempty"`
+	// Address restricts the cursor to events involving the given address (if set).
+	Address *types.Address `json:"address,omitempty"`
+
+	// Round is the round of the next event to process.
+	Round uint64 `json:"round"`
+	// Index is the index of the next event to process among all events emitted in Round.
+	Index int `json:"index"`
+}
+
+// NewEventCursor creates a new event cursor starting at the first event of the given round.
+func NewEventCursor(module string, address *types.Address, round uint64) *EventCursor {
+	return &EventCursor{
+		Module:  module,
+		Address: address,
+		Round:   round,
+	}
+}
+
+// Matches checks whether the given event is in scope of the cursor.
+//
+// An event involves the cursor's address in case the address appears anywhere in its body, which
+// works for all modules without having to know their event types.
+func (c *EventCursor) Matches(ev *types.Event) bool {
+	if c.Module != "" && ev.Module != c.Module {
+		return false
+	}
+	if c.Address == nil {
+		return true
+	}
+
+	var body interface{}
+	if err := cbor.Unmarshal(ev.Value, &body); err != nil {
+		return false
+	}
+	rawAddr, _ := c.Address.MarshalBinary()
+	return containsBytes(body, rawAddr)
+}
+
+// containsBytes checks whether the given generically decoded CBOR value contains the given byte
+// string.
+func containsBytes(v interface{}, b []byte) bool {
+	switch v := v.(type) {
+	case []byte:
+		return bytes.Equal(v, b)
+	case []interface{}:
+		for _, item := range v {
+			if containsBytes(item, b) {
+				return true
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			if containsBytes(key, b) || containsBytes(item, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProcessOption is an option for configuring EventCursor.Process.
+type ProcessOption func(*processOptions)
+
+type processOptions struct {
+	pacer *Pacer
+}
+
+// WithPacer paces the event lookups of the given pacer, e.g. to run a backfill of historical
+// events against a production node. Transient lookup errors are retried as long as the pacer
+// allows.
+func WithPacer(pacer *Pacer) ProcessOption {
+	return func(o *processOptions) {
+		o.pacer = pacer
+	}
+}
+
+// Process passes all events in scope of the cursor, up to and including those emitted in round
+// to, to fn and advances the cursor.
+//
+// The cursor is only advanced past an event after fn returns successfully for it, so in case fn
+// fails, processing stops and the cursor points at the failed event. Passing RoundLatest as to
+// processes all events up to the latest round.
+func (c *EventCursor) Process(
+	ctx context.Context,
+	rc RuntimeClient,
+	to uint64,
+	fn func(round uint64, ev *types.Event) error,
+	opts ...ProcessOption,
+) error {
+	var po processOptions
+	for _, opt := range opts {
+		opt(&po)
+	}
+
+	if to == RoundLatest {
+		blk, err := rc.GetBlock(ctx, RoundLatest)
+		if err != nil {
+			return fmt.Errorf("cursor: failed to fetch latest block: %w", err)
+		}
+		to = blk.Header.Round
+	}
+	if c.Round <= to {
+		// Fail fast instead of failing on the first lookup, which may only happen after a pacer
+		// delay or a lengthy scan of a range the node retains only partially.
+		if err := rc.CheckRetained(ctx, c.Round); err != nil {
+			return fmt.Errorf("cursor: %w", err)
+		}
+	}
+
+	for ; c.Round <= to; c.Round, c.Index = c.Round+1, 0 {
+		evs, err := c.fetch(ctx, rc, po.pacer)
+		if err != nil {
+			return fmt.Errorf("cursor: failed to fetch events at round %d: %w", c.Round, err)
+		}
+		for ; c.Index < len(evs); c.Index++ {
+			if !c.Matches(evs[c.Index]) {
+				continue
+			}
+			if err = fn(c.Round, evs[c.Index]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fetch fetches the events of the cursor's round, pacing the lookups in case a pacer is given.
+func (c *EventCursor) fetch(ctx context.Context, rc RuntimeClient, pacer *Pacer) ([]*types.Event, error) {
+	if pacer == nil {
+		return rc.GetEventsRaw(ctx, c.Round)
+	}
+	for {
+		if err := pacer.Wait(ctx); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		evs, err := rc.GetEventsRaw(ctx, c.Round)
+		if pacer.Observe(time.Since(start), err) {
+			if err == nil {
+				return evs, nil
+			}
+			continue
+		}
+		return nil, err
+	}
+}
+
+// Save stores the cursor into the given file.
+//
+// The file is replaced atomically so that a crash while saving does not lose the position.
+func (c *EventCursor) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("cursor: failed to marshal cursor: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("cursor: failed to write cursor: %w", err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("cursor: failed to write cursor: %w", err)
+	}
+	return nil
+}
+
+// LoadEventCursor loads a cursor previously stored using Save.
+func LoadEventCursor(path string) (*EventCursor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cursor: failed to read cursor: %w", err)
+	}
+	var c EventCursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cursor: malformed cursor: %w", err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type eventsRuntimeClient struct {
+	RuntimeClient
+
+	events       map[uint64][]*types.Event
+	latest       uint64
+	lastRetained uint64
+}
+
+func (e *eventsRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = e.latest
+	return &blk, nil
+}
+
+func (e *eventsRuntimeClient) CheckRetained(ctx context.Context, round uint64) error {
+	if round < e.lastRetained {
+		return &PrunedError{Round: round, LastRetainedRound: e.lastRetained}
+	}
+	return nil
+}
+
+func (e *eventsRuntimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	return e.events[round], nil
+}
+
+func TestEventCursor(t *testing.T) {
+	require := require.New(t)
+
+	transfer := func(from, to types.Address) *types.Event {
+		return &types.Event{
+			Module: "accounts",
+			Code:   1,
+			Value:  cbor.Marshal([]interface{}{map[string]interface{}{"from": from, "to": to}}),
+		}
+	}
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	rc := &eventsRuntimeClient{
+		events: map[uint64][]*types.Event{
+			1: {transfer(alice, bob), {Module: "core", Code: 1, Value: cbor.Marshal([]interface{}{alice})}},
+			2: {transfer(bob, charlie), transfer(charlie, alice)},
+			3: {transfer(alice, charlie)},
+		},
+		latest: 3,
+	}
+
+	cursor := NewEventCursor("accounts", &alice, 1)
+	var seen []uint64
+	failAt := 3
+	err := cursor.Process(context.Background(), rc, RoundLatest, func(round uint64, ev *types.Event) error {
+		if round == uint64(failAt) {
+			return errors.New("processing failed")
+		}
+		seen = append(seen, round)
+		return nil
+	})
+	require.Error(err)
+	require.Equal([]uint64{1, 2}, seen, "only in-scope events should be processed")
+	require.EqualValues(3, cursor.Round, "cursor should point at the failed event")
+	require.EqualValues(0, cursor.Index)
+
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	require.NoError(cursor.Save(path))
+	restored, err := LoadEventCursor(path)
+	require.NoError(err)
+	require.Equal(cursor, restored)
+
+	failAt = 0
+	seen = nil
+	err = restored.Process(context.Background(), rc, RoundLatest, func(round uint64, ev *types.Event) error {
+		seen = append(seen, round)
+		return nil
+	})
+	require.NoError(err)
+	require.Equal([]uint64{3}, seen, "processing should resume exactly where it left off")
+	require.EqualValues(4, restored.Round)
+
+	// Scans of pruned rounds should fail before any lookup.
+	rc.lastRetained = 2
+	cursor = NewEventCursor("accounts", nil, 1)
+	err = cursor.Process(context.Background(), rc, RoundLatest, func(round uint64, ev *types.Event) error {
+		return errors.New("unexpected event")
+	})
+	require.True(IsPruned(err), "scans of pruned rounds should fail fast")
+	require.EqualValues(1, cursor.Round)
+}
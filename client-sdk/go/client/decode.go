@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// decodeEvent decodes the given event using the given decoder, converting decoder panics caused
+// by malformed events into a *types.DecodeError. The context error is returned in case the context
+// was cancelled.
+func decodeEvent(ctx context.Context, decoder EventDecoder, ev *types.Event) (evs []DecodedEvent, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer types.RecoverDecode(&err, fmt.Sprintf("%s event %d", ev.Module, ev.Code))
+	if cd, ok := decoder.(ContextEventDecoder); ok {
+		return cd.DecodeEventContext(ctx, ev)
+	}
+	return decoder.DecodeEvent(ev)
+}
+
+// unmarshalResponse decodes the response of the given query method, converting panics caused by
+// malformed responses into a *types.DecodeError.
+func unmarshalResponse(method string, data []byte, rsp interface{}) (err error) {
+	defer types.RecoverDecode(&err, method+" response")
+	return cbor.Unmarshal(data, rsp)
+}
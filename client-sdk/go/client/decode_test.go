@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type panickingValue struct{}
+
+func (v *panickingValue) UnmarshalCBOR([]byte) error {
+	var s []int
+	_ = s[1]
+	return nil
+}
+
+func TestPanicFreeDecoding(t *testing.T) {
+	require := require.New(t)
+
+	decoder := EventDecoderFunc(func(ev *types.Event) ([]DecodedEvent, error) {
+		panic("malformed event")
+	})
+	_, err := decodeEvent(context.Background(), decoder, &types.Event{Module: "test", Code: 7})
+	var de *types.DecodeError
+	require.True(errors.As(err, &de), "decoder panics should be converted into decode errors")
+	require.Equal("test event 7", de.What)
+	require.Equal("malformed event", de.Panic)
+
+	err = unmarshalResponse("test.Query", cbor.Marshal(42), &panickingValue{})
+	require.True(errors.As(err, &de))
+	require.Equal("test.Query response", de.What)
+
+	var v uint64
+	require.NoError(unmarshalResponse("test.Query", cbor.Marshal(42), &v))
+	require.EqualValues(42, v)
+}
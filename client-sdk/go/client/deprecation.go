@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// DeprecationWarning describes a use of a method or field name that the node only knows under a
+// different name, e.g. because the method was renamed in a newer runtime version.
+type DeprecationWarning struct {
+	// Method is the method the SDK issued.
+	Method string
+	// Field is the name of the renamed response field (if the warning is about a field).
+	Field string
+	// Used is the name the node actually understands.
+	Used string
+
+	// RequestInfo is the request metadata of the query the warning was raised by.
+	RequestInfo
+}
+
+// String returns a string representation of the deprecation warning.
+func (w *DeprecationWarning) String() string {
+	if w.Field != "" {
+		return fmt.Sprintf("%s: node returned field '%s' under its alias '%s'", w.Method, w.Field, w.Used)
+	}
+	return fmt.Sprintf("method '%s' is only known to the node as '%s'", w.Method, w.Used)
+}
+
+// aliasRegistry is the registry of method and field aliases.
+type aliasRegistry struct {
+	sync.RWMutex
+
+	methods map[string]string
+	fields  map[string]map[string]string
+	handler func(*DeprecationWarning)
+}
+
+var aliases = aliasRegistry{
+	methods: make(map[string]string),
+	fields:  make(map[string]map[string]string),
+	handler: logDeprecation,
+}
+
+var deprecationLogger = logging.GetLogger("client-sdk/deprecation")
+
+func logDeprecation(w *DeprecationWarning) {
+	deprecationLogger.Warn("deprecated name in use", append([]interface{}{
+		"method", w.Method,
+		"field", w.Field,
+		"used", w.Used,
+	}, w.LogFields()...)...)
+}
+
+// RegisterMethodAlias registers alias as another name of the given method, e.g. the name the
+// method had before being renamed in a newer runtime version.
+//
+// When the node rejects a method as unknown, queries are transparently retried using its alias
+// and later queries and transactions built with NewTransactionBuilder through the same runtime
+// client use the alias. The already signed transaction that was rejected still fails. Aliases
+// work in both directions.
+//
+// Response fields are renamed only at the top level of the response.
+func RegisterMethodAlias(method, alias string) {
+	aliases.Lock()
+	defer aliases.Unlock()
+
+	aliases.methods[method] = alias
+	aliases.methods[alias] = method
+}
+
+// RegisterFieldAlias registers alias as another name of the given top-level field in responses to
+// the given method. Responses using the alias are rewritten to use the field name before being
+// decoded.
+func RegisterFieldAlias(method, field, alias string) {
+	aliases.Lock()
+	defer aliases.Unlock()
+
+	if aliases.fields[method] == nil {
+		aliases.fields[method] = make(map[string]string)
+	}
+	aliases.fields[method][alias] = field
+}
+
+// SetDeprecationHandler sets the function called whenever an alias is used. By default warnings
+// are logged.
+func SetDeprecationHandler(handler func(*DeprecationWarning)) {
+	aliases.Lock()
+	defer aliases.Unlock()
+
+	aliases.handler = handler
+}
+
+// warn reports the given deprecation warning.
+func (r *aliasRegistry) warn(ctx context.Context, w *DeprecationWarning) {
+	w.RequestInfo = RequestInfoFromContext(ctx)
+
+	r.RLock()
+	handler := r.handler
+	r.RUnlock()
+
+	if handler != nil {
+		handler(w)
+	}
+}
+
+// alias returns the alternative name of the given method. The last return value is false in case
+// the method has no alias.
+func (r *aliasRegistry) alias(method string) (string, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	alias, ok := r.methods[method]
+	return alias, ok
+}
+
+// preferredNames are the names of aliased methods a node was found to understand.
+type preferredNames struct {
+	sync.RWMutex
+
+	names map[string]string
+}
+
+// resolve returns the name that should be used for the given method.
+func (p *preferredNames) resolve(method string) string {
+	p.RLock()
+	defer p.RUnlock()
+
+	if name, ok := p.names[method]; ok {
+		return name
+	}
+	return method
+}
+
+// prefer records that the node understands the given method under the given name.
+func (p *preferredNames) prefer(method, name string) {
+	p.Lock()
+	defer p.Unlock()
+
+	if name == method {
+		delete(p.names, method)
+		return
+	}
+	if p.names == nil {
+		p.names = make(map[string]string)
+	}
+	p.names[method] = name
+}
+
+// MethodResolver is implemented by runtime clients that track the names under which the node
+// knows aliased methods (see RegisterMethodAlias).
+type MethodResolver interface {
+	// ResolveMethod returns the name that should be used for the given method.
+	ResolveMethod(method string) string
+}
+
+// ResolveMethod returns the name that should be used for the given method with the given runtime
+// client. Clients not implementing MethodResolver use the method name as is.
+func ResolveMethod(rc RuntimeClient, method string) string {
+	if mr, ok := rc.(MethodResolver); ok {
+		return mr.ResolveMethod(method)
+	}
+	return method
+}
+
+// Implements MethodResolver.
+func (rc *runtimeClient) ResolveMethod(method string) string {
+	return rc.methods.resolve(method)
+}
+
+// rewriteFields rewrites the aliased fields of the given response to the given method.
+func (r *aliasRegistry) rewriteFields(ctx context.Context, method string, data cbor.RawMessage) (cbor.RawMessage, error) {
+	r.RLock()
+	fields := r.fields[method]
+	r.RUnlock()
+
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	var rsp interface{}
+	if err := cbor.Unmarshal(data, &rsp); err != nil {
+		// Leave malformed responses to the actual decoder.
+		return data, nil //nolint: nilerr
+	}
+	var renamed []*DeprecationWarning
+	rsp = renameFields(rsp, fields, func(alias, field string) {
+		renamed = append(renamed, &DeprecationWarning{Method: method, Field: field, Used: alias})
+	})
+	if len(renamed) == 0 {
+		return data, nil
+	}
+	for _, w := range renamed {
+		r.warn(ctx, w)
+	}
+	return cbor.Marshal(rsp), nil
+}
+
+// renameFields renames the top-level map keys of the generically decoded CBOR value according to
+// fields. Nested values are left untouched as they may legitimately use the aliased names.
+func renameFields(v interface{}, fields map[string]string, onRename func(alias, field string)) interface{} {
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return v
+	}
+	renamed := make(map[interface{}]interface{}, len(m))
+	for key, item := range m {
+		if name, ok := key.(string); ok {
+			if field, aliased := fields[name]; aliased {
+				onRename(name, field)
+				key = field
+			}
+		}
+		renamed[key] = item
+	}
+	return renamed
+}
+
+// isUnknownMethod checks whether the given error is caused by the node not knowing the method.
+func isUnknownMethod(err error) bool {
+	module, code, ok := ErrorCode(err)
+	return ok && module == "core" && code == 3
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// legacyRuntimeClient only knows the given query methods.
+type legacyRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	methods map[string]interface{}
+	calls   []string
+}
+
+func (l *legacyRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	l.calls = append(l.calls, request.Method)
+	rsp, ok := l.methods[request.Method]
+	if !ok {
+		return nil, types.FailedCallResult{Module: "core", Code: 3, Message: "invalid method"}
+	}
+	return &coreClient.QueryResponse{Data: cbor.Marshal(rsp)}, nil
+}
+
+func TestDeprecationAliases(t *testing.T) {
+	require := require.New(t)
+
+	var warnings []*DeprecationWarning
+	SetDeprecationHandler(func(w *DeprecationWarning) {
+		warnings = append(warnings, w)
+	})
+	defer SetDeprecationHandler(logDeprecation)
+
+	RegisterMethodAlias("deprecationtest.MintST", "deprecationtest.Mintst")
+	RegisterFieldAlias("deprecationtest.MintST", "proposal_id", "proposalid")
+
+	cc := &legacyRuntimeClient{
+		methods: map[string]interface{}{
+			"deprecationtest.Mintst": map[string]uint64{"proposalid": 7},
+		},
+	}
+	rc := &runtimeClient{cc: cc}
+
+	type response struct {
+		ProposalID uint64 `json:"proposal_id"`
+	}
+	var rsp response
+	err := rc.Query(WithRequestID(context.Background(), "req-1"), 10, "deprecationtest.MintST", nil, &rsp)
+	require.NoError(err, "queries should fall back to the alias")
+	require.EqualValues(7, rsp.ProposalID, "aliased fields should be renamed")
+	require.Equal([]string{"deprecationtest.MintST", "deprecationtest.Mintst"}, cc.calls)
+	require.Len(warnings, 2)
+	require.Equal("deprecationtest.Mintst", warnings[0].Used)
+	require.Equal("proposal_id", warnings[1].Field)
+	require.Equal("req-1", warnings[0].RequestID, "warnings should carry the request metadata")
+	require.Equal("req-1", warnings[1].RequestID)
+
+	cc.calls = nil
+	err = rc.Query(context.Background(), 10, "deprecationtest.MintST", nil, &rsp)
+	require.NoError(err)
+	require.Equal([]string{"deprecationtest.Mintst"}, cc.calls, "the alias should be preferred afterwards")
+
+	tb := NewTransactionBuilder(rc, "deprecationtest.MintST", nil)
+	require.Equal("deprecationtest.Mintst", tb.GetTransaction().Call.Method)
+
+	cc.calls = nil
+	err = rc.Query(context.Background(), 10, "deprecationtest.Unknown", nil, &rsp)
+	require.Error(err, "methods without aliases should not be retried")
+	require.Len(cc.calls, 1)
+
+	// Other clients should not be affected.
+	current := &legacyRuntimeClient{
+		methods: map[string]interface{}{
+			"deprecationtest.MintST": map[string]interface{}{
+				"proposal_id": 8,
+				"nested":      map[string]uint64{"proposalid": 1},
+			},
+		},
+	}
+	other := &runtimeClient{cc: current}
+	var nested struct {
+		ProposalID uint64            `json:"proposal_id"`
+		Nested     map[string]uint64 `json:"nested"`
+	}
+	err = other.Query(context.Background(), 10, "deprecationtest.MintST", nil, &nested)
+	require.NoError(err)
+	require.EqualValues(8, nested.ProposalID)
+	require.Equal(map[string]uint64{"proposalid": 1}, nested.Nested, "only top-level fields should be renamed")
+	require.Equal([]string{"deprecationtest.MintST"}, current.calls)
+	require.Equal("deprecationtest.MintST", NewTransactionBuilder(other, "deprecationtest.MintST", nil).GetTransaction().Call.Method)
+
+	// Failing aliases should not change the preferred name.
+	cc.methods = nil
+	cc.calls = nil
+	err = rc.Query(context.Background(), 10, "deprecationtest.MintST", nil, &rsp)
+	require.Error(err)
+	require.Equal([]string{"deprecationtest.Mintst", "deprecationtest.MintST"}, cc.calls)
+	require.Equal("deprecationtest.Mintst", ResolveMethod(rc, "deprecationtest.MintST"))
+
+	// Once the node is upgraded, the method name should be preferred again.
+	rc.cc = current
+	current.calls = nil
+	err = rc.Query(context.Background(), 10, "deprecationtest.MintST", nil, &nested)
+	require.NoError(err)
+	require.Equal([]string{"deprecationtest.Mintst", "deprecationtest.MintST"}, current.calls)
+	require.Equal("deprecationtest.MintST", ResolveMethod(rc, "deprecationtest.MintST"))
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// SchemaDrift describes fields in a query response that the response type doesn't know about,
+// e.g. because the runtime was upgraded and started returning additional fields.
+type SchemaDrift struct {
+	// Method is the query method.
+	Method string
+	// Fields are the paths of the unknown fields, e.g. "info.new_field" or "[0].extra".
+	Fields []string
+
+	// RequestInfo is the request metadata of the query.
+	RequestInfo
+}
+
+// String returns a string representation of the schema drift.
+func (d *SchemaDrift) String() string {
+	return fmt.Sprintf("%s: response contains unknown fields: %s", d.Method, strings.Join(d.Fields, ", "))
+}
+
+var (
+	driftLogger = logging.GetLogger("client-sdk/drift")
+
+	// loggedDrift are the method/field pairs that were already logged by logSchemaDrift.
+	loggedDrift sync.Map
+
+	driftMetricsOnce sync.Once
+
+	unknownResponseFields = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_sdk_client_unknown_response_fields",
+			Help: "Number of unknown fields encountered in query responses.",
+		},
+		[]string{"method"},
+	)
+)
+
+// logSchemaDrift logs each unknown field of each method once.
+func logSchemaDrift(d *SchemaDrift) {
+	for _, field := range d.Fields {
+		if _, logged := loggedDrift.LoadOrStore(d.Method+"/"+field, struct{}{}); logged {
+			continue
+		}
+		driftLogger.Warn("query response contains unknown field, the SDK may be outdated", append([]interface{}{
+			"method", d.Method,
+			"field", field,
+		}, d.LogFields()...)...)
+	}
+}
+
+// decodeWithDriftDetection decodes the response of the given query method, reporting any fields
+// unknown to the response type to the given handler. Responses with unknown fields are decoded
+// leniently, ignoring the unknown fields.
+func decodeWithDriftDetection(ctx context.Context, method string, data []byte, rsp interface{}, handler func(*SchemaDrift)) (err error) {
+	var raw interface{}
+	if err = cbor.Unmarshal(data, &raw); err != nil {
+		// Leave malformed responses to the actual decoder.
+		return unmarshalResponse(method, data, rsp)
+	}
+	var fields []string
+	findUnknownFields(raw, reflect.TypeOf(rsp), "", &fields)
+	if len(fields) == 0 {
+		return unmarshalResponse(method, data, rsp)
+	}
+	sort.Strings(fields)
+
+	driftMetricsOnce.Do(func() {
+		prometheus.MustRegister(unknownResponseFields)
+	})
+	unknownResponseFields.WithLabelValues(method).Add(float64(len(fields)))
+	handler(&SchemaDrift{Method: method, Fields: fields, RequestInfo: RequestInfoFromContext(ctx)})
+
+	return unmarshalResponseLenient(method, data, rsp)
+}
+
+// unmarshalResponseLenient is like unmarshalResponse, but ignores unknown fields.
+func unmarshalResponseLenient(method string, data []byte, rsp interface{}) (err error) {
+	defer types.RecoverDecode(&err, method+" response")
+	return cbor.UnmarshalTrusted(data, rsp)
+}
+
+var (
+	cborUnmarshalerType   = reflect.TypeOf((*interface{ UnmarshalCBOR([]byte) error })(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// findUnknownFields appends the paths of all map keys in the generically decoded CBOR value that
+// don't correspond to a field of the given type.
+func findUnknownFields(v interface{}, t reflect.Type, path string, fields *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types with custom decoding have their own notion of which fields are valid.
+	ptr := reflect.PtrTo(t)
+	if ptr.Implements(cborUnmarshalerType) || ptr.Implements(binaryUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		known := structFields(t)
+		for key, item := range m {
+			name, ok := key.(string)
+			if !ok {
+				continue
+			}
+			field, ok := known.lookup(name)
+			if !ok {
+				*fields = append(*fields, joinPath(path, name))
+				continue
+			}
+			findUnknownFields(item, field, joinPath(path, name), fields)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range arr {
+			findUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case reflect.Map:
+		m, ok := v.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		for key, item := range m {
+			findUnknownFields(item, t.Elem(), joinPath(path, fmt.Sprintf("%v", key)), fields)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// knownFields are the CBOR field names of a struct type and the types of the fields.
+type knownFields map[string]reflect.Type
+
+// lookup returns the type of the given field, matching names case-insensitively in case there is
+// no exact match (like the decoder does).
+func (k knownFields) lookup(name string) (reflect.Type, bool) {
+	if t, ok := k[name]; ok {
+		return t, true
+	}
+	for known, t := range k {
+		if strings.EqualFold(known, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// structFields returns the CBOR field names of the given struct type.
+func structFields(t reflect.Type) knownFields {
+	known := make(knownFields)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("cbor")
+		if !ok {
+			tag, ok = f.Tag.Lookup("json")
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && !ok {
+			// Fields of embedded structs are promoted.
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for name, t := range structFields(ft) {
+					if _, exists := known[name]; !exists {
+						known[name] = t
+					}
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		known[name] = f.Type
+	}
+	return known
+}
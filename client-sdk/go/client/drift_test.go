@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSchemaDriftDetection(t *testing.T) {
+	require := require.New(t)
+
+	type info struct {
+		Round uint64 `json:"round"`
+	}
+	type response struct {
+		types.EventMeta `cbor:"-"`
+
+		Name    string           `json:"name"`
+		Info    *info            `json:"info"`
+		Entries []info           `json:"entries"`
+		ByKey   map[string]*info `json:"by_key"`
+	}
+	cc := &legacyRuntimeClient{
+		methods: map[string]interface{}{
+			"drifttest.Known": map[string]interface{}{
+				"name": "a",
+				"info": map[string]uint64{"round": 1},
+			},
+			"drifttest.Drifted": map[string]interface{}{
+				"name":    "b",
+				"version": 2,
+				"info":    map[string]uint64{"round": 1, "epoch": 3},
+				"entries": []map[string]uint64{{"round": 1}, {"round": 2, "extra": 1}},
+				"by_key":  map[string]interface{}{"x": map[string]uint64{"other": 1}},
+			},
+		},
+	}
+
+	var rsp response
+	rc := &runtimeClient{cc: cc}
+	err := rc.Query(context.Background(), 10, "drifttest.Drifted", nil, &rsp)
+	require.Error(err, "unknown fields should fail decoding by default")
+
+	var drifts []*SchemaDrift
+	opts := &options{}
+	WithSchemaDriftDetection(func(d *SchemaDrift) {
+		drifts = append(drifts, d)
+	})(opts)
+	rc = &runtimeClient{cc: cc, opts: *opts}
+
+	err = rc.Query(context.Background(), 10, "drifttest.Known", nil, &rsp)
+	require.NoError(err)
+	require.Empty(drifts, "responses without unknown fields should not be reported")
+
+	rsp = response{}
+	err = rc.Query(WithCaller(context.Background(), "tenant-a"), 10, "drifttest.Drifted", nil, &rsp)
+	require.NoError(err, "unknown fields should be ignored when drift detection is enabled")
+	require.Equal("b", rsp.Name)
+	require.EqualValues(1, rsp.Info.Round)
+	require.Len(rsp.Entries, 2)
+	require.Len(drifts, 1)
+	require.Equal("drifttest.Drifted", drifts[0].Method)
+	require.Equal(RequestInfo{Caller: "tenant-a"}, drifts[0].RequestInfo)
+	require.Equal([]string{"by_key.x.other", "entries[1].extra", "info.epoch", "version"}, drifts[0].Fields)
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrTransient is the class of errors which may succeed when retried later, e.g. because the
+	// node is temporarily unavailable or not yet synced.
+	ErrTransient = errors.New("transient error")
+	// ErrPermanent is the class of errors which will fail again when retried.
+	ErrPermanent = errors.New("permanent error")
+	// ErrAuth is the class of errors caused by the caller not being authorized to perform the
+	// operation. It is a subclass of ErrPermanent.
+	ErrAuth = errors.New("not authorized")
+	// ErrNotFound is the class of errors caused by the requested item not existing. It is a
+	// subclass of ErrPermanent.
+	ErrNotFound = errors.New("not found")
+)
+
+// classifiedError is an error annotated with its class.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+// NewClassifiedError returns a new error with the given message, annotated with the given class
+// (one of ErrTransient, ErrPermanent, ErrAuth or ErrNotFound). It is meant for sentinel errors of
+// modules, so that callers can also check their class with errors.Is.
+func NewClassifiedError(class error, msg string) error {
+	return &classifiedError{class: class, err: errors.New(msg)}
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+func (e *classifiedError) Is(target error) bool {
+	switch target {
+	case e.class:
+		return true
+	case ErrPermanent:
+		return e.class == ErrAuth || e.class == ErrNotFound
+	default:
+		return false
+	}
+}
+
+// errorCodeKey identifies a module-specific error.
+type errorCodeKey struct {
+	module string
+	code   uint32
+}
+
+// errorCodeClasses are the classes of known module-specific errors. Errors not listed here are
+// considered permanent unless their class is set when registering the module (see RegisterModule).
+var errorCodeClasses = map[errorCodeKey]error{
+	// Runtime SDK core module.
+	{"core", 4}:  ErrTransient, // Invalid nonce, most likely due to a concurrent submission.
+	{"core", 26}: ErrTransient, // Future nonce.
+	// Runtime SDK accounts module.
+	{"accounts", 3}: ErrAuth,     // Forbidden.
+	{"accounts", 4}: ErrNotFound, // Not found.
+	{"accounts", 5}: ErrAuth,     // Invalid role.
+	// Oasis Core runtime client.
+	{coreClient.ModuleName, 1}: ErrNotFound,  // Not found.
+	{coreClient.ModuleName, 3}: ErrTransient, // Transaction expired.
+	{coreClient.ModuleName, 4}: ErrTransient, // Not synced.
+	{coreClient.ModuleName, 6}: ErrTransient, // No hosted runtime.
+	// Oasis Core roothash.
+	{roothash.ModuleName, 2}: ErrNotFound, // Block not found.
+}
+
+// grpcError is the error detail attached by Oasis nodes to gRPC errors.
+type grpcError struct {
+	Module string `json:"module,omitempty"`
+	Code   uint32 `json:"code,omitempty"`
+}
+
+// ErrorCode returns the module name and error code of a module-specific error, e.g. a failed call
+// result or an error returned by the node. The last return value is false in case the error does
+// not carry a module-specific error code.
+func ErrorCode(err error) (string, uint32, bool) {
+	var failedPtr *types.FailedCallResult
+	if errors.As(err, &failedPtr) && failedPtr != nil {
+		return failedPtr.Module, failedPtr.Code, true
+	}
+	var failed types.FailedCallResult
+	if errors.As(err, &failed) {
+		return failed.Module, failed.Code, true
+	}
+	if module, code := coreErrors.Code(err); module != coreErrors.UnknownModule {
+		return module, code, true
+	}
+	if s, ok := status.FromError(err); ok {
+		if details := s.Proto().GetDetails(); len(details) == 1 {
+			var ge grpcError
+			if cbor.Unmarshal(details[0].GetValue(), &ge) == nil && ge.Module != "" {
+				return ge.Module, ge.Code, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// classOf determines the class of the given error. Returns nil in case the error should not be
+// classified (e.g. because it was caused by the caller cancelling the operation).
+func classOf(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTransient
+	}
+
+	if module, code, ok := ErrorCode(err); ok {
+		if class, known := errorCodeClasses[errorCodeKey{module, code}]; known {
+			return class
+		}
+		if me, known := LookupError(module, code); known && me.Class != nil {
+			return me.Class
+		}
+		return ErrPermanent
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Canceled:
+			return nil
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return ErrTransient
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return ErrAuth
+		case codes.NotFound:
+			return ErrNotFound
+		}
+	}
+	return ErrPermanent
+}
+
+// Classify annotates the given error with its class so that errors.Is can be used to check
+// whether it is one of ErrTransient, ErrPermanent, ErrAuth or ErrNotFound.
+//
+// All errors returned by the runtime client are already classified, so this is only needed for
+// errors obtained from elsewhere.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *classifiedError
+	if errors.As(err, &ce) {
+		return err
+	}
+	class := classOf(err)
+	if class == nil {
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// IsTransient checks whether the given error is transient and the operation may be retried.
+func IsTransient(err error) bool {
+	return errors.Is(Classify(err), ErrTransient)
+}
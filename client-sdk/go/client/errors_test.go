@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestClassify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(Classify(nil))
+
+	for _, tc := range []struct {
+		err   error
+		class error
+	}{
+		{status.Error(codes.Unavailable, "unavailable"), ErrTransient},
+		{status.Error(codes.PermissionDenied, "denied"), ErrAuth},
+		{status.Error(codes.NotFound, "not found"), ErrNotFound},
+		{status.Error(codes.InvalidArgument, "bad"), ErrPermanent},
+		{context.DeadlineExceeded, ErrTransient},
+		{&types.FailedCallResult{Module: "core", Code: 4}, ErrTransient},
+		{types.FailedCallResult{Module: "accounts", Code: 3}, ErrAuth},
+		{&types.FailedCallResult{Module: "accounts", Code: 4}, ErrNotFound},
+		{&types.FailedCallResult{Module: "accounts", Code: 2}, ErrPermanent},
+		{fmt.Errorf("wrapped: %w", roothash.ErrNotFound), ErrNotFound},
+		{errors.New("something else"), ErrPermanent},
+	} {
+		err := Classify(tc.err)
+		require.ErrorIs(err, tc.class, "%v", tc.err)
+		require.ErrorIs(err, tc.err, "classification should preserve the original error")
+		require.Equal(tc.err.Error(), err.Error())
+		require.Equal(tc.class == ErrTransient, IsTransient(err))
+		if tc.class != ErrTransient {
+			require.ErrorIs(err, ErrPermanent, "%v", tc.err)
+		}
+	}
+
+	// Cancellation is not classified.
+	require.Equal(context.Canceled, Classify(context.Canceled))
+
+	// Classification is idempotent.
+	err := Classify(status.Error(codes.Unavailable, "unavailable"))
+	require.Equal(err, Classify(err))
+
+	// Sentinel errors can be classified upfront.
+	sentinel := NewClassifiedError(ErrNotFound, "thing not found")
+	err = fmt.Errorf("wrapped: %w", sentinel)
+	require.ErrorIs(err, sentinel)
+	require.ErrorIs(err, ErrNotFound)
+	require.ErrorIs(err, ErrPermanent)
+	require.Equal(err, Classify(err))
+	require.Equal("thing not found", sentinel.Error())
+
+	module, code, ok := ErrorCode(Classify(&types.FailedCallResult{Module: "accounts", Code: 10}))
+	require.True(ok)
+	require.Equal("accounts", module)
+	require.EqualValues(10, code)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LimitKind is the kind of data subject to a hard limit.
+type LimitKind string
+
+const (
+	// LimitEventsPerBlock limits the number of events processed per block.
+	LimitEventsPerBlock LimitKind = "events_per_block"
+	// LimitProposalVoters limits the number of voters decoded per proposal.
+	LimitProposalVoters LimitKind = "proposal_voters"
+	// LimitAddresses limits the number of addresses decoded per response.
+	LimitAddresses LimitKind = "addresses"
+)
+
+// Limits are hard limits on the amount of on-chain data decoded by the SDK, protecting the memory
+// usage of services from pathological on-chain data. Data exceeding a limit is truncated and a
+// TruncationNotice is reported. Zero values mean no limit.
+type Limits struct {
+	// MaxEventsPerBlock is the maximum number of events processed per block. Events beyond the
+	// limit are dropped by the event queries of the runtime client.
+	MaxEventsPerBlock uint64 `json:"max_events_per_block,omitempty"`
+	// MaxProposalVoters is the maximum number of voters decoded per accounts proposal.
+	MaxProposalVoters uint64 `json:"max_proposal_voters,omitempty"`
+	// MaxAddresses is the maximum number of addresses decoded per accounts Addresses and RolesTeam
+	// response. When unset, responses with more than accounts.DefaultMaxAddresses addresses are
+	// rejected instead.
+	MaxAddresses uint64 `json:"max_addresses,omitempty"`
+
+	// OnTruncation is called for each truncation, in addition to the truncation being counted in
+	// the oasis_sdk_client_truncations metric (see NewLimiter).
+	OnTruncation func(*TruncationNotice) `json:"-"`
+}
+
+// TruncationNotice reports data that was truncated because it exceeded a limit.
+type TruncationNotice struct {
+	// Kind is the kind of the exceeded limit.
+	Kind LimitKind
+	// Limit is the exceeded limit.
+	Limit uint64
+	// Total is the number of items before truncation.
+	Total uint64
+	// Round is the round of the truncated data.
+	Round uint64
+	// Source identifies the truncated data, e.g. the query method.
+	Source string
+}
+
+// String returns a string representation of the notice.
+func (n *TruncationNotice) String() string {
+	return fmt.Sprintf("%s: truncated %d %s to %d in round %d", n.Source, n.Total, n.Kind, n.Limit, n.Round)
+}
+
+// Limiter enforces limits on behalf of runtime clients (see WithLimits) and the module helpers
+// using them.
+//
+// A nil limiter enforces no limits.
+type Limiter struct {
+	cfg         Limits
+	truncations *prometheus.CounterVec
+}
+
+// NewLimiter creates a new limiter enforcing the given limits.
+//
+// In case a registerer is given, truncations are counted in the oasis_sdk_client_truncations
+// metric registered with it.
+func NewLimiter(cfg Limits, reg prometheus.Registerer) *Limiter {
+	l := &Limiter{cfg: cfg}
+	if reg != nil {
+		l.truncations = registerCounterVec(reg, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "oasis_sdk_client_truncations",
+				Help: "Number of responses truncated due to configured limits.",
+			},
+			[]string{"kind"},
+		))
+	}
+	return l
+}
+
+// Limits returns the enforced limits.
+func (l *Limiter) Limits() Limits {
+	if l == nil {
+		return Limits{}
+	}
+	return l.cfg
+}
+
+// ReportTruncation reports the given truncation. It is used by module helpers enforcing the
+// limits.
+func (l *Limiter) ReportTruncation(n *TruncationNotice) {
+	if l == nil {
+		return
+	}
+	if l.truncations != nil {
+		l.truncations.WithLabelValues(string(n.Kind)).Inc()
+	}
+	if fn := l.cfg.OnTruncation; fn != nil {
+		fn(n)
+	}
+}
+
+// LimiterProvider is implemented by runtime clients enforcing limits. Clients wrapping a runtime
+// client should implement it by forwarding to LimiterOf.
+type LimiterProvider interface {
+	// Limiter returns the limiter of the runtime client.
+	Limiter() *Limiter
+}
+
+// LimiterOf returns the limiter of the given runtime client. Clients not implementing
+// LimiterProvider enforce no limits.
+func LimiterOf(rc RuntimeClient) *Limiter {
+	if lp, ok := rc.(LimiterProvider); ok {
+		return lp.Limiter()
+	}
+	return nil
+}
+
+// Implements LimiterProvider.
+func (rc *runtimeClient) Limiter() *Limiter {
+	return rc.opts.limiter
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLimits(t *testing.T) {
+	require := require.New(t)
+
+	var notices []*TruncationNotice
+	reg := prometheus.NewRegistry()
+	limiter := NewLimiter(Limits{
+		MaxEventsPerBlock: 4,
+		OnTruncation:      func(n *TruncationNotice) { notices = append(notices, n) },
+	}, reg)
+
+	unlimited := &runtimeClient{cc: &manyEventsRuntimeClient{count: 10}}
+	evs, err := unlimited.GetEventsRaw(context.Background(), 7)
+	require.NoError(err, "GetEventsRaw")
+	require.Len(evs, 10, "clients without limits should not be affected")
+
+	rc := &runtimeClient{cc: &manyEventsRuntimeClient{count: 10}}
+	WithLimits(limiter)(&rc.opts)
+	require.Equal(limiter, LimiterOf(rc))
+	evs, err = rc.GetEventsRaw(context.Background(), 7)
+	require.NoError(err, "GetEventsRaw")
+	require.Len(evs, 4, "events beyond the limit should be dropped")
+	require.Equal([]*TruncationNotice{{
+		Kind:   LimitEventsPerBlock,
+		Limit:  4,
+		Total:  10,
+		Round:  7,
+		Source: "GetEvents",
+	}}, notices)
+
+	rc = &runtimeClient{cc: &manyEventsRuntimeClient{count: 3}, opts: options{limiter: limiter}}
+	evs, err = rc.GetEventsRaw(context.Background(), 7)
+	require.NoError(err, "GetEventsRaw")
+	require.Len(evs, 3)
+	require.Len(notices, 1, "blocks within the limit should not be reported")
+
+	families, err := reg.Gather()
+	require.NoError(err, "Gather")
+	require.Len(families, 1)
+	require.Equal("oasis_sdk_client_truncations", families[0].GetName())
+	require.EqualValues(1, families[0].GetMetric()[0].GetCounter().GetValue())
+	require.NotPanics(func() { NewLimiter(Limits{}, reg) }, "limiters should share the metric")
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memo memoizes a rarely changing value (e.g. module parameters) for a limited time.
+//
+// Concurrent callers share a single fetch of the value. Callers waiting for a fetch started by
+// another caller give up as soon as their own context is done.
+type Memo[T any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	l       sync.Mutex
+	value   T
+	valid   bool
+	expires time.Time
+	// pending is closed when the in-flight fetch (if any) completes.
+	pending chan struct{}
+	// generation is incremented by Invalidate, so that fetches started before are not stored.
+	generation uint64
+}
+
+// NewMemo creates a new memo keeping values for the given time. A zero TTL keeps values until
+// they are invalidated.
+func NewMemo[T any](ttl time.Duration) *Memo[T] {
+	return &Memo[T]{
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Get returns the memoized value, calling fetch in case there is none or it expired. Errors are
+// not memoized.
+func (m *Memo[T]) Get(ctx context.Context, fetch func(context.Context) (T, error)) (T, error) {
+	for {
+		m.l.Lock()
+		if m.valid && (m.ttl == 0 || m.now().Before(m.expires)) {
+			value := m.value
+			m.l.Unlock()
+			return value, nil
+		}
+		if m.pending == nil {
+			break
+		}
+		pending := m.pending
+		m.l.Unlock()
+
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+
+	// Fetch the value while holding the pending slot.
+	pending := make(chan struct{})
+	m.pending = pending
+	generation := m.generation
+	m.l.Unlock()
+
+	value, err := fetch(ctx)
+
+	m.l.Lock()
+	defer m.l.Unlock()
+	if err == nil && generation == m.generation {
+		m.value = value
+		m.valid = true
+		m.expires = m.now().Add(m.ttl)
+	}
+	m.pending = nil
+	close(pending)
+	return value, err
+}
+
+// Invalidate drops the memoized value, so that the next Get fetches it again.
+func (m *Memo[T]) Invalidate() {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var zero T
+	m.value = zero
+	m.valid = false
+	m.generation++
+}
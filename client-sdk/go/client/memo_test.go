@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemo(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	m := NewMemo[int](time.Minute)
+	m.now = func() time.Time { return now }
+
+	var fetches int32
+	fetch := func(ctx context.Context) (int, error) {
+		return int(atomic.AddInt32(&fetches, 1)), nil
+	}
+
+	v, err := m.Get(ctx, fetch)
+	require.NoError(err, "Get")
+	require.Equal(1, v)
+	v, _ = m.Get(ctx, fetch)
+	require.Equal(1, v, "values should be memoized")
+
+	now = now.Add(time.Minute)
+	v, _ = m.Get(ctx, fetch)
+	require.Equal(2, v, "expired values should be fetched again")
+
+	m.Invalidate()
+	v, _ = m.Get(ctx, fetch)
+	require.Equal(3, v, "invalidated values should be fetched again")
+
+	m.Invalidate()
+	errFailed := errors.New("failed")
+	_, err = m.Get(ctx, func(ctx context.Context) (int, error) { return 0, errFailed })
+	require.ErrorIs(err, errFailed)
+	v, _ = m.Get(ctx, fetch)
+	require.Equal(4, v, "errors should not be memoized")
+
+	// Concurrent callers share a single fetch and waiting callers honor their context.
+	m.Invalidate()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return fetch(ctx)
+	}
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = m.Get(ctx, slow)
+	}()
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = m.Get(ctx, fetch)
+		}(i)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = m.Get(cancelled, fetch)
+	require.ErrorIs(err, context.Canceled, "waiting callers should honor their context")
+
+	close(release)
+	wg.Wait()
+	require.Equal([]int{5, 5, 5, 5, 5}, results)
+	require.EqualValues(5, atomic.LoadInt32(&fetches))
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ResultMeta is the metadata about the runtime state a query result was computed at.
+//
+// A ResultMeta may be shared by concurrent queries, e.g. when passed to helpers issuing multiple
+// queries. In that case it records the highest round any of the queries was computed at, so a
+// fresh ResultMeta should be used for each operation.
+type ResultMeta struct {
+	// Round is the runtime round the result was computed at.
+	Round uint64
+	// Timestamp is the timestamp of the runtime block at Round.
+	Timestamp time.Time
+
+	mu sync.Mutex
+}
+
+// record records the given block round and timestamp unless a higher round was already recorded.
+func (m *ResultMeta) record(round uint64, timestamp time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if round < m.Round {
+		return
+	}
+	m.Round = round
+	m.Timestamp = timestamp
+}
+
+type resultMetaKey struct{}
+
+// WithResultMeta returns a derived context that makes queries issued with it record the round and
+// block timestamp their result was computed at into the given ResultMeta.
+//
+// Since this works on the RuntimeClient level, it can be used with all module query helpers, e.g.:
+//
+//	var meta client.ResultMeta
+//	balances, err := rc.Accounts.Balances(client.WithResultMeta(ctx, &meta), client.RoundLatest, addr)
+//
+// Note that requesting metadata requires an additional block lookup per query, as the query is
+// pinned to a concrete round before being executed.
+func WithResultMeta(ctx context.Context, meta *ResultMeta) context.Context {
+	return context.WithValue(ctx, resultMetaKey{}, meta)
+}
+
+// resultMetaFromContext returns the ResultMeta that should be populated, if any.
+func resultMetaFromContext(ctx context.Context) *ResultMeta {
+	meta, _ := ctx.Value(resultMetaKey{}).(*ResultMeta)
+	return meta
+}
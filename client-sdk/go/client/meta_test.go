@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// metaRuntimeClient serves blocks up to the latest round and records the rounds of queries.
+type metaRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	mu      sync.Mutex
+	latest  uint64
+	blocks  int
+	queries []uint64
+}
+
+func (m *metaRuntimeClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blocks++
+	round := request.Round
+	if round == coreClient.RoundLatest {
+		round = m.latest
+	}
+	var blk block.Block
+	blk.Header.Round = round
+	blk.Header.Timestamp = block.Timestamp(1_000_000 + round)
+	return &blk, nil
+}
+
+func (m *metaRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queries = append(m.queries, request.Round)
+	return &coreClient.QueryResponse{Data: cbor.Marshal(request.Round)}, nil
+}
+
+func TestResultMeta(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	node := &metaRuntimeClient{latest: 42}
+	rc := &runtimeClient{cc: node}
+
+	var meta ResultMeta
+	data, err := rc.QueryRaw(WithResultMeta(ctx, &meta), RoundLatest, "accounts.Parameters", nil)
+	require.NoError(err)
+	require.EqualValues(42, meta.Round)
+	require.Equal(time.Unix(1_000_042, 0), meta.Timestamp)
+	require.Equal([]uint64{42}, node.queries, "query should be pinned to the round of the metadata")
+	require.Equal(cbor.Marshal(uint64(42)), []byte(data))
+
+	meta = ResultMeta{}
+	_, err = rc.QueryRaw(WithResultMeta(ctx, &meta), 10, "accounts.Parameters", nil)
+	require.NoError(err)
+	require.EqualValues(10, meta.Round)
+	require.Equal(time.Unix(1_000_010, 0), meta.Timestamp)
+	require.EqualValues(10, node.queries[1])
+
+	// Metadata shared by concurrent queries should record the highest round.
+	meta = ResultMeta{}
+	var wg sync.WaitGroup
+	for _, round := range []uint64{20, 30, 10} {
+		wg.Add(1)
+		go func(round uint64) {
+			defer wg.Done()
+			_, qerr := rc.QueryRaw(WithResultMeta(ctx, &meta), round, "accounts.Parameters", nil)
+			require.NoError(qerr)
+		}(round)
+	}
+	wg.Wait()
+	require.EqualValues(30, meta.Round)
+	require.Equal(time.Unix(1_000_030, 0), meta.Timestamp)
+
+	// Without a ResultMeta, queries should not require a block lookup.
+	node.blocks = 0
+	_, err = rc.QueryRaw(ctx, RoundLatest, "accounts.Parameters", nil)
+	require.NoError(err)
+	require.Zero(node.blocks)
+	require.EqualValues(RoundLatest, node.queries[len(node.queries)-1])
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerCounterVec registers the given counter vector with the given registerer and returns it.
+// In case an identical counter vector is already registered (e.g. by another client sharing the
+// registerer), the existing one is returned instead.
+func registerCounterVec(reg prometheus.Registerer, cv *prometheus.CounterVec) *prometheus.CounterVec {
+	err := reg.Register(cv)
+	if err == nil {
+		return cv
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+	panic(err)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// ErrStaleRead is the error returned when monotonic reads are enabled and the node serving a
+// request is behind a round already observed by the client. It is a transient error.
+var ErrStaleRead = errors.New("stale read")
+
+// roundTracker tracks the highest round observed by a client.
+type roundTracker struct {
+	highest uint64
+}
+
+// observe records the given round as observed.
+func (t *roundTracker) observe(round uint64) {
+	for {
+		highest := atomic.LoadUint64(&t.highest)
+		if round <= highest || atomic.CompareAndSwapUint64(&t.highest, highest, round) {
+			return
+		}
+	}
+}
+
+// check returns an error in case the given round is lower than the highest observed round and
+// records the round as observed otherwise.
+func (t *roundTracker) check(round uint64) error {
+	if highest := atomic.LoadUint64(&t.highest); round < highest {
+		return &classifiedError{
+			class: ErrTransient,
+			err:   fmt.Errorf("%w: node is at round %d but round %d was already observed", ErrStaleRead, round, highest),
+		}
+	}
+	t.observe(round)
+	return nil
+}
+
+type pinnedNodeKey struct{}
+
+// resolveLatestMonotonic resolves RoundLatest into the latest round of the node serving the
+// request, retrying with other nodes in case the node is behind a round already observed.
+//
+// The returned context pins operations to the node that resolved the round, so that they are not
+// routed to another node which may not have reached the round yet.
+func (rc *runtimeClient) resolveLatestMonotonic(ctx context.Context) (context.Context, uint64, error) {
+	var err error
+	for attempt := 0; attempt < rc.opts.monotonicAttempts; attempt++ {
+		cc := rc.node(ctx, PriorityInteractive)
+		blk, blkErr := rc.wrap(cc).GetBlock(ctx, &coreClient.GetBlockRequest{
+			RuntimeID: rc.runtimeID,
+			Round:     RoundLatest,
+		})
+		if blkErr != nil {
+			return ctx, 0, blkErr
+		}
+		if err = rc.rounds.check(blk.Header.Round); err == nil {
+			return context.WithValue(ctx, pinnedNodeKey{}, cc), blk.Header.Round, nil
+		}
+	}
+	return ctx, 0, err
+}
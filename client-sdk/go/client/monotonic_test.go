@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// laggingRuntimeClient simulates load-balanced nodes by returning the given latest rounds in turn.
+type laggingRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	rounds []uint64
+}
+
+func (l *laggingRuntimeClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = l.rounds[0]
+	l.rounds = l.rounds[1:]
+	return &blk, nil
+}
+
+func TestMonotonicReads(t *testing.T) {
+	require := require.New(t)
+
+	cc := &laggingRuntimeClient{rounds: []uint64{10, 12, 11, 13, 9, 8}}
+	rc := &runtimeClient{cc: cc, opts: options{monotonicAttempts: 2}}
+	ctx := context.Background()
+
+	_, round, err := rc.resolveRound(ctx, RoundLatest)
+	require.NoError(err)
+	require.EqualValues(10, round)
+	_, round, err = rc.resolveRound(ctx, RoundLatest)
+	require.NoError(err)
+	require.EqualValues(12, round)
+
+	pinnedCtx, round, err := rc.resolveRound(ctx, RoundLatest)
+	require.NoError(err)
+	require.EqualValues(13, round, "stale nodes should be retried")
+
+	_, _, err = rc.resolveRound(ctx, RoundLatest)
+	require.ErrorIs(err, ErrStaleRead)
+	require.True(IsTransient(err))
+	require.False(errors.Is(err, ErrPermanent))
+
+	_, round, err = rc.resolveRound(ctx, 5)
+	require.NoError(err)
+	require.EqualValues(5, round, "explicit rounds should not be affected")
+
+	// Operations at the resolved round should go to the node that resolved it.
+	other := &laggingRuntimeClient{rounds: []uint64{11}}
+	rc.cc = other
+	require.Equal(cc, rc.node(pinnedCtx, PriorityInteractive))
+	require.Equal(other, rc.node(pinnedCtx, PrioritySubmit), "submissions should not be pinned")
+	require.Equal(other, rc.node(ctx, PriorityInteractive))
+
+	cc.rounds = []uint64{13}
+	blk, err := rc.runtime(pinnedCtx, PriorityInteractive).GetBlock(pinnedCtx, &coreClient.GetBlockRequest{Round: 13})
+	require.NoError(err)
+	require.EqualValues(13, blk.Header.Round)
+	require.Len(other.rounds, 1, "the other node should not be used")
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// NodeSet splits client operations between a set of write nodes, which receive all transaction
+// submissions, and a pool of read nodes, which serve all other operations.
+//
+// Read nodes are used round-robin. Once the set has been refreshed (see Refresh and Watch), read
+// nodes lagging more than the configured number of rounds behind the most recent one, or which
+// could not be reached, are skipped. When no read node is eligible, the write nodes are used.
+type NodeSet struct {
+	write []*grpc.ClientConn
+	read  []*grpc.ClientConn
+
+	maxLag uint64
+
+	nextWrite uint32
+	nextRead  uint32
+
+	lock     sync.RWMutex
+	eligible []*grpc.ClientConn
+}
+
+// NewNodeSet creates a new node set from the given write and read node connections. Read nodes
+// more than maxLag rounds behind the most recent read node are skipped.
+func NewNodeSet(write, read []*grpc.ClientConn, maxLag uint64) (*NodeSet, error) {
+	if len(write) == 0 {
+		return nil, fmt.Errorf("nodeset: at least one write node is required")
+	}
+	return &NodeSet{
+		write:    write,
+		read:     read,
+		maxLag:   maxLag,
+		eligible: read,
+	}, nil
+}
+
+// Conn returns a connection to be used for operations of the given priority class. Transaction
+// submissions go to the write nodes while everything else goes to eligible read nodes.
+func (ns *NodeSet) Conn(prio Priority) *grpc.ClientConn {
+	if prio != PrioritySubmit {
+		ns.lock.RLock()
+		eligible := ns.eligible
+		ns.lock.RUnlock()
+
+		if len(eligible) > 0 {
+			idx := atomic.AddUint32(&ns.nextRead, 1)
+			return eligible[int(idx)%len(eligible)]
+		}
+	}
+
+	idx := atomic.AddUint32(&ns.nextWrite, 1)
+	return ns.write[int(idx)%len(ns.write)]
+}
+
+// Refresh fetches the latest round of the given runtime from all read nodes and updates the set
+// of eligible read nodes.
+func (ns *NodeSet) Refresh(ctx context.Context, runtimeID common.Namespace) {
+	rounds := make([]*uint64, len(ns.read))
+	var wg sync.WaitGroup
+	for i, conn := range ns.read {
+		wg.Add(1)
+		go func(i int, conn *grpc.ClientConn) {
+			defer wg.Done()
+
+			blk, err := coreClient.NewRuntimeClient(conn).GetBlock(ctx, &coreClient.GetBlockRequest{
+				RuntimeID: runtimeID,
+				Round:     coreClient.RoundLatest,
+			})
+			if err != nil {
+				return
+			}
+			rounds[i] = &blk.Header.Round
+		}(i, conn)
+	}
+	wg.Wait()
+
+	ns.update(rounds)
+}
+
+// update updates the set of eligible read nodes given the latest round of each read node, where
+// nil means that the node could not be reached.
+func (ns *NodeSet) update(rounds []*uint64) {
+	var highest uint64
+	for _, round := range rounds {
+		if round != nil && *round > highest {
+			highest = *round
+		}
+	}
+
+	var eligible []*grpc.ClientConn
+	for i, round := range rounds {
+		if round != nil && *round+ns.maxLag >= highest {
+			eligible = append(eligible, ns.read[i])
+		}
+	}
+
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+	ns.eligible = eligible
+}
+
+// Watch refreshes the node set every interval until the context is cancelled.
+func (ns *NodeSet) Watch(ctx context.Context, runtimeID common.Namespace, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ns.Refresh(ctx, runtimeID)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestNodeSet(t *testing.T) {
+	require := require.New(t)
+
+	dial := func() *grpc.ClientConn {
+		conn, err := grpc.Dial("passthrough:///test", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(err)
+		return conn
+	}
+	write := dial()
+	read := []*grpc.ClientConn{dial(), dial(), dial()}
+	defer func() {
+		_ = write.Close()
+		for _, conn := range read {
+			_ = conn.Close()
+		}
+	}()
+
+	_, err := NewNodeSet(nil, read, 5)
+	require.Error(err, "node sets without write nodes should be rejected")
+
+	ns, err := NewNodeSet([]*grpc.ClientConn{write}, read, 5)
+	require.NoError(err)
+
+	require.Equal(write, ns.Conn(PrioritySubmit), "submissions should go to write nodes")
+	seen := make(map[*grpc.ClientConn]bool)
+	for i := 0; i < len(read); i++ {
+		seen[ns.Conn(PriorityInteractive)] = true
+	}
+	require.Len(seen, len(read), "read nodes should be used round-robin")
+	require.False(seen[write])
+
+	round := func(r uint64) *uint64 { return &r }
+	ns.update([]*uint64{round(100), round(94), nil})
+	for i := 0; i < len(read); i++ {
+		require.Equal(read[0], ns.Conn(PriorityBackfill), "lagging and unreachable nodes should be skipped")
+	}
+
+	ns.update([]*uint64{nil, nil, nil})
+	require.Equal(write, ns.Conn(PriorityInteractive), "write nodes should be used when no read node is eligible")
+}
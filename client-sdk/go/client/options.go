@@ -0,0 +1,172 @@
+package client
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// watchMinBackoff is the delay before the first resubscription of a resumed watch.
+const watchMinBackoff = 100 * time.Millisecond
+
+// Option is a runtime client option.
+type Option func(*options)
+
+type options struct {
+	dedupQueries bool
+	pool         *ConnPool
+	nodes        *NodeSet
+	timeouts     Timeouts
+	txIndices    bool
+	drift        func(*SchemaDrift)
+	quotas       *Quotas
+	limiter      *Limiter
+	archive      *archiveNodes
+	codec        CodecConfig
+
+	watchMaxBackoff time.Duration
+
+	monotonicAttempts int
+}
+
+// WithQueryDeduplication enables coalescing of identical concurrent queries.
+//
+// When enabled, queries with the same method, round and arguments that are issued while an
+// identical query is already in flight wait for and share its response instead of hitting the
+// node again. This is useful to prevent thundering herds from e.g. dashboards that refresh many
+// widgets issuing the same Parameters queries.
+//
+// The shared request is detached from the callers' contexts, so that it is not affected by any
+// caller giving up, and is subject to the default query timeout (see WithDefaultTimeouts) or 30
+// seconds if there is none. Each caller stops waiting when its own context is done.
+func WithQueryDeduplication() Option {
+	return func(o *options) {
+		o.dedupQueries = true
+	}
+}
+
+// WithConnPool makes the client dispatch operations over the given connection pool based on their
+// priority class (see WithPriority). The connection passed to New is still used for consensus
+// layer operations and as a fallback.
+func WithConnPool(pool *ConnPool) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// WithNodeSet makes the client route transaction submissions to the write nodes and all other
+// operations to the read nodes of the given node set. It takes precedence over WithConnPool. The
+// connection passed to New is still used for consensus layer operations.
+//
+// Use NodeSet.Watch to keep lagging read nodes out of rotation.
+func WithNodeSet(nodes *NodeSet) Option {
+	return func(o *options) {
+		o.nodes = nodes
+	}
+}
+
+// WithMonotonicReads guarantees that the rounds operations on the latest round are served at never
+// go backwards, e.g. when load-balanced nodes disagree on the latest round.
+//
+// When enabled, operations on RoundLatest are pinned to the latest round of the node serving them.
+// In case that round is lower than the highest round already observed by the client, the lookup
+// is retried (up to the given number of attempts in total) with the next node and finally fails
+// with ErrStaleRead.
+func WithMonotonicReads(attempts int) Option {
+	return func(o *options) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		o.monotonicAttempts = attempts
+	}
+}
+
+// WithDefaultTimeouts configures the default timeouts of the different operation classes which are
+// applied when the caller passes a context without a deadline.
+//
+// Operations that time out return a *TimeoutError identifying the stalled method and round.
+func WithDefaultTimeouts(timeouts Timeouts) Option {
+	return func(o *options) {
+		o.timeouts = timeouts
+	}
+}
+
+// WithTxIndices makes the client resolve the index of the transaction that emitted each event
+// returned by the event queries (e.g. GetEvents), so that decoded events can be linked to their
+// transaction via the event metadata.
+//
+// Resolving the indices requires fetching the transactions of every round that contains events
+// emitted by transactions.
+func WithTxIndices() Option {
+	return func(o *options) {
+		o.txIndices = true
+	}
+}
+
+// WithSchemaDriftDetection makes the client check query responses for fields unknown to the
+// response types, e.g. because the runtime was upgraded and the SDK is outdated.
+//
+// Unknown fields are counted in the oasis_sdk_client_unknown_response_fields metric and reported
+// to the given handler (each unknown field is logged once in case the handler is nil). Responses
+// with unknown fields are then decoded ignoring the unknown fields, instead of failing.
+func WithSchemaDriftDetection(handler func(*SchemaDrift)) Option {
+	return func(o *options) {
+		if handler == nil {
+			handler = logSchemaDrift
+		}
+		o.drift = handler
+	}
+}
+
+// WithWatchResumption makes WatchEvents resubscribe after the block subscription fails or stalls
+// (e.g. because the node restarted or is not reachable yet) instead of closing the channel. Failed
+// subscriptions are retried with exponential backoff up to the given maximum delay, and the events
+// of rounds missed in the meantime are delivered before those of new blocks, so that watchers
+// resume from their last round. The channel is only closed once the context is cancelled.
+//
+// Note that missed rounds must still be available to the node.
+func WithWatchResumption(maxBackoff time.Duration) Option {
+	return func(o *options) {
+		if maxBackoff < watchMinBackoff {
+			maxBackoff = watchMinBackoff
+		}
+		o.watchMaxBackoff = maxBackoff
+	}
+}
+
+// WithQuotas makes the client enforce the given per-tenant quotas. Queries and transaction
+// submissions issued with a context tagged with a caller identity (see WithCaller) are charged to
+// that tenant and fail with a *QuotaExceededError once its quota is exhausted.
+func WithQuotas(quotas *Quotas) Option {
+	return func(o *options) {
+		o.quotas = quotas
+	}
+}
+
+// WithLimits makes the client, and the module helpers using it, enforce the limits of the given
+// limiter (see Limits).
+func WithLimits(limiter *Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithArchiveNodes makes the client retry operations on historical rounds that fail because the
+// node serving them already pruned the round (see IsPruned) on the given archive nodes, which are
+// used round-robin. This allows mixing pruned and archive nodes without routing manually.
+//
+// Without archive nodes, such operations fail with a *PrunedError.
+func WithArchiveNodes(conns ...*grpc.ClientConn) Option {
+	return func(o *options) {
+		if len(conns) == 0 {
+			return
+		}
+		archive := &archiveNodes{}
+		for _, conn := range conns {
+			archive.clients = append(archive.clients, coreClient.NewRuntimeClient(conn))
+		}
+		o.archive = archive
+	}
+}
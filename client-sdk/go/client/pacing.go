@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Window is a daily time window, e.g. the off-peak hours of a node.
+//
+// Start and End are offsets from midnight in the window's location. Windows with End before Start
+// wrap around midnight, e.g. 22:00-06:00.
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+
+	// Location is the time zone of the window (UTC if nil).
+	Location *time.Location
+}
+
+// offset returns the offset of the given time from midnight in the window's location, together
+// with the midnight in question.
+func (w *Window) offset(t time.Time) (time.Duration, time.Time) {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return t.Sub(midnight), midnight
+}
+
+// Contains checks whether the given time falls into the window.
+func (w *Window) Contains(t time.Time) bool {
+	off, _ := w.offset(t)
+	if w.Start <= w.End {
+		return off >= w.Start && off < w.End
+	}
+	return off >= w.Start || off < w.End
+}
+
+// next returns the next time the window starts at or after the given time.
+func (w *Window) next(t time.Time) time.Time {
+	off, midnight := w.offset(t)
+	if off <= w.Start {
+		return midnight.Add(w.Start)
+	}
+	return midnight.AddDate(0, 0, 1).Add(w.Start)
+}
+
+const (
+	// DefaultMaxPacingDelay is the maximum delay between requests used in case none is configured.
+	DefaultMaxPacingDelay = time.Minute
+
+	// initialPacingDelay is the delay used when first slowing down.
+	initialPacingDelay = 100 * time.Millisecond
+)
+
+// PacingConfig is the configuration of a Pacer.
+type PacingConfig struct {
+	// TargetLatency is the node response latency above which requests are slowed down. A zero
+	// value only slows down on errors.
+	TargetLatency time.Duration
+	// MinDelay is the minimum delay between requests.
+	MinDelay time.Duration
+	// MaxDelay is the maximum delay between requests (DefaultMaxPacingDelay if zero).
+	MaxDelay time.Duration
+	// MaxRetries is the number of consecutive transient errors tolerated before giving up.
+	MaxRetries int
+
+	// Windows are the daily time windows requests may be issued in. Requests are issued at any
+	// time if empty.
+	Windows []Window
+}
+
+// Pacer paces bulk historical scans (e.g. compliance backfills) so that they can run against
+// production nodes. It adapts the delay between requests to the observed response latencies and
+// error rates of the node and confines requests to the configured time windows.
+//
+// The delay is doubled each time a request fails or exceeds the target latency and gradually
+// decreased again while the node keeps up.
+type Pacer struct {
+	cfg PacingConfig
+
+	l        sync.Mutex
+	delay    time.Duration
+	failures int
+	last     time.Time
+
+	now func() time.Time
+}
+
+// NewPacer creates a new pacer.
+func NewPacer(cfg PacingConfig) *Pacer {
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = DefaultMaxPacingDelay
+	}
+	if cfg.MaxDelay < cfg.MinDelay {
+		cfg.MaxDelay = cfg.MinDelay
+	}
+	return &Pacer{
+		cfg:   cfg,
+		delay: cfg.MinDelay,
+		now:   time.Now,
+	}
+}
+
+// Delay returns the current delay between requests.
+func (p *Pacer) Delay() time.Duration {
+	p.l.Lock()
+	defer p.l.Unlock()
+	return p.delay
+}
+
+// Wait waits until the next request may be issued.
+func (p *Pacer) Wait(ctx context.Context) error {
+	for {
+		p.l.Lock()
+		now := p.now()
+		var wait time.Duration
+		if next := p.last.Add(p.delay); next.After(now) {
+			wait = next.Sub(now)
+		}
+		if start, ok := p.nextWindow(now.Add(wait)); ok {
+			wait = start.Sub(now)
+		}
+		if wait <= 0 {
+			p.last = now
+			p.l.Unlock()
+			return ctx.Err()
+		}
+		p.l.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// nextWindow returns the start of the next window in case the given time is outside of all
+// configured windows.
+func (p *Pacer) nextWindow(t time.Time) (time.Time, bool) {
+	if len(p.cfg.Windows) == 0 {
+		return time.Time{}, false
+	}
+	var next time.Time
+	for i := range p.cfg.Windows {
+		w := &p.cfg.Windows[i]
+		if w.Contains(t) {
+			return time.Time{}, false
+		}
+		if start := w.next(t); next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next, true
+}
+
+// Observe records the outcome of a request and adapts the delay. It returns false in case the
+// request failed and should not be retried, either because the error is not transient or because
+// too many requests failed in a row.
+func (p *Pacer) Observe(latency time.Duration, err error) bool {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if err != nil || (p.cfg.TargetLatency > 0 && latency > p.cfg.TargetLatency) {
+		p.delay *= 2
+		if p.delay < initialPacingDelay {
+			p.delay = initialPacingDelay
+		}
+		if p.delay > p.cfg.MaxDelay {
+			p.delay = p.cfg.MaxDelay
+		}
+	} else {
+		p.delay -= p.delay / 4
+		if p.delay < p.cfg.MinDelay {
+			p.delay = p.cfg.MinDelay
+		}
+	}
+
+	if err == nil {
+		p.failures = 0
+		return true
+	}
+	p.failures++
+	return IsTransient(err) && p.failures <= p.cfg.MaxRetries
+}
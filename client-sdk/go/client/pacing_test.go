@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestWindow(t *testing.T) {
+	require := require.New(t)
+
+	at := func(hour, min int) time.Time {
+		return time.Date(2022, 10, 3, hour, min, 0, 0, time.UTC)
+	}
+	night := Window{Start: 22 * time.Hour, End: 6 * time.Hour}
+	require.True(night.Contains(at(23, 0)))
+	require.True(night.Contains(at(1, 30)))
+	require.False(night.Contains(at(6, 0)))
+	require.False(night.Contains(at(12, 0)))
+	require.Equal(at(22, 0), night.next(at(12, 0)))
+	require.Equal(at(22, 0).AddDate(0, 0, 1), night.next(at(23, 0)))
+
+	cet := time.FixedZone("CET", 3600)
+	lunch := Window{Start: 12 * time.Hour, End: 13 * time.Hour, Location: cet}
+	require.True(lunch.Contains(at(11, 30)), "windows should respect their location")
+	require.False(lunch.Contains(at(12, 30)), "windows should respect their location")
+
+	p := NewPacer(PacingConfig{Windows: []Window{night, lunch}})
+	start, ok := p.nextWindow(at(8, 0))
+	require.True(ok)
+	require.True(at(11, 0).Equal(start), "the earliest window should be used")
+	_, ok = p.nextWindow(at(11, 15))
+	require.False(ok)
+}
+
+func TestPacerObserve(t *testing.T) {
+	require := require.New(t)
+
+	p := NewPacer(PacingConfig{
+		TargetLatency: 100 * time.Millisecond,
+		MinDelay:      10 * time.Millisecond,
+		MaxDelay:      time.Second,
+		MaxRetries:    2,
+	})
+	require.Equal(10*time.Millisecond, p.Delay())
+
+	require.True(p.Observe(50*time.Millisecond, nil))
+	require.Equal(10*time.Millisecond, p.Delay(), "delay should not drop below the minimum")
+
+	require.True(p.Observe(200*time.Millisecond, nil))
+	require.Equal(initialPacingDelay, p.Delay(), "slow responses should increase the delay")
+	require.True(p.Observe(200*time.Millisecond, nil))
+	require.Equal(2*initialPacingDelay, p.Delay())
+
+	unavailable := status.Error(codes.Unavailable, "node overloaded")
+	for i := 0; i < 10; i++ {
+		p.Observe(0, unavailable)
+	}
+	require.Equal(time.Second, p.Delay(), "delay should not exceed the maximum")
+
+	require.True(p.Observe(0, nil))
+	require.Equal(750*time.Millisecond, p.Delay(), "delay should decrease while the node keeps up")
+
+	require.True(p.Observe(0, unavailable), "transient errors should be retried")
+	require.True(p.Observe(0, unavailable), "transient errors should be retried")
+	require.False(p.Observe(0, unavailable), "retries should be limited")
+	require.True(p.Observe(0, nil))
+	require.False(p.Observe(0, errors.New("permanent")), "permanent errors should not be retried")
+}
+
+type flakyEventsRuntimeClient struct {
+	RuntimeClient
+
+	failures int
+	calls    int
+}
+
+func (f *flakyEventsRuntimeClient) CheckRetained(ctx context.Context, round uint64) error {
+	return nil
+}
+
+func (f *flakyEventsRuntimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, status.Error(codes.ResourceExhausted, "too many requests")
+	}
+	return []*types.Event{{Module: "test", Round: round}}, nil
+}
+
+func TestEventCursorPacing(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &flakyEventsRuntimeClient{failures: 2}
+	pacer := NewPacer(PacingConfig{MaxDelay: time.Millisecond, MaxRetries: 2})
+
+	var rounds []uint64
+	cursor := NewEventCursor("", nil, 1)
+	err := cursor.Process(ctx, rc, 3, func(round uint64, ev *types.Event) error {
+		rounds = append(rounds, round)
+		return nil
+	}, WithPacer(pacer))
+	require.NoError(err, "Process should retry transient errors")
+	require.Equal([]uint64{1, 2, 3}, rounds)
+	require.Equal(5, rc.calls)
+
+	rc = &flakyEventsRuntimeClient{failures: 3}
+	cursor = NewEventCursor("", nil, 1)
+	err = cursor.Process(ctx, rc, 3, func(round uint64, ev *types.Event) error {
+		return nil
+	}, WithPacer(pacer))
+	require.Error(err, "Process should give up after too many retries")
+	require.EqualValues(1, cursor.Round)
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// Priority is the priority class of a client operation.
+//
+// Operations of different priority classes are dispatched over separate connections when a
+// connection pool is configured (see WithConnPool), so that e.g. a heavy backfill can't starve
+// user-facing transaction submissions sharing one client.
+type Priority uint8
+
+const (
+	// PriorityBackfill is the priority class of bulk historical scans.
+	PriorityBackfill Priority = iota
+	// PriorityInteractive is the priority class of interactive queries. This is the default
+	// priority class of all operations except transaction submissions.
+	PriorityInteractive
+	// PrioritySubmit is the priority class of transaction submissions.
+	PrioritySubmit
+
+	numPriorities = int(PrioritySubmit) + 1
+)
+
+// String returns a string representation of the priority class.
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackfill:
+		return "backfill"
+	case PriorityInteractive:
+		return "interactive"
+	case PrioritySubmit:
+		return "submit"
+	default:
+		return fmt.Sprintf("[unknown priority: %d]", uint8(p))
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a derived context that makes operations issued with it use the given
+// priority class.
+func WithPriority(ctx context.Context, prio Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, prio)
+}
+
+// PriorityFromContext returns the priority class set in the given context or the passed default
+// in case none is set.
+func PriorityFromContext(ctx context.Context, def Priority) Priority {
+	if prio, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return prio
+	}
+	return def
+}
+
+// ConnPool is a pool of gRPC connections partitioned by priority class.
+type ConnPool struct {
+	conns [numPriorities][]*grpc.ClientConn
+	next  [numPriorities]uint32
+}
+
+// NewConnPool creates a new connection pool, dialing the given number of connections for each
+// priority class.
+//
+// Priority classes without any dedicated connections share the connections of the closest higher
+// priority class, or of the closest lower one if no higher class has any.
+func NewConnPool(dial func() (*grpc.ClientConn, error), sizes map[Priority]int) (*ConnPool, error) {
+	var p ConnPool
+	for prio, n := range sizes {
+		if int(prio) >= numPriorities {
+			p.Close()
+			return nil, fmt.Errorf("pool: unknown priority class: %s", prio)
+		}
+		for i := 0; i < n; i++ {
+			conn, err := dial()
+			if err != nil {
+				p.Close()
+				return nil, fmt.Errorf("pool: failed to dial %s connection: %w", prio, err)
+			}
+			p.conns[prio] = append(p.conns[prio], conn)
+		}
+	}
+	return &p, nil
+}
+
+// Conn returns a connection to be used for operations of the given priority class.
+//
+// Returns nil in case the pool contains no connections.
+func (p *ConnPool) Conn(prio Priority) *grpc.ClientConn {
+	if int(prio) >= numPriorities {
+		prio = PriorityInteractive
+	}
+
+	class := -1
+	for i := int(prio); i < numPriorities; i++ {
+		if len(p.conns[i]) > 0 {
+			class = i
+			break
+		}
+	}
+	for i := int(prio) - 1; class < 0 && i >= 0; i-- {
+		if len(p.conns[i]) > 0 {
+			class = i
+		}
+	}
+	if class < 0 {
+		return nil
+	}
+
+	conns := p.conns[class]
+	idx := atomic.AddUint32(&p.next[class], 1)
+	return conns[int(idx)%len(conns)]
+}
+
+// Close closes all connections in the pool.
+func (p *ConnPool) Close() {
+	for i := range p.conns {
+		for _, conn := range p.conns[i] {
+			_ = conn.Close()
+		}
+		p.conns[i] = nil
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestConnPool(t *testing.T) {
+	require := require.New(t)
+
+	dial := func() (*grpc.ClientConn, error) {
+		return grpc.Dial("passthrough:///test", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	pool, err := NewConnPool(dial, map[Priority]int{
+		PrioritySubmit:   1,
+		PriorityBackfill: 2,
+	})
+	require.NoError(err)
+	defer pool.Close()
+
+	submit := pool.Conn(PrioritySubmit)
+	require.NotNil(submit)
+	require.Equal(submit, pool.Conn(PriorityInteractive), "interactive should fall back to submit connections")
+
+	backfill := pool.Conn(PriorityBackfill)
+	require.NotNil(backfill)
+	require.NotEqual(submit, backfill)
+	require.NotEqual(backfill, pool.Conn(PriorityBackfill), "connections should be used round-robin")
+
+	_, err = NewConnPool(dial, map[Priority]int{Priority(42): 1})
+	require.Error(err, "unknown priority classes should be rejected")
+
+	var empty ConnPool
+	require.Nil(empty.Conn(PriorityInteractive))
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	require.Equal(PrioritySubmit, PriorityFromContext(ctx, PrioritySubmit))
+	ctx = WithPriority(ctx, PriorityBackfill)
+	require.Equal(PriorityBackfill, PriorityFromContext(ctx, PrioritySubmit))
+}
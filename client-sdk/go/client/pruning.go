@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// ErrPruned is the error returned when an operation failed because the node already pruned the
+// requested round. Such errors are also ErrNotFound or ErrPermanent, depending on how the node
+// reported the missing state.
+var ErrPruned = errors.New("round pruned")
+
+// prunedErrorCodes are module-specific errors reported by nodes when state of a pruned round is
+// accessed (in addition to any ErrNotFound error).
+var prunedErrorCodes = map[errorCodeKey]bool{
+	{"storage/mkvs/db", 1}: true, // Node not found.
+	{"storage/mkvs/db", 2}: true, // Write log not found.
+	{"storage/mkvs/db", 5}: true, // Version not found.
+}
+
+// PrunedError is the error returned when an operation failed because the node already pruned the
+// requested round.
+type PrunedError struct {
+	// Round is the round the operation was issued for.
+	Round uint64
+	// LastRetainedRound is the earliest round still retained by the node.
+	LastRetainedRound uint64
+
+	err error
+}
+
+// Error returns the string representation of the pruned error.
+func (e *PrunedError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("round %d pruned (last retained round is %d)", e.Round, e.LastRetainedRound)
+	}
+	return fmt.Sprintf("round %d pruned (last retained round is %d): %s", e.Round, e.LastRetainedRound, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PrunedError) Unwrap() error {
+	return e.err
+}
+
+// Is checks whether the target is ErrPruned.
+func (e *PrunedError) Is(target error) bool {
+	return target == ErrPruned
+}
+
+// IsPruned checks whether the given error was caused by the node having pruned the requested
+// round.
+func IsPruned(err error) bool {
+	return errors.Is(err, ErrPruned)
+}
+
+// archiveNodes are the nodes historical operations are routed to, used round-robin.
+type archiveNodes struct {
+	clients []coreClient.RuntimeClient
+	next    uint32
+}
+
+func (an *archiveNodes) client() coreClient.RuntimeClient {
+	idx := atomic.AddUint32(&an.next, 1)
+	return an.clients[int(idx)%len(an.clients)]
+}
+
+// pruningClient is a runtime client wrapper that detects operations failing because the node
+// pruned the requested round and, in case archive nodes are configured, retries them there.
+type pruningClient struct {
+	coreClient.RuntimeClient
+
+	runtimeID common.Namespace
+	archive   *archiveNodes
+	timeouts  Timeouts
+}
+
+// withArchive runs the given operation on the node and, in case it failed because the node pruned
+// the given round, retries it on an archive node.
+func withArchive[T any](ctx context.Context, pc *pruningClient, round uint64, op func(coreClient.RuntimeClient) (T, error)) (T, error) {
+	rsp, err := op(pc.RuntimeClient)
+	if err == nil || round == coreClient.RoundLatest {
+		return rsp, err
+	}
+	if err = pc.checkPruned(ctx, round, err); !IsPruned(err) || pc.archive == nil {
+		return rsp, err
+	}
+	return op(&wrappedClient{RuntimeClient: pc.archive.client(), timeouts: pc.timeouts})
+}
+
+// checkPruned wraps the given error into a *PrunedError in case the operation at the given round
+// failed because the node pruned the round.
+func (pc *pruningClient) checkPruned(ctx context.Context, round uint64, err error) error {
+	if !errors.Is(err, ErrNotFound) {
+		module, code, ok := ErrorCode(err)
+		if !ok || !prunedErrorCodes[errorCodeKey{module, code}] {
+			return err
+		}
+	}
+	blk, lerr := pc.RuntimeClient.GetLastRetainedBlock(ctx, pc.runtimeID)
+	if lerr != nil || round >= blk.Header.Round {
+		return err
+	}
+	return &PrunedError{Round: round, LastRetainedRound: blk.Header.Round, err: err}
+}
+
+func (pc *pruningClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	return withArchive(ctx, pc, request.Round, func(cc coreClient.RuntimeClient) (*block.Block, error) {
+		return cc.GetBlock(ctx, request)
+	})
+}
+
+func (pc *pruningClient) GetTransactions(ctx context.Context, request *coreClient.GetTransactionsRequest) ([][]byte, error) {
+	return withArchive(ctx, pc, request.Round, func(cc coreClient.RuntimeClient) ([][]byte, error) {
+		return cc.GetTransactions(ctx, request)
+	})
+}
+
+func (pc *pruningClient) GetTransactionsWithResults(ctx context.Context, request *coreClient.GetTransactionsRequest) ([]*coreClient.TransactionWithResults, error) {
+	return withArchive(ctx, pc, request.Round, func(cc coreClient.RuntimeClient) ([]*coreClient.TransactionWithResults, error) {
+		return cc.GetTransactionsWithResults(ctx, request)
+	})
+}
+
+func (pc *pruningClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	return withArchive(ctx, pc, request.Round, func(cc coreClient.RuntimeClient) ([]*coreClient.Event, error) {
+		return cc.GetEvents(ctx, request)
+	})
+}
+
+func (pc *pruningClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	return withArchive(ctx, pc, request.Round, func(cc coreClient.RuntimeClient) (*coreClient.QueryResponse, error) {
+		return cc.Query(ctx, request)
+	})
+}
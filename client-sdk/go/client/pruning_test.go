@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// prunedRuntimeClient simulates a node which retains rounds starting at lastRetained.
+type prunedRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	lastRetained uint64
+	latest       uint64
+	queries      int
+}
+
+func (p *prunedRuntimeClient) GetLastRetainedBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = p.lastRetained
+	return &blk, nil
+}
+
+func (p *prunedRuntimeClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	if request.Round < p.lastRetained || (request.Round > p.latest && request.Round != coreClient.RoundLatest) {
+		return nil, roothash.ErrNotFound
+	}
+	var blk block.Block
+	blk.Header.Round = request.Round
+	return &blk, nil
+}
+
+func (p *prunedRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	p.queries++
+	if request.Round < p.lastRetained {
+		return nil, roothash.ErrNotFound
+	}
+	return &coreClient.QueryResponse{Data: []byte{byte(p.lastRetained)}}, nil
+}
+
+func TestPruning(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	node := &prunedRuntimeClient{lastRetained: 100, latest: 200}
+	rc := &runtimeClient{cc: node}
+
+	blk, err := rc.GetBlock(ctx, 150)
+	require.NoError(err)
+	require.EqualValues(150, blk.Header.Round)
+
+	_, err = rc.GetBlock(ctx, 50)
+	require.True(IsPruned(err), "rounds before the last retained round should be reported as pruned")
+	require.ErrorIs(err, ErrNotFound)
+	var pe *PrunedError
+	require.True(errors.As(err, &pe))
+	require.EqualValues(50, pe.Round)
+	require.EqualValues(100, pe.LastRetainedRound)
+
+	_, err = rc.GetBlock(ctx, 300)
+	require.ErrorIs(err, ErrNotFound)
+	require.False(IsPruned(err), "future rounds should not be reported as pruned")
+
+	_, err = rc.QueryRaw(ctx, 50, "accounts.Parameters", nil)
+	require.True(IsPruned(err))
+
+	require.NoError(rc.CheckRetained(ctx, 100))
+	require.NoError(rc.CheckRetained(ctx, RoundLatest))
+	err = rc.CheckRetained(ctx, 50)
+	require.True(IsPruned(err), "preflight should detect pruned rounds")
+	require.ErrorIs(err, ErrNotFound)
+
+	// With archive nodes, historical operations should be retried there.
+	archive := &prunedRuntimeClient{lastRetained: 10, latest: 200}
+	rc.opts.archive = &archiveNodes{clients: []coreClient.RuntimeClient{archive}}
+
+	require.NoError(rc.CheckRetained(ctx, 50), "preflight should account for archive nodes")
+	err = rc.CheckRetained(ctx, 5)
+	require.True(IsPruned(err))
+	require.Contains(err.Error(), "round 5 pruned (last retained round is 100)")
+
+	blk, err = rc.GetBlock(ctx, 50)
+	require.NoError(err)
+	require.EqualValues(50, blk.Header.Round)
+
+	data, err := rc.QueryRaw(ctx, 50, "accounts.Parameters", nil)
+	require.NoError(err)
+	require.EqualValues([]byte{10}, data, "query should be served by the archive node")
+
+	data, err = rc.QueryRaw(ctx, 150, "accounts.Parameters", nil)
+	require.NoError(err)
+	require.EqualValues([]byte{100}, data, "retained rounds should be served by the node")
+	require.Equal(1, archive.queries)
+
+	_, err = rc.GetBlock(ctx, 300)
+	require.ErrorIs(err, ErrNotFound, "only pruned rounds should be retried on archive nodes")
+}
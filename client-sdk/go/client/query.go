@@ -0,0 +1,48 @@
+package client
+
+// QueryOption configures the round against which a query is executed.
+//
+// All module query methods accept a round argument, so the resolved round should be passed there,
+// e.g. `accounts.Balances(ctx, client.QueryRound(client.AtRound(round)), addr)`.
+type QueryOption func(*QueryOptions)
+
+// QueryOptions are the options controlling query execution.
+type QueryOptions struct {
+	// Round is the round against which the query is executed. It may be the special RoundLatest
+	// value.
+	Round uint64
+}
+
+// Latest requests the query to be executed against the latest round.
+//
+// This is the default. As consensus blocks are final as soon as they are committed, the latest
+// round is already finalized, so there is no separate option for finalized rounds.
+func Latest() QueryOption {
+	return func(o *QueryOptions) {
+		o.Round = RoundLatest
+	}
+}
+
+// AtRound requests the query to be executed against the given round.
+func AtRound(round uint64) QueryOption {
+	return func(o *QueryOptions) {
+		o.Round = round
+	}
+}
+
+// NewQueryOptions applies the given options on top of the defaults.
+func NewQueryOptions(opts ...QueryOption) *QueryOptions {
+	o := QueryOptions{
+		Round: RoundLatest,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// QueryRound returns the round argument that should be passed to query methods in order to
+// satisfy the given options.
+func QueryRound(opts ...QueryOption) uint64 {
+	return NewQueryOptions(opts...).Round
+}
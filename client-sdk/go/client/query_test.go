@@ -0,0 +1,16 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRound(t *testing.T) {
+	require := require.New(t)
+
+	require.EqualValues(RoundLatest, QueryRound())
+	require.EqualValues(RoundLatest, QueryRound(Latest()))
+	require.EqualValues(42, QueryRound(AtRound(42)))
+	require.EqualValues(42, QueryRound(Latest(), AtRound(42)), "later options should take precedence")
+}
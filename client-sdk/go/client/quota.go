@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrQuotaExceeded is the class of errors caused by a tenant exceeding its quota (see
+// QuotaExceededError).
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaKind is the kind of operations a quota limits.
+type QuotaKind string
+
+const (
+	// QuotaQueries limits runtime queries (per minute).
+	QuotaQueries QuotaKind = "queries"
+	// QuotaSubmissions limits transaction submissions (per UTC day).
+	QuotaSubmissions QuotaKind = "submissions"
+)
+
+// Quota is the operation budget of a tenant. A zero limit disables the given limit.
+type Quota struct {
+	// QueriesPerMinute is the number of runtime queries allowed per minute.
+	QueriesPerMinute int
+	// SubmissionsPerDay is the number of transaction submissions allowed per UTC day.
+	SubmissionsPerDay int
+}
+
+// QuotaUsage is the usage of a tenant in the current quota windows.
+type QuotaUsage struct {
+	// Queries is the number of runtime queries issued in the current minute.
+	Queries int
+	// Submissions is the number of transaction submissions issued in the current UTC day.
+	Submissions int
+}
+
+// QuotaExceededError is the error returned when an operation is rejected because the tenant it is
+// performed on behalf of exceeded its quota.
+type QuotaExceededError struct {
+	// Tenant is the tenant that exceeded its quota.
+	Tenant string
+	// Kind is the kind of the exceeded quota.
+	Kind QuotaKind
+	// Limit is the exceeded limit.
+	Limit int
+	// Reset is the time the quota window resets.
+	Reset time.Time
+}
+
+// Error returns the string representation of the quota error.
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("tenant '%s' exceeded its %s quota of %d (resets at %s)",
+		e.Tenant, e.Kind, e.Limit, e.Reset.Format(time.RFC3339))
+}
+
+// Is checks whether the error is of the given class.
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// quotaWindow is the usage of a quota in a fixed time window.
+type quotaWindow struct {
+	start time.Time
+	used  int
+}
+
+// charge records an operation in the window starting at the given time, failing in case the limit
+// is reached.
+func (w *quotaWindow) charge(start time.Time, limit int) bool {
+	if !w.start.Equal(start) {
+		w.start, w.used = start, 0
+	}
+	if limit > 0 && w.used >= limit {
+		return false
+	}
+	w.used++
+	return true
+}
+
+// current returns the usage in the window starting at the given time.
+func (w *quotaWindow) current(start time.Time) int {
+	if !w.start.Equal(start) {
+		return 0
+	}
+	return w.used
+}
+
+// tenantUsage is the usage of a tenant.
+type tenantUsage struct {
+	queries     quotaWindow
+	submissions quotaWindow
+}
+
+// Quotas enforces per-tenant operation budgets inside the client (see WithQuotas), so that
+// multi-tenant backends don't need to wrap the client to do so.
+//
+// Tenants are identified by the caller identity set via WithCaller. Operations issued without a
+// caller identity are not limited. The usage of tenants without any operations in the current
+// quota windows is discarded.
+type Quotas struct {
+	l         sync.Mutex
+	def       Quota
+	quotas    map[string]Quota
+	usage     map[string]*tenantUsage
+	lastSweep time.Time
+
+	operations *prometheus.CounterVec
+
+	now func() time.Time
+}
+
+// NewQuotas creates new quotas, applying the given default quota to tenants without a quota of
+// their own.
+//
+// In case a registerer is given, operations are counted in the oasis_sdk_client_quota_operations
+// metric registered with it, labeled by kind and outcome (but not by tenant, to bound the number
+// of time series).
+func NewQuotas(def Quota, reg prometheus.Registerer) *Quotas {
+	q := &Quotas{
+		def:    def,
+		quotas: make(map[string]Quota),
+		usage:  make(map[string]*tenantUsage),
+		now:    time.Now,
+	}
+	if reg != nil {
+		q.operations = registerCounterVec(reg, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "oasis_sdk_client_quota_operations",
+				Help: "Number of operations subject to tenant quotas by outcome.",
+			},
+			[]string{"kind", "outcome"},
+		))
+	}
+	return q
+}
+
+// SetQuota sets the quota of the given tenant.
+func (q *Quotas) SetQuota(tenant string, quota Quota) {
+	q.l.Lock()
+	defer q.l.Unlock()
+	q.quotas[tenant] = quota
+}
+
+// RemoveQuota removes the quota of the given tenant, so that the default quota applies again.
+func (q *Quotas) RemoveQuota(tenant string) {
+	q.l.Lock()
+	defer q.l.Unlock()
+	delete(q.quotas, tenant)
+}
+
+// Usage returns the usage of the given tenant in the current quota windows.
+func (q *Quotas) Usage(tenant string) QuotaUsage {
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	u, ok := q.usage[tenant]
+	if !ok {
+		return QuotaUsage{}
+	}
+	minute, day := quotaWindows(q.now())
+	return QuotaUsage{
+		Queries:     u.queries.current(minute),
+		Submissions: u.submissions.current(day),
+	}
+}
+
+// quotaWindows returns the start of the query and submission windows containing the given time.
+func quotaWindows(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	return now.Truncate(time.Minute), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// charge charges an operation of the given kind to the tenant the context is tagged with.
+func (q *Quotas) charge(ctx context.Context, kind QuotaKind) error {
+	tenant, ok := CallerFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	q.l.Lock()
+	quota, ok := q.quotas[tenant]
+	if !ok {
+		quota = q.def
+	}
+	minute, day := quotaWindows(q.now())
+	if !q.lastSweep.Equal(minute) {
+		q.sweep(minute, day)
+	}
+	u, ok := q.usage[tenant]
+	if !ok {
+		u = &tenantUsage{}
+		q.usage[tenant] = u
+	}
+	var err error
+	switch kind {
+	case QuotaQueries:
+		if !u.queries.charge(minute, quota.QueriesPerMinute) {
+			err = &QuotaExceededError{Tenant: tenant, Kind: kind, Limit: quota.QueriesPerMinute, Reset: minute.Add(time.Minute)}
+		}
+	case QuotaSubmissions:
+		if !u.submissions.charge(day, quota.SubmissionsPerDay) {
+			err = &QuotaExceededError{Tenant: tenant, Kind: kind, Limit: quota.SubmissionsPerDay, Reset: day.AddDate(0, 0, 1)}
+		}
+	}
+	q.l.Unlock()
+
+	if q.operations != nil {
+		outcome := "allowed"
+		if err != nil {
+			outcome = "rejected"
+		}
+		q.operations.WithLabelValues(string(kind), outcome).Inc()
+	}
+	return err
+}
+
+// sweep discards the usage of tenants without any operations in the given quota windows. It is
+// called at most once per query window so that idle tenants do not accumulate.
+func (q *Quotas) sweep(minute, day time.Time) {
+	for tenant, u := range q.usage {
+		if u.queries.current(minute) == 0 && u.submissions.current(day) == 0 {
+			delete(q.usage, tenant)
+		}
+	}
+	q.lastSweep = minute
+}
+
+// chargeQuota charges an operation of the given kind in case quotas are enabled.
+func (rc *runtimeClient) chargeQuota(ctx context.Context, kind QuotaKind) error {
+	if rc.opts.quotas == nil {
+		return nil
+	}
+	return rc.opts.quotas.charge(ctx, kind)
+}
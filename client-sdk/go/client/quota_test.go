@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// submittingRuntimeClient accepts all transactions without waiting.
+type submittingRuntimeClient struct {
+	legacyRuntimeClient
+
+	submitted int
+}
+
+func (s *submittingRuntimeClient) SubmitTxNoWait(ctx context.Context, request *coreClient.SubmitTxRequest) error {
+	s.submitted++
+	return nil
+}
+
+func TestQuotas(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2022, 5, 1, 23, 59, 0, 0, time.UTC)
+	reg := prometheus.NewRegistry()
+	quotas := NewQuotas(Quota{QueriesPerMinute: 2}, reg)
+	quotas.now = func() time.Time { return now }
+	quotas.SetQuota("paid", Quota{QueriesPerMinute: 3, SubmissionsPerDay: 1})
+
+	cc := &submittingRuntimeClient{legacyRuntimeClient: legacyRuntimeClient{
+		methods: map[string]interface{}{"test.Query": 42},
+	}}
+	rc := &runtimeClient{cc: cc}
+	WithQuotas(quotas)(&rc.opts)
+
+	free := WithCaller(context.Background(), "free")
+	paid := WithCaller(context.Background(), "paid")
+	for i := 0; i < 2; i++ {
+		_, err := rc.QueryRaw(free, RoundLatest, "test.Query", nil)
+		require.NoError(err, "QueryRaw")
+	}
+	_, err := rc.QueryRaw(free, RoundLatest, "test.Query", nil)
+	require.ErrorIs(err, ErrQuotaExceeded)
+	var qe *QuotaExceededError
+	require.True(errors.As(err, &qe))
+	require.Equal("free", qe.Tenant)
+	require.Equal(QuotaQueries, qe.Kind)
+	require.Equal(2, qe.Limit)
+	require.Equal(now.Add(time.Minute), qe.Reset)
+	require.Len(cc.calls, 2, "rejected queries should not reach the node")
+
+	// Tenants are limited independently and untagged operations are not limited.
+	_, err = rc.QueryRaw(paid, RoundLatest, "test.Query", nil)
+	require.NoError(err, "QueryRaw")
+	for i := 0; i < 5; i++ {
+		_, err = rc.QueryRaw(context.Background(), RoundLatest, "test.Query", nil)
+		require.NoError(err, "QueryRaw")
+	}
+	require.Equal(QuotaUsage{Queries: 2}, quotas.Usage("free"))
+
+	require.NoError(rc.SubmitTxNoWait(paid, &types.UnverifiedTransaction{}))
+	err = rc.SubmitTxNoWait(paid, &types.UnverifiedTransaction{})
+	require.ErrorIs(err, ErrQuotaExceeded)
+	require.Equal(1, cc.submitted)
+	require.Equal(QuotaUsage{Queries: 1, Submissions: 1}, quotas.Usage("paid"))
+
+	// Windows reset.
+	now = now.Add(time.Minute)
+	require.Equal(QuotaUsage{}, quotas.Usage("paid"))
+	_, err = rc.QueryRaw(free, RoundLatest, "test.Query", nil)
+	require.NoError(err, "queries should be allowed in the next minute")
+	require.NoError(rc.SubmitTxNoWait(paid, &types.UnverifiedTransaction{}), "submissions should be allowed on the next day")
+
+	quotas.RemoveQuota("paid")
+	require.NoError(rc.SubmitTxNoWait(paid, &types.UnverifiedTransaction{}), "the default quota has no submission limit")
+
+	// Idle tenants should be discarded.
+	require.Len(quotas.usage, 2)
+	now = now.Add(24 * time.Hour)
+	_, err = rc.QueryRaw(free, RoundLatest, "test.Query", nil)
+	require.NoError(err, "QueryRaw")
+	require.Len(quotas.usage, 1)
+	require.Contains(quotas.usage, "free")
+
+	// Operations should be counted by kind and outcome only.
+	families, err := reg.Gather()
+	require.NoError(err, "Gather")
+	require.Len(families, 1)
+	require.Equal("oasis_sdk_client_quota_operations", families[0].GetName())
+	counts := make(map[string]float64)
+	for _, m := range families[0].GetMetric() {
+		var labels []string
+		for _, l := range m.GetLabel() {
+			labels = append(labels, l.GetName()+"="+l.GetValue())
+		}
+		counts[strings.Join(labels, ",")] = m.GetCounter().GetValue()
+	}
+	require.Equal(map[string]float64{
+		"kind=queries,outcome=allowed":      5,
+		"kind=queries,outcome=rejected":     1,
+		"kind=submissions,outcome=allowed":  3,
+		"kind=submissions,outcome=rejected": 1,
+	}, counts)
+
+	// Quotas sharing a registerer should share the metric.
+	require.NotPanics(func() { NewQuotas(Quota{}, reg) })
+}
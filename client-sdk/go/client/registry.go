@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// EventDecoderFunc is an adapter to allow the use of ordinary functions as event decoders.
+type EventDecoderFunc func(*types.Event) ([]DecodedEvent, error)
+
+// DecodeEvent calls f(event).
+func (f EventDecoderFunc) DecodeEvent(event *types.Event) ([]DecodedEvent, error) {
+	return f(event)
+}
+
+// ModuleError describes a module-specific error.
+type ModuleError struct {
+	// Description is a human readable description of the error.
+	Description string
+	// Class is the class of the error (one of ErrTransient, ErrPermanent, ErrAuth or ErrNotFound).
+	// When nil, the error is considered permanent.
+	Class error
+}
+
+// MethodInfo describes a callable method for humans (e.g. CLI help) and authorization prechecks.
+type MethodInfo struct {
+	// Description is a human readable description of the method.
+	Description string
+	// Roles are the roles allowed to call the method. When empty, the method is not restricted to
+	// specific roles.
+	Roles []types.Role
+	// BodyRoles returns the roles allowed to call the method with the given decoded body, for
+	// methods where the roles depend on the body (e.g. on the action of a proposal). It takes
+	// precedence over Roles when set.
+	BodyRoles func(body interface{}) []types.Role
+	// Events are the codes of the events the method may emit.
+	Events []uint32
+}
+
+// ModuleDescriptor describes a runtime module so that the client can provide typed decoding of
+// its method bodies, events and errors.
+type ModuleDescriptor struct {
+	// Name is the module name, e.g. "accounts".
+	Name string
+	// Methods maps full method names (e.g. "accounts.Transfer") to values of the corresponding
+	// method body types. The values are only used to determine the types.
+	Methods map[string]interface{}
+	// MethodInfos maps full method names to their descriptions.
+	MethodInfos map[string]MethodInfo
+	// Queries maps full query method names to values of the corresponding argument types (nil
+	// for queries without arguments). The values are only used to determine the types.
+	Queries map[string]interface{}
+	// Events is the decoder of events emitted by the module.
+	Events EventDecoder
+	// EventTypes maps event codes to values of the types of the individual events. The values
+	// are only used to determine the types.
+	EventTypes map[uint32]interface{}
+	// Errors maps module error codes to their descriptions.
+	Errors map[uint32]ModuleError
+}
+
+// registry is the registry of module descriptors.
+type registry struct {
+	sync.RWMutex
+
+	modules map[string]*ModuleDescriptor
+	methods map[string]reflect.Type
+}
+
+var modules = registry{
+	modules: make(map[string]*ModuleDescriptor),
+	methods: make(map[string]reflect.Type),
+}
+
+// RegisterModule registers a runtime module with the client's registries. This is usually called
+// from the init function of the package implementing the module client, including packages
+// outside of this SDK that implement clients for custom runtime modules.
+//
+// Returns an error in case a module with the same name or any of its methods is already
+// registered.
+func RegisterModule(desc *ModuleDescriptor) error {
+	if desc.Name == "" {
+		return fmt.Errorf("registry: missing module name")
+	}
+
+	modules.Lock()
+	defer modules.Unlock()
+
+	if _, exists := modules.modules[desc.Name]; exists {
+		return fmt.Errorf("registry: module '%s' already registered", desc.Name)
+	}
+	methods := make(map[string]reflect.Type, len(desc.Methods))
+	for method, body := range desc.Methods {
+		if !strings.HasPrefix(method, desc.Name+".") {
+			return fmt.Errorf("registry: method '%s' does not belong to module '%s'", method, desc.Name)
+		}
+		if _, exists := modules.methods[method]; exists {
+			return fmt.Errorf("registry: method '%s' already registered", method)
+		}
+		typ := reflect.TypeOf(body)
+		if typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		methods[method] = typ
+	}
+	for method := range desc.MethodInfos {
+		if _, ok := desc.Methods[method]; !ok {
+			return fmt.Errorf("registry: description of unknown method '%s'", method)
+		}
+	}
+
+	modules.modules[desc.Name] = desc
+	for method, typ := range methods {
+		modules.methods[method] = typ
+	}
+	return nil
+}
+
+// MustRegisterModule is like RegisterModule but panics on failure.
+func MustRegisterModule(desc *ModuleDescriptor) {
+	if err := RegisterModule(desc); err != nil {
+		panic(err)
+	}
+}
+
+// LookupModule returns the descriptor of the given registered module.
+func LookupModule(name string) (*ModuleDescriptor, bool) {
+	modules.RLock()
+	defer modules.RUnlock()
+
+	desc, ok := modules.modules[name]
+	return desc, ok
+}
+
+// RegisteredModules returns the names of all registered modules in lexicographic order.
+func RegisteredModules() []string {
+	modules.RLock()
+	defer modules.RUnlock()
+
+	names := make([]string, 0, len(modules.modules))
+	for name := range modules.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Method is a registered method.
+type Method struct {
+	MethodInfo
+
+	// Name is the full method name, e.g. "accounts.Transfer".
+	Name string
+	// Module is the name of the module implementing the method.
+	Module string
+	// BodyType is the type of the method body (nil for methods without a body).
+	BodyType reflect.Type
+}
+
+// LookupMethod returns the given registered method.
+func LookupMethod(method string) (*Method, bool) {
+	modules.RLock()
+	defer modules.RUnlock()
+
+	typ, ok := modules.methods[method]
+	if !ok {
+		return nil, false
+	}
+	module := method[:strings.LastIndex(method, ".")]
+	return &Method{
+		MethodInfo: modules.modules[module].MethodInfos[method],
+		Name:       method,
+		Module:     module,
+		BodyType:   typ,
+	}, true
+}
+
+// RegisteredMethods returns the names of all registered methods in lexicographic order.
+func RegisteredMethods() []string {
+	modules.RLock()
+	defer modules.RUnlock()
+
+	names := make([]string, 0, len(modules.methods))
+	for name := range modules.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AllowedRoles returns the roles allowed to call the method with the given decoded body (e.g. as
+// returned by DecodeCall). The last return value is false in case the method is not restricted to
+// specific roles.
+func (m *Method) AllowedRoles(body interface{}) ([]types.Role, bool) {
+	roles := m.Roles
+	if m.BodyRoles != nil {
+		roles = m.BodyRoles(body)
+	}
+	return roles, len(roles) > 0
+}
+
+// CheckRole checks whether an account with the given role may call the given registered method
+// with the given decoded body, so that unauthorized calls can be rejected before submitting them.
+// The returned error is classified as ErrAuth.
+//
+// Unregistered methods and methods not restricted to specific roles always pass the check.
+func CheckRole(method string, role types.Role, body interface{}) error {
+	m, ok := LookupMethod(method)
+	if !ok {
+		return nil
+	}
+	roles, restricted := m.AllowedRoles(body)
+	if !restricted {
+		return nil
+	}
+	for _, allowed := range roles {
+		if role == allowed {
+			return nil
+		}
+	}
+	return &classifiedError{
+		class: ErrAuth,
+		err:   fmt.Errorf("registry: role %s may not call '%s'", role, method),
+	}
+}
+
+// NewMethodBody returns a pointer to a new zero value of the body type of the given registered
+// method. The second return value is false in case the method is not registered.
+func NewMethodBody(method string) (interface{}, bool) {
+	modules.RLock()
+	typ, ok := modules.methods[method]
+	modules.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+	if typ == nil {
+		// Method without a body.
+		return nil, true
+	}
+	return reflect.New(typ).Interface(), true
+}
+
+// DecodeCall decodes the body of the given plain call into a value of the body type registered
+// for its method.
+func DecodeCall(call *types.Call) (body interface{}, err error) {
+	defer types.RecoverDecode(&err, fmt.Sprintf("'%s' body", call.Method))
+
+	if call.Format != types.CallFormatPlain {
+		return nil, fmt.Errorf("registry: cannot decode call with format %s", call.Format)
+	}
+	body, ok := NewMethodBody(call.Method)
+	if !ok {
+		return nil, fmt.Errorf("registry: unknown method '%s'", call.Method)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	if err = cbor.Unmarshal(call.Body, body); err != nil {
+		return nil, fmt.Errorf("registry: failed to decode '%s' body: %w", call.Method, err)
+	}
+	return body, nil
+}
+
+// DecodeEvent decodes the given event using the decoder of the registered module that emitted
+// it. Returns `nil, nil` in case the module is not registered or has no event decoder.
+func DecodeEvent(event *types.Event) ([]DecodedEvent, error) {
+	desc, ok := LookupModule(event.Module)
+	if !ok || desc.Events == nil {
+		return nil, nil
+	}
+	return decodeEvent(context.Background(), desc.Events, event)
+}
+
+// LookupError returns the description of the given module-specific error.
+func LookupError(module string, code uint32) (*ModuleError, bool) {
+	desc, ok := LookupModule(module)
+	if !ok {
+		return nil, false
+	}
+	me, ok := desc.Errors[code]
+	if !ok {
+		return nil, false
+	}
+	return &me, true
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type testBody struct {
+	Value uint64 `json:"value"`
+}
+
+type testEvent struct {
+	Value uint64
+}
+
+func TestRegisterModule(t *testing.T) {
+	require := require.New(t)
+
+	desc := &ModuleDescriptor{
+		Name: "test.registry",
+		Methods: map[string]interface{}{
+			"test.registry.Do":     testBody{},
+			"test.registry.DoPtr":  &testBody{},
+			"test.registry.NoBody": nil,
+		},
+		Events: EventDecoderFunc(func(ev *types.Event) ([]DecodedEvent, error) {
+			var value uint64
+			if err := cbor.Unmarshal(ev.Value, &value); err != nil {
+				return nil, err
+			}
+			return []DecodedEvent{&testEvent{Value: value}}, nil
+		}),
+		Errors: map[uint32]ModuleError{
+			1: {Description: "busy", Class: ErrTransient},
+			2: {Description: "broken"},
+		},
+	}
+	require.NoError(RegisterModule(desc), "RegisterModule")
+	require.Error(RegisterModule(desc), "duplicate registration should fail")
+	require.Error(RegisterModule(&ModuleDescriptor{
+		Name:    "test.other",
+		Methods: map[string]interface{}{"test.registry.Do": testBody{}},
+	}), "registering methods of other modules should fail")
+	require.Contains(RegisteredModules(), "test.registry")
+
+	for _, method := range []string{"test.registry.Do", "test.registry.DoPtr"} {
+		body, err := DecodeCall(&types.Call{Method: method, Body: cbor.Marshal(&testBody{Value: 42})})
+		require.NoError(err, "DecodeCall")
+		require.Equal(&testBody{Value: 42}, body)
+	}
+	body, err := DecodeCall(&types.Call{Method: "test.registry.NoBody"})
+	require.NoError(err, "DecodeCall")
+	require.Nil(body)
+	_, err = DecodeCall(&types.Call{Method: "test.registry.Unknown"})
+	require.Error(err, "unknown methods should fail to decode")
+
+	evs, err := DecodeEvent(&types.Event{Module: "test.registry", Code: 1, Value: cbor.Marshal(uint64(7))})
+	require.NoError(err, "DecodeEvent")
+	require.Equal([]DecodedEvent{&testEvent{Value: 7}}, evs)
+	evs, err = DecodeEvent(&types.Event{Module: "test.unknown", Code: 1})
+	require.NoError(err, "DecodeEvent")
+	require.Nil(evs)
+
+	me, ok := LookupError("test.registry", 2)
+	require.True(ok)
+	require.Equal("broken", me.Description)
+	require.True(IsTransient(&types.FailedCallResult{Module: "test.registry", Code: 1}))
+	require.False(IsTransient(&types.FailedCallResult{Module: "test.registry", Code: 2}))
+}
+
+func TestMethodInfo(t *testing.T) {
+	require := require.New(t)
+
+	require.Error(RegisterModule(&ModuleDescriptor{
+		Name:        "test.info.invalid",
+		MethodInfos: map[string]MethodInfo{"test.info.invalid.Unknown": {}},
+	}), "describing unknown methods should fail")
+
+	require.NoError(RegisterModule(&ModuleDescriptor{
+		Name: "test.info",
+		Methods: map[string]interface{}{
+			"test.info.Open":     nil,
+			"test.info.Admin":    testBody{},
+			"test.info.Variable": testBody{},
+		},
+		MethodInfos: map[string]MethodInfo{
+			"test.info.Admin": {
+				Description: "Admins only.",
+				Roles:       []types.Role{types.Admin},
+				Events:      []uint32{1},
+			},
+			"test.info.Variable": {
+				BodyRoles: func(body interface{}) []types.Role {
+					if body.(*testBody).Value > 10 {
+						return []types.Role{types.Admin}
+					}
+					return nil
+				},
+			},
+		},
+	}))
+
+	m, ok := LookupMethod("test.info.Admin")
+	require.True(ok)
+	require.Equal("test.info", m.Module)
+	require.Equal("Admins only.", m.Description)
+	require.Equal([]uint32{1}, m.Events)
+	require.Equal("testBody", m.BodyType.Name())
+	_, ok = LookupMethod("test.info.Unknown")
+	require.False(ok)
+	require.Subset(RegisteredMethods(), []string{"test.info.Admin", "test.info.Open", "test.info.Variable"})
+
+	require.NoError(CheckRole("test.info.Open", types.User, nil))
+	require.NoError(CheckRole("test.info.Admin", types.Admin, &testBody{}))
+	err := CheckRole("test.info.Admin", types.User, &testBody{})
+	require.ErrorIs(err, ErrAuth)
+	require.NoError(CheckRole("test.info.Variable", types.User, &testBody{Value: 1}))
+	require.ErrorIs(CheckRole("test.info.Variable", types.User, &testBody{Value: 11}), ErrAuth)
+	require.NoError(CheckRole("test.info.Unknown", types.User, nil), "unknown methods should pass")
+}
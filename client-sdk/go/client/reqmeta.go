@@ -0,0 +1,89 @@
+package client
+
+import "context"
+
+// Metadata headers carrying request metadata set via WithRequestID and WithCaller.
+const (
+	// RequestIDHeader is the header containing the request identifier.
+	RequestIDHeader = "x-request-id"
+	// CallerHeader is the header containing the caller identity.
+	CallerHeader = "x-oasis-caller"
+)
+
+type (
+	requestIDKey struct{}
+	callerKey    struct{}
+)
+
+// WithRequestID returns a derived context that tags all operations issued with it with the given
+// request identifier, enabling end-to-end correlation with e.g. the backend request that caused
+// them. Connections created via the connection package forward it to the node as a request
+// header (see RequestIDHeader) and it is included in the logs and audit records of the SDK (see
+// RequestInfo).
+//
+// Request metadata is deliberately not used as a metric label, to bound the number of time series.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request identifier set in the given context (if any).
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// WithCaller returns a derived context that tags all operations issued with it with the given
+// caller identity (e.g. the tenant on whose behalf they are performed). Connections created via
+// the connection package forward it to the node as a request header (see CallerHeader) and it is
+// included in the logs and audit records of the SDK (see RequestInfo).
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller identity set in the given context (if any).
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerKey{}).(string)
+	return caller, ok
+}
+
+// RequestMetadata returns the request metadata set in the given context as alternating header
+// names and values, suitable for use as gRPC metadata or structured log fields.
+func RequestMetadata(ctx context.Context) []string {
+	var kv []string
+	if id, ok := RequestIDFromContext(ctx); ok {
+		kv = append(kv, RequestIDHeader, id)
+	}
+	if caller, ok := CallerFromContext(ctx); ok {
+		kv = append(kv, CallerHeader, caller)
+	}
+	return kv
+}
+
+// RequestInfo is the request metadata of an operation, included in logs and audit records so that
+// they can be correlated with the request that caused them.
+type RequestInfo struct {
+	// RequestID is the request identifier (see WithRequestID), if any.
+	RequestID string `json:"request_id,omitempty"`
+	// Caller is the caller identity (see WithCaller), if any.
+	Caller string `json:"caller,omitempty"`
+}
+
+// RequestInfoFromContext returns the request metadata set in the given context.
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	var ri RequestInfo
+	ri.RequestID, _ = RequestIDFromContext(ctx)
+	ri.Caller, _ = CallerFromContext(ctx)
+	return ri
+}
+
+// LogFields returns the set request metadata as alternating structured log field names and values.
+func (ri RequestInfo) LogFields() []interface{} {
+	var kv []interface{}
+	if ri.RequestID != "" {
+		kv = append(kv, "request_id", ri.RequestID)
+	}
+	if ri.Caller != "" {
+		kv = append(kv, "caller", ri.Caller)
+	}
+	return kv
+}
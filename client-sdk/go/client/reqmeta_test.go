@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestInfo(t *testing.T) {
+	require := require.New(t)
+
+	ri := RequestInfoFromContext(context.Background())
+	require.Equal(RequestInfo{}, ri)
+	require.Empty(ri.LogFields())
+
+	ctx := WithCaller(WithRequestID(context.Background(), "req-1"), "tenant-a")
+	ri = RequestInfoFromContext(ctx)
+	require.Equal(RequestInfo{RequestID: "req-1", Caller: "tenant-a"}, ri)
+	require.Equal([]interface{}{"request_id", "req-1", "caller", "tenant-a"}, ri.LogFields())
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// Shape is the expected shape of a CBOR-encoded value, loosely following CDDL.
+type Shape interface {
+	// check checks whether the given generically decoded value matches the shape.
+	check(v interface{}, path string) error
+	// String returns the CDDL-like representation of the shape.
+	String() string
+}
+
+type primitiveShape struct {
+	name  string
+	match func(v interface{}) bool
+}
+
+func (s *primitiveShape) check(v interface{}, path string) error {
+	if !s.match(v) {
+		return shapeMismatch(path, s, v)
+	}
+	return nil
+}
+
+func (s *primitiveShape) String() string {
+	return s.name
+}
+
+var (
+	// ShapeAny matches any value.
+	ShapeAny Shape = &primitiveShape{"any", func(interface{}) bool { return true }}
+	// ShapeUint matches unsigned integers.
+	ShapeUint Shape = &primitiveShape{"uint", func(v interface{}) bool {
+		_, ok := v.(uint64)
+		return ok
+	}}
+	// ShapeInt matches signed and unsigned integers.
+	ShapeInt Shape = &primitiveShape{"int", func(v interface{}) bool {
+		switch v.(type) {
+		case uint64, int64:
+			return true
+		default:
+			return false
+		}
+	}}
+	// ShapeBytes matches byte strings.
+	ShapeBytes Shape = &primitiveShape{"bstr", func(v interface{}) bool {
+		_, ok := v.([]byte)
+		return ok
+	}}
+	// ShapeText matches text strings.
+	ShapeText Shape = &primitiveShape{"tstr", func(v interface{}) bool {
+		_, ok := v.(string)
+		return ok
+	}}
+	// ShapeBool matches booleans.
+	ShapeBool Shape = &primitiveShape{"bool", func(v interface{}) bool {
+		_, ok := v.(bool)
+		return ok
+	}}
+	// ShapeNull matches null.
+	ShapeNull Shape = &primitiveShape{"null", func(v interface{}) bool { return v == nil }}
+)
+
+type arrayShape struct {
+	elem Shape
+}
+
+func (s *arrayShape) check(v interface{}, path string) error {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return shapeMismatch(path, s, v)
+	}
+	for i, elem := range arr {
+		if err := s.elem.check(elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *arrayShape) String() string {
+	return fmt.Sprintf("[* %s]", s.elem)
+}
+
+// ShapeArray matches arrays whose elements all match the given shape.
+func ShapeArray(elem Shape) Shape {
+	return &arrayShape{elem}
+}
+
+type tupleShape struct {
+	elems []Shape
+}
+
+func (s *tupleShape) check(v interface{}, path string) error {
+	arr, ok := v.([]interface{})
+	if !ok || len(arr) != len(s.elems) {
+		return shapeMismatch(path, s, v)
+	}
+	for i, elem := range arr {
+		if err := s.elems[i].check(elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *tupleShape) String() string {
+	elems := make([]string, 0, len(s.elems))
+	for _, elem := range s.elems {
+		elems = append(elems, elem.String())
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+// ShapeTuple matches arrays of the given length whose elements match the given shapes in order,
+// e.g. ShapeTuple(ShapeBytes, ShapeBytes) for BaseUnits.
+func ShapeTuple(elems ...Shape) Shape {
+	return &tupleShape{elems}
+}
+
+type optionalShape struct {
+	Shape
+}
+
+// ShapeOptional marks a struct field as optional. Optional fields may be missing or null.
+func ShapeOptional(s Shape) Shape {
+	return &optionalShape{s}
+}
+
+type structShape struct {
+	fields map[string]Shape
+}
+
+func (s *structShape) check(v interface{}, path string) error {
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return shapeMismatch(path, s, v)
+	}
+	for key, fs := range s.fields {
+		fv, present := m[key]
+		if _, optional := fs.(*optionalShape); optional && (!present || fv == nil) {
+			continue
+		}
+		if !present {
+			return fmt.Errorf("shape: %s: missing field '%s'", path, key)
+		}
+		if err := fs.check(fv, path+"."+key); err != nil {
+			return err
+		}
+	}
+	for key := range m {
+		name, ok := key.(string)
+		if !ok {
+			return fmt.Errorf("shape: %s: unexpected non-text key %v", path, key)
+		}
+		if _, known := s.fields[name]; !known {
+			return fmt.Errorf("shape: %s: unexpected field '%s'", path, name)
+		}
+	}
+	return nil
+}
+
+func (s *structShape) String() string {
+	keys := make([]string, 0, len(s.fields))
+	for key := range s.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fs := s.fields[key]
+		if opt, ok := fs.(*optionalShape); ok {
+			fields = append(fields, fmt.Sprintf("? %s: %s", key, opt.Shape))
+		} else {
+			fields = append(fields, fmt.Sprintf("%s: %s", key, fs))
+		}
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// ShapeStruct matches maps with text keys that contain exactly the given fields (apart from
+// optional ones, see ShapeOptional), each matching the corresponding shape.
+func ShapeStruct(fields map[string]Shape) Shape {
+	return &structShape{fields}
+}
+
+type mapShape struct {
+	key, value Shape
+}
+
+func (s *mapShape) check(v interface{}, path string) error {
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return shapeMismatch(path, s, v)
+	}
+	for key, value := range m {
+		kp := fmt.Sprintf("%s{%v}", path, key)
+		if err := s.key.check(key, kp); err != nil {
+			return err
+		}
+		if err := s.value.check(value, kp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mapShape) String() string {
+	return fmt.Sprintf("{* %s => %s}", s.key, s.value)
+}
+
+// ShapeMap matches maps whose keys and values all match the given shapes.
+func ShapeMap(key, value Shape) Shape {
+	return &mapShape{key, value}
+}
+
+func shapeMismatch(path string, expected Shape, v interface{}) error {
+	return fmt.Errorf("shape: %s: expected %s, got %T", path, expected, v)
+}
+
+// CheckShape checks whether the given CBOR-encoded value matches the given shape.
+func CheckShape(data cbor.RawMessage, shape Shape) error {
+	var v interface{}
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("shape: malformed value: %w", err)
+	}
+	return shape.check(v, "$")
+}
+
+// DecodeChecked checks whether the given CBOR-encoded value matches the given shape and decodes
+// it into dst. This makes decoding into user-supplied types fail loudly in case the response
+// has an unexpected shape, instead of silently ignoring unknown fields.
+func DecodeChecked(data cbor.RawMessage, shape Shape, dst interface{}) error {
+	if err := CheckShape(data, shape); err != nil {
+		return err
+	}
+	return cbor.Unmarshal(data, dst)
+}
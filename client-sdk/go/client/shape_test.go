@@ -0,0 +1,63 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestCheckShape(t *testing.T) {
+	require := require.New(t)
+
+	type entry struct {
+		Name    string          `json:"name"`
+		Amount  types.BaseUnits `json:"amount"`
+		Tags    []string        `json:"tags,omitempty"`
+		Enabled bool            `json:"enabled"`
+	}
+	shape := ShapeStruct(map[string]Shape{
+		"name":    ShapeText,
+		"amount":  ShapeTuple(ShapeBytes, ShapeBytes),
+		"tags":    ShapeOptional(ShapeArray(ShapeText)),
+		"enabled": ShapeBool,
+	})
+	require.Equal("{amount: [bstr, bstr], enabled: bool, name: tstr, ? tags: [* tstr]}", shape.String())
+
+	value := entry{
+		Name:    "test",
+		Amount:  types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		Enabled: true,
+	}
+	var decoded entry
+	require.NoError(DecodeChecked(cbor.Marshal(value), shape, &decoded), "DecodeChecked")
+	require.Equal(value.Name, decoded.Name)
+
+	value.Tags = []string{"a", "b"}
+	require.NoError(CheckShape(cbor.Marshal(value), shape), "optional fields may be present")
+
+	for _, tc := range []struct {
+		value interface{}
+		shape Shape
+		msg   string
+	}{
+		{map[string]interface{}{"name": "x"}, shape, "missing field"},
+		{map[string]interface{}{"name": "x", "amount": []interface{}{[]byte{}, []byte{}}, "enabled": true, "extra": 1}, shape, "unexpected field"},
+		{map[string]interface{}{"name": 1, "amount": []interface{}{[]byte{}, []byte{}}, "enabled": true}, shape, "wrong field type"},
+		{[]interface{}{uint64(1), "x"}, ShapeArray(ShapeUint), "wrong element type"},
+		{[]interface{}{uint64(1)}, ShapeTuple(ShapeUint, ShapeUint), "wrong tuple length"},
+		{map[uint64]string{1: "x"}, ShapeMap(ShapeUint, ShapeUint), "wrong map value type"},
+		{int64(-1), ShapeUint, "negative integer"},
+	} {
+		require.Error(CheckShape(cbor.Marshal(tc.value), tc.shape), tc.msg)
+	}
+
+	require.NoError(CheckShape(cbor.Marshal(int64(-1)), ShapeInt))
+	require.NoError(CheckShape(cbor.Marshal(map[uint64]string{1: "x"}), ShapeMap(ShapeUint, ShapeText)))
+	require.NoError(CheckShape(cbor.Marshal(nil), ShapeNull))
+	require.Error(CheckShape([]byte{0xff}, ShapeAny), "malformed CBOR should fail")
+}
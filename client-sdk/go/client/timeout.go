@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// Timeouts are the default timeouts of the different operation classes. They are only applied
+// when the caller passes a context without a deadline. A zero value disables the given timeout.
+type Timeouts struct {
+	// Query is the timeout of queries and block, transaction and event lookups.
+	Query time.Duration
+	// Submit is the timeout of transaction submissions.
+	Submit time.Duration
+	// Watch is the maximum amount of time a watcher waits for the next block before it considers
+	// the subscription stalled and terminates.
+	Watch time.Duration
+}
+
+// TimeoutError is the error returned when an operation did not complete in time.
+type TimeoutError struct {
+	// Operation is the name of the operation that stalled, e.g. a query method name.
+	Operation string
+	// Round is the round the operation was issued for (if any).
+	Round *uint64
+
+	err error
+}
+
+// Error returns the string representation of the timeout error.
+func (e *TimeoutError) Error() string {
+	if e.Round != nil {
+		return fmt.Sprintf("%s at round %d: timed out: %s", e.Operation, *e.Round, e.err)
+	}
+	return fmt.Sprintf("%s: timed out: %s", e.Operation, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.err
+}
+
+// isTimeout checks whether the given error signals an expired deadline.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return status.Code(err) == codes.DeadlineExceeded
+}
+
+// annotateTimeout wraps timeout errors with information about the stalled operation.
+func annotateTimeout(err error, op string, round *uint64) error {
+	if err == nil || !isTimeout(err) {
+		return err
+	}
+	return &TimeoutError{Operation: op, Round: round, err: err}
+}
+
+// wrapError annotates and classifies errors returned by the node.
+func wrapError(err error, op string, round *uint64) error {
+	return Classify(annotateTimeout(err, op, round))
+}
+
+// sharedQueryTimeout is the timeout of deduplicated queries (see WithQueryDeduplication) in case no
+// default query timeout is configured.
+const sharedQueryTimeout = 30 * time.Second
+
+// detachedContext is a context carrying the values of its parent, but not its deadline and
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// withDefaultTimeout applies the given timeout in case the context has no deadline.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// wrappedClient is a runtime client wrapper that applies default timeouts and classifies errors.
+type wrappedClient struct {
+	coreClient.RuntimeClient
+
+	timeouts Timeouts
+}
+
+func (wc *wrappedClient) SubmitTx(ctx context.Context, request *coreClient.SubmitTxRequest) ([]byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Submit)
+	defer cancel()
+	rsp, err := wc.RuntimeClient.SubmitTx(ctx, request)
+	return rsp, wrapError(err, "SubmitTx", nil)
+}
+
+func (wc *wrappedClient) SubmitTxMeta(ctx context.Context, request *coreClient.SubmitTxRequest) (*coreClient.SubmitTxMetaResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Submit)
+	defer cancel()
+	rsp, err := wc.RuntimeClient.SubmitTxMeta(ctx, request)
+	return rsp, wrapError(err, "SubmitTxMeta", nil)
+}
+
+func (wc *wrappedClient) SubmitTxNoWait(ctx context.Context, request *coreClient.SubmitTxRequest) error {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Submit)
+	defer cancel()
+	return wrapError(wc.RuntimeClient.SubmitTxNoWait(ctx, request), "SubmitTxNoWait", nil)
+}
+
+func (wc *wrappedClient) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	blk, err := wc.RuntimeClient.GetGenesisBlock(ctx, runtimeID)
+	return blk, wrapError(err, "GetGenesisBlock", nil)
+}
+
+func (wc *wrappedClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	blk, err := wc.RuntimeClient.GetBlock(ctx, request)
+	return blk, wrapError(err, "GetBlock", &request.Round)
+}
+
+func (wc *wrappedClient) GetLastRetainedBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	blk, err := wc.RuntimeClient.GetLastRetainedBlock(ctx, runtimeID)
+	return blk, wrapError(err, "GetLastRetainedBlock", nil)
+}
+
+func (wc *wrappedClient) GetTransactions(ctx context.Context, request *coreClient.GetTransactionsRequest) ([][]byte, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	txs, err := wc.RuntimeClient.GetTransactions(ctx, request)
+	return txs, wrapError(err, "GetTransactions", &request.Round)
+}
+
+func (wc *wrappedClient) GetTransactionsWithResults(ctx context.Context, request *coreClient.GetTransactionsRequest) ([]*coreClient.TransactionWithResults, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	txs, err := wc.RuntimeClient.GetTransactionsWithResults(ctx, request)
+	return txs, wrapError(err, "GetTransactionsWithResults", &request.Round)
+}
+
+func (wc *wrappedClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	evs, err := wc.RuntimeClient.GetEvents(ctx, request)
+	return evs, wrapError(err, "GetEvents", &request.Round)
+}
+
+func (wc *wrappedClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, wc.timeouts.Query)
+	defer cancel()
+	rsp, err := wc.RuntimeClient.Query(ctx, request)
+	return rsp, wrapError(err, request.Method, &request.Round)
+}
+
+func (wc *wrappedClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ch, sub, err := wc.RuntimeClient.WatchBlocks(ctx, runtimeID)
+	return ch, sub, wrapError(err, "WatchBlocks", nil)
+}
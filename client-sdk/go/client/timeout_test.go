@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+type blockingRuntimeClient struct {
+	coreClient.RuntimeClient
+}
+
+func (b *blockingRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTimeoutClient(t *testing.T) {
+	require := require.New(t)
+
+	tc := &wrappedClient{
+		RuntimeClient: &blockingRuntimeClient{},
+		timeouts:      Timeouts{Query: 10 * time.Millisecond},
+	}
+
+	_, err := tc.Query(context.Background(), &coreClient.QueryRequest{Method: "accounts.Balances", Round: 42})
+	require.Error(err)
+	require.ErrorIs(err, context.DeadlineExceeded)
+	var te *TimeoutError
+	require.True(errors.As(err, &te))
+	require.Equal("accounts.Balances", te.Operation)
+	require.EqualValues(42, *te.Round)
+	require.Contains(err.Error(), "accounts.Balances at round 42")
+	require.ErrorIs(err, ErrTransient)
+
+	// Cancellation should not be reported as a timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tc.Query(ctx, &coreClient.QueryRequest{Method: "accounts.Balances"})
+	require.ErrorIs(err, context.Canceled)
+	require.False(errors.As(err, &te))
+}
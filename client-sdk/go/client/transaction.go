@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
@@ -17,14 +18,26 @@ type TransactionBuilder struct {
 	ts *types.TransactionSigner
 
 	callMeta interface{}
+	err      error
 }
 
 // NewTransactionBuilder creates a new transaction builder.
+//
+// In case the node was found to only know the method under an alias (see RegisterMethodAlias),
+// the alias is used instead.
+//
+// The body is encoded using the codec configuration of the runtime client (see WithCodec).
+// Encoding failures are reported when signing.
 func NewTransactionBuilder(rc RuntimeClient, method string, body interface{}) *TransactionBuilder {
-	return &TransactionBuilder{
+	method = ResolveMethod(rc, method)
+	tb := &TransactionBuilder{
 		rc: rc,
 		tx: types.NewTransaction(nil, method, body),
 	}
+	if codec := CodecOf(rc); codec != (CodecConfig{}) {
+		tb.tx.Call.Body, tb.err = codec.EncodeCallBody(method, body)
+	}
+	return tb
 }
 
 // SetFeeAmount configures the fee amount to be paid by the caller.
@@ -39,6 +52,18 @@ func (tb *TransactionBuilder) SetFeeGas(gas uint64) *TransactionBuilder {
 	return tb
 }
 
+// SetFeePriorityTip raises the gas price implied by the fee by the given per-gas tip, increasing
+// the priority of the transaction (see types.Fee.AddPriorityTip).
+//
+// The tip is added to the current fee amount so it must be set after SetFeeAmount and SetFeeGas.
+// Failures to add the tip are reported when signing.
+func (tb *TransactionBuilder) SetFeePriorityTip(tip types.Quantity) *TransactionBuilder {
+	if err := tb.tx.AuthInfo.Fee.AddPriorityTip(&tip); err != nil && tb.err == nil {
+		tb.err = err
+	}
+	return tb
+}
+
 // SetFeeConsensusMessages configures the maximum number of consensus messages that can be emitted
 // by the transaction.
 func (tb *TransactionBuilder) SetFeeConsensusMessages(consensusMessages uint32) *TransactionBuilder {
@@ -114,10 +139,24 @@ func (tb *TransactionBuilder) GetSignedTransaction() *types.UnverifiedTransactio
 	return tb.ts.UnverifiedTransaction()
 }
 
+// Hash returns the hash of the signed transaction as reported by nodes and explorers.
+//
+// Since the hash covers all signatures, it should be computed after all signatures have been
+// appended. Returns an error in case the transaction has not been signed yet.
+func (tb *TransactionBuilder) Hash() (hash.Hash, error) {
+	if tb.ts == nil {
+		return hash.Hash{}, fmt.Errorf("unable to compute hash of unsigned transaction")
+	}
+	return tb.ts.UnverifiedTransaction().Hash(), nil
+}
+
 // AppendSign signs the transaction and appends the signature.
 //
 // The signer must be specified in the AuthInfo.
 func (tb *TransactionBuilder) AppendSign(ctx context.Context, signer signature.Signer) error {
+	if tb.err != nil {
+		return tb.err
+	}
 	if tb.ts == nil {
 		tb.ts = tb.tx.PrepareForSigning()
 	}
@@ -138,7 +177,7 @@ func (tb *TransactionBuilder) DecodeResult(result *types.CallResult, rsp interfa
 	switch {
 	case result.IsUnknown():
 		// This should never happen as the inner result should not be unknown.
-		return fmt.Errorf("got unknown result: %X", result.Unknown)
+		return Classify(fmt.Errorf("got unknown result: %s", types.RedactPayload(result.Unknown)))
 	case result.IsSuccess():
 		if rsp != nil {
 			if err := cbor.Unmarshal(result.Ok, rsp); err != nil {
@@ -147,7 +186,7 @@ func (tb *TransactionBuilder) DecodeResult(result *types.CallResult, rsp interfa
 		}
 		return nil
 	default:
-		return result.Failed
+		return Classify(result.Failed)
 	}
 }
 
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestTransactionBuilderPriorityTip(t *testing.T) {
+	require := require.New(t)
+
+	tb := NewTransactionBuilder(&runtimeClient{}, "accounts.Transfer", nil).
+		SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(1_000), types.NativeDenomination)).
+		SetFeeGas(100).
+		SetFeePriorityTip(*quantity.NewFromUint64(5))
+	require.EqualValues(quantity.NewFromUint64(1_500), &tb.GetTransaction().AuthInfo.Fee.Amount.Amount)
+
+	// A tip without a gas limit should be reported when signing.
+	tb = NewTransactionBuilder(&runtimeClient{}, "accounts.Transfer", nil).
+		SetFeePriorityTip(*quantity.NewFromUint64(5))
+	err := tb.AppendSign(context.Background(), nil)
+	require.ErrorContains(err, "gas limit must be set")
+}
@@ -0,0 +1,304 @@
+// Package clustering groups related addresses for compliance review, based on heuristics over
+// indexed accounts events and transactions: shared funding sources, co-spending (signing the same
+// transaction) and role co-assignment.
+//
+// Clusters are only hints of common control and need to be reviewed, e.g. exchanges funding many
+// unrelated customers should be excluded (see WithExcluded).
+package clustering
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/statements"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// methodInitOwners is the method assigning roles to the initial owners.
+const methodInitOwners = "accounts.InitOwners"
+
+// IndexedTransaction is a transaction together with the block it was included in.
+type IndexedTransaction struct {
+	// Round is the round in which the transaction was included.
+	Round uint64
+	// Index is the index of the transaction in the block.
+	Index int
+	// Tx is the transaction.
+	Tx *types.Transaction
+	// Success is true iff the transaction succeeded.
+	Success bool
+}
+
+// Source is the source of the indexed data addresses are clustered by, e.g. an indexer.
+type Source interface {
+	// ForEachEvent calls the given function for all accounts events emitted in the given range of
+	// rounds (inclusive), in order. Iteration stops at the first error.
+	ForEachEvent(ctx context.Context, from, to uint64, fn func(*statements.IndexedEvent) error) error
+	// ForEachTransaction calls the given function for all transactions included in the given
+	// range of rounds (inclusive), in order. Iteration stops at the first error.
+	ForEachTransaction(ctx context.Context, from, to uint64, fn func(*IndexedTransaction) error) error
+}
+
+// nodeSource is a source querying a node directly.
+type nodeSource struct {
+	statements.Source
+
+	rc client.RuntimeClient
+}
+
+// NewNodeSource creates a new source querying the given node directly instead of an indexer. As
+// this fetches the events and transactions of each round, it is only suitable for short ranges.
+func NewNodeSource(rc client.RuntimeClient) Source {
+	return &nodeSource{
+		Source: statements.NewNodeSource(rc),
+		rc:     rc,
+	}
+}
+
+func (s *nodeSource) ForEachTransaction(ctx context.Context, from, to uint64, fn func(*IndexedTransaction) error) error {
+	for round := from; round <= to; round++ {
+		txs, err := s.rc.GetTransactionsWithResults(ctx, round)
+		if err != nil {
+			return fmt.Errorf("clustering: failed to fetch transactions at round %d: %w", round, err)
+		}
+		for i, txr := range txs {
+			var tx types.Transaction
+			if err = cbor.Unmarshal(txr.Tx.Body, &tx); err != nil {
+				continue
+			}
+			itx := IndexedTransaction{
+				Round:   round,
+				Index:   i,
+				Tx:      &tx,
+				Success: txr.Result.IsSuccess() && !txr.Result.IsUnknown(),
+			}
+			if err = fn(&itx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reason is the reason two addresses were linked.
+type Reason string
+
+const (
+	// ReasonFunding links an address to the source of its first incoming transfer.
+	ReasonFunding Reason = "funding"
+	// ReasonCoSpending links the signers of the same transaction.
+	ReasonCoSpending Reason = "co_spending"
+	// ReasonRoleAssignment links addresses assigned roles in the same transaction.
+	ReasonRoleAssignment Reason = "role_assignment"
+)
+
+// Link is evidence that two addresses are related.
+type Link struct {
+	// From is the first linked address (e.g. the funding source).
+	From types.Address `json:"from"`
+	// To is the second linked address.
+	To types.Address `json:"to"`
+	// Reason is the reason for the link.
+	Reason Reason `json:"reason"`
+	// Round is the round of the event or transaction the link was derived from.
+	Round uint64 `json:"round"`
+}
+
+// Cluster is a group of related addresses.
+type Cluster struct {
+	// ID is the identifier of the cluster, derived from its lowest address.
+	ID string `json:"id"`
+	// Addresses are the addresses in the cluster in lexicographic order.
+	Addresses []types.Address `json:"addresses"`
+	// Links are the links between the addresses of the cluster.
+	Links []Link `json:"links"`
+}
+
+// Option is a clustering option.
+type Option func(*options)
+
+type options struct {
+	heuristics map[Reason]bool
+	excluded   map[types.Address]bool
+}
+
+// WithHeuristics only applies the given heuristics (all by default).
+func WithHeuristics(reasons ...Reason) Option {
+	return func(o *options) {
+		o.heuristics = make(map[Reason]bool)
+		for _, r := range reasons {
+			o.heuristics[r] = true
+		}
+	}
+}
+
+// WithExcluded never links the given addresses, e.g. exchanges or faucets funding many unrelated
+// addresses.
+func WithExcluded(addresses ...types.Address) Option {
+	return func(o *options) {
+		for _, a := range addresses {
+			o.excluded[a] = true
+		}
+	}
+}
+
+// Clustering is the result of clustering addresses.
+type Clustering struct {
+	// Clusters are the clusters of at least two addresses, ordered by identifier.
+	Clusters []*Cluster `json:"clusters"`
+
+	byAddress map[types.Address]*Cluster
+}
+
+// ClusterOf returns the cluster containing the given address (if any).
+func (c *Clustering) ClusterOf(address types.Address) (*Cluster, bool) {
+	cluster, ok := c.byAddress[address]
+	return cluster, ok
+}
+
+// builder accumulates links using a disjoint-set forest.
+type builder struct {
+	opts    options
+	parents map[types.Address]types.Address
+	links   []Link
+}
+
+func (b *builder) find(a types.Address) types.Address {
+	for {
+		parent, ok := b.parents[a]
+		if !ok || parent == a {
+			return a
+		}
+		// Path halving.
+		if grandparent := b.parents[parent]; grandparent != parent {
+			b.parents[a] = grandparent
+		}
+		a = parent
+	}
+}
+
+func (b *builder) link(from, to types.Address, reason Reason, round uint64) {
+	if !b.opts.heuristics[reason] || from == to || b.opts.excluded[from] || b.opts.excluded[to] {
+		return
+	}
+	b.links = append(b.links, Link{From: from, To: to, Reason: reason, Round: round})
+	for _, a := range []types.Address{from, to} {
+		if _, ok := b.parents[a]; !ok {
+			b.parents[a] = a
+		}
+	}
+	if rf, rt := b.find(from), b.find(to); rf != rt {
+		b.parents[rt] = rf
+	}
+}
+
+// Build clusters the addresses active in the given range of rounds (inclusive).
+func Build(ctx context.Context, src Source, from, to uint64, opts ...Option) (*Clustering, error) {
+	if from > to {
+		return nil, fmt.Errorf("clustering: invalid range %d-%d", from, to)
+	}
+	o := options{excluded: make(map[types.Address]bool)}
+	WithHeuristics(ReasonFunding, ReasonCoSpending, ReasonRoleAssignment)(&o)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	b := builder{
+		opts:    o,
+		parents: make(map[types.Address]types.Address),
+	}
+
+	if o.heuristics[ReasonFunding] {
+		funded := make(map[types.Address]bool)
+		err := src.ForEachEvent(ctx, from, to, func(ev *statements.IndexedEvent) error {
+			t := ev.Event.Transfer
+			if t == nil || funded[t.To] {
+				return nil
+			}
+			funded[t.To] = true
+			b.link(t.From, t.To, ReasonFunding, ev.Round)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if o.heuristics[ReasonCoSpending] || o.heuristics[ReasonRoleAssignment] {
+		err := src.ForEachTransaction(ctx, from, to, func(itx *IndexedTransaction) error {
+			if itx.Success {
+				b.addTransaction(itx)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.clustering(), nil
+}
+
+// addTransaction adds the links derived from the given successful transaction.
+func (b *builder) addTransaction(itx *IndexedTransaction) {
+	var signers []types.Address
+	for _, si := range itx.Tx.AuthInfo.SignerInfo {
+		if addr, err := si.AddressSpec.Address(); err == nil {
+			signers = append(signers, addr)
+		}
+	}
+	for i := 1; i < len(signers); i++ {
+		b.link(signers[0], signers[i], ReasonCoSpending, itx.Round)
+	}
+
+	if itx.Tx.Call.Method != methodInitOwners || itx.Tx.Call.Format != types.CallFormatPlain {
+		return
+	}
+	var owners []accounts.RoleAddress
+	if err := cbor.Unmarshal(itx.Tx.Call.Body, &owners); err != nil || len(owners) == 0 {
+		return
+	}
+	for _, owner := range owners[1:] {
+		b.link(owners[0].Addr, owner.Addr, ReasonRoleAssignment, itx.Round)
+	}
+}
+
+// clustering assembles the clusters from the accumulated links.
+func (b *builder) clustering() *Clustering {
+	members := make(map[types.Address][]types.Address)
+	for a := range b.parents {
+		root := b.find(a)
+		members[root] = append(members[root], a)
+	}
+
+	c := Clustering{
+		Clusters:  []*Cluster{},
+		byAddress: make(map[types.Address]*Cluster),
+	}
+	for _, addrs := range members {
+		sort.Slice(addrs, func(i, j int) bool { return addrs[i].String() < addrs[j].String() })
+		rawRep, _ := addrs[0].MarshalBinary()
+		id := hash.NewFromBytes(rawRep)
+		cluster := &Cluster{
+			ID:        hex.EncodeToString(id[:8]),
+			Addresses: addrs,
+			Links:     []Link{},
+		}
+		for _, a := range addrs {
+			c.byAddress[a] = cluster
+		}
+		c.Clusters = append(c.Clusters, cluster)
+	}
+	for _, link := range b.links {
+		cluster := c.byAddress[link.From]
+		cluster.Links = append(cluster.Links, link)
+	}
+	sort.Slice(c.Clusters, func(i, j int) bool { return c.Clusters[i].ID < c.Clusters[j].ID })
+	return &c
+}
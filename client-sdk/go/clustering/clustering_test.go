@@ -0,0 +1,108 @@
+package clustering
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/statements"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeSource struct {
+	events []*statements.IndexedEvent
+	txs    []*IndexedTransaction
+}
+
+func (s *fakeSource) ForEachEvent(ctx context.Context, from, to uint64, fn func(*statements.IndexedEvent) error) error {
+	for _, ev := range s.events {
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeSource) ForEachTransaction(ctx context.Context, from, to uint64, fn func(*IndexedTransaction) error) error {
+	for _, tx := range s.txs {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBuild(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie, dave := sdkTesting.Alice, sdkTesting.Bob, sdkTesting.Charlie, sdkTesting.Dave
+	exchange := types.NewAddressForModule("test", []byte("exchange"))
+	transfer := func(round uint64, from, to types.Address) *statements.IndexedEvent {
+		return &statements.IndexedEvent{Round: round, Event: &accounts.Event{Transfer: &accounts.TransferEvent{From: from, To: to}}}
+	}
+	tx := func(round uint64, method string, body interface{}, success bool, signers ...sdkTesting.TestKey) *IndexedTransaction {
+		itx := types.NewTransaction(nil, method, body)
+		for _, signer := range signers {
+			itx.AppendAuthSignature(signer.SigSpec, 0)
+		}
+		return &IndexedTransaction{Round: round, Tx: itx, Success: success}
+	}
+
+	src := &fakeSource{
+		events: []*statements.IndexedEvent{
+			transfer(1, alice.Address, bob.Address),
+			// Only the first incoming transfer is a funding source.
+			transfer(2, charlie.Address, bob.Address),
+			transfer(3, exchange, charlie.Address),
+			transfer(3, exchange, dave.Address),
+		},
+		txs: []*IndexedTransaction{
+			tx(4, "accounts.Transfer", nil, true, charlie, dave),
+			tx(5, "accounts.Transfer", nil, false, alice, charlie),
+			tx(6, "accounts.InitOwners", []accounts.RoleAddress{
+				{Addr: sdkTesting.Erin.Address, Role: types.Admin},
+				{Addr: sdkTesting.Frank.Address, Role: types.MintVoter},
+			}, true, sdkTesting.Grace),
+		},
+	}
+	ctx := context.Background()
+
+	c, err := Build(ctx, src, 1, 6, WithExcluded(exchange))
+	require.NoError(err, "Build")
+	require.Len(c.Clusters, 3)
+	reasons := make(map[Reason][]types.Address)
+	for _, cluster := range c.Clusters {
+		require.Len(cluster.Addresses, 2)
+		require.Len(cluster.Links, 1)
+		reasons[cluster.Links[0].Reason] = cluster.Addresses
+	}
+	require.ElementsMatch([]types.Address{alice.Address, bob.Address}, reasons[ReasonFunding])
+	require.ElementsMatch([]types.Address{charlie.Address, dave.Address}, reasons[ReasonCoSpending])
+	require.ElementsMatch([]types.Address{sdkTesting.Erin.Address, sdkTesting.Frank.Address}, reasons[ReasonRoleAssignment])
+
+	ca, ok := c.ClusterOf(alice.Address)
+	require.True(ok)
+	cb, _ := c.ClusterOf(bob.Address)
+	require.Equal(ca.ID, cb.ID)
+	_, ok = c.ClusterOf(sdkTesting.Grace.Address)
+	require.False(ok, "unlinked addresses should not be clustered")
+	_, ok = c.ClusterOf(exchange)
+	require.False(ok, "excluded addresses should not be clustered")
+
+	// Without exclusions, the exchange links its customers.
+	c, err = Build(ctx, src, 1, 6)
+	require.NoError(err, "Build")
+	cluster, ok := c.ClusterOf(exchange)
+	require.True(ok)
+	require.ElementsMatch([]types.Address{exchange, charlie.Address, dave.Address}, cluster.Addresses)
+
+	c, err = Build(ctx, src, 1, 6, WithHeuristics(ReasonRoleAssignment))
+	require.NoError(err, "Build")
+	require.Len(c.Clusters, 1)
+
+	_, err = Build(ctx, src, 6, 1)
+	require.Error(err, "invalid ranges should be rejected")
+}
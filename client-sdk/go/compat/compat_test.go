@@ -0,0 +1,235 @@
+package compat
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var update = flag.Bool("update", false, "regenerate the test vectors")
+
+var vectorsPath = filepath.Join("testdata", "vectors.json")
+
+type addressVector struct {
+	// Kind is the signature address spec kind (ed25519 or secp256k1eth).
+	Kind string `json:"kind"`
+	// PublicKey is the Base64-encoded public key.
+	PublicKey string `json:"public_key"`
+	// Address is the hex-encoded raw address.
+	Address string `json:"address"`
+}
+
+type transferVector struct {
+	// Signer is the hex-encoded raw address of the signer.
+	Signer string `json:"signer"`
+	// SignerPublicKey is the Base64-encoded Ed25519 public key of the signer.
+	SignerPublicKey   string `json:"signer_public_key"`
+	Nonce             uint64 `json:"nonce"`
+	FeeAmount         string `json:"fee_amount"`
+	FeeDenomination   string `json:"fee_denomination"`
+	FeeGas            uint64 `json:"fee_gas"`
+	ConsensusMessages uint32 `json:"consensus_messages"`
+	// To is the hex-encoded raw address of the recipient.
+	To           string `json:"to"`
+	Amount       string `json:"amount"`
+	Denomination string `json:"denomination"`
+	// Encoded is the hex-encoded CBOR encoding of the transaction.
+	Encoded string `json:"encoded"`
+}
+
+type eventVector struct {
+	Module string `json:"module"`
+	Code   uint32 `json:"code"`
+	// Value is the hex-encoded CBOR event value.
+	Value string `json:"value"`
+	// From, To and Owner are the hex-encoded raw addresses in the event (if any).
+	From         string `json:"from,omitempty"`
+	To           string `json:"to,omitempty"`
+	Owner        string `json:"owner,omitempty"`
+	Amount       string `json:"amount"`
+	Denomination string `json:"denomination"`
+}
+
+type vectors struct {
+	Addresses []addressVector  `json:"addresses"`
+	Transfers []transferVector `json:"transfers"`
+	Events    []eventVector    `json:"events"`
+}
+
+func rawAddress(a types.Address) string {
+	data, _ := a.MarshalBinary()
+	return hex.EncodeToString(data)
+}
+
+func generateVectors(t *testing.T) *vectors {
+	var v vectors
+
+	for _, tk := range []sdkTesting.TestKey{sdkTesting.Alice, sdkTesting.Bob, sdkTesting.Dave} {
+		var (
+			kind string
+			pk   []byte
+			err  error
+		)
+		switch {
+		case tk.SigSpec.Ed25519 != nil:
+			kind = "ed25519"
+			pk, err = tk.SigSpec.Ed25519.MarshalBinary()
+		case tk.SigSpec.Secp256k1Eth != nil:
+			kind = "secp256k1eth"
+			pk, err = tk.SigSpec.Secp256k1Eth.MarshalBinary()
+		}
+		require.NoError(t, err, "MarshalBinary")
+		v.Addresses = append(v.Addresses, addressVector{
+			Kind:      kind,
+			PublicKey: base64.StdEncoding.EncodeToString(pk),
+			Address:   rawAddress(tk.Address),
+		})
+	}
+
+	for _, tc := range []struct {
+		nonce  uint64
+		fee    uint64
+		feeDen types.Denomination
+		msgs   uint32
+		amount uint64
+		den    types.Denomination
+	}{
+		{0, 0, types.NativeDenomination, 1, 1, types.NativeDenomination},
+		{7, 1_000_000, types.NativeDenomination, 1, 1_000_000_000, types.NativeDenomination},
+		{42, 2_500, types.NativeDenomination, 1, 12_345_678, types.Denomination("HLUSD")},
+		// The Go SDK omits a zero consensus_messages field.
+		{43, 2_500, types.NativeDenomination, 0, 1, types.NativeDenomination},
+	} {
+		fee := types.Fee{
+			Amount:            types.NewBaseUnits(*quantity.NewFromUint64(tc.fee), tc.feeDen),
+			Gas:               10_000,
+			ConsensusMessages: tc.msgs,
+		}
+		body := accounts.Transfer{
+			To:     sdkTesting.Bob.Address,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(tc.amount), tc.den),
+		}
+		tx := accounts.NewTransferTx(&fee, &body)
+		tx.AppendAuthSignature(sdkTesting.Alice.SigSpec, tc.nonce)
+
+		pk, err := sdkTesting.Alice.SigSpec.Ed25519.MarshalBinary()
+		require.NoError(t, err, "MarshalBinary")
+		v.Transfers = append(v.Transfers, transferVector{
+			Signer:            rawAddress(sdkTesting.Alice.Address),
+			SignerPublicKey:   base64.StdEncoding.EncodeToString(pk),
+			Nonce:             tc.nonce,
+			FeeAmount:         fee.Amount.Amount.String(),
+			FeeDenomination:   string(tc.feeDen),
+			FeeGas:            fee.Gas,
+			ConsensusMessages: fee.ConsensusMessages,
+			To:                rawAddress(body.To),
+			Amount:            body.Amount.Amount.String(),
+			Denomination:      string(tc.den),
+			Encoded:           hex.EncodeToString(cbor.Marshal(tx)),
+		})
+	}
+
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(1_000), types.Denomination("HLUSD"))
+	v.Events = append(v.Events,
+		eventVector{
+			Module: accounts.ModuleName,
+			Code:   accounts.TransferEventCode,
+			Value: hex.EncodeToString(cbor.Marshal([]*accounts.TransferEvent{
+				{From: sdkTesting.Alice.Address, To: sdkTesting.Bob.Address, Amount: amount},
+			})),
+			From:         rawAddress(sdkTesting.Alice.Address),
+			To:           rawAddress(sdkTesting.Bob.Address),
+			Amount:       amount.Amount.String(),
+			Denomination: string(amount.Denomination),
+		},
+		eventVector{
+			Module: accounts.ModuleName,
+			Code:   accounts.MintEventCode,
+			Value: hex.EncodeToString(cbor.Marshal([]*accounts.MintEvent{
+				{Owner: sdkTesting.Bob.Address, Amount: amount},
+			})),
+			Owner:        rawAddress(sdkTesting.Bob.Address),
+			Amount:       amount.Amount.String(),
+			Denomination: string(amount.Denomination),
+		},
+		eventVector{
+			Module: accounts.ModuleName,
+			Code:   accounts.BurnEventCode,
+			Value: hex.EncodeToString(cbor.Marshal([]*accounts.BurnEvent{
+				{Owner: sdkTesting.Alice.Address, Amount: amount},
+			})),
+			Owner:        rawAddress(sdkTesting.Alice.Address),
+			Amount:       amount.Amount.String(),
+			Denomination: string(amount.Denomination),
+		},
+	)
+	return &v
+}
+
+func TestVectors(t *testing.T) {
+	require := require.New(t)
+
+	generated := generateVectors(t)
+	if *update {
+		data, err := json.MarshalIndent(generated, "", "  ")
+		require.NoError(err, "json.MarshalIndent")
+		require.NoError(os.WriteFile(vectorsPath, append(data, '\n'), 0o644), "WriteFile") //nolint: gosec
+		return
+	}
+
+	data, err := os.ReadFile(vectorsPath)
+	require.NoError(err, "ReadFile")
+	var stored vectors
+	require.NoError(json.Unmarshal(data, &stored), "json.Unmarshal")
+	require.Equal(&stored, generated, "Go SDK encoding diverged from the test vectors (run with -update if intentional)")
+}
+
+func TestEventVectorsDecode(t *testing.T) {
+	require := require.New(t)
+
+	data, err := os.ReadFile(vectorsPath)
+	require.NoError(err, "ReadFile")
+	var stored vectors
+	require.NoError(json.Unmarshal(data, &stored), "json.Unmarshal")
+
+	for _, ev := range stored.Events {
+		value, err := hex.DecodeString(ev.Value)
+		require.NoError(err, "hex.DecodeString")
+		decoded, err := accounts.DecodeEvent(&types.Event{Module: ev.Module, Code: ev.Code, Value: value})
+		require.NoError(err, "DecodeEvent")
+		require.Len(decoded, 1)
+
+		ae := decoded[0].(*accounts.Event)
+		var (
+			owner  string
+			amount types.BaseUnits
+		)
+		switch {
+		case ae.Transfer != nil:
+			require.Equal(ev.From, rawAddress(ae.Transfer.From))
+			owner, amount = ev.To, ae.Transfer.Amount
+			require.Equal(owner, rawAddress(ae.Transfer.To))
+		case ae.Mint != nil:
+			require.Equal(ev.Owner, rawAddress(ae.Mint.Owner))
+			amount = ae.Mint.Amount
+		case ae.Burn != nil:
+			require.Equal(ev.Owner, rawAddress(ae.Burn.Owner))
+			amount = ae.Burn.Amount
+		}
+		require.Equal(ev.Amount, amount.Amount.String())
+		require.Equal(ev.Denomination, string(amount.Denomination))
+	}
+}
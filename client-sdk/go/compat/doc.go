@@ -0,0 +1,14 @@
+// Package compat contains the conformance suite checking that the Go and TypeScript SDKs encode
+// addresses, transactions and events identically.
+//
+// The suite is driven by the test vectors in testdata/vectors.json. The Go tests check that the
+// Go SDK reproduces the vectors byte for byte and the TypeScript tests (see
+// client-sdk/ts-web/rt/test/compat.test.ts) perform the same check against the web SDK.
+//
+// The one known difference is that the Go SDK omits a zero consensus_messages fee field while the
+// TypeScript SDK always encodes it. Both encodings decode to the same fee, and the vectors include
+// a transfer with zero consensus messages so that the TypeScript tests pin the difference down. To
+// regenerate the vectors after an intentional encoding change run:
+//
+//	go test ./compat -update
+package compat
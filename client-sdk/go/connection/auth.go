@@ -0,0 +1,141 @@
+package connection
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Metadata headers carrying request authentication.
+const (
+	// AuthPublicKeyHeader is the header containing the JSON-encoded public key of the signer.
+	AuthPublicKeyHeader = "x-oasis-auth-public-key"
+	// AuthTimestampHeader is the header containing the POSIX timestamp of the request.
+	AuthTimestampHeader = "x-oasis-auth-timestamp"
+	// AuthSignatureHeader is the header containing the base64-encoded request signature.
+	AuthSignatureHeader = "x-oasis-auth-signature"
+)
+
+// requestAuthMessage returns the message signed to authenticate a request.
+func requestAuthMessage(method string, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("%s\n%d", method, timestamp))
+}
+
+// requestAuthenticator signs outgoing requests.
+type requestAuthenticator struct {
+	signer signature.Signer
+	rawPk  string
+	now    func() time.Time
+}
+
+func newRequestAuthenticator(signer signature.Signer) (*requestAuthenticator, error) {
+	rawPk, err := json.Marshal(&types.PublicKey{PublicKey: signer.Public()})
+	if err != nil {
+		return nil, fmt.Errorf("unsupported request signer: %w", err)
+	}
+	return &requestAuthenticator{
+		signer: signer,
+		rawPk:  string(rawPk),
+		now:    time.Now,
+	}, nil
+}
+
+func (ra *requestAuthenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	timestamp := ra.now().Unix()
+	sig, err := ra.signer.ContextSign(signature.ContextBase(signature.ContextKindRequestAuth), requestAuthMessage(method, timestamp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		AuthPublicKeyHeader, ra.rawPk,
+		AuthTimestampHeader, strconv.FormatInt(timestamp, 10),
+		AuthSignatureHeader, base64.StdEncoding.EncodeToString(sig),
+	), nil
+}
+
+func (ra *requestAuthenticator) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	ctx, err := ra.authenticate(ctx, method)
+	if err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (ra *requestAuthenticator) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx, err := ra.authenticate(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// VerifyRequestAuth verifies the authentication headers of a request to the given gRPC method and
+// returns the public key of the signer. Requests with timestamps deviating by more than maxSkew
+// from now are rejected.
+//
+// This is meant for RPC providers authenticating SDK traffic.
+func VerifyRequestAuth(md metadata.MD, method string, now time.Time, maxSkew time.Duration) (*types.PublicKey, error) {
+	header := func(name string) (string, error) {
+		values := md.Get(name)
+		if len(values) != 1 {
+			return "", fmt.Errorf("request auth: missing or duplicate %s header", name)
+		}
+		return values[0], nil
+	}
+
+	rawPk, err := header(AuthPublicKeyHeader)
+	if err != nil {
+		return nil, err
+	}
+	rawTimestamp, err := header(AuthTimestampHeader)
+	if err != nil {
+		return nil, err
+	}
+	rawSig, err := header(AuthSignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var pk types.PublicKey
+	if err = json.Unmarshal([]byte(rawPk), &pk); err != nil {
+		return nil, fmt.Errorf("request auth: malformed public key: %w", err)
+	}
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("request auth: malformed timestamp: %w", err)
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > maxSkew || skew < -maxSkew {
+		return nil, fmt.Errorf("request auth: timestamp outside of allowed window")
+	}
+	sig, err := base64.StdEncoding.DecodeString(rawSig)
+	if err != nil {
+		return nil, fmt.Errorf("request auth: malformed signature: %w", err)
+	}
+	if !pk.Verify(signature.ContextBase(signature.ContextKindRequestAuth), requestAuthMessage(method, timestamp), sig) {
+		return nil, fmt.Errorf("request auth: signature verification failed")
+	}
+	return &pk, nil
+}
@@ -0,0 +1,38 @@
+package connection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+)
+
+func TestRequestAuth(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_600_000_000, 0)
+	ra, err := newRequestAuthenticator(sdkTesting.Alice.Signer)
+	require.NoError(err, "newRequestAuthenticator")
+	ra.now = func() time.Time { return now }
+
+	const method = "/oasis-core.RuntimeClient/Query"
+	ctx, err := ra.authenticate(context.Background(), method)
+	require.NoError(err, "authenticate")
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(ok, "request should carry metadata")
+
+	pk, err := VerifyRequestAuth(md, method, now.Add(time.Second), time.Minute)
+	require.NoError(err, "VerifyRequestAuth")
+	require.True(pk.Equal(sdkTesting.Alice.Signer.Public()))
+
+	_, err = VerifyRequestAuth(md, "/oasis-core.RuntimeClient/SubmitTx", now, time.Minute)
+	require.Error(err, "VerifyRequestAuth should fail for a different method")
+	_, err = VerifyRequestAuth(md, method, now.Add(time.Hour), time.Minute)
+	require.Error(err, "VerifyRequestAuth should fail for stale requests")
+	_, err = VerifyRequestAuth(metadata.MD{}, method, now, time.Minute)
+	require.Error(err, "VerifyRequestAuth should fail without headers")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"sync"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -34,6 +35,68 @@ type RuntimeClient struct {
 	ConsensusAccounts consensusaccounts.V1
 	Contracts         contracts.V1
 	Evm               evm.V1
+
+	// Capabilities are the capabilities of the runtime. They are only available after Negotiate
+	// has been called for the runtime on the same connection (see also WithNegotiation).
+	Capabilities *core.Capabilities
+
+	runtimeID common.Namespace
+	caps      *capabilityCache
+}
+
+// Codec implements client.CodecProvider.
+func (rc RuntimeClient) Codec() client.CodecConfig {
+	return client.CodecOf(rc.RuntimeClient)
+}
+
+// ResolveMethod implements client.MethodResolver.
+func (rc RuntimeClient) ResolveMethod(method string) string {
+	return client.ResolveMethod(rc.RuntimeClient, method)
+}
+
+// Limiter implements client.LimiterProvider.
+func (rc RuntimeClient) Limiter() *client.Limiter {
+	return client.LimiterOf(rc.RuntimeClient)
+}
+
+// Negotiate discovers the capabilities of the runtime so that callers can check whether a given
+// feature is supported (see core.Capabilities) before using it.
+//
+// The capabilities are cached on the connection, so runtime clients subsequently returned by
+// Connection.Runtime for the same runtime have them available without negotiating again.
+func (rc *RuntimeClient) Negotiate(ctx context.Context) error {
+	caps, err := core.DiscoverCapabilities(ctx, rc.Core)
+	if err != nil {
+		return err
+	}
+	rc.Capabilities = caps
+	if rc.caps != nil {
+		rc.caps.set(rc.runtimeID, caps)
+	}
+	return nil
+}
+
+// capabilityCache caches the negotiated capabilities of runtimes.
+type capabilityCache struct {
+	l    sync.RWMutex
+	caps map[common.Namespace]*core.Capabilities
+}
+
+func (c *capabilityCache) get(runtimeID common.Namespace) *core.Capabilities {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.caps[runtimeID]
+}
+
+func (c *capabilityCache) set(runtimeID common.Namespace, caps *core.Capabilities) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if c.caps == nil {
+		c.caps = make(map[common.Namespace]*core.Capabilities)
+	}
+	c.caps[runtimeID] = caps
 }
 
 // Connection is the general node connection interface.
@@ -50,6 +113,10 @@ type Connection interface {
 
 type connection struct {
 	conn *grpc.ClientConn
+	pool *client.ConnPool
+
+	clientOpts []client.Option
+	caps       capabilityCache
 }
 
 func (c *connection) Consensus() consensus.ClientBackend {
@@ -65,7 +132,11 @@ func (c *connection) Runtime(pt *config.ParaTime) RuntimeClient {
 	if err := runtimeID.UnmarshalHex(pt.ID); err != nil {
 		panic(err)
 	}
-	cli := client.New(c.conn, runtimeID)
+	opts := append([]client.Option{}, c.clientOpts...)
+	if c.pool != nil {
+		opts = append(opts, client.WithConnPool(c.pool))
+	}
+	cli := client.New(c.conn, runtimeID, opts...)
 	return RuntimeClient{
 		RuntimeClient:     cli,
 		Core:              core.NewV1(cli),
@@ -74,12 +145,31 @@ func (c *connection) Runtime(pt *config.ParaTime) RuntimeClient {
 		ConsensusAccounts: consensusaccounts.NewV1(cli),
 		Contracts:         contracts.NewV1(cli),
 		Evm:               evm.NewV1(cli),
+		Capabilities:      c.caps.get(runtimeID),
+		runtimeID:         runtimeID,
+		caps:              &c.caps,
 	}
 }
 
 // Connect establishes a connection with the target network.
-func Connect(ctx context.Context, net *config.Network) (Connection, error) {
-	conn, err := ConnectNoVerify(ctx, net)
+//
+// Unless WithLazyConnect is used, this fails in case the node is not reachable. With
+// WithNegotiation, the capabilities of all ParaTimes of the network are negotiated as well.
+func Connect(ctx context.Context, net *config.Network, opts ...Option) (Connection, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.lazy {
+		// Defer the chain context check to the first call.
+		check := &chainContextVerifier{expected: net.ChainContext}
+		opts = append(opts, func(o *options) {
+			o.chainContextCheck = check
+		})
+		return ConnectNoVerify(ctx, net, opts...)
+	}
+
+	conn, err := ConnectNoVerify(ctx, net, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,12 +185,26 @@ func Connect(ctx context.Context, net *config.Network) (Connection, error) {
 		return nil, fmt.Errorf("remote node's chain context mismatch (expected: %s got: %s)", net.ChainContext, chainContext)
 	}
 
+	if o.negotiate {
+		for name, pt := range net.ParaTimes.All {
+			rc := conn.Runtime(pt)
+			if err = rc.Negotiate(ctx); err != nil {
+				return nil, fmt.Errorf("failed to negotiate with paratime '%s': %w", name, err)
+			}
+		}
+	}
+
 	return conn, nil
 }
 
 // ConnectNoVerify establishes a connection with the target network,
 // omitting the chain context check.
-func ConnectNoVerify(ctx context.Context, net *config.Network) (Connection, error) {
+func ConnectNoVerify(ctx context.Context, net *config.Network, opts ...Option) (Connection, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	var dialOpts []grpc.DialOption
 	switch net.IsLocalRPC() {
 	case true:
@@ -108,16 +212,41 @@ func ConnectNoVerify(ctx context.Context, net *config.Network) (Connection, erro
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	case false:
 		// Configure TLS for non-local nodes.
-		creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+		tlsConfig := o.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	}
+	extraDialOpts, err := o.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, extraDialOpts...)
 
-	conn, err := cmnGrpc.Dial(net.RPC, dialOpts...)
+	dial := func() (*grpc.ClientConn, error) {
+		return cmnGrpc.Dial(net.RPC, dialOpts...)
+	}
+	conn, err := dial()
 	if err != nil {
 		return nil, err
 	}
 
-	return &connection{
-		conn: conn,
-	}, nil
+	var pool *client.ConnPool
+	if len(o.poolSizes) > 0 {
+		if pool, err = client.NewConnPool(dial, o.poolSizes); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	c := &connection{
+		conn:       conn,
+		pool:       pool,
+		clientOpts: append([]client.Option{}, o.clientOpts...),
+	}
+	if o.lazy {
+		c.clientOpts = append(c.clientOpts, client.WithWatchResumption(o.watchMaxBackoff()))
+	}
+	return c, nil
 }
@@ -0,0 +1,81 @@
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+)
+
+func TestConnectNegotiation(t *testing.T) {
+	require := require.New(t)
+
+	pt := &config.ParaTime{ID: "8000000000000000000000000000000000000000000000000000000000000000"}
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex(pt.ID))
+
+	info := &core.RuntimeInfoResponse{
+		Modules: map[string]core.ModuleInfo{
+			"accounts": {Methods: []core.MethodHandlerInfo{{Name: "accounts.Quorum", Kind: core.MethodHandlerKindQuery}}},
+		},
+	}
+	node := NewReplayer([]*Interaction{
+		{
+			Method:   methodGetChainContext,
+			Request:  cbor.Marshal(nil),
+			Response: cbor.Marshal("chain"),
+		},
+		{
+			Method: "/oasis-core.RuntimeClient/Query",
+			Request: cbor.Marshal(&coreClient.QueryRequest{
+				RuntimeID: runtimeID,
+				Round:     client.RoundLatest,
+				Method:    "core.RuntimeInfo",
+				Args:      cbor.Marshal(nil),
+			}),
+			Response: cbor.Marshal(&coreClient.QueryResponse{Data: cbor.Marshal(info)}),
+		},
+	})
+	net := &config.Network{
+		ChainContext: "chain",
+		RPC:          "127.0.0.1:1",
+		ParaTimes:    config.ParaTimes{All: map[string]*config.ParaTime{"test": pt}},
+	}
+
+	conn, err := Connect(context.Background(), net, WithReplayer(node))
+	require.NoError(err, "Connect")
+	require.Nil(conn.Runtime(pt).Capabilities, "capabilities should only be negotiated when requested")
+	require.Nil(client.LimiterOf(conn.Runtime(pt)))
+
+	limiter := client.NewLimiter(client.Limits{MaxAddresses: 10}, nil)
+	conn, err = Connect(context.Background(), net, WithReplayer(node), WithClientOptions(client.WithLimits(limiter)))
+	require.NoError(err, "Connect")
+	require.Same(limiter, client.LimiterOf(conn.Runtime(pt)), "client options should apply to runtime clients")
+
+	conn, err = Connect(context.Background(), net, WithReplayer(node), WithNegotiation())
+	require.NoError(err, "Connect")
+	caps := conn.Runtime(pt).Capabilities
+	require.NotNil(caps, "capabilities should be negotiated when connecting")
+	require.True(caps.Known())
+	require.True(caps.SupportsMethod("accounts.Quorum"))
+	require.False(caps.SupportsMethod("accounts.MintST"))
+
+	// Capabilities negotiated later should also be cached on the connection.
+	conn, err = Connect(context.Background(), net, WithReplayer(node))
+	require.NoError(err, "Connect")
+	rc := conn.Runtime(pt)
+	require.NoError(rc.Negotiate(context.Background()))
+	require.Equal(rc.Capabilities, conn.Runtime(pt).Capabilities)
+
+	net.ParaTimes.All["other"] = &config.ParaTime{ID: "8000000000000000000000000000000000000000000000000000000000000001"}
+	_, err = Connect(context.Background(), net, WithReplayer(node), WithNegotiation())
+	require.ErrorContains(err, "failed to negotiate with paratime 'other'")
+}
@@ -0,0 +1,78 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// methodGetChainContext is the full name of the consensus GetChainContext method.
+const methodGetChainContext = "/oasis-core.Consensus/GetChainContext"
+
+// chainContextVerifier checks the chain context of the node on first use of a lazily established
+// connection (see WithLazyConnect), instead of when connecting.
+type chainContextVerifier struct {
+	expected string
+
+	l        sync.Mutex
+	verified bool
+	err      error
+}
+
+// verify checks the chain context of the node using the given invoker unless it has already been
+// checked. A mismatch fails all further calls.
+func (v *chainContextVerifier) verify(ctx context.Context, cc *grpc.ClientConn, invoker grpc.UnaryInvoker) error {
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	if v.verified {
+		return v.err
+	}
+	var chainContext string
+	if err := invoker(ctx, methodGetChainContext, nil, &chainContext, cc); err != nil {
+		// Transient failures are retried on the next call.
+		return fmt.Errorf("failed to retrieve remote node's chain context: %w", err)
+	}
+	v.verified = true
+	if chainContext != v.expected {
+		v.err = fmt.Errorf("remote node's chain context mismatch (expected: %s got: %s)", v.expected, chainContext)
+	}
+	return v.err
+}
+
+func (v *chainContextVerifier) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if method != methodGetChainContext {
+		if err := v.verify(ctx, cc, invoker); err != nil {
+			return err
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (v *chainContextVerifier) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	// Streams have no unary invoker, so go through the whole interceptor chain of the connection,
+	// where the unary interceptor passes the GetChainContext call through.
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return cc.Invoke(ctx, method, req, reply, opts...)
+	}
+	if err := v.verify(ctx, cc, invoker); err != nil {
+		return nil, err
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+)
+
+func TestLazyConnect(t *testing.T) {
+	require := require.New(t)
+
+	net := &config.Network{ChainContext: "chain", RPC: "127.0.0.1:1"}
+	conn, err := Connect(context.Background(), net, WithLazyConnect(), WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond))
+	require.NoError(err, "Connect should not require the node to be reachable")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = conn.Consensus().GetChainContext(ctx)
+	require.Error(err, "calls should wait for the node until their context is done")
+}
+
+func TestChainContextVerifier(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	v := &chainContextVerifier{expected: "chain"}
+
+	var (
+		remote  string
+		nodeErr error
+		checks  int
+	)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if method == methodGetChainContext {
+			checks++
+			if nodeErr != nil {
+				return nodeErr
+			}
+			*reply.(*string) = remote
+		}
+		return nil
+	}
+	call := func() error {
+		return v.unaryInterceptor(ctx, methodGetBlock, nil, nil, nil, invoker)
+	}
+
+	nodeErr = errors.New("unreachable")
+	require.ErrorIs(call(), nodeErr)
+	nodeErr = nil
+
+	remote = "other"
+	require.ErrorContains(call(), "chain context mismatch")
+	require.ErrorContains(call(), "chain context mismatch", "mismatches should fail all calls")
+	require.Equal(2, checks, "failed checks should be retried")
+
+	v = &chainContextVerifier{expected: "chain"}
+	remote = "chain"
+	require.NoError(call())
+	require.NoError(call())
+	require.Equal(3, checks, "the chain context should only be checked once")
+}
@@ -0,0 +1,213 @@
+package connection
+
+import (
+	"crypto/tls"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/encoding/gzip"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+)
+
+// defaultWatchMaxBackoff is the maximum delay between resubscriptions of watches of lazily
+// established connections without a configured reconnect backoff.
+const defaultWatchMaxBackoff = 10 * time.Second
+
+// CompressionGzip is the name of the gzip compressor that can be passed to WithCompression.
+const CompressionGzip = gzip.Name
+
+// Option is a connection option.
+type Option func(*options)
+
+type options struct {
+	poolSizes      map[client.Priority]int
+	compressor     string
+	maxRecvMsgSize int
+	callOptions    []grpc.CallOption
+	tlsConfig      *tls.Config
+	requestSigner  signature.Signer
+	recorder       *Recorder
+	replayer       *Replayer
+
+	lazy              bool
+	reconnectBackoff  *backoff.Config
+	chainContextCheck *chainContextVerifier
+
+	negotiate bool
+
+	clientOpts []client.Option
+}
+
+// WithConnPool makes the connection maintain a pool of dedicated gRPC connections for each
+// priority class, with the given number of connections per class. Runtime clients created from
+// such a connection dispatch their operations based on the priority class (see
+// client.WithPriority), so that e.g. backfill scans can't starve transaction submissions.
+func WithConnPool(sizes map[client.Priority]int) Option {
+	return func(o *options) {
+		o.poolSizes = sizes
+	}
+}
+
+// WithCompression makes all calls use the given registered gRPC compressor (e.g. CompressionGzip)
+// by default. Note that the node must support the same compressor.
+func WithCompression(name string) Option {
+	return func(o *options) {
+		o.compressor = name
+	}
+}
+
+// WithMaxRecvMsgSize configures the default maximum size of a response message in bytes.
+//
+// Large responses like Addresses or events of busy blocks may exceed the default limit.
+func WithMaxRecvMsgSize(size int) Option {
+	return func(o *options) {
+		o.maxRecvMsgSize = size
+	}
+}
+
+// WithDefaultCallOptions configures additional default gRPC call options for all calls. To
+// override options for individual calls, use WithCallOptions on the call context instead.
+func WithDefaultCallOptions(opts ...grpc.CallOption) Option {
+	return func(o *options) {
+		o.callOptions = append(o.callOptions, opts...)
+	}
+}
+
+// WithTLSConfig configures the TLS configuration used for connecting to non-local nodes, e.g. to
+// present a client certificate to nodes requiring mutual TLS authentication.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithRequestSigner makes all requests carry metadata headers authenticating them with the
+// given signer, so that private RPC providers can authenticate SDK traffic (see
+// VerifyRequestAuth).
+func WithRequestSigner(signer signature.Signer) Option {
+	return func(o *options) {
+		o.requestSigner = signer
+	}
+}
+
+// WithRecorder makes the connection record all calls using the given recorder.
+func WithRecorder(r *Recorder) Option {
+	return func(o *options) {
+		o.recorder = r
+	}
+}
+
+// WithReplayer makes the connection serve all calls from the given replayer instead of the node.
+func WithReplayer(r *Replayer) Option {
+	return func(o *options) {
+		o.replayer = r
+	}
+}
+
+// WithLazyConnect makes Connect return without waiting for the node to be reachable, e.g. so that
+// services can start before their node. The chain context of the node is then checked on first
+// use instead, calls wait for the connection to become ready (bounded by their context) instead of
+// failing immediately while the node is unreachable, and watches of runtime clients resume from
+// their last round after reconnects (see client.WithWatchResumption).
+func WithLazyConnect() Option {
+	return func(o *options) {
+		o.lazy = true
+	}
+}
+
+// WithNegotiation makes Connect negotiate the capabilities of all ParaTimes of the network (see
+// RuntimeClient.Negotiate), so that runtime clients returned by the connection have them
+// available. Connecting fails in case negotiation fails.
+//
+// As lazily established connections do not contact the node when connecting, this has no effect
+// together with WithLazyConnect.
+func WithNegotiation() Option {
+	return func(o *options) {
+		o.negotiate = true
+	}
+}
+
+// WithClientOptions makes runtime clients returned by the connection use the given options, e.g.
+// client.WithLimits.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(o *options) {
+		o.clientOpts = append(o.clientOpts, opts...)
+	}
+}
+
+// WithReconnectBackoff configures the exponential backoff between attempts to (re)establish the
+// connection, which starts at base and grows up to max.
+func WithReconnectBackoff(base, max time.Duration) Option {
+	return func(o *options) {
+		cfg := backoff.DefaultConfig
+		cfg.BaseDelay = base
+		cfg.MaxDelay = max
+		o.reconnectBackoff = &cfg
+	}
+}
+
+// watchMaxBackoff returns the maximum delay between resubscriptions of watches.
+func (o *options) watchMaxBackoff() time.Duration {
+	if o.reconnectBackoff != nil {
+		return o.reconnectBackoff.MaxDelay
+	}
+	return defaultWatchMaxBackoff
+}
+
+// dialOptions returns the gRPC dial options implied by the connection options.
+func (o *options) dialOptions() ([]grpc.DialOption, error) {
+	var callOpts []grpc.CallOption
+	if o.compressor != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(o.compressor))
+	}
+	if o.maxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(o.maxRecvMsgSize))
+	}
+	if o.lazy {
+		callOpts = append(callOpts, grpc.WaitForReady(true))
+	}
+	callOpts = append(callOpts, o.callOptions...)
+
+	var dialOpts []grpc.DialOption
+	// The chain context check happens first so that its own call passes all other interceptors.
+	if o.chainContextCheck != nil {
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(o.chainContextCheck.unaryInterceptor),
+			grpc.WithChainStreamInterceptor(o.chainContextCheck.streamInterceptor),
+		)
+	}
+	dialOpts = append(dialOpts,
+		grpc.WithChainUnaryInterceptor(unaryTransportInterceptor),
+		grpc.WithChainStreamInterceptor(streamTransportInterceptor),
+	)
+	if o.reconnectBackoff != nil {
+		dialOpts = append(dialOpts, grpc.WithConnectParams(grpc.ConnectParams{Backoff: *o.reconnectBackoff}))
+	}
+	if o.requestSigner != nil {
+		ra, err := newRequestAuthenticator(o.requestSigner)
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(ra.unaryInterceptor),
+			grpc.WithChainStreamInterceptor(ra.streamInterceptor),
+		)
+	}
+	// Recording and replaying happens last so that it observes the calls as sent to the node.
+	if o.recorder != nil {
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(o.recorder.unaryInterceptor))
+	}
+	if o.replayer != nil {
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(o.replayer.unaryInterceptor),
+			grpc.WithChainStreamInterceptor(o.replayer.streamInterceptor),
+		)
+	}
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	return dialOpts, nil
+}
@@ -0,0 +1,214 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// Interaction is a single recorded call made over a connection.
+type Interaction struct {
+	// Method is the full gRPC method name.
+	Method string `json:"method"`
+	// Request is the CBOR-encoded request.
+	Request []byte `json:"request"`
+	// Response is the CBOR-encoded response in case the call succeeded.
+	Response []byte `json:"response,omitempty"`
+	// Error is the serialized gRPC status in case the call failed.
+	Error []byte `json:"error,omitempty"`
+}
+
+// key returns the key used to match calls with the interaction.
+func (i *Interaction) key() string {
+	return i.Method + "/" + string(i.Request)
+}
+
+// Recorder records all calls made over the connections it is attached to (see WithRecorder) into
+// a file, so that the node interactions can later be reproduced using a Replayer.
+//
+// The recording consists of one JSON-encoded Interaction per line. Only unary calls are recorded,
+// so subscriptions (e.g. WatchBlocks) can't be replayed.
+type Recorder struct {
+	l sync.Mutex
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewRecorder creates a new recorder writing into the given file.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("connection: failed to create recording: %w", err)
+	}
+	return &Recorder{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Close flushes and closes the recording.
+func (r *Recorder) Close() error {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if err := r.w.Flush(); err != nil {
+		_ = r.f.Close()
+		return fmt.Errorf("connection: failed to write recording: %w", err)
+	}
+	return r.f.Close()
+}
+
+func (r *Recorder) record(in *Interaction) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if _, err = r.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return r.w.Flush()
+}
+
+func (r *Recorder) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	in := Interaction{
+		Method:  method,
+		Request: cbor.Marshal(req),
+	}
+	switch err {
+	case nil:
+		in.Response = cbor.Marshal(reply)
+	default:
+		var marshalErr error
+		if in.Error, marshalErr = proto.Marshal(status.Convert(err).Proto()); marshalErr != nil {
+			return fmt.Errorf("connection: failed to record error: %w", marshalErr)
+		}
+	}
+	if recErr := r.record(&in); recErr != nil {
+		return fmt.Errorf("connection: failed to record call: %w", recErr)
+	}
+	return err
+}
+
+// Replayer serves calls from a recording made by a Recorder, without any network access.
+//
+// Calls are matched by method and request. Identical calls are served the recorded responses in
+// order, repeating the last response once all have been served. Calls that were not recorded
+// fail with codes.Unimplemented.
+type Replayer struct {
+	l            sync.Mutex
+	interactions map[string][]*Interaction
+	served       map[string]int
+}
+
+// NewReplayer creates a new replayer serving the given interactions.
+func NewReplayer(interactions []*Interaction) *Replayer {
+	r := &Replayer{
+		interactions: make(map[string][]*Interaction),
+		served:       make(map[string]int),
+	}
+	for _, in := range interactions {
+		key := in.key()
+		r.interactions[key] = append(r.interactions[key], in)
+	}
+	return r
+}
+
+// LoadReplayer creates a new replayer serving the recording stored in the given file.
+func LoadReplayer(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("connection: failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	var interactions []*Interaction
+	dec := json.NewDecoder(f)
+	for {
+		var in Interaction
+		if err = dec.Decode(&in); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("connection: malformed recording: %w", err)
+		}
+		interactions = append(interactions, &in)
+	}
+	return NewReplayer(interactions), nil
+}
+
+// next returns the interaction to serve for the given call.
+func (r *Replayer) next(method string, req interface{}) (*Interaction, bool) {
+	key := (&Interaction{Method: method, Request: cbor.Marshal(req)}).key()
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	interactions := r.interactions[key]
+	if len(interactions) == 0 {
+		return nil, false
+	}
+	idx := r.served[key]
+	if idx >= len(interactions) {
+		idx = len(interactions) - 1
+	}
+	r.served[key] = idx + 1
+	return interactions[idx], true
+}
+
+func (r *Replayer) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	in, ok := r.next(method, req)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "connection: no recorded response for %s", method)
+	}
+	if in.Error != nil {
+		var s spb.Status
+		if err := proto.Unmarshal(in.Error, &s); err != nil {
+			return fmt.Errorf("connection: malformed recorded error: %w", err)
+		}
+		return status.ErrorProto(&s)
+	}
+	if err := cbor.Unmarshal(in.Response, reply); err != nil {
+		return fmt.Errorf("connection: malformed recorded response: %w", err)
+	}
+	return nil
+}
+
+func (r *Replayer) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "connection: streams can't be replayed (%s)", method)
+}
@@ -0,0 +1,72 @@
+package connection
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+const methodGetBlock = "/oasis-core.RuntimeClient/GetBlock"
+
+func TestRecordReplay(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	rec, err := NewRecorder(path)
+	require.NoError(err, "NewRecorder")
+
+	// Record calls against a fake node.
+	var round uint64
+	node := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if req.(*coreClient.GetBlockRequest).Round == 0 {
+			return status.Error(codes.NotFound, "block not found")
+		}
+		round++
+		reply.(*block.Block).Header.Round = round
+		return nil
+	}
+	call := func(r uint64) (*block.Block, error) {
+		var blk block.Block
+		err := rec.unaryInterceptor(ctx, methodGetBlock, &coreClient.GetBlockRequest{Round: r}, &blk, nil, node)
+		return &blk, err
+	}
+	for _, r := range []uint64{client.RoundLatest, client.RoundLatest, 0} {
+		_, _ = call(r)
+	}
+	require.NoError(rec.Close(), "Close")
+
+	// Replay them over a connection without any node.
+	rep, err := LoadReplayer(path)
+	require.NoError(err, "LoadReplayer")
+	dialOpts, err := (&options{replayer: rep}).dialOptions()
+	require.NoError(err, "dialOptions")
+	conn, err := grpc.Dial("passthrough:///replay", append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	require.NoError(err, "Dial")
+	defer conn.Close()
+
+	rc := client.New(conn, common.Namespace{})
+	for _, expected := range []uint64{1, 2, 2} {
+		blk, err := rc.GetBlock(ctx, client.RoundLatest)
+		require.NoError(err, "GetBlock")
+		require.Equal(expected, blk.Header.Round, "responses should be replayed in order, repeating the last one")
+	}
+
+	_, err = coreClient.NewRuntimeClient(conn).GetBlock(ctx, &coreClient.GetBlockRequest{Round: 0})
+	require.Equal(codes.NotFound, status.Code(err), "errors should be replayed")
+
+	_, err = coreClient.NewRuntimeClient(conn).GetBlock(ctx, &coreClient.GetBlockRequest{Round: 5})
+	require.Equal(codes.Unimplemented, status.Code(err), "unrecorded calls should fail")
+}
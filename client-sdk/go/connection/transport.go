@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a derived context that makes all gRPC calls issued with it use the
+// given call options (e.g. grpc.MaxCallRecvMsgSize or grpc.UseCompressor) in addition to the
+// connection defaults.
+//
+// This can be used to override transport settings for individual calls, e.g. when fetching a
+// large Addresses response.
+func WithCallOptions(ctx context.Context, opts ...grpc.CallOption) context.Context {
+	existing, _ := ctx.Value(callOptionsKey{}).([]grpc.CallOption)
+	merged := make([]grpc.CallOption, 0, len(existing)+len(opts))
+	merged = append(merged, existing...)
+	merged = append(merged, opts...)
+	return context.WithValue(ctx, callOptionsKey{}, merged)
+}
+
+func callOptionsFromContext(ctx context.Context, opts []grpc.CallOption) []grpc.CallOption {
+	extra, _ := ctx.Value(callOptionsKey{}).([]grpc.CallOption)
+	if len(extra) == 0 {
+		return opts
+	}
+	return append(append([]grpc.CallOption{}, opts...), extra...)
+}
+
+// annotateTransportError makes errors caused by the receive message size limit explicit as
+// otherwise they fail in a rather opaque way. The gRPC status of the error is preserved, so that
+// the error is still mapped and classified based on its status code.
+func annotateTransportError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.ResourceExhausted || !isRecvMsgSizeError(s.Message()) {
+		return err
+	}
+	p := s.Proto()
+	p.Message = fmt.Sprintf("%s: response exceeds transport limits (consider raising the maximum receive message size via WithMaxRecvMsgSize or WithCallOptions): %s", method, p.Message)
+	return status.ErrorProto(p)
+}
+
+// isRecvMsgSizeError checks whether the given gRPC status message is the one reported by the
+// client when a received message exceeds the maximum receive message size, as opposed to e.g.
+// rate limiting by the server, which uses the same status code.
+func isRecvMsgSizeError(msg string) bool {
+	return strings.HasPrefix(msg, "grpc: received message") && strings.Contains(msg, "larger than max")
+}
+
+// withOutgoingRequestMetadata forwards the request metadata set in the context (see
+// client.WithRequestID and client.WithCaller) to the node as request headers.
+func withOutgoingRequestMetadata(ctx context.Context) context.Context {
+	if kv := client.RequestMetadata(ctx); len(kv) > 0 {
+		return metadata.AppendToOutgoingContext(ctx, kv...)
+	}
+	return ctx
+}
+
+func unaryTransportInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	ctx = withOutgoingRequestMetadata(ctx)
+	err := invoker(ctx, method, req, reply, cc, callOptionsFromContext(ctx, opts)...)
+	return annotateTransportError(method, err)
+}
+
+func streamTransportInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx = withOutgoingRequestMetadata(ctx)
+	stream, err := streamer(ctx, desc, cc, method, callOptionsFromContext(ctx, opts)...)
+	return stream, annotateTransportError(method, err)
+}
@@ -0,0 +1,63 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+func TestWithCallOptions(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	base := []grpc.CallOption{grpc.MaxCallSendMsgSize(1)}
+	require.Len(callOptionsFromContext(ctx, base), 1)
+
+	ctx = WithCallOptions(ctx, grpc.MaxCallRecvMsgSize(2))
+	ctx = WithCallOptions(ctx, grpc.MaxCallRecvMsgSize(3))
+	opts := callOptionsFromContext(ctx, base)
+	require.Len(opts, 3)
+	require.Len(base, 1, "base options should not be modified")
+}
+
+func TestAnnotateTransportError(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(annotateTransportError("m", nil))
+
+	plain := errors.New("plain")
+	require.Equal(plain, annotateTransportError("m", plain))
+
+	exhausted := status.Error(codes.ResourceExhausted, "grpc: received message larger than max (5000 vs. 4000)")
+	err := annotateTransportError("/oasis-core.RuntimeClient/Query", exhausted)
+	require.Contains(err.Error(), "WithMaxRecvMsgSize")
+	require.Contains(err.Error(), "grpc: received message larger than max (5000 vs. 4000)")
+	require.Equal(codes.ResourceExhausted, status.Code(err), "status code should be preserved")
+	require.True(client.IsTransient(err))
+
+	limited := status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	err = annotateTransportError("/oasis-core.RuntimeClient/Query", limited)
+	require.Equal(limited, err, "other resource exhaustion errors should not be annotated")
+}
+
+func TestOutgoingRequestMetadata(t *testing.T) {
+	require := require.New(t)
+
+	ctx := withOutgoingRequestMetadata(context.Background())
+	_, ok := metadata.FromOutgoingContext(ctx)
+	require.False(ok, "no metadata should be added without request metadata")
+
+	ctx = client.WithCaller(client.WithRequestID(context.Background(), "req-1"), "tenant-a")
+	md, ok := metadata.FromOutgoingContext(withOutgoingRequestMetadata(ctx))
+	require.True(ok)
+	require.Equal([]string{"req-1"}, md.Get(client.RequestIDHeader))
+	require.Equal([]string{"tenant-a"}, md.Get(client.CallerHeader))
+}
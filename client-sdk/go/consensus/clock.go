@@ -0,0 +1,198 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// Clock converts between consensus epochs, consensus heights and the rounds of a runtime, e.g. for
+// reward accounting and bridge timeouts. Conversions of past epochs and rounds are cached as they
+// never change.
+type Clock struct {
+	c         *Client
+	runtimeID common.Namespace
+
+	l            sync.Mutex
+	epochHeights map[beacon.EpochTime]int64
+	roundHeights map[uint64]int64
+}
+
+// Clock returns a clock for the given runtime.
+func (c *Client) Clock(runtimeID common.Namespace) *Clock {
+	return &Clock{
+		c:            c,
+		runtimeID:    runtimeID,
+		epochHeights: make(map[beacon.EpochTime]int64),
+		roundHeights: make(map[uint64]int64),
+	}
+}
+
+// EpochHeight returns the height of the first consensus block of the given epoch.
+func (k *Clock) EpochHeight(ctx context.Context, epoch beacon.EpochTime) (int64, error) {
+	k.l.Lock()
+	height, ok := k.epochHeights[epoch]
+	k.l.Unlock()
+	if ok {
+		return height, nil
+	}
+
+	height, err := k.c.backend.Beacon().GetEpochBlock(ctx, epoch)
+	if err != nil {
+		return 0, fmt.Errorf("consensus: failed to query start of epoch %d: %w", epoch, err)
+	}
+	k.l.Lock()
+	k.epochHeights[epoch] = height
+	k.l.Unlock()
+	return height, nil
+}
+
+// HeightEpoch returns the epoch at the given height.
+func (k *Clock) HeightEpoch(ctx context.Context, height int64) (beacon.EpochTime, error) {
+	return k.c.Epoch(ctx, height)
+}
+
+// HeightRound returns the latest runtime round finalized at the given height together with the
+// height it was finalized at.
+func (k *Clock) HeightRound(ctx context.Context, height int64) (uint64, int64, error) {
+	state, err := k.c.backend.RootHash().GetRuntimeState(ctx, &roothash.RuntimeRequest{
+		RuntimeID: k.runtimeID,
+		Height:    height,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("consensus: failed to query runtime state at height %d: %w", height, err)
+	}
+	round, roundHeight := state.CurrentBlock.Header.Round, state.CurrentBlockHeight
+
+	k.l.Lock()
+	k.roundHeights[round] = roundHeight
+	k.l.Unlock()
+	return round, roundHeight, nil
+}
+
+// RoundHeight returns the height of the consensus block the given runtime round was finalized in.
+//
+// The height is found by a binary search over the heights up to the latest one, which is narrowed
+// by previously converted rounds. The node must not have pruned the searched heights.
+func (k *Clock) RoundHeight(ctx context.Context, round uint64) (int64, error) {
+	lo, hi := int64(1), int64(-1)
+	k.l.Lock()
+	if height, ok := k.roundHeights[round]; ok {
+		k.l.Unlock()
+		return height, nil
+	}
+	for r, height := range k.roundHeights {
+		switch {
+		case r < round && height+1 > lo:
+			lo = height + 1
+		case r > round && (hi < 0 || height-1 < hi):
+			hi = height - 1
+		}
+	}
+	k.l.Unlock()
+
+	if hi < 0 {
+		latest, latestHeight, err := k.HeightRound(ctx, HeightLatest)
+		switch {
+		case err != nil:
+			return 0, err
+		case latest == round:
+			return latestHeight, nil
+		case latest < round:
+			return 0, fmt.Errorf("consensus: round %d not finalized yet (latest round: %d)", round, latest)
+		}
+		hi = latestHeight - 1
+	}
+
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+		r, height, err := k.HeightRound(ctx, mid)
+		switch {
+		case errors.Is(err, roothash.ErrInvalidRuntime):
+			// The runtime was registered later.
+			lo = mid + 1
+		case err != nil:
+			return 0, err
+		case r == round:
+			return height, nil
+		case r < round:
+			lo = mid + 1
+		default:
+			hi = height - 1
+		}
+	}
+	return 0, fmt.Errorf("consensus: round %d not found", round)
+}
+
+// RoundEpoch returns the epoch the given runtime round was finalized in.
+func (k *Clock) RoundEpoch(ctx context.Context, round uint64) (beacon.EpochTime, error) {
+	height, err := k.RoundHeight(ctx, round)
+	if err != nil {
+		return 0, err
+	}
+	return k.HeightEpoch(ctx, height)
+}
+
+// EpochRound returns the latest runtime round finalized at the start of the given epoch.
+func (k *Clock) EpochRound(ctx context.Context, epoch beacon.EpochTime) (uint64, error) {
+	height, err := k.EpochHeight(ctx, epoch)
+	if err != nil {
+		return 0, err
+	}
+	round, _, err := k.HeightRound(ctx, height)
+	return round, err
+}
+
+// WaitForEpoch waits until the given epoch is reached.
+func (k *Clock) WaitForEpoch(ctx context.Context, epoch beacon.EpochTime) error {
+	ch, sub, err := k.c.backend.Beacon().WatchEpochs(ctx)
+	if err != nil {
+		return fmt.Errorf("consensus: failed to watch epochs: %w", err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case current, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("consensus: epoch subscription closed")
+			}
+			if current >= epoch {
+				return nil
+			}
+		}
+	}
+}
+
+// WaitForRound waits until the given runtime round is finalized.
+func (k *Clock) WaitForRound(ctx context.Context, round uint64) error {
+	ch, sub, err := k.c.backend.RootHash().WatchBlocks(ctx, k.runtimeID)
+	if err != nil {
+		return fmt.Errorf("consensus: failed to watch runtime blocks: %w", err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case blk, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("consensus: runtime block subscription closed")
+			}
+			k.l.Lock()
+			k.roundHeights[blk.Block.Header.Round] = blk.Height
+			k.l.Unlock()
+			if blk.Block.Header.Round >= round {
+				return nil
+			}
+		}
+	}
+}
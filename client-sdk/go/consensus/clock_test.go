@@ -0,0 +1,106 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+)
+
+// fakeRootHash finalizes runtime round r at height 2r+10, up to the latest height 100.
+type fakeRootHash struct {
+	roothash.Backend
+
+	queries int
+}
+
+func (f *fakeRootHash) GetRuntimeState(ctx context.Context, query *roothash.RuntimeRequest) (*roothash.RuntimeState, error) {
+	f.queries++
+	height := query.Height
+	if height == HeightLatest {
+		height = 100
+	}
+	if height < 10 {
+		return nil, roothash.ErrInvalidRuntime
+	}
+	round := uint64(height-10) / 2
+	return &roothash.RuntimeState{
+		CurrentBlock:       &block.Block{Header: block.Header{Round: round}},
+		CurrentBlockHeight: int64(2*round + 10),
+	}, nil
+}
+
+func (f *fakeRootHash) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ch := make(chan *roothash.AnnotatedBlock, 3)
+	for round := uint64(45); round <= 47; round++ {
+		ch <- &roothash.AnnotatedBlock{
+			Height: int64(2*round + 10),
+			Block:  &block.Block{Header: block.Header{Round: round}},
+		}
+	}
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+func TestClock(t *testing.T) {
+	require := require.New(t)
+
+	backend := &fakeBackend{}
+	clock := New(backend).Clock(common.Namespace{})
+	ctx := context.Background()
+
+	height, err := clock.EpochHeight(ctx, 5)
+	require.NoError(err)
+	require.EqualValues(50, height)
+
+	epoch, err := clock.HeightEpoch(ctx, 57)
+	require.NoError(err)
+	require.EqualValues(5, epoch)
+
+	round, roundHeight, err := clock.HeightRound(ctx, 57)
+	require.NoError(err)
+	require.EqualValues(23, round)
+	require.EqualValues(56, roundHeight)
+
+	for _, round := range []uint64{0, 1, 17, 44, 45} {
+		height, err = clock.RoundHeight(ctx, round)
+		require.NoError(err, "round %d", round)
+		require.EqualValues(2*round+10, height, "round %d", round)
+	}
+
+	// Converted rounds are cached.
+	queries := backend.roothash.queries
+	height, err = clock.RoundHeight(ctx, 17)
+	require.NoError(err)
+	require.EqualValues(44, height)
+	require.Equal(queries, backend.roothash.queries)
+
+	_, err = clock.RoundHeight(ctx, 46)
+	require.Error(err, "round not finalized yet")
+
+	epoch, err = clock.RoundEpoch(ctx, 20)
+	require.NoError(err)
+	require.EqualValues(5, epoch)
+
+	round, err = clock.EpochRound(ctx, 5)
+	require.NoError(err)
+	require.EqualValues(20, round)
+
+	require.NoError(clock.WaitForEpoch(ctx, 11))
+	require.NoError(clock.WaitForRound(ctx, 47))
+
+	height, err = clock.RoundHeight(ctx, 47)
+	require.NoError(err)
+	require.EqualValues(104, height)
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(clock.WaitForEpoch(ctx, beacon.EpochTime(13)), context.DeadlineExceeded)
+}
@@ -0,0 +1,129 @@
+// Package consensus provides typed access to the consensus layer queries commonly needed next to
+// the consensus accounts module, e.g. by bridge and monitoring code, so that such code doesn't
+// need to use the raw oasis-core client.
+//
+// Addresses and amounts use the SDK types. The raw client remains available via Client.Backend
+// for anything not covered here.
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	coreConsensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// HeightLatest is the height referring to the latest consensus block.
+const HeightLatest = coreConsensus.HeightLatest
+
+// Client is a typed consensus layer client.
+type Client struct {
+	backend coreConsensus.ClientBackend
+}
+
+// New creates a new typed client using the given consensus backend, e.g. the one returned by
+// connection.Connection.Consensus.
+func New(backend coreConsensus.ClientBackend) *Client {
+	return &Client{backend: backend}
+}
+
+// Backend returns the raw consensus backend.
+func (c *Client) Backend() coreConsensus.ClientBackend {
+	return c.backend
+}
+
+// LatestHeight returns the height of the latest consensus block.
+func (c *Client) LatestHeight(ctx context.Context) (int64, error) {
+	blk, err := c.backend.GetBlock(ctx, HeightLatest)
+	if err != nil {
+		return 0, fmt.Errorf("consensus: failed to fetch latest block: %w", err)
+	}
+	return blk.Height, nil
+}
+
+// Epoch returns the epoch at the given height.
+func (c *Client) Epoch(ctx context.Context, height int64) (beacon.EpochTime, error) {
+	epoch, err := c.backend.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		return 0, fmt.Errorf("consensus: failed to query epoch: %w", err)
+	}
+	return epoch, nil
+}
+
+// Account returns the staking account of the given address at the given height.
+func (c *Client) Account(ctx context.Context, height int64, address types.Address) (*staking.Account, error) {
+	acct, err := c.backend.Staking().Account(ctx, &staking.OwnerQuery{
+		Height: height,
+		Owner:  address.ConsensusAddress(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("consensus: failed to query account %s: %w", address, err)
+	}
+	return acct, nil
+}
+
+// Balance returns the general balance of the given address at the given height.
+func (c *Client) Balance(ctx context.Context, height int64, address types.Address) (*types.Quantity, error) {
+	acct, err := c.Account(ctx, height, address)
+	if err != nil {
+		return nil, err
+	}
+	return &acct.General.Balance, nil
+}
+
+// Allowance returns the amount the beneficiary may withdraw from the owner's account at the given
+// height, e.g. the allowance of a runtime to withdraw deposited funds.
+func (c *Client) Allowance(ctx context.Context, height int64, owner, beneficiary types.Address) (*types.Quantity, error) {
+	allowance, err := c.backend.Staking().Allowance(ctx, &staking.AllowanceQuery{
+		Height:      height,
+		Owner:       owner.ConsensusAddress(),
+		Beneficiary: beneficiary.ConsensusAddress(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("consensus: failed to query allowance: %w", err)
+	}
+	return allowance, nil
+}
+
+// Validator is a member of the consensus validator set.
+type Validator struct {
+	// NodeID is the identifier of the validator node.
+	NodeID signature.PublicKey `json:"node_id"`
+	// EntityID is the identifier of the entity controlling the node.
+	EntityID signature.PublicKey `json:"entity_id"`
+	// EntityAddress is the staking address of the entity.
+	EntityAddress types.Address `json:"entity_address"`
+	// VotingPower is the consensus voting power of the validator.
+	VotingPower int64 `json:"voting_power"`
+}
+
+// ValidatorSet returns the validator set at the given height in order of decreasing voting power.
+func (c *Client) ValidatorSet(ctx context.Context, height int64) ([]*Validator, error) {
+	validators, err := c.backend.Scheduler().GetValidators(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("consensus: failed to query validators: %w", err)
+	}
+
+	set := make([]*Validator, 0, len(validators))
+	for _, v := range validators {
+		n, err := c.backend.Registry().GetNode(ctx, &registry.IDQuery{Height: height, ID: v.ID})
+		if err != nil {
+			return nil, fmt.Errorf("consensus: failed to query validator node %s: %w", v.ID, err)
+		}
+		set = append(set, &Validator{
+			NodeID:        v.ID,
+			EntityID:      n.EntityID,
+			EntityAddress: types.NewAddressFromConsensusPublicKey(n.EntityID),
+			VotingPower:   v.VotingPower,
+		})
+	}
+	sort.SliceStable(set, func(i, j int) bool { return set[i].VotingPower > set[j].VotingPower })
+	return set, nil
+}
@@ -0,0 +1,133 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	coreConsensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	nodeA   = signature.NewPublicKey("0000000000000000000000000000000000000000000000000000000000000001")
+	nodeB   = signature.NewPublicKey("0000000000000000000000000000000000000000000000000000000000000002")
+	entityA = signature.NewPublicKey("00000000000000000000000000000000000000000000000000000000000000aa")
+)
+
+type fakeBackend struct {
+	coreConsensus.ClientBackend
+
+	roothash fakeRootHash
+}
+
+func (f *fakeBackend) GetBlock(ctx context.Context, height int64) (*coreConsensus.Block, error) {
+	return &coreConsensus.Block{Height: 100}, nil
+}
+
+func (f *fakeBackend) Beacon() beacon.Backend       { return &fakeBeacon{} }
+func (f *fakeBackend) Staking() staking.Backend     { return &fakeStaking{} }
+func (f *fakeBackend) Scheduler() scheduler.Backend { return &fakeScheduler{} }
+func (f *fakeBackend) Registry() registry.Backend   { return &fakeRegistry{} }
+func (f *fakeBackend) RootHash() roothash.Backend   { return &f.roothash }
+
+type fakeBeacon struct {
+	beacon.Backend
+}
+
+func (f *fakeBeacon) GetEpoch(ctx context.Context, height int64) (beacon.EpochTime, error) {
+	if height == HeightLatest {
+		height = 100
+	}
+	return beacon.EpochTime(height / 10), nil
+}
+
+func (f *fakeBeacon) GetEpochBlock(ctx context.Context, epoch beacon.EpochTime) (int64, error) {
+	return int64(epoch) * 10, nil
+}
+
+func (f *fakeBeacon) WatchEpochs(ctx context.Context) (<-chan beacon.EpochTime, pubsub.ClosableSubscription, error) {
+	ch := make(chan beacon.EpochTime, 3)
+	for epoch := beacon.EpochTime(10); epoch <= 12; epoch++ {
+		ch <- epoch
+	}
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+type fakeStaking struct {
+	staking.Backend
+}
+
+func (f *fakeStaking) Account(ctx context.Context, query *staking.OwnerQuery) (*staking.Account, error) {
+	var acct staking.Account
+	if types.NewAddressFromConsensus(query.Owner).Equal(sdkTesting.Alice.Address) {
+		acct.General.Balance = *quantity.NewFromUint64(1000)
+	}
+	return &acct, nil
+}
+
+func (f *fakeStaking) Allowance(ctx context.Context, query *staking.AllowanceQuery) (*quantity.Quantity, error) {
+	return quantity.NewFromUint64(uint64(query.Height)), nil
+}
+
+type fakeScheduler struct {
+	scheduler.Backend
+}
+
+func (f *fakeScheduler) GetValidators(ctx context.Context, height int64) ([]*scheduler.Validator, error) {
+	return []*scheduler.Validator{{ID: nodeA, VotingPower: 1}, {ID: nodeB, VotingPower: 5}}, nil
+}
+
+type fakeRegistry struct {
+	registry.Backend
+}
+
+func (f *fakeRegistry) GetNode(ctx context.Context, query *registry.IDQuery) (*node.Node, error) {
+	return &node.Node{ID: query.ID, EntityID: entityA}, nil
+}
+
+func TestClient(t *testing.T) {
+	require := require.New(t)
+
+	c := New(&fakeBackend{})
+	ctx := context.Background()
+
+	height, err := c.LatestHeight(ctx)
+	require.NoError(err)
+	require.EqualValues(100, height)
+
+	epoch, err := c.Epoch(ctx, height)
+	require.NoError(err)
+	require.EqualValues(10, epoch)
+
+	balance, err := c.Balance(ctx, height, sdkTesting.Alice.Address)
+	require.NoError(err)
+	require.EqualValues(1000, balance.ToBigInt().Uint64())
+	balance, err = c.Balance(ctx, height, sdkTesting.Bob.Address)
+	require.NoError(err)
+	require.True(balance.IsZero())
+
+	allowance, err := c.Allowance(ctx, height, sdkTesting.Alice.Address, sdkTesting.Bob.Address)
+	require.NoError(err)
+	require.EqualValues(100, allowance.ToBigInt().Uint64())
+
+	validators, err := c.ValidatorSet(ctx, height)
+	require.NoError(err)
+	require.Len(validators, 2)
+	require.Equal(nodeB, validators[0].NodeID, "validators should be ordered by voting power")
+	require.Equal(entityA, validators[0].EntityID)
+	require.Equal(types.NewAddressFromConsensusPublicKey(entityA), validators[0].EntityAddress)
+}
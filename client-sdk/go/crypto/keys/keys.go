@@ -0,0 +1,161 @@
+// Package keys implements export and import of private keys in the formats used by the other
+// HeLa tools (the CLI and the web wallet), so that keys can be moved between them safely.
+//
+// Ed25519 and Sr25519 keys are encoded in Base64 while Secp256k1 keys are encoded in hex (with
+// or without the 0x prefix used by Ethereum tooling). Exported keys carry the derived address so
+// that imports can be cross-checked against it.
+package keys
+
+import (
+	"bytes"
+	goEd25519 "crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/sr25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Supported key algorithms, named as in the CLI.
+const (
+	AlgorithmEd25519   = "ed25519-raw"
+	AlgorithmSecp256k1 = "secp256k1-raw"
+	AlgorithmSr25519   = "sr25519-raw"
+)
+
+// ExportedKey is an exported private key.
+type ExportedKey struct {
+	// Algorithm is the key algorithm.
+	Algorithm string `json:"algorithm"`
+	// PrivateKey is the encoded private key.
+	PrivateKey string `json:"private_key"`
+	// Address is the address derived from the key.
+	Address types.Address `json:"address"`
+}
+
+// String returns a representation of the exported key with the private key redacted.
+func (ek ExportedKey) String() string {
+	return fmt.Sprintf("%s key for %s (private key %s)", ek.Algorithm, ek.Address, types.Sensitive(ek.PrivateKey))
+}
+
+// GoString returns a representation of the exported key with the private key redacted.
+func (ek ExportedKey) GoString() string {
+	return ek.String()
+}
+
+// Export encodes the given raw private key. Ed25519 keys are expected in the 64-byte form (seed
+// followed by the public key) that is also accepted by Import, but 32-byte seeds are accepted.
+func Export(algorithm string, privateKey []byte) (*ExportedKey, error) {
+	var encoded string
+	switch algorithm {
+	case AlgorithmEd25519:
+		if len(privateKey) == goEd25519.SeedSize {
+			privateKey = goEd25519.NewKeyFromSeed(privateKey)
+		}
+		encoded = base64.StdEncoding.EncodeToString(privateKey)
+	case AlgorithmSecp256k1:
+		encoded = hex.EncodeToString(privateKey)
+	case AlgorithmSr25519:
+		encoded = base64.StdEncoding.EncodeToString(privateKey)
+	default:
+		return nil, fmt.Errorf("keys: unsupported algorithm '%s'", algorithm)
+	}
+
+	// Import the key to validate it and derive its address.
+	_, spec, err := decode(algorithm, encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportedKey{
+		Algorithm:  algorithm,
+		PrivateKey: encoded,
+		Address:    types.NewAddress(spec),
+	}, nil
+}
+
+// Import decodes and validates the exported key, making sure that it derives the address stored
+// in the export.
+func Import(ek *ExportedKey) (signature.Signer, error) {
+	signer, spec, err := decode(ek.Algorithm, ek.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if addr := types.NewAddress(spec); !addr.Equal(ek.Address) {
+		signer.Reset()
+		return nil, fmt.Errorf("keys: key derives address %s instead of %s", addr, ek.Address)
+	}
+	return signer, nil
+}
+
+// ImportString decodes and validates the given encoded private key, e.g. one exported from the
+// web wallet. In case expected is non-nil, the key must derive the expected address.
+func ImportString(algorithm, encoded string, expected *types.Address) (signature.Signer, error) {
+	signer, spec, err := decode(algorithm, encoded)
+	if err != nil {
+		return nil, err
+	}
+	if expected != nil {
+		if addr := types.NewAddress(spec); !addr.Equal(*expected) {
+			signer.Reset()
+			return nil, fmt.Errorf("keys: key derives address %s instead of %s", addr, *expected)
+		}
+	}
+	return signer, nil
+}
+
+// decode decodes and validates the given encoded private key.
+func decode(algorithm, encoded string) (signature.Signer, types.SignatureAddressSpec, error) {
+	encoded = strings.TrimSpace(encoded)
+	switch algorithm {
+	case AlgorithmEd25519:
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed ed25519 key: %w", err)
+		}
+		if len(raw) != goEd25519.PrivateKeySize {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed ed25519 key: expected %d bytes, got %d", goEd25519.PrivateKeySize, len(raw))
+		}
+		// Make sure the embedded public key matches the seed.
+		sk := goEd25519.NewKeyFromSeed(raw[:goEd25519.SeedSize])
+		if !bytes.Equal(sk, raw) {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed ed25519 key: public key does not match seed")
+		}
+		signer := ed25519.WrapSigner(memorySigner.NewFromRuntime(sk))
+		return signer, types.NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey)), nil
+	case AlgorithmSecp256k1:
+		raw, err := hex.DecodeString(strings.TrimPrefix(encoded, "0x"))
+		if err != nil {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed secp256k1 key: %w", err)
+		}
+		if len(raw) != btcec.PrivKeyBytesLen {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed secp256k1 key: expected %d bytes, got %d", btcec.PrivKeyBytesLen, len(raw))
+		}
+		if d := new(big.Int).SetBytes(raw); d.Sign() == 0 || d.Cmp(btcec.S256().N) >= 0 {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed secp256k1 key: out of range")
+		}
+		signer := secp256k1.NewSigner(raw)
+		return signer, types.NewSignatureAddressSpecSecp256k1Eth(signer.Public().(secp256k1.PublicKey)), nil
+	case AlgorithmSr25519:
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed sr25519 key: %w", err)
+		}
+		signer, err := sr25519.NewSigner(raw)
+		if err != nil {
+			return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: malformed sr25519 key: %w", err)
+		}
+		return signer, types.NewSignatureAddressSpecSr25519(signer.Public().(sr25519.PublicKey)), nil
+	default:
+		return nil, types.SignatureAddressSpec{}, fmt.Errorf("keys: unsupported algorithm '%s'", algorithm)
+	}
+}
@@ -0,0 +1,89 @@
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+)
+
+func TestExportImport(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		algorithm string
+		key       sdkTesting.TestKey
+	}{
+		{AlgorithmEd25519, sdkTesting.Alice},
+		{AlgorithmSecp256k1, sdkTesting.Dave},
+		{AlgorithmSr25519, sdkTesting.Frank},
+	} {
+		ek, err := Export(tc.algorithm, tc.key.SecretKey)
+		require.NoError(err, "Export(%s)", tc.algorithm)
+		require.Equal(tc.key.Address, ek.Address)
+
+		raw, err := json.Marshal(ek)
+		require.NoError(err)
+		var decoded ExportedKey
+		require.NoError(json.Unmarshal(raw, &decoded))
+
+		signer, err := Import(&decoded)
+		require.NoError(err, "Import(%s)", tc.algorithm)
+		require.True(signer.Public().Equal(tc.key.Signer.Public()))
+
+		decoded.Address = sdkTesting.Bob.Address
+		_, err = Import(&decoded)
+		require.Error(err, "address mismatch should be detected")
+	}
+
+	_, err := ImportString(AlgorithmSecp256k1, "0x"+mustExport(t, AlgorithmSecp256k1, sdkTesting.Dave.SecretKey), &sdkTesting.Dave.Address)
+	require.NoError(err, "0x-prefixed secp256k1 keys should be accepted")
+	_, err = ImportString(AlgorithmSecp256k1, "00", nil)
+	require.Error(err, "truncated keys should be rejected")
+	_, err = ImportString(AlgorithmSecp256k1, "0000000000000000000000000000000000000000000000000000000000000000", nil)
+	require.Error(err, "zero keys should be rejected")
+
+	tampered := append([]byte{}, sdkTesting.Alice.SecretKey...)
+	tampered[63] ^= 0xff
+	_, err = Export(AlgorithmEd25519, tampered)
+	require.Error(err, "inconsistent ed25519 keys should be rejected")
+
+	_, err = Export("bogus", nil)
+	require.Error(err)
+}
+
+func mustExport(t *testing.T, algorithm string, key []byte) string {
+	ek, err := Export(algorithm, key)
+	require.NoError(t, err)
+	return ek.PrivateKey
+}
+
+func TestExportedKeyRedaction(t *testing.T) {
+	require := require.New(t)
+
+	ek, err := Export(AlgorithmSecp256k1, sdkTesting.Dave.SecretKey)
+	require.NoError(err)
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+		formatted := fmt.Sprintf(verb, ek)
+		require.NotContains(formatted, ek.PrivateKey, "private keys should be redacted (%s)", verb)
+		require.Contains(formatted, ek.Address.String())
+	}
+
+	for _, tc := range []struct {
+		scheme string
+		key    sdkTesting.TestKey
+	}{
+		{"ed25519", sdkTesting.Alice},
+		{"secp256k1", sdkTesting.Dave},
+		{"sr25519", sdkTesting.Frank},
+	} {
+		formatted := fmt.Sprintf("%#v", tc.key.Signer)
+		require.Equal(tc.scheme+".Signer("+tc.key.Signer.Public().String()+")", formatted, "signers should be redacted (%s)", tc.scheme)
+		require.NotContains(formatted, hex.EncodeToString(tc.key.SecretKey))
+		require.NotContains(fmt.Sprintf("%+v", tc.key.Signer), hex.EncodeToString(tc.key.SecretKey))
+	}
+}
@@ -1,15 +1,73 @@
 package signature
 
 import (
+	"fmt"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 )
 
-const chainContextSeparator = " for chain "
+const (
+	chainContextSeparator = " for chain "
+	contextBasePrefix     = "oasis-runtime-sdk/"
+)
+
+// ContextKind is the kind of data being signed, used for domain separation.
+type ContextKind string
+
+const (
+	// ContextKindTransaction is the context kind of runtime transactions.
+	ContextKindTransaction ContextKind = "tx"
+	// ContextKindMessage is the context kind of arbitrary off-chain messages.
+	ContextKindMessage ContextKind = "msg"
+	// ContextKindTypedMessage is the context kind of typed off-chain messages.
+	ContextKindTypedMessage ContextKind = "typed-msg"
+	// ContextKindRequestAuth is the context kind of node request authentication signatures.
+	ContextKindRequestAuth ContextKind = "request-auth"
+	// ContextKindReceipt is the context kind of signed query receipts.
+	ContextKindReceipt ContextKind = "receipt"
+	// ContextKindPaymentRequest is the context kind of signed payment requests.
+	ContextKindPaymentRequest ContextKind = "payment-request"
+)
+
+// latestContextVersions are the latest versions of each context kind.
+var latestContextVersions = map[ContextKind]uint{
+	ContextKindTransaction:    0,
+	ContextKindMessage:        0,
+	ContextKindTypedMessage:   0,
+	ContextKindRequestAuth:    0,
+	ContextKindReceipt:        0,
+	ContextKindPaymentRequest: 0,
+}
+
+// LatestContextVersion returns the latest version of the given context kind.
+func LatestContextVersion(kind ContextKind) (uint, bool) {
+	version, ok := latestContextVersions[kind]
+	return version, ok
+}
+
+// NewContextBase returns the domain separation context base for the given context kind and
+// version, e.g. "oasis-runtime-sdk/tx: v0".
+func NewContextBase(kind ContextKind, version uint) []byte {
+	return []byte(fmt.Sprintf("%s%s: v%d", contextBasePrefix, kind, version))
+}
+
+// ContextBase returns the domain separation context base for the latest version of the given
+// context kind.
+//
+// Panics in case the context kind is unknown.
+func ContextBase(kind ContextKind) []byte {
+	version, ok := LatestContextVersion(kind)
+	if !ok {
+		panic(fmt.Sprintf("signature: unknown context kind: %s", kind))
+	}
+	return NewContextBase(kind, version)
+}
 
 // Context is the chain domain separation context.
 type Context string
 
+// New returns the full signature context for the given context base, bound to this chain.
 func (c Context) New(base []byte) []byte {
 	ctx := append([]byte{}, base...)
 	ctx = append(ctx, []byte(chainContextSeparator)...)
@@ -17,6 +75,12 @@ func (c Context) New(base []byte) []byte {
 	return ctx
 }
 
+// For returns the full signature context for the latest version of the given context kind, bound
+// to this chain.
+func (c Context) For(kind ContextKind) []byte {
+	return c.New(ContextBase(kind))
+}
+
 // DeriveChainContext derives the chain domain separation context for a given runtime.
 func DeriveChainContext(runtimeID common.Namespace, consensusChainContext string) Context {
 	rawRuntimeID, _ := runtimeID.MarshalBinary()
@@ -25,3 +89,10 @@ func DeriveChainContext(runtimeID common.Namespace, consensusChainContext string
 		[]byte(consensusChainContext),
 	).String())
 }
+
+// ComputeContext computes the full signature context for the latest version of the given context
+// kind on the given runtime. It is meant for external verifiers that need to reproduce the exact
+// bytes signed by the SDK.
+func ComputeContext(runtimeID common.Namespace, consensusChainContext string, kind ContextKind) []byte {
+	return DeriveChainContext(runtimeID, consensusChainContext).For(kind)
+}
@@ -20,3 +20,56 @@ func TestDeriveChainContext(t *testing.T) {
 	ctx1 := chainCtx.New([]byte("oasis-runtime-sdk/tx: v0"))
 	require.Equal("oasis-runtime-sdk/tx: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9", string(ctx1))
 }
+
+func TestContextVectors(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	_ = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	consensusChainCtx := "643fb06848be7e970af3b5b2d772eb8cfb30499c8162bc18ac03df2f5e22520e"
+
+	for _, tc := range []struct {
+		kind ContextKind
+		base string
+		full string
+	}{
+		{
+			ContextKindTransaction,
+			"oasis-runtime-sdk/tx: v0",
+			"oasis-runtime-sdk/tx: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+		{
+			ContextKindMessage,
+			"oasis-runtime-sdk/msg: v0",
+			"oasis-runtime-sdk/msg: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+		{
+			ContextKindTypedMessage,
+			"oasis-runtime-sdk/typed-msg: v0",
+			"oasis-runtime-sdk/typed-msg: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+		{
+			ContextKindRequestAuth,
+			"oasis-runtime-sdk/request-auth: v0",
+			"oasis-runtime-sdk/request-auth: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+		{
+			ContextKindReceipt,
+			"oasis-runtime-sdk/receipt: v0",
+			"oasis-runtime-sdk/receipt: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+		{
+			ContextKindPaymentRequest,
+			"oasis-runtime-sdk/payment-request: v0",
+			"oasis-runtime-sdk/payment-request: v0 for chain ca4842870b97a6d5c0d025adce0b6a0dec94d2ba192ede70f96349cfbe3628b9",
+		},
+	} {
+		require.Equal(tc.base, string(ContextBase(tc.kind)), "ContextBase(%s)", tc.kind)
+		require.Equal(tc.full, string(ComputeContext(runtimeID, consensusChainCtx, tc.kind)), "ComputeContext(%s)", tc.kind)
+	}
+
+	require.Equal("oasis-runtime-sdk/tx: v1", string(NewContextBase(ContextKindTransaction, 1)))
+	_, ok := LatestContextVersion(ContextKind("bogus"))
+	require.False(ok, "unknown context kinds should have no version")
+	require.Panics(func() { ContextBase(ContextKind("bogus")) })
+}
@@ -28,6 +28,11 @@ func (w wrappedSigner) String() string {
 	return w.signer.String()
 }
 
+// GoString returns the public key so that the private key is never formatted (e.g. using %#v).
+func (w wrappedSigner) GoString() string {
+	return "ed25519.Signer(" + w.Public().String() + ")"
+}
+
 func (w wrappedSigner) Reset() {
 	w.signer.Reset()
 }
@@ -45,6 +45,11 @@ func (s Signer) String() string {
 	return s.Public().String()
 }
 
+// GoString returns the public key so that the private key is never formatted (e.g. using %#v).
+func (s Signer) GoString() string {
+	return "secp256k1.Signer(" + s.String() + ")"
+}
+
 func (s Signer) Reset() {
 	s.privateKey.D.SetBytes([]byte{})
 	s.privateKey.X.SetBytes([]byte{})
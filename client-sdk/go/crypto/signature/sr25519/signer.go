@@ -37,6 +37,11 @@ func (s *signer) String() string {
 	return "sr25519 signer: " + s.Public().String()
 }
 
+// GoString returns the public key so that the private key is never formatted (e.g. using %#v).
+func (s *signer) GoString() string {
+	return "sr25519.Signer(" + s.Public().String() + ")"
+}
+
 func (s *signer) Reset() {
 	// curve25519-voi acknowledges that memory sanitization in Go is
 	// a totally lost cause.
@@ -0,0 +1,220 @@
+// Package diagnostics generates support bundles describing the environment of an SDK client, so
+// that bug reports against the SDK include actionable data.
+//
+// Bundles contain the SDK and Go versions, the status and chain context of the node, the SDK
+// metrics counters (e.g. unknown response fields or quota rejections) and the network
+// configuration. Credentials embedded in the configuration are redacted.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+)
+
+const (
+	// sdkModule is the path of the SDK module.
+	sdkModule = "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
+	// metricsPrefix is the prefix of the metrics reported by the SDK.
+	metricsPrefix = "oasis_sdk_"
+	// redacted replaces redacted values.
+	redacted = "redacted"
+)
+
+// SDKInfo describes the SDK build.
+type SDKInfo struct {
+	// Version is the version of the SDK module.
+	Version string `json:"version"`
+	// GoVersion is the Go version the SDK was built with.
+	GoVersion string `json:"go_version"`
+	// OS is the operating system.
+	OS string `json:"os"`
+	// Arch is the architecture.
+	Arch string `json:"arch"`
+}
+
+// NetworkInfo is the (redacted) network configuration.
+type NetworkInfo struct {
+	// ChainContext is the configured chain context.
+	ChainContext string `json:"chain_context"`
+	// RPC is the configured RPC endpoint with credentials redacted.
+	RPC string `json:"rpc"`
+	// ParaTimes are the identifiers of the configured ParaTimes by name.
+	ParaTimes map[string]string `json:"paratimes,omitempty"`
+}
+
+// Bundle is a support bundle.
+type Bundle struct {
+	// Created is the time the bundle was created.
+	Created time.Time `json:"created"`
+	// SDK describes the SDK build.
+	SDK SDKInfo `json:"sdk"`
+	// Network is the network configuration.
+	Network NetworkInfo `json:"network"`
+	// Codec is the codec configuration of the client of the default ParaTime (see
+	// client.WithCodec).
+	Codec client.CodecConfig `json:"codec"`
+	// ChainContext is the chain context reported by the node.
+	ChainContext string `json:"chain_context,omitempty"`
+	// ChainContextMatches is true iff the chain context of the node matches the configuration.
+	ChainContextMatches bool `json:"chain_context_matches"`
+	// NodeStatus is the status of the node (if available to the client).
+	NodeStatus *control.Status `json:"node_status,omitempty"`
+	// Counters are the values of the SDK metrics counters, keyed by metric name and labels.
+	Counters map[string]float64 `json:"counters"`
+	// Errors are the errors encountered while gathering the bundle.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Diagnose gathers a support bundle using the given connection to the given network.
+//
+// Failing to gather some of the data (e.g. because the node is unreachable or doesn't expose its
+// status) doesn't fail the bundle, the errors are recorded in the bundle instead.
+func Diagnose(ctx context.Context, conn connection.Connection, net *config.Network) *Bundle {
+	b := Bundle{
+		Created: time.Now().UTC(),
+		SDK:     sdkInfo(),
+		Network: networkInfo(net),
+	}
+	if pt := net.ParaTimes.All[net.ParaTimes.Default]; pt != nil {
+		b.Codec = client.CodecOf(conn.Runtime(pt))
+	}
+
+	chainContext, err := conn.Consensus().GetChainContext(ctx)
+	if err != nil {
+		b.addError("failed to query chain context", err)
+	} else {
+		b.ChainContext = chainContext
+		b.ChainContextMatches = chainContext == net.ChainContext
+	}
+
+	if b.NodeStatus, err = conn.Control().GetStatus(ctx); err != nil {
+		b.addError("failed to query node status", err)
+	}
+
+	if b.Counters, err = counters(prometheus.DefaultGatherer); err != nil {
+		b.addError("failed to gather metrics", err)
+	}
+
+	return &b
+}
+
+// WriteFile writes the bundle as JSON into the given file.
+func (b *Bundle) WriteFile(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("diagnostics: failed to encode bundle: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (b *Bundle) addError(msg string, err error) {
+	b.Errors = append(b.Errors, fmt.Sprintf("%s: %s", msg, err))
+}
+
+// sdkInfo returns information about the SDK build.
+func sdkInfo() SDKInfo {
+	info := SDKInfo{
+		Version:   "unknown",
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if bi.Main.Path == sdkModule {
+		info.Version = bi.Main.Version
+		return info
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path != sdkModule {
+			continue
+		}
+		info.Version = dep.Version
+		if dep.Replace != nil {
+			info.Version += " => " + dep.Replace.Path + " " + dep.Replace.Version
+		}
+	}
+	return info
+}
+
+// networkInfo returns the redacted network configuration.
+func networkInfo(net *config.Network) NetworkInfo {
+	info := NetworkInfo{
+		ChainContext: net.ChainContext,
+		RPC:          redactURL(net.RPC),
+	}
+	if len(net.ParaTimes.All) > 0 {
+		info.ParaTimes = make(map[string]string)
+		for name, pt := range net.ParaTimes.All {
+			info.ParaTimes[name] = pt.ID
+		}
+	}
+	return info
+}
+
+// redactURL redacts the credentials and query parameters (e.g. API keys) of the given URL.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, redacted)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// counters returns the values of the SDK counters reported by the given gatherer.
+func counters(g prometheus.Gatherer) (map[string]float64, error) {
+	mfs, err := g.Gather()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64)
+	for _, mf := range mfs {
+		if !strings.HasPrefix(mf.GetName(), metricsPrefix) {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if m.GetCounter() == nil {
+				continue
+			}
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+			}
+			sort.Strings(labels)
+			key := mf.GetName()
+			if len(labels) > 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			values[key] = m.GetCounter().GetValue()
+		}
+	}
+	return values, nil
+}
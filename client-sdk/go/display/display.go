@@ -0,0 +1,246 @@
+// Package display converts amounts to and from human readable strings in different locales, e.g.
+// for wallet backends showing balances to users.
+package display
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrInvalidAmount is the error returned when parsing a malformed amount.
+var ErrInvalidAmount = errors.New("display: invalid amount")
+
+// RoundingMode is the way amounts are rounded to the displayed number of decimal places.
+type RoundingMode uint8
+
+const (
+	// RoundHalfUp rounds to the nearest value, rounding halves up.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, rounding halves to the even neighbour (banker's
+	// rounding).
+	RoundHalfEven
+	// RoundFloor rounds down, so the displayed amount is never more than the actual amount.
+	RoundFloor
+	// RoundCeil rounds up.
+	RoundCeil
+)
+
+// Default number of decimal places, meaning all decimals of the denomination.
+const allDecimals = -1
+
+type options struct {
+	locale    *Locale
+	places    int32
+	rounding  RoundingMode
+	trimZeros bool
+}
+
+// Option is an option for configuring a formatter.
+type Option func(*options)
+
+// WithLocale sets the locale. By default English is used.
+func WithLocale(locale *Locale) Option {
+	return func(opts *options) {
+		opts.locale = locale
+	}
+}
+
+// WithDecimalPlaces sets the number of displayed decimal places. By default all decimals of the
+// denomination are displayed.
+func WithDecimalPlaces(places uint8) Option {
+	return func(opts *options) {
+		opts.places = int32(places)
+	}
+}
+
+// WithRounding sets the rounding mode used when displaying fewer decimal places than the
+// denomination has. By default RoundHalfUp is used.
+func WithRounding(mode RoundingMode) Option {
+	return func(opts *options) {
+		opts.rounding = mode
+	}
+}
+
+// WithTrimZeros removes trailing zeros of the fractional part.
+func WithTrimZeros() Option {
+	return func(opts *options) {
+		opts.trimZeros = true
+	}
+}
+
+// Formatter formats and parses amounts.
+type Formatter struct {
+	opts options
+}
+
+// NewFormatter creates a new formatter.
+func NewFormatter(opts ...Option) *Formatter {
+	f := &Formatter{
+		opts: options{
+			locale: English,
+			places: allDecimals,
+		},
+	}
+	for _, opt := range opts {
+		opt(&f.opts)
+	}
+	return f
+}
+
+// Format formats the given amount of a denomination with the given information.
+func (f *Formatter) Format(amount *types.BaseUnits, info *accounts.DenominationInfo) string {
+	places := f.opts.places
+	if places == allDecimals {
+		places = int32(info.Decimals)
+	}
+
+	v := decimal.NewFromBigInt(amount.Amount.ToBigInt(), -int32(info.Decimals))
+	switch f.opts.rounding {
+	case RoundHalfEven:
+		v = v.RoundBank(places)
+	case RoundFloor:
+		v = v.RoundFloor(places)
+	case RoundCeil:
+		v = v.RoundCeil(places)
+	default:
+		v = v.Round(places)
+	}
+
+	intPart, fracPart, _ := strings.Cut(v.StringFixed(places), ".")
+	if f.opts.trimZeros {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+	s := f.group(intPart)
+	if fracPart != "" {
+		s += f.opts.locale.DecimalSeparator + fracPart
+	}
+	return s
+}
+
+// group inserts group separators into the given integer digits.
+func (f *Formatter) group(digits string) string {
+	l := f.opts.locale
+	if l.GroupSeparator == "" || l.GroupSize <= 0 || len(digits) <= l.GroupSize {
+		return digits
+	}
+
+	groups := []string{digits[len(digits)-l.GroupSize:]}
+	rest := digits[:len(digits)-l.GroupSize]
+	size := l.secondaryGroupSize()
+	for len(rest) > size {
+		groups = append(groups, rest[len(rest)-size:])
+		rest = rest[:len(rest)-size]
+	}
+	groups = append(groups, rest)
+
+	var b strings.Builder
+	for i := len(groups) - 1; i >= 0; i-- {
+		b.WriteString(groups[i])
+		if i > 0 {
+			b.WriteString(l.GroupSeparator)
+		}
+	}
+	return b.String()
+}
+
+// Parse parses an amount of the given denomination entered by a user in the formatter's locale.
+//
+// Group separators are optional but must be placed correctly, so that e.g. "1,000" is not
+// accidentally read as one in a locale using a comma as the decimal separator. Amounts with more
+// decimal places than the denomination has are rejected instead of being rounded.
+func (f *Formatter) Parse(input string, denomination types.Denomination, info *accounts.DenominationInfo) (*types.BaseUnits, error) {
+	l := f.opts.locale
+	input = strings.TrimFunc(input, unicode.IsSpace)
+	if input == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	if sep, _ := utf8.DecodeRuneInString(l.GroupSeparator); unicode.IsSpace(sep) {
+		// Accept any kind of space where the locale uses one.
+		input = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return sep
+			}
+			return r
+		}, input)
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(input, l.DecimalSeparator)
+	if hasFrac && fracPart == "" {
+		return nil, fmt.Errorf("%w: missing decimals", ErrInvalidAmount)
+	}
+	if strings.Contains(fracPart, l.DecimalSeparator) {
+		return nil, fmt.Errorf("%w: multiple decimal separators", ErrInvalidAmount)
+	}
+	digits, err := f.ungroup(intPart)
+	if err != nil {
+		return nil, err
+	}
+	if !isDigits(fracPart) {
+		return nil, fmt.Errorf("%w: malformed decimals '%s'", ErrInvalidAmount, fracPart)
+	}
+	if len(fracPart) > int(info.Decimals) {
+		return nil, fmt.Errorf("%w: at most %d decimal places allowed", ErrInvalidAmount, info.Decimals)
+	}
+
+	// Scale to base units.
+	raw := digits + fracPart + strings.Repeat("0", int(info.Decimals)-len(fracPart))
+	v, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed amount '%s'", ErrInvalidAmount, input)
+	}
+	var q types.Quantity
+	if err = q.FromBigInt(v); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAmount, err)
+	}
+	bu := types.NewBaseUnits(q, denomination)
+	return &bu, nil
+}
+
+// ungroup validates and removes the group separators from the given integer part.
+func (f *Formatter) ungroup(intPart string) (string, error) {
+	l := f.opts.locale
+	if l.GroupSeparator == "" || !strings.Contains(intPart, l.GroupSeparator) {
+		if !isDigits(intPart) || intPart == "" {
+			return "", fmt.Errorf("%w: malformed amount '%s'", ErrInvalidAmount, intPart)
+		}
+		return intPart, nil
+	}
+
+	groups := strings.Split(intPart, l.GroupSeparator)
+	for i, g := range groups {
+		var valid bool
+		switch {
+		case !isDigits(g) || g == "":
+		case i == len(groups)-1:
+			valid = len(g) == l.GroupSize
+		case i == 0:
+			valid = len(g) <= l.secondaryGroupSize()
+		default:
+			valid = len(g) == l.secondaryGroupSize()
+		}
+		if !valid {
+			return "", fmt.Errorf("%w: misplaced group separator in '%s'", ErrInvalidAmount, intPart)
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// isDigits checks whether the given string only consists of ASCII digits.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package display
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestFormat(t *testing.T) {
+	require := require.New(t)
+
+	info := &accounts.DenominationInfo{Decimals: 6}
+	amount := func(v uint64) *types.BaseUnits {
+		bu := types.NewBaseUnits(*quantity.NewFromUint64(v), "USD")
+		return &bu
+	}
+
+	require.Equal("1,234,567.891250", NewFormatter().Format(amount(1_234_567_891_250), info))
+	require.Equal("1.234.567,89125", NewFormatter(WithLocale(German), WithTrimZeros()).Format(amount(1_234_567_891_250), info))
+	require.Equal("1\u202f234\u202f567,89", NewFormatter(WithLocale(French), WithDecimalPlaces(2)).Format(amount(1_234_567_891_250), info))
+	require.Equal("12,34,567.9", NewFormatter(WithLocale(Indian), WithDecimalPlaces(1)).Format(amount(1_234_567_891_250), info))
+	require.Equal("0.000", NewFormatter(WithDecimalPlaces(3)).Format(amount(1), info))
+	require.Equal("0", NewFormatter(WithTrimZeros()).Format(amount(0), &accounts.DenominationInfo{}))
+
+	for _, tc := range []struct {
+		mode     RoundingMode
+		expected []string
+	}{
+		{RoundHalfUp, []string{"2", "3", "3"}},
+		{RoundHalfEven, []string{"2", "2", "3"}},
+		{RoundFloor, []string{"1", "2", "2"}},
+		{RoundCeil, []string{"2", "3", "3"}},
+	} {
+		f := NewFormatter(WithDecimalPlaces(0), WithRounding(tc.mode))
+		for i, v := range []uint64{1_500_000, 2_500_000, 2_600_000} {
+			require.Equal(tc.expected[i], f.Format(amount(v), info), "rounding mode %d", tc.mode)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+
+	info := &accounts.DenominationInfo{Decimals: 6}
+	for _, tc := range []struct {
+		locale   *Locale
+		input    string
+		expected uint64
+	}{
+		{English, "1,234,567.89", 1_234_567_890_000},
+		{English, " 1234567.89 ", 1_234_567_890_000},
+		{English, "0.000001", 1},
+		{German, "1.234,5", 1_234_500_000},
+		{French, "1 234,5", 1_234_500_000},
+		{French, "1\u00a0234,5", 1_234_500_000},
+		{Swiss, "1’234.5", 1_234_500_000},
+		{Indian, "12,34,567", 1_234_567_000_000},
+	} {
+		bu, err := NewFormatter(WithLocale(tc.locale)).Parse(tc.input, "USD", info)
+		require.NoError(err, tc.input)
+		require.EqualValues(quantity.NewFromUint64(tc.expected), &bu.Amount, tc.input)
+		require.EqualValues("USD", bu.Denomination)
+	}
+
+	for _, tc := range []struct {
+		locale *Locale
+		input  string
+	}{
+		{English, ""},
+		{English, "1,00"},
+		{English, "1,0000"},
+		{English, ",100"},
+		{English, "1.0000001"},
+		{English, "1.2.3"},
+		{English, "1."},
+		{English, "-1"},
+		{English, "1e6"},
+		{German, "1,000.5"},
+		{Indian, "1,234,567"},
+	} {
+		_, err := NewFormatter(WithLocale(tc.locale)).Parse(tc.input, "USD", info)
+		require.True(errors.Is(err, ErrInvalidAmount), "'%s' should be rejected", tc.input)
+	}
+
+	// Formatted amounts should parse back.
+	f := NewFormatter(WithLocale(Indian))
+	bu := types.NewBaseUnits(*quantity.NewFromUint64(98_765_432_100_001), "USD")
+	parsed, err := f.Parse(f.Format(&bu, info), "USD", info)
+	require.NoError(err)
+	require.EqualValues(&bu, parsed)
+}
+
+func TestLookupLocale(t *testing.T) {
+	require := require.New(t)
+
+	l, ok := LookupLocale("de_CH")
+	require.True(ok)
+	require.Equal(Swiss, l)
+	l, ok = LookupLocale("de-AT")
+	require.True(ok, "unknown regions should fall back to the language")
+	require.Equal(German, l)
+	_, ok = LookupLocale("xx")
+	require.False(ok)
+}
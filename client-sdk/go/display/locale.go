@@ -0,0 +1,68 @@
+package display
+
+import "strings"
+
+// Locale describes how amounts are written in a locale.
+type Locale struct {
+	// GroupSeparator separates groups of integer digits (e.g. thousands).
+	GroupSeparator string
+	// DecimalSeparator separates the integer and fractional digits.
+	DecimalSeparator string
+	// GroupSize is the number of digits in the group closest to the decimal separator.
+	GroupSize int
+	// SecondaryGroupSize is the number of digits in the remaining groups. Zero means GroupSize.
+	SecondaryGroupSize int
+}
+
+// Predefined locales.
+var (
+	// English uses "1,234,567.89".
+	English = &Locale{GroupSeparator: ",", DecimalSeparator: ".", GroupSize: 3}
+	// German uses "1.234.567,89".
+	German = &Locale{GroupSeparator: ".", DecimalSeparator: ",", GroupSize: 3}
+	// French uses "1 234 567,89" with narrow no-break spaces.
+	French = &Locale{GroupSeparator: "\u202f", DecimalSeparator: ",", GroupSize: 3}
+	// Swiss uses "1’234’567.89".
+	Swiss = &Locale{GroupSeparator: "’", DecimalSeparator: ".", GroupSize: 3}
+	// Indian uses "12,34,567.89".
+	Indian = &Locale{GroupSeparator: ",", DecimalSeparator: ".", GroupSize: 3, SecondaryGroupSize: 2}
+)
+
+// locales are the predefined locales by language tag.
+var locales = map[string]*Locale{
+	"en":    English,
+	"de":    German,
+	"de-ch": Swiss,
+	"es":    German,
+	"fr":    French,
+	"fr-ch": Swiss,
+	"hi":    Indian,
+	"en-in": Indian,
+	"it":    German,
+	"ja":    English,
+	"ko":    English,
+	"nl":    German,
+	"pt":    German,
+	"zh":    English,
+}
+
+// LookupLocale returns the predefined locale for the given language tag (e.g. "de-CH"), falling
+// back to the language alone (e.g. "de") in case the region is unknown.
+func LookupLocale(tag string) (*Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if l, ok := locales[tag]; ok {
+		return l, true
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	l, ok := locales[lang]
+	return l, ok
+}
+
+// secondaryGroupSize returns the size of groups other than the one closest to the decimal
+// separator.
+func (l *Locale) secondaryGroupSize() int {
+	if l.SecondaryGroupSize > 0 {
+		return l.SecondaryGroupSize
+	}
+	return l.GroupSize
+}
@@ -0,0 +1,211 @@
+// Package faucet implements clients for funding fresh accounts on test networks.
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrRateLimited is the error returned when the faucet refuses a request because too many
+// requests were made. Use errors.As with *RateLimitError to obtain the suggested backoff.
+var ErrRateLimited = errors.New("faucet: rate limited")
+
+// RateLimitError is the error returned when the faucet refuses a request due to rate limiting.
+type RateLimitError struct {
+	// RetryAfter is the amount of time after which the request may be retried. It is zero in
+	// case the faucet did not specify it.
+	RetryAfter time.Duration
+}
+
+// Error returns the string representation of the rate limit error.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", ErrRateLimited, e.RetryAfter)
+	}
+	return ErrRateLimited.Error()
+}
+
+// Is checks whether the target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Faucet is a source of test network funds.
+type Faucet interface {
+	// Fund requests the given amount to be sent to the given address. It returns once the
+	// request has been accepted, which does not mean the funds have already arrived (see Await).
+	Fund(ctx context.Context, to types.Address, amount types.BaseUnits) error
+}
+
+// fundRequest is the request body of the HTTP faucet.
+type fundRequest struct {
+	Address      string `json:"address"`
+	Amount       string `json:"amount"`
+	Denomination string `json:"denomination,omitempty"`
+}
+
+// fundResponse is the error response body of the HTTP faucet.
+type fundResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+type httpFaucet struct {
+	url        string
+	httpClient *http.Client
+}
+
+// Fund implements Faucet.
+func (f *httpFaucet) Fund(ctx context.Context, to types.Address, amount types.BaseUnits) error {
+	body, err := json.Marshal(&fundRequest{
+		Address:      to.String(),
+		Amount:       amount.Amount.String(),
+		Denomination: string(amount.Denomination),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("faucet: request failed: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	switch {
+	case rsp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{RetryAfter: parseRetryAfter(rsp.Header.Get("Retry-After"))}
+	case rsp.StatusCode < 200 || rsp.StatusCode > 299:
+		var fr fundResponse
+		data, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+		if json.Unmarshal(data, &fr) == nil && fr.Error != "" {
+			return fmt.Errorf("faucet: request rejected (status %d): %s", rsp.StatusCode, fr.Error)
+		}
+		return fmt.Errorf("faucet: request rejected (status %d)", rsp.StatusCode)
+	}
+	return nil
+}
+
+// parseRetryAfter parses the value of a Retry-After header given in seconds.
+func parseRetryAfter(v string) time.Duration {
+	secs, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// NewHTTP creates a new client for an HTTP faucet.
+//
+// Funds are requested by POSTing a JSON object with the address, the amount in base units and
+// the denomination (empty for the native denomination) to the given URL. The faucet is expected
+// to respond with status 429 and an optional Retry-After header (in seconds) when rate limiting.
+//
+// In case httpClient is nil, http.DefaultClient is used.
+func NewHTTP(url string, httpClient *http.Client) Faucet {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &httpFaucet{url: url, httpClient: httpClient}
+}
+
+type transferFaucet struct {
+	rc     client.RuntimeClient
+	signer signature.Signer
+	spec   types.SignatureAddressSpec
+	fee    *types.Fee
+}
+
+// Fund implements Faucet.
+func (f *transferFaucet) Fund(ctx context.Context, to types.Address, amount types.BaseUnits) error {
+	ac := accounts.NewV1(f.rc)
+	nonce, err := ac.Nonce(ctx, client.RoundLatest, types.NewAddress(f.spec))
+	if err != nil {
+		return fmt.Errorf("faucet: failed to query nonce: %w", err)
+	}
+
+	tb := ac.Transfer(to, amount)
+	if f.fee != nil {
+		tb.SetFeeAmount(f.fee.Amount)
+		tb.SetFeeGas(f.fee.Gas)
+	}
+	tb.AppendAuthSignature(f.spec, nonce)
+	if err = tb.AppendSign(ctx, f.signer); err != nil {
+		return err
+	}
+	if err = tb.SubmitTx(ctx, nil); err != nil {
+		return fmt.Errorf("faucet: transfer failed: %w", err)
+	}
+	return nil
+}
+
+// NewTransfer creates a faucet that funds accounts using accounts.Transfer transactions signed by
+// the given (pre-funded) signer, paying the given fee (if any). This is useful on local test
+// networks without an HTTP faucet.
+func NewTransfer(rc client.RuntimeClient, signer signature.Signer, spec types.SignatureAddressSpec, fee *types.Fee) Faucet {
+	return &transferFaucet{rc: rc, signer: signer, spec: spec, fee: fee}
+}
+
+// FundWithRetry requests funds from the given faucet, waiting and retrying for as long as the
+// faucet is rate limiting the requests and the context is not done.
+func FundWithRetry(ctx context.Context, f Faucet, to types.Address, amount types.BaseUnits) error {
+	const defaultBackoff = 10 * time.Second
+	for {
+		err := f.Fund(ctx, to, amount)
+		var rle *RateLimitError
+		if !errors.As(err, &rle) {
+			return err
+		}
+
+		backoff := rle.RetryAfter
+		if backoff <= 0 {
+			backoff = defaultBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// Await waits until the balance of the given address in the denomination of the given amount is
+// at least the given amount, polling at the given interval.
+func Await(ctx context.Context, ac accounts.V1, address types.Address, amount types.BaseUnits, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		balances, err := ac.Balances(ctx, client.RoundLatest, address)
+		if err != nil && !client.IsTransient(err) {
+			return fmt.Errorf("faucet: failed to query balances: %w", err)
+		}
+		if err == nil {
+			balance := balances.Balances[amount.Denomination]
+			if balance.Cmp(&amount.Amount) >= 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestHTTPFaucet(t *testing.T) {
+	require := require.New(t)
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req fundRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case req.Address != sdkTesting.Alice.Address.String():
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"address not allowed"}`))
+		case atomic.AddInt32(&requests, 1) == 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	f := NewHTTP(srv.URL, nil)
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)
+
+	err := f.Fund(context.Background(), sdkTesting.Bob.Address, amount)
+	require.Error(err)
+	require.Contains(err.Error(), "address not allowed")
+
+	err = f.Fund(context.Background(), sdkTesting.Alice.Address, amount)
+	require.True(errors.Is(err, ErrRateLimited), "first request should be rate limited")
+	var rle *RateLimitError
+	require.True(errors.As(err, &rle))
+	require.Equal(time.Second, rle.RetryAfter)
+
+	atomic.StoreInt32(&requests, 0)
+	err = FundWithRetry(context.Background(), f, sdkTesting.Alice.Address, amount)
+	require.NoError(err, "FundWithRetry should retry after the rate limit")
+	require.EqualValues(2, atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,512 @@
+// Package faults implements a fault-injecting runtime client for testing the resilience of
+// applications built on the SDK.
+//
+// The faults to inject are described by a scenario, which can be loaded from a JSON or YAML file
+// (see LoadScenario). For example:
+//
+//	seed: 42
+//	rules:
+//	  - operations: [query, get_block]
+//	    probability: 0.2
+//	    latency: 500ms
+//	    stale_rounds: 3
+//	  - operations: [submit]
+//	    probability: 0.05
+//	    drop: true
+//	    duplicate: true
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrDropped is the error returned in place of a dropped response. It is classified as transient
+// by the client (see client.IsTransient).
+var ErrDropped = status.Error(codes.Unavailable, "faults: response dropped")
+
+// Operations that faults can be injected into.
+const (
+	// OpQuery are runtime queries.
+	OpQuery = "query"
+	// OpSubmit are transaction submissions.
+	OpSubmit = "submit"
+	// OpGetInfo are runtime information lookups.
+	OpGetInfo = "get_info"
+	// OpGetBlock are block lookups.
+	OpGetBlock = "get_block"
+	// OpGetTransactions are transaction lookups.
+	OpGetTransactions = "get_transactions"
+	// OpGetEvents are event lookups.
+	OpGetEvents = "get_events"
+)
+
+var operations = map[string]bool{
+	OpQuery:           true,
+	OpSubmit:          true,
+	OpGetInfo:         true,
+	OpGetBlock:        true,
+	OpGetTransactions: true,
+	OpGetEvents:       true,
+}
+
+// Duration is a duration encoded as a string, e.g. "1.5s".
+type Duration time.Duration
+
+// MarshalText encodes the duration into text form.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText decodes a text marshaled duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("faults: malformed duration: %w", err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Rule is a fault injection rule.
+type Rule struct {
+	// Operations are the operations the rule applies to. An empty list matches all operations.
+	Operations []string `json:"operations,omitempty" yaml:"operations,omitempty"`
+	// Probability is the probability of the rule being applied to a matching operation.
+	Probability float64 `json:"probability" yaml:"probability"`
+
+	// Latency is the latency added before the operation.
+	Latency Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+	// Drop drops the response of the operation. The operation is still performed, so e.g. a
+	// transaction whose response was dropped may have been executed.
+	Drop bool `json:"drop,omitempty" yaml:"drop,omitempty"`
+	// Duplicate submits transactions a second time.
+	Duplicate bool `json:"duplicate,omitempty" yaml:"duplicate,omitempty"`
+	// StaleRounds makes operations on the latest round observe a round this many rounds behind.
+	StaleRounds uint64 `json:"stale_rounds,omitempty" yaml:"stale_rounds,omitempty"`
+}
+
+// Validate performs basic validation on the rule.
+func (r *Rule) Validate() error {
+	for _, op := range r.Operations {
+		if !operations[op] {
+			return fmt.Errorf("unknown operation '%s'", op)
+		}
+	}
+	if r.Probability < 0 || r.Probability > 1 {
+		return fmt.Errorf("probability %v out of range", r.Probability)
+	}
+	if r.Latency < 0 {
+		return fmt.Errorf("negative latency")
+	}
+	return nil
+}
+
+func (r *Rule) matches(op string) bool {
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, o := range r.Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Scenario is a fault injection scenario.
+type Scenario struct {
+	// Seed is the seed of the random number generator, which makes fault injection reproducible
+	// for a given sequence of operations.
+	Seed int64 `json:"seed" yaml:"seed"`
+	// Rules are the fault injection rules. All matching rules are applied.
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Validate performs basic validation on the scenario.
+func (s *Scenario) Validate() error {
+	for i := range s.Rules {
+		if err := s.Rules[i].Validate(); err != nil {
+			return fmt.Errorf("faults: rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// LoadScenario loads a scenario from the given JSON or YAML file, depending on its extension.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("faults: failed to read scenario: %w", err)
+	}
+
+	var s Scenario
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &s)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &s)
+	default:
+		return nil, fmt.Errorf("faults: unsupported scenario file extension: '%s'", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("faults: malformed scenario: %w", err)
+	}
+	if err = s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// fault is the combination of the faults injected into a single operation.
+type fault struct {
+	drop        bool
+	duplicate   bool
+	staleRounds uint64
+}
+
+// result returns the error to return for an operation that completed with the given error.
+func (f *fault) result(err error) error {
+	if err == nil && f.drop {
+		return ErrDropped
+	}
+	return err
+}
+
+// runtimeClient is a fault-injecting runtime client.
+type runtimeClient struct {
+	client.RuntimeClient
+
+	rules []Rule
+
+	l   sync.Mutex
+	rng *rand.Rand
+}
+
+// Codec implements client.CodecProvider.
+func (rc *runtimeClient) Codec() client.CodecConfig {
+	return client.CodecOf(rc.RuntimeClient)
+}
+
+// ResolveMethod implements client.MethodResolver.
+func (rc *runtimeClient) ResolveMethod(method string) string {
+	return client.ResolveMethod(rc.RuntimeClient, method)
+}
+
+// Limiter implements client.LimiterProvider.
+func (rc *runtimeClient) Limiter() *client.Limiter {
+	return client.LimiterOf(rc.RuntimeClient)
+}
+
+// New wraps the given runtime client to inject faults according to the given scenario.
+//
+// Operations not covered by the scenario operations (e.g. block subscriptions) are passed through
+// unchanged.
+func New(rc client.RuntimeClient, scenario *Scenario) (client.RuntimeClient, error) {
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &runtimeClient{
+		RuntimeClient: rc,
+		rules:         scenario.Rules,
+		rng:           rand.New(rand.NewSource(scenario.Seed)), //nolint: gosec
+	}, nil
+}
+
+// inject determines the faults to inject into the given operation and waits for the added
+// latency.
+func (rc *runtimeClient) inject(ctx context.Context, op string) (*fault, error) {
+	var (
+		f       fault
+		latency time.Duration
+	)
+	rc.l.Lock()
+	for i := range rc.rules {
+		r := &rc.rules[i]
+		if !r.matches(op) || rc.rng.Float64() >= r.Probability {
+			continue
+		}
+		latency += time.Duration(r.Latency)
+		f.drop = f.drop || r.Drop
+		f.duplicate = f.duplicate || r.Duplicate
+		if r.StaleRounds > f.staleRounds {
+			f.staleRounds = r.StaleRounds
+		}
+	}
+	rc.l.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return &f, nil
+}
+
+// round returns the round an operation on the given round is performed at.
+func (rc *runtimeClient) round(ctx context.Context, round uint64, f *fault) (uint64, error) {
+	if round != client.RoundLatest || f.staleRounds == 0 {
+		return round, nil
+	}
+	blk, err := rc.RuntimeClient.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, err
+	}
+	if blk.Header.Round < f.staleRounds {
+		return 0, nil
+	}
+	return blk.Header.Round - f.staleRounds, nil
+}
+
+// prepare injects faults into an operation on the given round and returns the round to perform
+// the operation at.
+func (rc *runtimeClient) prepare(ctx context.Context, op string, round uint64) (*fault, uint64, error) {
+	f, err := rc.inject(ctx, op)
+	if err != nil {
+		return nil, 0, err
+	}
+	if round, err = rc.round(ctx, round, f); err != nil {
+		return nil, 0, err
+	}
+	return f, round, nil
+}
+
+// duplicate submits the given transaction again in case requested.
+func (rc *runtimeClient) duplicate(ctx context.Context, f *fault, tx *types.UnverifiedTransaction) {
+	if f.duplicate {
+		_ = rc.RuntimeClient.SubmitTxNoWait(ctx, tx)
+	}
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	f, err := rc.inject(ctx, OpGetInfo)
+	if err != nil {
+		return nil, err
+	}
+	info, err := rc.RuntimeClient.GetInfo(ctx)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+	f, err := rc.inject(ctx, OpSubmit)
+	if err != nil {
+		return nil, err
+	}
+	result, err := rc.RuntimeClient.SubmitTxRaw(ctx, tx)
+	rc.duplicate(ctx, f, tx)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) SubmitTxRawMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	f, err := rc.inject(ctx, OpSubmit)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := rc.RuntimeClient.SubmitTxRawMeta(ctx, tx)
+	rc.duplicate(ctx, f, tx)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	f, err := rc.inject(ctx, OpSubmit)
+	if err != nil {
+		return nil, err
+	}
+	result, err := rc.RuntimeClient.SubmitTx(ctx, tx)
+	rc.duplicate(ctx, f, tx)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) SubmitTxMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*client.SubmitTxMeta, error) {
+	f, err := rc.inject(ctx, OpSubmit)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := rc.RuntimeClient.SubmitTxMeta(ctx, tx)
+	rc.duplicate(ctx, f, tx)
+	if f.drop {
+		// Dropped responses carry no metadata.
+		return nil, ErrDropped
+	}
+	return meta, err
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
+	f, err := rc.inject(ctx, OpSubmit)
+	if err != nil {
+		return err
+	}
+	err = rc.RuntimeClient.SubmitTxNoWait(ctx, tx)
+	rc.duplicate(ctx, f, tx)
+	return f.result(err)
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	f, round, err := rc.prepare(ctx, OpGetBlock, round)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := rc.RuntimeClient.GetBlock(ctx, round)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	f, round, err := rc.prepare(ctx, OpGetTransactions, round)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := rc.RuntimeClient.GetTransactions(ctx, round)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*client.TransactionWithResults, error) {
+	f, round, err := rc.prepare(ctx, OpGetTransactions, round)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := rc.RuntimeClient.GetTransactionsWithResults(ctx, round)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	f, round, err := rc.prepare(ctx, OpGetEvents, round)
+	if err != nil {
+		return nil, err
+	}
+	evs, err := rc.RuntimeClient.GetEventsRaw(ctx, round)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetEvents(ctx context.Context, round uint64, decoders []client.EventDecoder, includeUndecoded bool) ([]client.DecodedEvent, error) {
+	f, round, err := rc.prepare(ctx, OpGetEvents, round)
+	if err != nil {
+		return nil, err
+	}
+	evs, err := rc.RuntimeClient.GetEvents(ctx, round, decoders, includeUndecoded)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) GetEventsForModule(ctx context.Context, round uint64, module string) ([]client.DecodedEvent, error) {
+	f, round, err := rc.prepare(ctx, OpGetEvents, round)
+	if err != nil {
+		return nil, err
+	}
+	evs, err := rc.RuntimeClient.GetEventsForModule(ctx, round, module)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) StreamEventsRaw(ctx context.Context, round uint64, chunkSize int, fn func([]*types.Event) error) error {
+	f, round, err := rc.prepare(ctx, OpGetEvents, round)
+	if err != nil {
+		return err
+	}
+	if f.drop {
+		return ErrDropped
+	}
+	return rc.RuntimeClient.StreamEventsRaw(ctx, round, chunkSize, fn)
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) StreamEvents(
+	ctx context.Context,
+	round uint64,
+	decoders []client.EventDecoder,
+	includeUndecoded bool,
+	chunkSize int,
+	fn func([]client.DecodedEvent) error,
+) error {
+	f, round, err := rc.prepare(ctx, OpGetEvents, round)
+	if err != nil {
+		return err
+	}
+	if f.drop {
+		return ErrDropped
+	}
+	return rc.RuntimeClient.StreamEvents(ctx, round, decoders, includeUndecoded, chunkSize, fn)
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	f, round, err := rc.prepare(ctx, OpQuery, round)
+	if err != nil {
+		return err
+	}
+	return f.result(rc.RuntimeClient.Query(ctx, round, method, args, rsp))
+}
+
+// Implements client.RuntimeClient.
+func (rc *runtimeClient) QueryRaw(ctx context.Context, round uint64, method string, args interface{}) (cbor.RawMessage, error) {
+	f, round, err := rc.prepare(ctx, OpQuery, round)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := rc.RuntimeClient.QueryRaw(ctx, round, method, args)
+	if err = f.result(err); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -0,0 +1,117 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeRuntimeClient struct {
+	client.RuntimeClient
+
+	latest      uint64
+	queryRounds []uint64
+	submissions int
+}
+
+func (f *fakeRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = round
+	if round == client.RoundLatest {
+		blk.Header.Round = f.latest
+	}
+	return &blk, nil
+}
+
+func (f *fakeRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	f.queryRounds = append(f.queryRounds, round)
+	return nil
+}
+
+func (f *fakeRuntimeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
+	f.submissions++
+	return nil
+}
+
+func TestLoadScenario(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.yaml")
+	require.NoError(os.WriteFile(path, []byte(`
+seed: 42
+rules:
+  - operations: [query]
+    probability: 1
+    latency: 10ms
+    stale_rounds: 3
+  - operations: [submit]
+    probability: 0.5
+    drop: true
+`), 0o600))
+	s, err := LoadScenario(path)
+	require.NoError(err, "LoadScenario")
+	require.EqualValues(42, s.Seed)
+	require.Len(s.Rules, 2)
+	require.Equal(Duration(10*time.Millisecond), s.Rules[0].Latency)
+	require.EqualValues(3, s.Rules[0].StaleRounds)
+	require.True(s.Rules[1].Drop)
+
+	path = filepath.Join(dir, "scenario.json")
+	require.NoError(os.WriteFile(path, []byte(`{"rules": [{"operations": ["get_block"], "probability": 0.1, "latency": "1s"}]}`), 0o600))
+	s, err = LoadScenario(path)
+	require.NoError(err, "LoadScenario")
+	require.Equal(Duration(time.Second), s.Rules[0].Latency)
+
+	require.NoError(os.WriteFile(path, []byte(`{"rules": [{"operations": ["unknown"], "probability": 0.1}]}`), 0o600))
+	_, err = LoadScenario(path)
+	require.Error(err, "unknown operations should be rejected")
+
+	require.NoError(os.WriteFile(path, []byte(`{"rules": [{"probability": 2}]}`), 0o600))
+	_, err = LoadScenario(path)
+	require.Error(err, "invalid probabilities should be rejected")
+}
+
+func TestFaults(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	upstream := &fakeRuntimeClient{latest: 100}
+	rc, err := New(upstream, &Scenario{Rules: []Rule{
+		{Operations: []string{OpQuery}, Probability: 1, Latency: Duration(10 * time.Millisecond), StaleRounds: 3},
+		{Operations: []string{OpSubmit}, Probability: 1, Drop: true, Duplicate: true},
+	}})
+	require.NoError(err, "New")
+
+	start := time.Now()
+	require.NoError(rc.Query(ctx, client.RoundLatest, "test.Query", nil, nil))
+	require.GreaterOrEqual(time.Since(start), 10*time.Millisecond, "latency should be added")
+	require.NoError(rc.Query(ctx, 50, "test.Query", nil, nil))
+	require.Equal([]uint64{97, 50}, upstream.queryRounds, "only the latest round should be stale")
+
+	blk, err := rc.GetBlock(ctx, client.RoundLatest)
+	require.NoError(err, "GetBlock")
+	require.EqualValues(100, blk.Header.Round, "rules should only apply to their operations")
+
+	err = rc.SubmitTxNoWait(ctx, &types.UnverifiedTransaction{})
+	require.True(errors.Is(err, ErrDropped), "responses should be dropped")
+	require.True(client.IsTransient(err), "dropped responses should be transient")
+	require.Equal(2, upstream.submissions, "transactions should be submitted twice")
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(rc.Query(cctx, client.RoundLatest, "test.Query", nil, nil), context.Canceled)
+
+	_, err = New(upstream, &Scenario{Rules: []Rule{{Probability: -1}}})
+	require.Error(err, "New should validate the scenario")
+}
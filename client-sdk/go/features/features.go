@@ -0,0 +1,176 @@
+// Package features implements SDK-level feature flags, so that experimental APIs can ship without
+// destabilizing the stable surface.
+//
+// Each feature is registered by the package providing it (e.g. accounts.FeatureWatchEvents)
+// together with the runtime methods it needs. Experimental features are disabled until enabled
+// with EnableExperimental, while stable features are always enabled. Probe additionally checks
+// the runtime capabilities (see core.Capabilities), so callers can branch cleanly on whether a
+// feature is usable against a given deployment:
+//
+//	// In the package providing the feature.
+//	const FeatureWatch features.Feature = "example.Watch"
+//
+//	func init() {
+//		features.Register(FeatureWatch, features.Spec{Experimental: true, Methods: []string{"example.Watch"}})
+//	}
+//
+//	// In the caller.
+//	_ = features.EnableExperimental(example.FeatureWatch)
+//	if err := features.Probe(example.FeatureWatch, rc.Capabilities); err != nil {
+//		// Fall back to polling.
+//	}
+package features
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+)
+
+var (
+	// ErrUnknown is the error returned for features which have not been registered.
+	ErrUnknown = errors.New("features: unknown feature")
+	// ErrNotEnabled is the error returned when using an experimental feature which has not been
+	// enabled.
+	ErrNotEnabled = errors.New("features: experimental feature not enabled")
+)
+
+// Feature is the name of a feature, e.g. "accounts.WatchProposal".
+type Feature string
+
+// Spec describes a feature.
+type Spec struct {
+	// Experimental marks the feature as experimental, i.e. disabled by default and subject to
+	// change without notice.
+	Experimental bool
+	// Methods are the runtime methods the feature needs.
+	Methods []string
+	// Description is a short description of the feature.
+	Description string
+}
+
+// Status is the status of a feature.
+type Status struct {
+	Feature Feature
+	Spec    Spec
+	// Enabled is true iff the feature is enabled, see Enabled.
+	Enabled bool
+}
+
+// registry holds all registered features.
+var registry struct {
+	sync.RWMutex
+
+	specs   map[Feature]Spec
+	enabled map[Feature]bool
+}
+
+// Register registers a new feature. It is meant to be called from init functions of the packages
+// providing the features and panics in case the feature is already registered.
+func Register(f Feature, spec Spec) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.specs[f]; ok {
+		panic(fmt.Sprintf("features: feature %s already registered", f))
+	}
+	if registry.specs == nil {
+		registry.specs = make(map[Feature]Spec)
+		registry.enabled = make(map[Feature]bool)
+	}
+	registry.specs[f] = spec
+}
+
+// EnableExperimental enables the given experimental features. Enabling stable features has no
+// effect.
+func EnableExperimental(features ...Feature) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, f := range features {
+		if _, ok := registry.specs[f]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknown, f)
+		}
+	}
+	for _, f := range features {
+		registry.enabled[f] = true
+	}
+	return nil
+}
+
+// DisableExperimental disables the given experimental features.
+func DisableExperimental(features ...Feature) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, f := range features {
+		delete(registry.enabled, f)
+	}
+}
+
+// Enabled checks whether the given feature is enabled. Stable features are always enabled, while
+// experimental features need to be enabled with EnableExperimental.
+func Enabled(f Feature) bool {
+	return Require(f) == nil
+}
+
+// Require returns ErrNotEnabled in case the given feature is not enabled and ErrUnknown in case
+// the feature has not been registered. Experimental APIs should call it before doing anything.
+func Require(f Feature) error {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	spec, ok := registry.specs[f]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: %s", ErrUnknown, f)
+	case spec.Experimental && !registry.enabled[f]:
+		return fmt.Errorf("%w: %s", ErrNotEnabled, f)
+	default:
+		return nil
+	}
+}
+
+// Probe checks whether the given feature is usable against the runtime with the given
+// capabilities, i.e. whether it is enabled (see Require) and the runtime supports all methods the
+// feature needs. Missing methods fail with core.ErrUnsupported.
+//
+// Unknown (or nil) capabilities are assumed to support all methods.
+func Probe(f Feature, caps *core.Capabilities) error {
+	if err := Require(f); err != nil {
+		return err
+	}
+	if caps == nil {
+		return nil
+	}
+
+	registry.RLock()
+	methods := registry.specs[f].Methods
+	registry.RUnlock()
+	for _, method := range methods {
+		if err := caps.CheckMethod(method); err != nil {
+			return fmt.Errorf("features: %s: %w", f, err)
+		}
+	}
+	return nil
+}
+
+// List returns the status of all registered features ordered by name.
+func List() []Status {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	statuses := make([]Status, 0, len(registry.specs))
+	for f, spec := range registry.specs {
+		statuses = append(statuses, Status{
+			Feature: f,
+			Spec:    spec,
+			Enabled: !spec.Experimental || registry.enabled[f],
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Feature < statuses[j].Feature })
+	return statuses
+}
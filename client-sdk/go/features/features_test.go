@@ -0,0 +1,52 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+)
+
+func TestFeatures(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		stable       Feature = "test.Stable"
+		experimental Feature = "test.Experimental"
+	)
+	Register(stable, Spec{Methods: []string{"accounts.Transfer"}})
+	Register(experimental, Spec{Experimental: true, Methods: []string{"accounts.Transfer", "accounts.Watch"}})
+	require.Panics(func() { Register(stable, Spec{}) }, "duplicate features should be rejected")
+
+	require.True(Enabled(stable), "stable features should always be enabled")
+	require.False(Enabled(experimental), "experimental features should be disabled by default")
+	require.ErrorIs(Require(experimental), ErrNotEnabled)
+	require.ErrorIs(Require("test.Unknown"), ErrUnknown)
+	require.ErrorIs(EnableExperimental(experimental, "test.Unknown"), ErrUnknown)
+	require.False(Enabled(experimental), "failed calls should not enable any feature")
+
+	require.NoError(EnableExperimental(experimental), "EnableExperimental")
+	require.True(Enabled(experimental))
+	require.Equal([]Status{
+		{Feature: experimental, Spec: Spec{Experimental: true, Methods: []string{"accounts.Transfer", "accounts.Watch"}}, Enabled: true},
+		{Feature: stable, Spec: Spec{Methods: []string{"accounts.Transfer"}}, Enabled: true},
+	}, List())
+
+	caps := core.NewCapabilities(&core.RuntimeInfoResponse{
+		Modules: map[string]core.ModuleInfo{
+			"accounts": {
+				Methods: []core.MethodHandlerInfo{
+					{Name: "accounts.Transfer", Kind: core.MethodHandlerKindCall},
+				},
+			},
+		},
+	})
+	require.NoError(Probe(stable, caps), "Probe")
+	require.ErrorIs(Probe(experimental, caps), core.ErrUnsupported, "features should need all their methods")
+	require.NoError(Probe(experimental, core.NewCapabilities(nil)), "unknown capabilities should assume support")
+	require.NoError(Probe(experimental, nil))
+
+	DisableExperimental(experimental)
+	require.ErrorIs(Probe(experimental, nil), ErrNotEnabled)
+}
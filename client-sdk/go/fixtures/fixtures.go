@@ -0,0 +1,138 @@
+// Package fixtures provides an INSECURE deterministic wallet for examples and tutorials, so that
+// they are runnable against local and test networks without manual key setup.
+//
+// All keys of the wallet are derived from fixed, publicly known seeds. Anyone can sign with them,
+// so they must never hold anything of value. To prevent accidental use, opening the wallet requires
+// the OASIS_SDK_INSECURE_FIXTURES environment variable to be set to 1 and is only allowed on
+// Testnet and explicitly allowed (e.g. local) networks.
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/account"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// OptInEnv is the environment variable which must be set to 1 to open the wallet.
+const OptInEnv = "OASIS_SDK_INSECURE_FIXTURES"
+
+// committeeName is the name the keys of the wallet are derived from.
+const committeeName = "fixtures"
+
+var (
+	// ErrNotOptedIn is the error returned when opening the wallet without opting in.
+	ErrNotOptedIn = fmt.Errorf("fixtures: insecure fixture wallet requires %s=1", OptInEnv)
+	// ErrNetworkNotAllowed is the error returned when opening the wallet against a network which
+	// is not allowed (see WithChainContexts).
+	ErrNetworkNotAllowed = errors.New("fixtures: insecure fixture wallet can't be used on this network")
+)
+
+// Option is a fixture wallet option.
+type Option func(*options)
+
+type options struct {
+	chainContexts []string
+}
+
+// WithChainContexts additionally allows opening the wallet on the consensus networks with the
+// given chain contexts, e.g. a local network.
+func WithChainContexts(chainContexts ...string) Option {
+	return func(o *options) {
+		o.chainContexts = append(o.chainContexts, chainContexts...)
+	}
+}
+
+// Wallet is an insecure wallet of deterministic accounts, one for each role plus a few users.
+type Wallet struct {
+	rc        client.RuntimeClient
+	committee *sdkTesting.Committee
+}
+
+// Open opens the fixture wallet for the given runtime.
+//
+// It fails with ErrNotOptedIn unless the OptInEnv environment variable is set to 1 and with
+// ErrNetworkNotAllowed unless the runtime is on Testnet or one of the networks allowed with
+// WithChainContexts.
+func Open(ctx context.Context, rc client.RuntimeClient, opts ...Option) (*Wallet, error) {
+	if os.Getenv(OptInEnv) != "1" {
+		return nil, ErrNotOptedIn
+	}
+	var o options
+	if testnet, ok := config.DefaultNetworks.All["testnet"]; ok {
+		o.chainContexts = append(o.chainContexts, testnet.ChainContext)
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	info, err := rc.GetInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: failed to retrieve runtime info: %w", err)
+	}
+	if !allowed(info, o.chainContexts) {
+		return nil, ErrNetworkNotAllowed
+	}
+
+	return &Wallet{
+		rc: rc,
+		committee: sdkTesting.NewCommittee(committeeName, map[types.Role]int{
+			types.Admin:             1,
+			types.MintProposer:      1,
+			types.MintVoter:         1,
+			types.BurnProposer:      1,
+			types.BurnVoter:         1,
+			types.WhitelistProposer: 1,
+			types.WhitelistVoter:    1,
+			types.BlacklistProposer: 1,
+			types.BlacklistVoter:    1,
+			types.User:              3,
+		}),
+	}, nil
+}
+
+// allowed checks whether the given runtime is on one of the given consensus networks.
+func allowed(info *types.RuntimeInfo, chainContexts []string) bool {
+	for _, chainContext := range chainContexts {
+		if signature.DeriveChainContext(info.ID, chainContext) == info.ChainContext {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the key holding the given role. For the User role, use User instead.
+//
+// Panics if the wallet has no key with the given role.
+func (w *Wallet) Key(role types.Role) sdkTesting.TestKey {
+	return w.committee.Member(role, 0)
+}
+
+// User returns the i-th user key, i.e. a key without any governance role. There are three users.
+//
+// Panics if there is no such user.
+func (w *Wallet) User(i int) sdkTesting.TestKey {
+	return w.committee.Member(types.User, i)
+}
+
+// Account returns an account controlled by the given key of the wallet.
+func (w *Wallet) Account(key sdkTesting.TestKey, opts ...account.Option) *account.Account {
+	return account.New(w.rc, key.Signer, key.SigSpec, opts...)
+}
+
+// Roles returns the roles of all keys, e.g. for use in an InitOwners transaction.
+func (w *Wallet) Roles() map[types.Address]types.Role {
+	return w.committee.Roles()
+}
+
+// Keys returns all keys of the wallet ordered by role, e.g. for funding them with a faucet.
+func (w *Wallet) Keys() []sdkTesting.TestKey {
+	return w.committee.Keys()
+}
@@ -0,0 +1,64 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type fakeClient struct {
+	client.RuntimeClient
+
+	id           common.Namespace
+	chainContext string
+}
+
+func (c *fakeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ID: c.id, ChainContext: signature.DeriveChainContext(c.id, c.chainContext)}, nil
+}
+
+func TestOpen(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	const localChainContext = "a1b2c3"
+	local := &fakeClient{id: common.NewTestNamespaceFromSeed([]byte("fixtures"), 0), chainContext: localChainContext}
+
+	t.Setenv(OptInEnv, "")
+	_, err := Open(ctx, local)
+	require.ErrorIs(err, ErrNotOptedIn, "the wallet should require opting in")
+
+	t.Setenv(OptInEnv, "1")
+	mainnet := config.DefaultNetworks.All["mainnet"]
+	for _, id := range []common.Namespace{
+		mainnet.ParaTimes.All["sapphire"].Namespace(),
+		common.NewTestNamespaceFromSeed([]byte("unknown mainnet paratime"), 0),
+	} {
+		_, err = Open(ctx, &fakeClient{id: id, chainContext: mainnet.ChainContext}, WithChainContexts(localChainContext))
+		require.ErrorIs(err, ErrNetworkNotAllowed, "the wallet should refuse Mainnet")
+	}
+	_, err = Open(ctx, local)
+	require.ErrorIs(err, ErrNetworkNotAllowed, "the wallet should refuse networks which are not allowed")
+
+	testnet := config.DefaultNetworks.All["testnet"]
+	_, err = Open(ctx, &fakeClient{id: testnet.ParaTimes.All["sapphire"].Namespace(), chainContext: testnet.ChainContext})
+	require.NoError(err, "the wallet should be allowed on Testnet")
+
+	w, err := Open(ctx, local, WithChainContexts(localChainContext))
+	require.NoError(err, "Open")
+	w2, err := Open(ctx, local, WithChainContexts(localChainContext))
+	require.NoError(err, "Open")
+	require.Equal(w.Key(types.Admin).Address, w2.Key(types.Admin).Address, "keys should be deterministic")
+	require.NotEqual(w.User(0).Address, w.User(1).Address)
+	require.Len(w.Keys(), 12)
+	require.Equal(types.MintVoter, w.Roles()[w.Key(types.MintVoter).Address])
+	require.Equal(w.User(2).Address, w.Account(w.User(2)).Address())
+}
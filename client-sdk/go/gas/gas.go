@@ -0,0 +1,174 @@
+// Package gas implements introspection of the gas cost tables of the runtime modules, e.g. to
+// estimate transaction fees offline or to alert when a runtime upgrade changes gas pricing.
+package gas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Gas cost keys of the core module used by Estimate.
+const (
+	CostTxByte        = "core.tx_byte"
+	CostAuthSignature = "core.auth_signature"
+)
+
+// signatureSize is the size of a signature assumed by Estimate.
+const signatureSize = 64
+
+// methodCosts are the gas cost keys charged by each method.
+var methodCosts = map[string]string{
+	"accounts.Transfer":   "accounts.tx_transfer",
+	"accounts.InitOwners": "accounts.tx_managest",
+	"accounts.Propose":    "accounts.tx_managest",
+	"accounts.VoteST":     "accounts.tx_managest",
+	"accounts.MintST":     "accounts.tx_managest",
+	"accounts.BurnST":     "accounts.tx_managest",
+	"consensus.Deposit":   "consensus_accounts.tx_deposit",
+	"consensus.Withdraw":  "consensus_accounts.tx_withdraw",
+}
+
+// Table is the gas cost table of a runtime at a given round.
+type Table struct {
+	// Round is the round the table was read at.
+	Round uint64 `json:"round"`
+	// Costs are the gas costs keyed by module and cost name, e.g. "accounts.tx_transfer".
+	Costs map[string]uint64 `json:"costs"`
+}
+
+// Load reads the gas cost table from the parameters of the core, accounts and consensus accounts
+// modules at the given round.
+func Load(ctx context.Context, rc client.RuntimeClient, round uint64) (*Table, error) {
+	if round == client.RoundLatest {
+		blk, err := rc.GetBlock(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("gas: failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	t := Table{
+		Round: round,
+		Costs: make(map[string]uint64),
+	}
+	coreParams, err := core.NewV1(rc).Parameters(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("gas: failed to query core parameters: %w", err)
+	}
+	if err = t.add(core.ModuleName, coreParams.GasCosts); err != nil {
+		return nil, err
+	}
+	accountsParams, err := accounts.NewV1(rc).Parameters(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("gas: failed to query accounts parameters: %w", err)
+	}
+	if err = t.add(accounts.ModuleName, accountsParams.GasCosts); err != nil {
+		return nil, err
+	}
+	consensusParams, err := consensusaccounts.NewV1(rc).Parameters(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("gas: failed to query consensus accounts parameters: %w", err)
+	}
+	if err = t.add(consensusaccounts.ModuleName, consensusParams.GasCosts); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// add adds the gas costs of the given module.
+func (t *Table) add(module string, costs interface{}) error {
+	data, err := json.Marshal(costs)
+	if err != nil {
+		return fmt.Errorf("gas: failed to encode %s gas costs: %w", module, err)
+	}
+	var flat map[string]uint64
+	if err = json.Unmarshal(data, &flat); err != nil {
+		return fmt.Errorf("gas: malformed %s gas costs: %w", module, err)
+	}
+	for name, cost := range flat {
+		t.Costs[module+"."+name] = cost
+	}
+	return nil
+}
+
+// MethodCost returns the gas charged by the given method, excluding the costs charged for every
+// transaction (see Estimate). The last return value is false in case the cost is not known.
+func (t *Table) MethodCost(method string) (uint64, bool) {
+	key, ok := methodCosts[method]
+	if !ok {
+		return 0, false
+	}
+	cost, ok := t.Costs[key]
+	return cost, ok
+}
+
+// Estimate estimates the gas used by the given transaction, i.e. the cost of its method plus the
+// costs charged for the transaction size and signatures.
+//
+// The estimate is computed offline and does not account for gas used dynamically during
+// execution, so it is only suitable for methods with fixed costs. Use core.V1.EstimateGas for
+// other methods.
+func (t *Table) Estimate(tx *types.Transaction) (uint64, error) {
+	cost, ok := t.MethodCost(tx.Call.Method)
+	if !ok {
+		return 0, fmt.Errorf("gas: unknown cost of method '%s'", tx.Call.Method)
+	}
+	signatures := uint64(len(tx.AuthInfo.SignerInfo))
+	size := uint64(len(cbor.Marshal(tx))) + signatures*signatureSize
+	return cost + size*t.Costs[CostTxByte] + signatures*t.Costs[CostAuthSignature], nil
+}
+
+// Change is a changed gas cost.
+type Change struct {
+	// Key is the key of the changed gas cost, e.g. "accounts.tx_transfer".
+	Key string `json:"key"`
+	// Old is the previous cost (zero if the cost was added).
+	Old uint64 `json:"old"`
+	// New is the current cost (zero if the cost was removed).
+	New uint64 `json:"new"`
+	// Methods are the methods affected by the change.
+	Methods []string `json:"methods,omitempty"`
+}
+
+// String returns a string representation of the change.
+func (c *Change) String() string {
+	return fmt.Sprintf("gas cost %s changed from %d to %d", c.Key, c.Old, c.New)
+}
+
+// Diff returns the gas costs that changed between the given tables, ordered by key.
+func Diff(from, to *Table) []Change {
+	keys := make(map[string]struct{})
+	for key := range from.Costs {
+		keys[key] = struct{}{}
+	}
+	for key := range to.Costs {
+		keys[key] = struct{}{}
+	}
+
+	var changes []Change
+	for key := range keys {
+		if from.Costs[key] == to.Costs[key] {
+			continue
+		}
+		c := Change{Key: key, Old: from.Costs[key], New: to.Costs[key]}
+		for method, costKey := range methodCosts {
+			if costKey == key {
+				c.Methods = append(c.Methods, method)
+			}
+		}
+		sort.Strings(c.Methods)
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
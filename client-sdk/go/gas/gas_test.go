@@ -0,0 +1,96 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// paramsRuntimeClient serves the module parameters.
+type paramsRuntimeClient struct {
+	client.RuntimeClient
+
+	params map[string]interface{}
+}
+
+func (rc *paramsRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	params, ok := rc.params[method]
+	if !ok {
+		return fmt.Errorf("unknown method: %s", method)
+	}
+	return cbor.Unmarshal(cbor.Marshal(params), rsp)
+}
+
+func TestTable(t *testing.T) {
+	require := require.New(t)
+
+	rc := &paramsRuntimeClient{params: map[string]interface{}{
+		"core.Parameters": &core.Parameters{
+			GasCosts: core.GasCosts{TxByte: 1, AuthSignature: 1000},
+		},
+		"accounts.Parameters": &accounts.Parameters{
+			GasCosts: accounts.GasCosts{TxTransfer: 100, TxManageST: 500},
+		},
+		"consensus_accounts.Parameters": &consensusaccounts.Parameters{
+			GasCosts: consensusaccounts.GasCosts{TxDeposit: 200, TxWithdraw: 300},
+		},
+	}}
+	table, err := Load(context.Background(), rc, 10)
+	require.NoError(err)
+	require.EqualValues(10, table.Round)
+
+	cost, ok := table.MethodCost("accounts.MintST")
+	require.True(ok)
+	require.EqualValues(500, cost)
+	cost, ok = table.MethodCost("consensus.Withdraw")
+	require.True(ok)
+	require.EqualValues(300, cost)
+	_, ok = table.MethodCost("evm.Call")
+	require.False(ok, "methods with dynamic costs should not be known")
+
+	tx := accounts.NewTransferTx(nil, &accounts.Transfer{
+		To:     sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+	})
+	tx.AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	gas, err := table.Estimate(tx)
+	require.NoError(err)
+	require.EqualValues(100+1000+uint64(len(cbor.Marshal(tx)))+signatureSize, gas)
+
+	tx.Call.Method = "evm.Call"
+	_, err = table.Estimate(tx)
+	require.Error(err)
+
+	upgraded := &Table{Round: 20, Costs: make(map[string]uint64)}
+	for key, cost := range table.Costs {
+		upgraded.Costs[key] = cost
+	}
+	upgraded.Costs["accounts.tx_managest"] = 600
+	delete(upgraded.Costs, "consensus_accounts.tx_deposit")
+	require.Empty(Diff(table, table))
+	require.Equal([]Change{
+		{
+			Key:     "accounts.tx_managest",
+			Old:     500,
+			New:     600,
+			Methods: []string{"accounts.BurnST", "accounts.InitOwners", "accounts.MintST", "accounts.Propose", "accounts.VoteST"},
+		},
+		{
+			Key:     "consensus_accounts.tx_deposit",
+			Old:     200,
+			Methods: []string{"consensus.Deposit"},
+		},
+	}, Diff(table, upgraded))
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/shopspring/decimal"
 
@@ -50,6 +51,28 @@ func FormatParaTimeDenomination(pt *config.ParaTime, amount types.BaseUnits) str
 	return formatDenomination(pt.GetDenominationInfo(amount.Denomination), amount.Amount)
 }
 
+// FormatParaTimeBalances formats the given ParaTime account balances, one per line. The gas
+// denomination is always listed first and marked as such, followed by the other (e.g. stable-token)
+// denominations in lexicographic order.
+func FormatParaTimeBalances(pt *config.ParaTime, balances map[types.Denomination]types.Quantity) []string {
+	gas := balances[types.GasDenomination]
+	lines := []string{
+		FormatParaTimeDenomination(pt, types.NewBaseUnits(gas, types.GasDenomination)) + " (gas)",
+	}
+
+	denoms := make([]types.Denomination, 0, len(balances))
+	for denom := range balances {
+		if denom != types.GasDenomination {
+			denoms = append(denoms, denom)
+		}
+	}
+	sort.Slice(denoms, func(i, j int) bool { return denoms[i] < denoms[j] })
+	for _, denom := range denoms {
+		lines = append(lines, FormatParaTimeDenomination(pt, types.NewBaseUnits(balances[denom], denom)))
+	}
+	return lines
+}
+
 func formatDenomination(di *config.DenominationInfo, amount types.Quantity) string {
 	return fmt.Sprintf("%s %s", prettyprint.QuantityFrac(amount, di.Decimals), di.Symbol)
 }
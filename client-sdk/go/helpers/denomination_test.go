@@ -175,3 +175,32 @@ func TestFormatParaTimeDenomination(t *testing.T) {
 		require.EqualValues(tc.expected, FormatParaTimeDenomination(&pt, amount), "%d", tc.amount)
 	}
 }
+
+func TestFormatParaTimeBalances(t *testing.T) {
+	require := require.New(t)
+
+	pt := config.ParaTime{
+		ID: "0000000000000000000000000000000000000000000000000000000000000000",
+		Denominations: map[string]*config.DenominationInfo{
+			"_": {
+				Symbol:   "HELA",
+				Decimals: 5,
+			},
+			"HLUSD": {
+				Symbol:   "HLUSD",
+				Decimals: 2,
+			},
+		},
+	}
+
+	lines := FormatParaTimeBalances(&pt, map[types.Denomination]types.Quantity{
+		types.Denomination("HLUSD"): *quantity.NewFromUint64(12_345),
+	})
+	require.Equal([]string{"0.0 HELA (gas)", "123.45 HLUSD"}, lines)
+
+	lines = FormatParaTimeBalances(&pt, map[types.Denomination]types.Quantity{
+		types.NativeDenomination:    *quantity.NewFromUint64(100_000),
+		types.Denomination("HLUSD"): *quantity.NewFromUint64(5),
+	})
+	require.Equal([]string{"1.0 HELA (gas)", "0.05 HLUSD"}, lines)
+}
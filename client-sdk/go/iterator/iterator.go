@@ -0,0 +1,186 @@
+// Package iterator implements iterating over paginated results, so that pagination loops with
+// retries and cancellation don't need to be reimplemented for each paginated query.
+//
+//	it := iterator.New(ctx, fetchPage, iterator.WithRetries(3, client.IsTransient))
+//	defer it.Close()
+//	for it.Next() {
+//		item := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+package iterator
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultMinBackoff is the delay before the first retry of a failed page fetch.
+	DefaultMinBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff is the maximum delay between retries of a failed page fetch.
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// FetchFunc fetches the page of items starting at the given offset, i.e. the number of items
+// already returned by the iterator. It returns whether more pages follow.
+type FetchFunc[T any] func(ctx context.Context, offset uint64) (items []T, more bool, err error)
+
+// Option is an iterator option.
+type Option func(*options)
+
+type options struct {
+	retries    int
+	retryable  func(error) bool
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// WithRetries retries failed page fetches up to the given number of times. In case retryable is
+// not nil, only errors for which it returns true are retried (e.g. client.IsTransient).
+func WithRetries(retries int, retryable func(error) bool) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.retryable = retryable
+	}
+}
+
+// WithBackoff configures the delay between retries, which starts at min and is doubled after each
+// failed attempt up to max.
+func WithBackoff(min, max time.Duration) Option {
+	return func(o *options) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// Iterator iterates over the items of paginated results, fetching pages as needed.
+type Iterator[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	fetch  FetchFunc[T]
+	opts   options
+
+	page   []T
+	more   bool
+	offset uint64
+	item   T
+	err    error
+	closed bool
+}
+
+// New creates a new iterator fetching pages using the given function. Fetching stops when the
+// given context is cancelled or the iterator is closed.
+func New[T any](ctx context.Context, fetch FetchFunc[T], opts ...Option) *Iterator[T] {
+	o := options{
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxBackoff < o.minBackoff {
+		o.maxBackoff = o.minBackoff
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &Iterator[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		fetch:  fetch,
+		opts:   o,
+		more:   true,
+	}
+}
+
+// Next advances to the next item and returns true iff there is one. After Next returns false, Err
+// should be checked to distinguish the end of the results from a failure.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || it.closed {
+		return false
+	}
+	for len(it.page) == 0 {
+		if !it.more {
+			return false
+		}
+		if err := it.fetchPage(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.item, it.page = it.page[0], it.page[1:]
+	it.offset++
+	return true
+}
+
+// fetchPage fetches the next page, retrying failures as configured.
+func (it *Iterator[T]) fetchPage() error {
+	backoff := it.opts.minBackoff
+	for attempt := 0; ; attempt++ {
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		items, more, err := it.fetch(it.ctx, it.offset)
+		if err == nil {
+			if len(items) == 0 && more {
+				return errors.New("iterator: empty page")
+			}
+			it.page, it.more = items, more
+			return nil
+		}
+		if attempt >= it.opts.retries || (it.opts.retryable != nil && !it.opts.retryable(err)) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-it.ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > it.opts.maxBackoff {
+			backoff = it.opts.maxBackoff
+		}
+	}
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.item
+}
+
+// Offset returns the number of items returned so far.
+func (it *Iterator[T]) Offset() uint64 {
+	return it.offset
+}
+
+// Err returns the error encountered during iteration (if any).
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and releases the resources associated with the iterator. It is safe
+// to call Close multiple times.
+func (it *Iterator[T]) Close() error {
+	it.closed = true
+	it.cancel()
+	return nil
+}
+
+// Collect collects all remaining items of the given iterator and closes it.
+func Collect[T any](it *Iterator[T]) ([]T, error) {
+	defer it.Close()
+
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,77 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+// pages serves the numbers 0 up to n in pages of the given size, failing the given number of
+// fetches first.
+type pages struct {
+	n, size  uint64
+	failures int
+	fetches  int
+}
+
+func (p *pages) fetch(ctx context.Context, offset uint64) ([]uint64, bool, error) {
+	p.fetches++
+	if p.failures > 0 {
+		p.failures--
+		return nil, false, errTransient
+	}
+	var items []uint64
+	for i := offset; i < offset+p.size && i < p.n; i++ {
+		items = append(items, i)
+	}
+	return items, offset+p.size < p.n, nil
+}
+
+func TestIterator(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	p := &pages{n: 7, size: 3}
+	items, err := Collect(New(ctx, p.fetch))
+	require.NoError(err)
+	require.Equal([]uint64{0, 1, 2, 3, 4, 5, 6}, items)
+	require.Equal(3, p.fetches)
+
+	p = &pages{n: 7, size: 3, failures: 2}
+	_, err = Collect(New(ctx, p.fetch))
+	require.ErrorIs(err, errTransient, "failures should not be retried by default")
+
+	p = &pages{n: 7, size: 3, failures: 2}
+	retryable := func(err error) bool { return errors.Is(err, errTransient) }
+	it := New(ctx, p.fetch, WithRetries(2, retryable), WithBackoff(time.Millisecond, time.Millisecond))
+	items, err = Collect(it)
+	require.NoError(err, "failures should be retried")
+	require.Len(items, 7)
+	require.EqualValues(7, it.Offset())
+
+	p = &pages{n: 7, size: 3, failures: 2}
+	it = New(ctx, p.fetch, WithRetries(2, func(error) bool { return false }))
+	_, err = Collect(it)
+	require.ErrorIs(err, errTransient, "only retryable errors should be retried")
+	require.Equal(1, p.fetches)
+
+	p = &pages{n: 7, size: 3}
+	it = New(ctx, p.fetch)
+	require.True(it.Next())
+	require.EqualValues(0, it.Value())
+	require.NoError(it.Close())
+	require.False(it.Next(), "closed iterators should stop")
+	require.NoError(it.Err())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	p = &pages{n: 7, size: 3}
+	_, err = Collect(New(cancelCtx, p.fetch))
+	require.ErrorIs(err, context.Canceled)
+	require.Zero(p.fetches)
+}
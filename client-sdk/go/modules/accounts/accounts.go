@@ -1,8 +1,10 @@
 package accounts
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 
@@ -50,14 +52,21 @@ type V1 interface {
 	// Transfer generates an accounts.Transfer transaction.
 	Transfer(to types.Address, amount types.BaseUnits) *client.TransactionBuilder
 
-	// GB: InitOwners generates an accounts.InitOwners transaction.
-	// InitOwners(addr types.Address, role types.Role) *client.TransactionBuilder
-	// InitOwners(accounts map[types.Address]types.Role) *client.TransactionBuilder
+	// InitOwners generates an accounts.InitOwners transaction assigning the given roles. The
+	// role/address pairs are ordered by address (see NewRoleAddresses).
+	InitOwners(accounts map[types.Address]types.Role) *client.TransactionBuilder
 
 	// GB: MintST generates an accounts.MintST transaction.
 	MintST(to types.Address, amount types.BaseUnits) *client.TransactionBuilder
 	BurnST(amount types.BaseUnits) *client.TransactionBuilder
 
+	// Propose generates an accounts.Propose transaction.
+	Propose(content *ProposalContent) *client.TransactionBuilder
+
+	// VoteST generates an accounts.VoteST transaction. See NewVoteBuilder for a builder which
+	// checks the proposal state first.
+	VoteST(id uint32, option types.Vote) *client.TransactionBuilder
+
 	// Parameters queries the accounts module parameters.
 	Parameters(ctx context.Context, round uint64) (*Parameters, error)
 
@@ -69,15 +78,43 @@ type V1 interface {
 	Blacklist(ctx context.Context, round uint64, address types.Address) (bool, error)
 	Quorums(ctx context.Context, round uint64, action types.Action) (uint8, error)
 	RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error)
+
+	// RolesTeamIter queries the addresses with the given role, returning an iterator that decodes
+	// them one at a time. Responses with more than maxAddresses addresses are rejected.
+	RolesTeamIter(ctx context.Context, round uint64, role types.Role, maxAddresses uint64) (*AddressIterator, error)
+
 	ProposalIDInfo(ctx context.Context, round uint64) (uint32, error)
 	ProposalInfo(ctx context.Context, round uint64, id uint32) (*ProposalOutput, error)
 
+	// ProposalInfos queries the given proposals concurrently (see ProposalQueryParallelism),
+	// failing in case any of the queries fails or any of the proposals does not exist (see
+	// ErrProposalNotFound).
+	ProposalInfos(ctx context.Context, round uint64, ids []uint32) (map[uint32]*ProposalOutput, error)
+
+	// Proposals queries the proposals matching the given filter (all if nil). All proposals are
+	// queried at the same round, which is resolved in case RoundLatest is given.
+	Proposals(ctx context.Context, round uint64, filter *ProposalFilter) (*ProposalList, error)
+
+	// WatchProposal watches the given proposal, emitting its current state followed by every
+	// change of its votes or state, and closes the returned channel once the proposal reaches a
+	// final state. The channel is also closed when the context is cancelled, the block
+	// subscription fails or the proposal can no longer be queried.
+	WatchProposal(ctx context.Context, id uint32) (<-chan *ProposalOutput, error)
+
 	// Balances queries the given account's balances.
 	Balances(ctx context.Context, round uint64, address types.Address) (*AccountBalances, error)
 
+	// CanTransact checks whether the given transfer can be made at the latest round, combining
+	// all checks a payment gateway needs before accepting an order into a single verdict.
+	CanTransact(ctx context.Context, from, to types.Address, amount types.BaseUnits) (*TransactVerdict, error)
+
 	// Addresses queries all account addresses.
 	Addresses(ctx context.Context, round uint64, denomination types.Denomination) (Addresses, error)
 
+	// AddressesIter queries all account addresses, returning an iterator that decodes them one at
+	// a time. Responses with more than maxAddresses addresses are rejected.
+	AddressesIter(ctx context.Context, round uint64, denomination types.Denomination, maxAddresses uint64) (*AddressIterator, error)
+
 	// DenominationInfo queries the information about a given denomination.
 	DenominationInfo(ctx context.Context, round uint64, denomination types.Denomination) (*DenominationInfo, error)
 
@@ -97,39 +134,39 @@ func (a *v1) Transfer(to types.Address, amount types.BaseUnits) *client.Transact
 	})
 }
 
-// GB: Implements V1 for InitOwners, this one is for mutiple accounts with roles.
-// func (a *v1) InitOwners(accounts map[types.Address]types.Role) *client.TransactionBuilder {
-// 	roleAddrs := make([]*RoleAddress, 0, len(accounts))
-// 	for addr, role := range accounts {
-// 		roleAddrs = append(roleAddrs, &RoleAddress{
-// 			Addr: addr,
-// 			Role: role,
-// 		})
-// 	}
-// 	return client.NewTransactionBuilder(a.rc, methodInitOwners, roleAddrs)
-// }
-
-// GB: Implements V1 for Propose mint/burn/blacklist etc.
-// func (a *v1) Propose(
-// 	id uint32,
-// 	submitter types.Address,
-// 	state types.ProposalState,
-// 	content *types.ProposalContent,
-// 	results map[types.Vote]uint16,
-// 	invalidVotes *uint16
-// ) *client.TransactionBuilder {
-
-// 	proposal := &Proposal{
-// 		ID:           id,
-// 		Submitter:    submitter,
-// 		State:        state,
-// 		Content:      content,
-// 		Results:      results,
-// 		InvalidVotes: invalidVotes,
-// 	}
-
-// 	return client.NewTransactionBuilder(a.rc, methodPropose, proposal)
-// }
+// Implements V1.
+func (a *v1) InitOwners(accounts map[types.Address]types.Role) *client.TransactionBuilder {
+	return client.NewTransactionBuilder(a.rc, methodInitOwners, NewRoleAddresses(accounts))
+}
+
+// NewRoleAddresses converts the given roles into the body of an accounts.InitOwners call. The
+// pairs are ordered by address, so that the same roles always result in the same transaction.
+func NewRoleAddresses(accounts map[types.Address]types.Role) []RoleAddress {
+	roleAddrs := make([]RoleAddress, 0, len(accounts))
+	for addr, role := range accounts {
+		roleAddrs = append(roleAddrs, RoleAddress{
+			Addr: addr,
+			Role: role,
+		})
+	}
+	sort.Slice(roleAddrs, func(i, j int) bool {
+		return bytes.Compare(roleAddrs[i].Addr[:], roleAddrs[j].Addr[:]) < 0
+	})
+	return roleAddrs
+}
+
+// Implements V1.
+func (a *v1) Propose(content *ProposalContent) *client.TransactionBuilder {
+	return client.NewTransactionBuilder(a.rc, methodPropose, content)
+}
+
+// Implements V1.
+func (a *v1) VoteST(id uint32, option types.Vote) *client.TransactionBuilder {
+	return client.NewTransactionBuilder(a.rc, methodVoteST, &VoteProposal{
+		ID:     id,
+		Option: option,
+	})
+}
 
 // GB: Implements V1 for MintST and BurnST
 func (a *v1) MintST(to types.Address, amount types.BaseUnits) *client.TransactionBuilder {
@@ -198,12 +235,12 @@ func (a *v1) Blacklist(ctx context.Context, round uint64, address types.Address)
 
 
 func (a *v1) RolesTeam(ctx context.Context, round uint64, role types.Role) ([]types.Address, error) {
-	var addresses []types.Address
-	err := a.rc.Query(ctx, round, methodRoleAddresses, &RoleAddressesQuery{Role: role}, &addresses)
+	limiter := client.LimiterOf(a.rc)
+	it, err := a.RolesTeamIter(ctx, round, role, maxAddresses(limiter))
 	if err != nil {
 		return nil, err
 	}
-	return addresses, nil
+	return collectAddresses(limiter, it, round, methodRoleAddresses)
 }
 
 func (a *v1) Quorums(ctx context.Context, round uint64, action types.Action) (uint8, error) {
@@ -226,6 +263,14 @@ func (a *v1) ProposalIDInfo(ctx context.Context, round uint64) (uint32, error) {
 }
 
 func (a *v1) ProposalInfo(ctx context.Context, round uint64, id uint32) (*ProposalOutput, error) {
+	if limiter := client.LimiterOf(a.rc); limiter.Limits().MaxProposalVoters > 0 {
+		data, err := a.rc.QueryRaw(ctx, round, methodProposalInfo, &id)
+		if err != nil {
+			return nil, err
+		}
+		return decodeProposalOutput(limiter, data, round)
+	}
+
 	var proposalOutput ProposalOutput
 	err := a.rc.Query(ctx, round, methodProposalInfo, &id, &proposalOutput)
 	if err != nil {
@@ -246,12 +291,12 @@ func (a *v1) Balances(ctx context.Context, round uint64, address types.Address)
 
 // Implements V1.
 func (a *v1) Addresses(ctx context.Context, round uint64, denomination types.Denomination) (Addresses, error) {
-	var addresses Addresses
-	err := a.rc.Query(ctx, round, methodAddresses, &AddressesQuery{Denomination: denomination}, &addresses)
+	limiter := client.LimiterOf(a.rc)
+	it, err := a.AddressesIter(ctx, round, denomination, maxAddresses(limiter))
 	if err != nil {
 		return nil, err
 	}
-	return addresses, nil
+	return collectAddresses(limiter, it, round, methodAddresses)
 }
 
 // Implements V1.
@@ -291,7 +336,11 @@ func (a *v1) DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
 }
 
 // DecodeEvent decodes an accounts event.
-func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
+//
+// Panics caused by malformed events are reported as a *types.DecodeError.
+func DecodeEvent(event *types.Event) (_ []client.DecodedEvent, err error) {
+	defer types.RecoverDecode(&err, "accounts event")
+
 	if event.Module != ModuleName {
 		return nil, nil
 	}
@@ -302,24 +351,24 @@ func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode account transfer event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{Transfer: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{Transfer: ev, EventMeta: event.MetaAt(i)})
 		}
 	case BurnEventCode:
 		var evs []*BurnEvent
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode account burn event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{Burn: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{Burn: ev, EventMeta: event.MetaAt(i)})
 		}
 	case MintEventCode:
 		var evs []*MintEvent
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode account mint event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{Mint: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{Mint: ev, EventMeta: event.MetaAt(i)})
 		}
 	// GBTODO: may need to insert MintSTEventCode here.
 	default:
@@ -0,0 +1,113 @@
+package accounts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestProposeBuilder(t *testing.T) {
+	require := require.New(t)
+
+	to := sdkTesting.Bob.Address
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination)
+	memo := "invoice 42"
+	meta, err := types.StringToMeta(&memo)
+	require.NoError(err, "StringToMeta")
+	role := types.MintVoter
+	quorum := uint8(60)
+
+	for _, tc := range []struct {
+		name     string
+		content  *ProposalContent
+		expected map[string]interface{}
+	}{
+		{
+			"Mint",
+			&ProposalContent{Action: types.Mint, Data: types.ProposalData{Address: &to, Amount: &amount, Meta: meta}},
+			map[string]interface{}{
+				"address": to,
+				"amount":  amount,
+				"meta":    meta[:],
+			},
+		},
+		{
+			"SetRoles",
+			&ProposalContent{Action: types.SetRoles, Data: types.ProposalData{Address: &to, Role: &role}},
+			map[string]interface{}{
+				"address": to,
+				"role":    []byte{byte(role)},
+			},
+		},
+		{
+			"Config",
+			&ProposalContent{Action: types.Config, Data: types.ProposalData{MintQuorum: &quorum}},
+			map[string]interface{}{
+				"mint_quorum": quorum,
+			},
+		},
+	} {
+		tb := NewV1(nil).Propose(tc.content)
+		tx := tb.GetTransaction()
+		require.Equal("accounts.Propose", tx.Call.Method, tc.name)
+
+		// The runtime encodes actions and roles as single-byte byte strings.
+		expected := cbor.Marshal(map[string]interface{}{
+			"action": []byte{byte(tc.content.Action)},
+			"data":   tc.expected,
+		})
+		require.EqualValues(expected, tx.Call.Body, "%s: body should match the runtime encoding", tc.name)
+
+		var decoded ProposalContent
+		require.NoError(cbor.Unmarshal(tx.Call.Body, &decoded), tc.name)
+		require.Equal(*tc.content, decoded, tc.name)
+	}
+}
+
+func TestVoteSTBuilder(t *testing.T) {
+	require := require.New(t)
+
+	tx := NewV1(nil).VoteST(7, types.VoteNo).GetTransaction()
+	require.Equal("accounts.VoteST", tx.Call.Method)
+
+	// The runtime encodes votes as unsigned integers.
+	expected := cbor.Marshal(map[string]interface{}{
+		"id":     uint32(7),
+		"option": uint8(types.VoteNo),
+	})
+	require.EqualValues(expected, tx.Call.Body, "body should match the runtime encoding")
+
+	var decoded VoteProposal
+	require.NoError(cbor.Unmarshal(tx.Call.Body, &decoded))
+	require.Equal(VoteProposal{ID: 7, Option: types.VoteNo}, decoded)
+}
+
+func TestInitOwnersBuilder(t *testing.T) {
+	require := require.New(t)
+
+	committee := sdkTesting.NewGovernanceCommittee("init owners", 3)
+	roles := committee.Roles()
+
+	body := NewV1(nil).InitOwners(roles).GetTransaction().Call.Body
+	for i := 0; i < 10; i++ {
+		require.EqualValues(body, NewV1(nil).InitOwners(roles).GetTransaction().Call.Body,
+			"the same roles should result in the same transaction")
+	}
+
+	var decoded []RoleAddress
+	require.NoError(cbor.Unmarshal(body, &decoded))
+	require.Len(decoded, len(roles))
+	for i, ra := range decoded {
+		require.Equal(roles[ra.Addr], ra.Role)
+		if i > 0 {
+			require.Negative(bytes.Compare(decoded[i-1].Addr[:], ra.Addr[:]), "pairs should be ordered by address")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// DefaultMaxAddresses is the maximum number of addresses accepted in Addresses and RolesTeam
+// responses. Use AddressesIter or RolesTeamIter to configure a different limit, or configure
+// client.Limits.MaxAddresses to truncate responses instead.
+const DefaultMaxAddresses = 1 << 20
+
+// AddressIterator iterates over the addresses in an Addresses or RoleAddresses response, decoding
+// and validating them one at a time.
+//
+//	it, err := ac.AddressesIter(ctx, round, types.NativeDenomination, 10_000)
+//	...
+//	for it.Next() {
+//		addr := it.Address()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type AddressIterator struct {
+	dec  *types.ArrayDecoder
+	addr types.Address
+	err  error
+}
+
+// NewAddressIterator creates a new iterator over the given raw Addresses or RoleAddresses
+// response. In case maxAddresses is non-zero, responses with more addresses are rejected with
+// types.ErrArrayTooLarge.
+func NewAddressIterator(data cbor.RawMessage, maxAddresses uint64) (*AddressIterator, error) {
+	dec, err := types.NewArrayDecoder(data, maxAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: malformed addresses response: %w", err)
+	}
+	return &AddressIterator{dec: dec}, nil
+}
+
+// Len returns the total number of addresses in the response.
+func (it *AddressIterator) Len() uint64 {
+	return it.dec.Len()
+}
+
+// Next decodes the next address and returns true iff there is one. After Next returns false, Err
+// should be checked to distinguish the end of the response from a malformed one.
+func (it *AddressIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	err := it.dec.Decode(&it.addr)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, io.EOF):
+	default:
+		it.err = fmt.Errorf("accounts: malformed addresses response: %w", err)
+	}
+	return false
+}
+
+// Address returns the current address.
+func (it *AddressIterator) Address() types.Address {
+	return it.addr
+}
+
+// Err returns the error encountered during iteration (if any).
+func (it *AddressIterator) Err() error {
+	return it.err
+}
+
+// collect collects all remaining addresses.
+func (it *AddressIterator) collect() ([]types.Address, error) {
+	addresses := make([]types.Address, 0, it.Len())
+	for it.Next() {
+		addresses = append(addresses, it.Address())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// Implements V1.
+func (a *v1) AddressesIter(ctx context.Context, round uint64, denomination types.Denomination, maxAddresses uint64) (*AddressIterator, error) {
+	data, err := a.rc.QueryRaw(ctx, round, methodAddresses, &AddressesQuery{Denomination: denomination})
+	if err != nil {
+		return nil, err
+	}
+	return NewAddressIterator(data, maxAddresses)
+}
+
+// Implements V1.
+func (a *v1) RolesTeamIter(ctx context.Context, round uint64, role types.Role, maxAddresses uint64) (*AddressIterator, error) {
+	data, err := a.rc.QueryRaw(ctx, round, methodRoleAddresses, &RoleAddressesQuery{Role: role})
+	if err != nil {
+		return nil, err
+	}
+	return NewAddressIterator(data, maxAddresses)
+}
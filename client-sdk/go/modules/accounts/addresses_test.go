@@ -0,0 +1,34 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestAddressIterator(t *testing.T) {
+	require := require.New(t)
+
+	addresses := Addresses{sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address}
+	it, err := NewAddressIterator(cbor.Marshal(addresses), 0)
+	require.NoError(err)
+	require.EqualValues(3, it.Len())
+	all, err := it.collect()
+	require.NoError(err)
+	require.EqualValues(addresses, all)
+
+	_, err = NewAddressIterator(cbor.Marshal(addresses), 2)
+	require.ErrorIs(err, types.ErrArrayTooLarge)
+
+	it, err = NewAddressIterator(cbor.Marshal([]interface{}{sdkTesting.Alice.Address, []byte{1, 2, 3}}), 0)
+	require.NoError(err)
+	require.True(it.Next())
+	require.Equal(sdkTesting.Alice.Address, it.Address())
+	require.False(it.Next(), "malformed addresses should stop iteration")
+	require.Error(it.Err())
+}
@@ -0,0 +1,251 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrApprovalRequired is the error returned by a TwoPersonSigner asked to sign a MintST
+// transaction above the threshold without a valid approval.
+var ErrApprovalRequired = client.NewClassifiedError(client.ErrAuth, "accounts: mint requires a second approval")
+
+// Kinds of mint audit events.
+const (
+	MintAuditApproved = "approved"
+	MintAuditSigned   = "signed"
+	MintAuditDenied   = "denied"
+)
+
+// mintApprovalBody is the message signed by the approver of a MintST transaction.
+type mintApprovalBody struct {
+	BodyHash hash.Hash `json:"body_hash"`
+}
+
+// MintApproval is the approval of a specific MintST transaction by a second person.
+type MintApproval struct {
+	// BodyHash is the hash of the approved transaction body.
+	BodyHash hash.Hash `json:"body_hash"`
+	// PublicKey is the public key of the approver.
+	PublicKey types.PublicKey `json:"public_key"`
+	// Signature is the approver's signature.
+	Signature []byte `json:"signature"`
+}
+
+// ApproveMint approves the given MintST transaction for the given chain domain separation context.
+// The approval only covers the exact transaction, including its nonce and fee.
+func ApproveMint(ctx signature.Context, approver signature.Signer, tx *types.Transaction) (*MintApproval, error) {
+	if tx.Call.Method != methodMintST {
+		return nil, fmt.Errorf("accounts: transaction is not a mint")
+	}
+	bodyHash := hash.NewFromBytes(cbor.Marshal(tx))
+	sig, err := types.SignMessage(ctx, approver, cbor.Marshal(&mintApprovalBody{BodyHash: bodyHash}))
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to sign mint approval: %w", err)
+	}
+	return &MintApproval{
+		BodyHash:  bodyHash,
+		PublicKey: types.PublicKey{PublicKey: approver.Public()},
+		Signature: sig,
+	}, nil
+}
+
+// MintAuditEvent is an audit event emitted by a TwoPersonSigner.
+type MintAuditEvent struct {
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+	// Kind is the kind of the event (one of the MintAudit constants).
+	Kind string `json:"kind"`
+	// BodyHash is the hash of the transaction body the event refers to.
+	BodyHash hash.Hash `json:"body_hash"`
+	// Mint is the body of the mint the event refers to (if known).
+	Mint *MintST `json:"mint,omitempty"`
+	// Approver is the approver of the mint (if any).
+	Approver *types.PublicKey `json:"approver,omitempty"`
+	// Reason is the reason for denying to sign.
+	Reason string `json:"reason,omitempty"`
+
+	// RequestInfo is the request metadata of the operation that caused the event (see
+	// TwoPersonSigner.WithContext).
+	client.RequestInfo
+}
+
+// TwoPersonSigner is a signer enforcing the two-person rule for MintST transactions: mints above
+// the threshold are only signed after an approval by one of the configured approvers was added.
+// All other messages are passed through to the wrapped signer, except for undecodable messages
+// under the transaction signature context, which are refused.
+type TwoPersonSigner struct {
+	signature.Signer
+
+	chainCtx   signature.Context
+	approvers  []signature.PublicKey
+	thresholds map[types.Denomination]quantity.Quantity
+	audit      func(*MintAuditEvent)
+
+	l         sync.Mutex
+	approvals map[hash.Hash]*MintApproval
+}
+
+// NewTwoPersonSigner wraps the given signer to enforce the two-person rule for mints.
+//
+// Mints of more than the threshold of their denomination require an approval while mints of
+// denominations without a threshold always require one. All approvals and signing decisions are
+// reported to the audit function (if non-nil).
+func NewTwoPersonSigner(
+	signer signature.Signer,
+	chainCtx signature.Context,
+	approvers []signature.PublicKey,
+	thresholds map[types.Denomination]quantity.Quantity,
+	audit func(*MintAuditEvent),
+) (*TwoPersonSigner, error) {
+	if len(approvers) == 0 {
+		return nil, fmt.Errorf("accounts: no mint approvers configured")
+	}
+	for _, pk := range approvers {
+		if pk.Equal(signer.Public()) {
+			return nil, fmt.Errorf("accounts: signer can't approve its own mints")
+		}
+	}
+	return &TwoPersonSigner{
+		Signer:     signer,
+		chainCtx:   chainCtx,
+		approvers:  approvers,
+		thresholds: thresholds,
+		audit:      audit,
+		approvals:  make(map[hash.Hash]*MintApproval),
+	}, nil
+}
+
+func (s *TwoPersonSigner) emit(info client.RequestInfo, ev *MintAuditEvent) {
+	if s.audit == nil {
+		return
+	}
+	ev.Time = time.Now()
+	ev.RequestInfo = info
+	s.audit(ev)
+}
+
+// WithContext returns a signer enforcing the two-person rule with the same configuration and
+// approvals as s, which tags its audit events with the request metadata set in the given context
+// (see client.WithRequestID and client.WithCaller), e.g.:
+//
+//	err := tb.AppendSign(ctx, signer.WithContext(ctx))
+func (s *TwoPersonSigner) WithContext(ctx context.Context) *TwoPersonRequestSigner {
+	return &TwoPersonRequestSigner{TwoPersonSigner: s, info: client.RequestInfoFromContext(ctx)}
+}
+
+// AddApproval verifies the given approval and makes it available for signing the approved mint.
+//
+// The approver is identified by the configured approver key the signature verifies with, the
+// public key included in the approval is not trusted.
+func (s *TwoPersonSigner) AddApproval(approval *MintApproval) error {
+	return s.addApproval(client.RequestInfo{}, approval)
+}
+
+func (s *TwoPersonSigner) addApproval(info client.RequestInfo, approval *MintApproval) error {
+	msg := cbor.Marshal(&mintApprovalBody{BodyHash: approval.BodyHash})
+	var verified *MintApproval
+	for _, pk := range s.approvers {
+		if types.VerifyMessage(s.chainCtx, pk, msg, approval.Signature) == nil {
+			verified = &MintApproval{
+				BodyHash:  approval.BodyHash,
+				PublicKey: types.PublicKey{PublicKey: pk},
+				Signature: approval.Signature,
+			}
+			break
+		}
+	}
+	if verified == nil {
+		return fmt.Errorf("accounts: mint approval not signed by a configured approver")
+	}
+
+	s.l.Lock()
+	s.approvals[verified.BodyHash] = verified
+	s.l.Unlock()
+
+	s.emit(info, &MintAuditEvent{
+		Kind:     MintAuditApproved,
+		BodyHash: verified.BodyHash,
+		Approver: &verified.PublicKey,
+	})
+	return nil
+}
+
+// requiresApproval checks whether the given mint requires an approval.
+func (s *TwoPersonSigner) requiresApproval(mint *MintST) bool {
+	threshold, ok := s.thresholds[mint.Amount.Denomination]
+	return !ok || mint.Amount.Amount.Cmp(&threshold) > 0
+}
+
+// ContextSign implements signature.Signer.
+//
+// Approvals are single-use, so signing the same mint again requires a new approval.
+func (s *TwoPersonSigner) ContextSign(context, message []byte) ([]byte, error) {
+	return s.contextSign(client.RequestInfo{}, context, message)
+}
+
+func (s *TwoPersonSigner) contextSign(info client.RequestInfo, context, message []byte) ([]byte, error) {
+	if !bytes.HasPrefix(context, types.SignatureContextBase) {
+		return s.Signer.ContextSign(context, message)
+	}
+	bodyHash := hash.NewFromBytes(message)
+	var tx types.Transaction
+	if err := cbor.Unmarshal(message, &tx); err != nil {
+		// Refuse to sign what can't be checked, it may still be accepted as a mint.
+		s.emit(info, &MintAuditEvent{Kind: MintAuditDenied, BodyHash: bodyHash, Reason: "malformed transaction"})
+		return nil, fmt.Errorf("accounts: malformed transaction: %w", err)
+	}
+	if tx.Call.Method != methodMintST {
+		return s.Signer.ContextSign(context, message)
+	}
+
+	var mint MintST
+	if err := cbor.Unmarshal(tx.Call.Body, &mint); err != nil {
+		s.emit(info, &MintAuditEvent{Kind: MintAuditDenied, BodyHash: bodyHash, Reason: "malformed mint"})
+		return nil, fmt.Errorf("accounts: malformed mint: %w", err)
+	}
+	if !s.requiresApproval(&mint) {
+		s.emit(info, &MintAuditEvent{Kind: MintAuditSigned, BodyHash: bodyHash, Mint: &mint})
+		return s.Signer.ContextSign(context, message)
+	}
+
+	s.l.Lock()
+	approval, ok := s.approvals[bodyHash]
+	delete(s.approvals, bodyHash)
+	s.l.Unlock()
+	if !ok {
+		s.emit(info, &MintAuditEvent{Kind: MintAuditDenied, BodyHash: bodyHash, Mint: &mint, Reason: "missing approval"})
+		return nil, ErrApprovalRequired
+	}
+
+	s.emit(info, &MintAuditEvent{Kind: MintAuditSigned, BodyHash: bodyHash, Mint: &mint, Approver: &approval.PublicKey})
+	return s.Signer.ContextSign(context, message)
+}
+
+// TwoPersonRequestSigner is a TwoPersonSigner tagging its audit events with the request metadata
+// of an operation (see TwoPersonSigner.WithContext).
+type TwoPersonRequestSigner struct {
+	*TwoPersonSigner
+
+	info client.RequestInfo
+}
+
+// AddApproval is TwoPersonSigner.AddApproval, tagging the audit event with the request metadata.
+func (r *TwoPersonRequestSigner) AddApproval(approval *MintApproval) error {
+	return r.addApproval(r.info, approval)
+}
+
+// ContextSign implements signature.Signer, tagging the audit events with the request metadata.
+func (r *TwoPersonRequestSigner) ContextSign(context, message []byte) ([]byte, error) {
+	return r.contextSign(r.info, context, message)
+}
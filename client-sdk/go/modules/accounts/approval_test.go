@@ -0,0 +1,101 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestTwoPersonSigner(t *testing.T) {
+	require := require.New(t)
+
+	chainCtx := signature.Context("test chain")
+	approvers := []signature.PublicKey{sdkTesting.Bob.Signer.Public()}
+	thresholds := map[types.Denomination]quantity.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(1000),
+	}
+
+	_, err := NewTwoPersonSigner(sdkTesting.Alice.Signer, chainCtx, nil, thresholds, nil)
+	require.Error(err, "signers without approvers should be rejected")
+	_, err = NewTwoPersonSigner(sdkTesting.Alice.Signer, chainCtx, []signature.PublicKey{sdkTesting.Alice.Signer.Public()}, thresholds, nil)
+	require.Error(err, "signers approving their own mints should be rejected")
+
+	var events []*MintAuditEvent
+	signer, err := NewTwoPersonSigner(sdkTesting.Alice.Signer, chainCtx, approvers, thresholds, func(ev *MintAuditEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(err, "NewTwoPersonSigner")
+
+	mint := func(amount uint64, nonce uint64) *types.Transaction {
+		tx := NewMintSTTx(nil, &MintST{
+			To:     sdkTesting.Charlie.Address,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination),
+		})
+		tx.AppendAuthSignature(sdkTesting.Alice.SigSpec, nonce)
+		return tx
+	}
+	sign := func(tx *types.Transaction) error {
+		return tx.PrepareForSigning().AppendSign(chainCtx, signer)
+	}
+
+	require.NoError(sign(mint(1000, 0)), "mints up to the threshold should be signed")
+	require.Len(events, 1)
+	require.Equal(MintAuditSigned, events[0].Kind)
+
+	large := mint(1001, 1)
+	require.ErrorIs(sign(large), ErrApprovalRequired, "mints above the threshold should require an approval")
+	require.Equal(MintAuditDenied, events[1].Kind)
+
+	selfApproval, err := ApproveMint(chainCtx, sdkTesting.Alice.Signer, large)
+	require.NoError(err, "ApproveMint")
+	require.Error(signer.AddApproval(selfApproval), "approvals by the signer should be rejected")
+	otherChain, err := ApproveMint(signature.Context("other chain"), sdkTesting.Bob.Signer, large)
+	require.NoError(err, "ApproveMint")
+	require.Error(signer.AddApproval(otherChain), "approvals for other chains should be rejected")
+
+	approval, err := ApproveMint(chainCtx, sdkTesting.Bob.Signer, large)
+	require.NoError(err, "ApproveMint")
+	approval.PublicKey = types.PublicKey{PublicKey: sdkTesting.Dave.Signer.Public()}
+	require.NoError(signer.AddApproval(approval), "AddApproval")
+	require.Equal(MintAuditApproved, events[2].Kind)
+	require.True(events[2].Approver.Equal(sdkTesting.Bob.Signer.Public()), "only the verified approver key should be recorded")
+
+	require.ErrorIs(sign(mint(1001, 2)), ErrApprovalRequired, "approvals should only cover the approved transaction")
+	require.NoError(sign(large), "approved mints should be signed")
+	last := events[len(events)-1]
+	require.Equal(MintAuditSigned, last.Kind)
+	require.NotNil(last.Approver)
+	require.ErrorIs(sign(large), ErrApprovalRequired, "approvals should be single-use")
+
+	_, err = ApproveMint(chainCtx, sdkTesting.Bob.Signer, types.NewTransaction(nil, methodTransfer, nil))
+	require.Error(err, "only mints should be approved")
+	transfer := types.NewTransaction(nil, methodTransfer, &Transfer{To: sdkTesting.Bob.Address})
+	transfer.AppendAuthSignature(sdkTesting.Alice.SigSpec, 3)
+	require.NoError(sign(transfer), "other transactions should be passed through")
+
+	_, err = signer.ContextSign(chainCtx.New(types.SignatureContextBase), []byte("not a transaction"))
+	require.Error(err, "malformed transactions should not be signed")
+	require.Equal(MintAuditDenied, events[len(events)-1].Kind)
+	_, err = signer.ContextSign(chainCtx.New(types.MessageSignatureContextBase), []byte("not a transaction"))
+	require.NoError(err, "other messages should be passed through")
+	require.Empty(events[len(events)-1].RequestID)
+
+	// Audit events should carry the request metadata of the operation.
+	reqSigner := signer.WithContext(client.WithCaller(client.WithRequestID(context.Background(), "req-1"), "tenant-a"))
+	approval, err = ApproveMint(chainCtx, sdkTesting.Bob.Signer, large)
+	require.NoError(err, "ApproveMint")
+	require.NoError(reqSigner.AddApproval(approval))
+	require.Equal(client.RequestInfo{RequestID: "req-1", Caller: "tenant-a"}, events[len(events)-1].RequestInfo)
+	require.NoError(large.PrepareForSigning().AppendSign(chainCtx, reqSigner), "approvals should be shared")
+	last = events[len(events)-1]
+	require.Equal(MintAuditSigned, last.Kind)
+	require.Equal("req-1", last.RequestID)
+}
@@ -0,0 +1,86 @@
+package accounts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// DefaultCacheTTL is the default time for which CachedV1 memoizes values.
+const DefaultCacheTTL = 10 * time.Minute
+
+// CachedV1 is a V1 client helper memoizing the module parameters and denomination information,
+// which rarely change but are needed in hot paths (e.g. for gas estimation and amount formatting).
+//
+// Only queries at client.RoundLatest are memoized, queries at other rounds are passed through.
+// The returned values are shared between callers and must not be modified.
+//
+// The accounts module emits no events when these values change (they only change with runtime
+// upgrades), so they are kept for a limited time. Callers that learn about a change earlier, e.g.
+// by watching for runtime upgrades, should call Invalidate.
+type CachedV1 struct {
+	V1
+
+	ttl    time.Duration
+	params *client.Memo[*Parameters]
+
+	l             sync.Mutex
+	denominations map[types.Denomination]*client.Memo[*DenominationInfo]
+}
+
+// NewCachedV1 generates a V1 client helper for the accounts module which memoizes values for the
+// given time (DefaultCacheTTL if zero).
+func NewCachedV1(rc client.RuntimeClient, ttl time.Duration) *CachedV1 {
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachedV1{
+		V1:            NewV1(rc),
+		ttl:           ttl,
+		params:        client.NewMemo[*Parameters](ttl),
+		denominations: make(map[types.Denomination]*client.Memo[*DenominationInfo]),
+	}
+}
+
+// Implements V1.
+func (a *CachedV1) Parameters(ctx context.Context, round uint64) (*Parameters, error) {
+	if round != client.RoundLatest {
+		return a.V1.Parameters(ctx, round)
+	}
+	return a.params.Get(ctx, func(ctx context.Context) (*Parameters, error) {
+		return a.V1.Parameters(ctx, round)
+	})
+}
+
+// Implements V1.
+func (a *CachedV1) DenominationInfo(ctx context.Context, round uint64, denomination types.Denomination) (*DenominationInfo, error) {
+	if round != client.RoundLatest {
+		return a.V1.DenominationInfo(ctx, round, denomination)
+	}
+
+	a.l.Lock()
+	memo, ok := a.denominations[denomination]
+	if !ok {
+		memo = client.NewMemo[*DenominationInfo](a.ttl)
+		a.denominations[denomination] = memo
+	}
+	a.l.Unlock()
+
+	return memo.Get(ctx, func(ctx context.Context) (*DenominationInfo, error) {
+		return a.V1.DenominationInfo(ctx, round, denomination)
+	})
+}
+
+// Invalidate drops all memoized values.
+func (a *CachedV1) Invalidate() {
+	a.params.Invalidate()
+
+	a.l.Lock()
+	defer a.l.Unlock()
+	for _, memo := range a.denominations {
+		memo.Invalidate()
+	}
+}
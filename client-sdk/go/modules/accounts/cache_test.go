@@ -0,0 +1,65 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// countingRuntimeClient counts the parameter and denomination queries.
+type countingRuntimeClient struct {
+	client.RuntimeClient
+
+	queries map[string]int
+}
+
+func (c *countingRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	c.queries[method]++
+	var result interface{}
+	switch method {
+	case methodParameters:
+		result = Parameters{GasCosts: GasCosts{TxTransfer: uint64(c.queries[method])}}
+	case methodDenominationInfo:
+		result = DenominationInfo{Decimals: 18}
+	}
+	return cbor.Unmarshal(cbor.Marshal(result), rsp)
+}
+
+func TestCachedV1(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &countingRuntimeClient{queries: make(map[string]int)}
+	a := NewCachedV1(rc, 0)
+
+	for i := 0; i < 3; i++ {
+		params, err := a.Parameters(ctx, client.RoundLatest)
+		require.NoError(err, "Parameters")
+		require.EqualValues(1, params.GasCosts.TxTransfer)
+		info, err := a.DenominationInfo(ctx, client.RoundLatest, types.NativeDenomination)
+		require.NoError(err, "DenominationInfo")
+		require.EqualValues(18, info.Decimals)
+	}
+	_, err := a.DenominationInfo(ctx, client.RoundLatest, types.Denomination("TEST"))
+	require.NoError(err, "DenominationInfo")
+	require.Equal(1, rc.queries[methodParameters], "parameters should be memoized")
+	require.Equal(2, rc.queries[methodDenominationInfo], "denomination infos should be memoized per denomination")
+
+	_, err = a.Parameters(ctx, 10)
+	require.NoError(err, "Parameters")
+	require.Equal(2, rc.queries[methodParameters], "queries at specific rounds should not be memoized")
+
+	a.Invalidate()
+	params, err := a.Parameters(ctx, client.RoundLatest)
+	require.NoError(err, "Parameters")
+	require.EqualValues(3, params.GasCosts.TxTransfer, "invalidation should drop memoized values")
+	_, err = a.DenominationInfo(ctx, client.RoundLatest, types.NativeDenomination)
+	require.NoError(err, "DenominationInfo")
+	require.Equal(3, rc.queries[methodDenominationInfo])
+}
@@ -0,0 +1,107 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Digest is a summary of the governance actions relevant to a given address, suitable for
+// periodic (e.g. daily) notifications.
+type Digest struct {
+	// Address is the address the digest was compiled for.
+	Address types.Address `json:"address"`
+	// Role is the role of the address.
+	Role types.Role `json:"role"`
+	// Round is the round at which the digest was compiled.
+	Round uint64 `json:"round"`
+	// LatestID is the identifier of the latest proposal. It should be passed as sinceID when
+	// compiling the next digest.
+	LatestID uint32 `json:"latest_id"`
+
+	// AwaitingVote are the active proposals the address may vote on but has not voted on yet.
+	AwaitingVote []*ProposalOutput `json:"awaiting_vote,omitempty"`
+	// Submitted are the active proposals submitted by the address.
+	//
+	// Note that the runtime does not track proposal expiry, so these are all pending proposals
+	// rather than the ones about to expire.
+	Submitted []*ProposalOutput `json:"submitted,omitempty"`
+	// Executed are the proposals created after sinceID that have passed and affect the address.
+	Executed []*ProposalOutput `json:"executed,omitempty"`
+}
+
+// IsEmpty returns true iff the digest contains no proposals.
+func (d *Digest) IsEmpty() bool {
+	return len(d.AwaitingVote) == 0 && len(d.Submitted) == 0 && len(d.Executed) == 0
+}
+
+// String returns a plain text rendering of the digest.
+func (d *Digest) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Governance digest for %s (%s) at round %d\n", d.Address, d.Role, d.Round)
+	section := func(title string, proposals []*ProposalOutput) {
+		if len(proposals) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%s:\n", title)
+		for _, p := range proposals {
+			fmt.Fprintf(&sb, "  - #%d %s (%s)\n", p.ID, p.Content.Action, p.State)
+		}
+	}
+	section("Awaiting your vote", d.AwaitingVote)
+	section("Your pending proposals", d.Submitted)
+	section("Executed proposals affecting you", d.Executed)
+	if d.IsEmpty() {
+		sb.WriteString("\nNo actions required.\n")
+	}
+	return sb.String()
+}
+
+// BuildDigest compiles the governance digest for the given address at the given round. Only
+// proposals with identifiers greater than sinceID are considered for Executed.
+func BuildDigest(ctx context.Context, a V1, round uint64, address types.Address, sinceID uint32) (*Digest, error) {
+	role, err := a.Role(ctx, round, address)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query role: %w", err)
+	}
+	latestID, err := a.ProposalIDInfo(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query latest proposal ID: %w", err)
+	}
+
+	digest := Digest{
+		Address:  address,
+		Role:     role,
+		Round:    round,
+		LatestID: latestID,
+	}
+	it := ProposalsIter(ctx, a, round, 1, latestID)
+	defer it.Close()
+	for it.Next() {
+		digest.add(it.Value(), sinceID)
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+func (d *Digest) add(p *ProposalOutput, sinceID uint32) {
+	switch p.State {
+	case types.Active:
+		if p.Submitter.Equal(d.Address) {
+			d.Submitted = append(d.Submitted, p)
+		}
+		if role, ok := p.Content.Action.VoterRole(); ok && role == d.Role {
+			if _, voted := p.VoteOption[d.Address]; !voted {
+				d.AwaitingVote = append(d.AwaitingVote, p)
+			}
+		}
+	case types.Passed:
+		if p.ID > sinceID && p.Content.Data.Address != nil && p.Content.Data.Address.Equal(d.Address) {
+			d.Executed = append(d.Executed, p)
+		}
+	}
+}
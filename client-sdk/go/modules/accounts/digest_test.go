@@ -0,0 +1,53 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestDigest(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob := sdkTesting.Alice.Address, sdkTesting.Bob.Address
+	d := Digest{Address: alice, Role: types.MintVoter}
+	require.True(d.IsEmpty())
+	require.Contains(d.String(), "No actions required")
+
+	proposal := func(id uint32, submitter types.Address, state types.ProposalState, action types.Action, target types.Address) *ProposalOutput {
+		return &ProposalOutput{
+			ID:        id,
+			Submitter: submitter,
+			State:     state,
+			Content:   ProposalContent{Action: action, Data: types.ProposalData{Address: &target}},
+		}
+	}
+
+	voted := proposal(2, bob, types.Active, types.Mint, bob)
+	voted.VoteOption = map[types.Address]types.Vote{alice: types.VoteYes}
+	for _, p := range []*ProposalOutput{
+		proposal(1, bob, types.Active, types.Mint, bob),
+		voted,
+		proposal(3, bob, types.Active, types.Burn, bob),
+		proposal(4, alice, types.Active, types.Config, bob),
+		proposal(5, bob, types.Passed, types.Mint, alice),
+		proposal(6, bob, types.Passed, types.Mint, alice),
+	} {
+		d.add(p, 5)
+	}
+
+	ids := func(ps []*ProposalOutput) []uint32 {
+		var ids []uint32
+		for _, p := range ps {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	require.EqualValues([]uint32{1}, ids(d.AwaitingVote))
+	require.EqualValues([]uint32{4}, ids(d.Submitted))
+	require.EqualValues([]uint32{6}, ids(d.Executed))
+	require.Contains(d.String(), "#1 Mint (Active)")
+}
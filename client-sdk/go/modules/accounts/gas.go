@@ -0,0 +1,84 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// GasWarning is a warning that an account lacks the funds to pay the fees of its queued
+// transactions.
+type GasWarning struct {
+	// Address is the address of the account.
+	Address types.Address `json:"address"`
+	// Shortfall are the missing amounts, per fee denomination.
+	Shortfall map[types.Denomination]types.Quantity `json:"shortfall"`
+}
+
+// String returns a string representation of the warning.
+func (w *GasWarning) String() string {
+	denoms := make([]types.Denomination, 0, len(w.Shortfall))
+	for denom := range w.Shortfall {
+		denoms = append(denoms, denom)
+	}
+	sort.Slice(denoms, func(i, j int) bool { return denoms[i] < denoms[j] })
+
+	missing := make([]string, 0, len(denoms))
+	for _, denom := range denoms {
+		missing = append(missing, types.NewBaseUnits(w.Shortfall[denom], denom).String())
+	}
+	return fmt.Sprintf("account %s lacks funds to pay for gas of queued transactions (missing: %s)", w.Address, strings.Join(missing, ", "))
+}
+
+// GasShortfall computes the amounts by which the given balances fall short of covering the fees
+// of the given transactions, per fee denomination. Returns nil when the balances are sufficient.
+//
+// Only fees are taken into account, not any amounts the transactions themselves transfer.
+func GasShortfall(balances map[types.Denomination]types.Quantity, queued []*types.Transaction) map[types.Denomination]types.Quantity {
+	required := make(map[types.Denomination]*types.Quantity)
+	for _, tx := range queued {
+		fee := tx.AuthInfo.Fee.Amount
+		if fee.Amount.IsZero() {
+			continue
+		}
+		if total, ok := required[fee.Denomination]; ok {
+			_ = total.Add(&fee.Amount)
+		} else {
+			required[fee.Denomination] = fee.Amount.Clone()
+		}
+	}
+
+	var shortfall map[types.Denomination]types.Quantity
+	for denom, total := range required {
+		balance := balances[denom]
+		if total.Cmp(&balance) <= 0 {
+			continue
+		}
+		_ = total.Sub(&balance)
+		if shortfall == nil {
+			shortfall = make(map[types.Denomination]types.Quantity)
+		}
+		shortfall[denom] = *total
+	}
+	return shortfall
+}
+
+// CheckGasFunds checks whether the given account has enough funds at the given round to pay the
+// fees of its queued transactions. Returns a non-nil warning in case it does not.
+func CheckGasFunds(ctx context.Context, a V1, round uint64, address types.Address, queued []*types.Transaction) (*GasWarning, error) {
+	balances, err := a.Balances(ctx, round, address)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query balances: %w", err)
+	}
+	shortfall := GasShortfall(balances.Balances, queued)
+	if shortfall == nil {
+		return nil, nil
+	}
+	return &GasWarning{
+		Address:   address,
+		Shortfall: shortfall,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestGasShortfall(t *testing.T) {
+	require := require.New(t)
+
+	stable := types.Denomination("HLUSD")
+	txWithFee := func(amount uint64, denom types.Denomination) *types.Transaction {
+		return types.NewTransaction(&types.Fee{
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), denom),
+			Gas:    1000,
+		}, "accounts.Transfer", nil)
+	}
+	queued := []*types.Transaction{
+		txWithFee(100, types.GasDenomination),
+		txWithFee(50, types.GasDenomination),
+		txWithFee(0, types.GasDenomination),
+		txWithFee(10, stable),
+	}
+
+	balances := map[types.Denomination]types.Quantity{
+		types.GasDenomination: *quantity.NewFromUint64(150),
+		stable:                *quantity.NewFromUint64(10),
+	}
+	require.Nil(GasShortfall(balances, queued), "exact balances should be sufficient")
+
+	balances = map[types.Denomination]types.Quantity{
+		types.GasDenomination: *quantity.NewFromUint64(120),
+	}
+	shortfall := GasShortfall(balances, queued)
+	require.Len(shortfall, 2)
+	require.EqualValues(*quantity.NewFromUint64(30), shortfall[types.GasDenomination])
+	require.EqualValues(*quantity.NewFromUint64(10), shortfall[stable])
+
+	w := GasWarning{Shortfall: shortfall}
+	require.Contains(w.String(), "30 <native>, 10 HLUSD")
+}
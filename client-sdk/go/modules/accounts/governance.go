@@ -0,0 +1,233 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// GovernanceEventKind is the kind of a governance event.
+type GovernanceEventKind string
+
+const (
+	// GovernanceProposal is the submission of a proposal.
+	GovernanceProposal GovernanceEventKind = "proposal"
+	// GovernanceVote is a vote on a proposal.
+	GovernanceVote GovernanceEventKind = "vote"
+)
+
+// GovernanceEvent is a proposal submission or vote.
+//
+// The accounts module does not emit events for proposals and votes, so governance events are
+// derived from the successful Propose and VoteST transactions of each block.
+type GovernanceEvent struct {
+	// Kind is the kind of the event.
+	Kind GovernanceEventKind `json:"kind"`
+	// Round is the round of the block containing the transaction.
+	Round uint64 `json:"round"`
+	// TxIndex is the index of the transaction in the block.
+	TxIndex int `json:"tx_index"`
+	// TxHash is the hash of the transaction.
+	TxHash hash.Hash `json:"tx_hash"`
+	// Signer is the address of the (first) signer of the transaction, i.e. the submitter of the
+	// proposal or the voter.
+	Signer types.Address `json:"signer"`
+	// ProposalID is the identifier of the submitted or voted on proposal.
+	ProposalID uint32 `json:"proposal_id"`
+	// Proposal is the content of the submitted proposal (proposal events only).
+	Proposal *ProposalContent `json:"proposal,omitempty"`
+	// Vote is the vote option (vote events only).
+	Vote *types.Vote `json:"vote,omitempty"`
+}
+
+// WatchGovernance watches the chain for proposal submissions and votes.
+//
+// Unlike client.RuntimeClient.WatchEvents, which fetches and decodes all events of every block,
+// this only fetches the transactions of each block, so that governance bots receive proposals and
+// votes with low latency even in busy periods. To also isolate the watch from other traffic, pass
+// a runtime client using a dedicated connection (e.g. a separate connection.Connect).
+//
+// The returned channel is closed when the context is cancelled or the block subscription fails.
+func WatchGovernance(ctx context.Context, rc client.RuntimeClient) (<-chan *GovernanceEvent, error) {
+	blkCh, blkSub, err := rc.WatchBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a := NewV1(rc)
+	ch := make(chan *GovernanceEvent)
+
+	go func() {
+		defer blkSub.Close()
+		defer close(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case blk, ok := <-blkCh:
+				if !ok {
+					return
+				}
+
+				evs, err := GovernanceEvents(ctx, rc, a, blk.Block.Header.Round)
+				if err != nil {
+					return
+				}
+				for _, ev := range evs {
+					select {
+					case ch <- ev:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// GovernanceEvents returns the governance events of the given round in transaction order.
+func GovernanceEvents(ctx context.Context, rc client.RuntimeClient, a V1, round uint64) ([]*GovernanceEvent, error) {
+	txs, err := rc.GetTransactionsWithResults(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to fetch transactions of round %d: %w", round, err)
+	}
+
+	var (
+		evs       []*GovernanceEvent
+		proposals []*GovernanceEvent
+	)
+	for i, txr := range txs {
+		if !txr.Result.IsSuccess() || txr.Result.IsUnknown() {
+			continue
+		}
+		var tx types.Transaction
+		if err = cbor.Unmarshal(txr.Tx.Body, &tx); err != nil {
+			// Skip malformed transactions, they can't be governance transactions either.
+			continue
+		}
+		if tx.Call.Format != types.CallFormatPlain || len(tx.AuthInfo.SignerInfo) == 0 {
+			continue
+		}
+		signer, err := tx.AuthInfo.SignerInfo[0].AddressSpec.Address()
+		if err != nil {
+			continue
+		}
+
+		ev := GovernanceEvent{
+			Round:   round,
+			TxIndex: i,
+			TxHash:  txr.Tx.Hash(),
+			Signer:  signer,
+		}
+		switch tx.Call.Method {
+		case methodPropose:
+			var body ProposalContent
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				return nil, fmt.Errorf("accounts: malformed proposal in round %d: %w", round, err)
+			}
+			ev.Kind = GovernanceProposal
+			ev.Proposal = &body
+			proposals = append(proposals, &ev)
+		case methodVoteST:
+			var body VoteProposal
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				return nil, fmt.Errorf("accounts: malformed vote in round %d: %w", round, err)
+			}
+			ev.Kind = GovernanceVote
+			ev.ProposalID = body.ID
+			ev.Vote = &body.Option
+		default:
+			continue
+		}
+		evs = append(evs, &ev)
+	}
+
+	if len(proposals) > 0 {
+		// Proposals are numbered sequentially in transaction order, so the identifiers follow from
+		// the latest identifier at the end of the round.
+		latestID, err := a.ProposalIDInfo(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("accounts: failed to query latest proposal ID: %w", err)
+		}
+		firstID := latestID - uint32(len(proposals)) + 1
+		for i, ev := range proposals {
+			ev.ProposalID = firstID + uint32(i)
+		}
+	}
+	return evs, nil
+}
+
+// WatchProposal implements V1.
+func (a *v1) WatchProposal(ctx context.Context, id uint32) (<-chan *ProposalOutput, error) {
+	blkCh, blkSub, err := a.rc.WatchBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Query the current state upfront, so that e.g. unknown proposals are reported to the caller.
+	round, err := a.resolveRound(ctx, client.RoundLatest)
+	if err != nil {
+		blkSub.Close()
+		return nil, err
+	}
+	p, err := a.ProposalInfo(ctx, round, id)
+	if err == nil && p.ID != id {
+		// The runtime returns an empty proposal for unknown identifiers.
+		err = ErrProposalNotFound
+	}
+	if err != nil {
+		blkSub.Close()
+		return nil, fmt.Errorf("accounts: failed to query proposal %d: %w", id, err)
+	}
+	ch := make(chan *ProposalOutput)
+
+	go func() {
+		defer blkSub.Close()
+		defer close(ch)
+
+		var last *ProposalOutput
+		for {
+			if last == nil || !reflect.DeepEqual(last, p) {
+				select {
+				case ch <- p:
+				case <-ctx.Done():
+					return
+				}
+				last = p
+			}
+			if p.State != types.Active {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case blk, ok := <-blkCh:
+				if !ok {
+					return
+				}
+				if blk.Block.Header.Round <= round {
+					continue
+				}
+				next, err := a.ProposalInfo(ctx, blk.Block.Header.Round, id)
+				switch {
+				case err == nil:
+					round, p = blk.Block.Header.Round, next
+				case client.IsTransient(err):
+					// Try again with the next block.
+				default:
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,155 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type governanceRuntimeClient struct {
+	client.RuntimeClient
+
+	txs []*client.TransactionWithResults
+}
+
+func (g *governanceRuntimeClient) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*client.TransactionWithResults, error) {
+	return g.txs, nil
+}
+
+type proposalIDAccounts struct {
+	V1
+
+	latestID uint32
+}
+
+func (p *proposalIDAccounts) ProposalIDInfo(ctx context.Context, round uint64) (uint32, error) {
+	return p.latestID, nil
+}
+
+func TestGovernanceEvents(t *testing.T) {
+	require := require.New(t)
+
+	txResult := func(signer sdkTesting.TestKey, method string, body interface{}, ok bool) *client.TransactionWithResults {
+		tx := types.NewTransaction(nil, method, body)
+		tx.AppendAuthSignature(signer.SigSpec, 0)
+		txr := &client.TransactionWithResults{Tx: types.UnverifiedTransaction{Body: cbor.Marshal(tx)}}
+		if !ok {
+			txr.Result.Failed = &types.FailedCallResult{Module: ModuleName, Code: 1}
+		}
+		return txr
+	}
+	target := sdkTesting.Charlie.Address
+	proposal := &ProposalContent{Action: types.Mint, Data: types.ProposalData{Address: &target}}
+	vote := &VoteProposal{ID: 7, Option: types.VoteNo}
+
+	rc := &governanceRuntimeClient{txs: []*client.TransactionWithResults{
+		txResult(sdkTesting.Alice, methodPropose, proposal, true),
+		txResult(sdkTesting.Alice, methodTransfer, &Transfer{To: target}, true),
+		txResult(sdkTesting.Bob, methodVoteST, vote, true),
+		txResult(sdkTesting.Bob, methodPropose, proposal, false),
+		txResult(sdkTesting.Bob, methodPropose, proposal, true),
+	}}
+	evs, err := GovernanceEvents(context.Background(), rc, &proposalIDAccounts{latestID: 10}, 42)
+	require.NoError(err)
+	require.Len(evs, 3)
+
+	require.Equal(GovernanceProposal, evs[0].Kind)
+	require.EqualValues(42, evs[0].Round)
+	require.Equal(0, evs[0].TxIndex)
+	require.Equal(sdkTesting.Alice.Address, evs[0].Signer)
+	require.EqualValues(9, evs[0].ProposalID, "proposal identifiers should be assigned in order")
+	require.Equal(proposal, evs[0].Proposal)
+
+	require.Equal(GovernanceVote, evs[1].Kind)
+	require.Equal(2, evs[1].TxIndex)
+	require.Equal(sdkTesting.Bob.Address, evs[1].Signer)
+	require.EqualValues(7, evs[1].ProposalID)
+	require.Equal(types.VoteNo, *evs[1].Vote)
+
+	require.Equal(GovernanceProposal, evs[2].Kind)
+	require.Equal(4, evs[2].TxIndex, "failed transactions should be skipped")
+	require.EqualValues(10, evs[2].ProposalID)
+}
+
+// watchRuntimeClient serves the given proposal states (by round) and a block subscription with all
+// rounds after the latest one.
+type watchRuntimeClient struct {
+	client.RuntimeClient
+
+	latest uint64
+	states map[uint64]*ProposalOutput
+}
+
+func (w *watchRuntimeClient) WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ch := make(chan *roothash.AnnotatedBlock, len(w.states))
+	for round := w.latest; ; round++ {
+		if _, ok := w.states[round]; !ok {
+			break
+		}
+		ch <- &roothash.AnnotatedBlock{Block: &block.Block{Header: block.Header{Round: round}}}
+	}
+	close(ch)
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+func (w *watchRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	return &block.Block{Header: block.Header{Round: w.latest}}, nil
+}
+
+func (w *watchRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	p, ok := w.states[round]
+	if !ok {
+		return fmt.Errorf("round %d not found", round)
+	}
+	if *args.(*uint32) != p.ID {
+		// Like the runtime, return an empty proposal for unknown identifiers.
+		p = &ProposalOutput{}
+	}
+	return cbor.Unmarshal(cbor.Marshal(p), rsp)
+}
+
+func TestWatchProposal(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob := sdkTesting.Alice.Address, sdkTesting.Bob.Address
+	proposal := func(state types.ProposalState, votes map[types.Address]types.Vote) *ProposalOutput {
+		return &ProposalOutput{ID: 3, Submitter: alice, State: state, VoteOption: votes}
+	}
+	rc := &watchRuntimeClient{
+		latest: 10,
+		states: map[uint64]*ProposalOutput{
+			10: proposal(types.Active, nil),
+			11: proposal(types.Active, nil),
+			12: proposal(types.Active, map[types.Address]types.Vote{alice: types.VoteYes}),
+			13: proposal(types.Passed, map[types.Address]types.Vote{alice: types.VoteYes, bob: types.VoteYes}),
+			14: proposal(types.Passed, map[types.Address]types.Vote{alice: types.VoteYes, bob: types.VoteYes}),
+		},
+	}
+	ctx := context.Background()
+	a := NewV1(rc)
+
+	ch, err := a.WatchProposal(ctx, 3)
+	require.NoError(err, "WatchProposal")
+	var updates []*ProposalOutput
+	for p := range ch {
+		updates = append(updates, p)
+	}
+	require.Equal([]*ProposalOutput{rc.states[10], rc.states[12], rc.states[13]}, updates,
+		"only changes should be emitted until the proposal reaches a final state")
+
+	_, err = a.WatchProposal(ctx, 4)
+	require.ErrorIs(err, ErrProposalNotFound, "unknown proposals should be reported")
+	require.ErrorContains(err, "failed to query proposal 4")
+}
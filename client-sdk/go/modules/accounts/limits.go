@@ -0,0 +1,112 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// cborNull is the CBOR encoding of nil.
+const cborNull = 0xf6
+
+// maxAddresses returns the limit passed to the address iterators of Addresses and RolesTeam. In
+// case client.Limits.MaxAddresses is configured, responses are truncated by collectAddresses
+// instead of being rejected.
+func maxAddresses(limiter *client.Limiter) uint64 {
+	if limiter.Limits().MaxAddresses > 0 {
+		return 0
+	}
+	return DefaultMaxAddresses
+}
+
+// collectAddresses collects the addresses of the given iterator up to client.Limits.MaxAddresses.
+func collectAddresses(limiter *client.Limiter, it *AddressIterator, round uint64, source string) ([]types.Address, error) {
+	limit := limiter.Limits().MaxAddresses
+	if limit == 0 || it.Len() <= limit {
+		return it.collect()
+	}
+
+	addresses := make([]types.Address, 0, limit)
+	for uint64(len(addresses)) < limit && it.Next() {
+		addresses = append(addresses, it.Address())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	limiter.ReportTruncation(&client.TruncationNotice{
+		Kind:   client.LimitAddresses,
+		Limit:  limit,
+		Total:  it.Len(),
+		Round:  round,
+		Source: source,
+	})
+	return addresses, nil
+}
+
+// limitedProposalOutput is ProposalOutput with the voters left undecoded.
+type limitedProposalOutput struct {
+	ID         uint32
+	Submitter  types.Address
+	State      types.ProposalState
+	Content    ProposalContent
+	Results    map[types.Vote]uint16
+	VoteOption cbor.RawMessage
+}
+
+// decodeProposalOutput decodes a ProposalInfo response, decoding at most
+// client.Limits.MaxProposalVoters voters.
+func decodeProposalOutput(limiter *client.Limiter, data []byte, round uint64) (*ProposalOutput, error) {
+	limit := limiter.Limits().MaxProposalVoters
+	var raw limitedProposalOutput
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("accounts: malformed proposal: %w", err)
+	}
+	p := ProposalOutput{
+		ID:        raw.ID,
+		Submitter: raw.Submitter,
+		State:     raw.State,
+		Content:   raw.Content,
+		Results:   raw.Results,
+	}
+	if len(raw.VoteOption) == 0 || raw.VoteOption[0] == cborNull {
+		return &p, nil
+	}
+
+	dec, err := types.NewMapDecoder(raw.VoteOption)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: malformed proposal voters: %w", err)
+	}
+	n := dec.Len()
+	if n > limit {
+		n = limit
+	}
+	p.VoteOption = make(map[types.Address]types.Vote, n)
+	for uint64(len(p.VoteOption)) < n {
+		var (
+			voter types.Address
+			vote  types.Vote
+		)
+		if err = dec.Decode(&voter, &vote); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("accounts: malformed proposal voters: %w", err)
+		}
+		p.VoteOption[voter] = vote
+	}
+	if dec.Len() > limit {
+		limiter.ReportTruncation(&client.TruncationNotice{
+			Kind:   client.LimitProposalVoters,
+			Limit:  limit,
+			Total:  dec.Len(),
+			Round:  round,
+			Source: methodProposalInfo,
+		})
+	}
+	return &p, nil
+}
@@ -0,0 +1,84 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// rawRuntimeClient serves raw Addresses and ProposalInfo responses.
+type rawRuntimeClient struct {
+	client.RuntimeClient
+
+	addresses Addresses
+	proposal  ProposalOutput
+	limiter   *client.Limiter
+}
+
+func (r *rawRuntimeClient) Limiter() *client.Limiter {
+	return r.limiter
+}
+
+func (r *rawRuntimeClient) QueryRaw(ctx context.Context, round uint64, method string, args interface{}) (cbor.RawMessage, error) {
+	switch method {
+	case methodAddresses:
+		return cbor.Marshal(r.addresses), nil
+	case methodProposalInfo:
+		return cbor.Marshal(r.proposal), nil
+	default:
+		return nil, fmt.Errorf("unexpected query: %s", method)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	rc := &rawRuntimeClient{
+		addresses: Addresses{alice, bob, charlie},
+		proposal: ProposalOutput{
+			ID:         3,
+			Submitter:  alice,
+			Results:    map[types.Vote]uint16{types.VoteYes: 3},
+			VoteOption: map[types.Address]types.Vote{alice: types.VoteYes, bob: types.VoteYes, charlie: types.VoteYes},
+		},
+	}
+	a := NewV1(rc)
+	ctx := context.Background()
+
+	var notices []*client.TruncationNotice
+	rc.limiter = client.NewLimiter(client.Limits{
+		MaxAddresses:      2,
+		MaxProposalVoters: 1,
+		OnTruncation:      func(n *client.TruncationNotice) { notices = append(notices, n) },
+	}, nil)
+
+	addresses, err := a.Addresses(ctx, 5, types.NativeDenomination)
+	require.NoError(err, "Addresses")
+	require.Equal(Addresses{alice, bob}, addresses)
+
+	p, err := a.ProposalInfo(ctx, 5, 3)
+	require.NoError(err, "ProposalInfo")
+	require.EqualValues(3, p.ID)
+	require.Equal(alice, p.Submitter)
+	require.Equal(rc.proposal.Results, p.Results)
+	require.Len(p.VoteOption, 1, "voters beyond the limit should be dropped")
+
+	require.Equal([]*client.TruncationNotice{
+		{Kind: client.LimitAddresses, Limit: 2, Total: 3, Round: 5, Source: methodAddresses},
+		{Kind: client.LimitProposalVoters, Limit: 1, Total: 3, Round: 5, Source: methodProposalInfo},
+	}, notices)
+
+	rc.limiter = client.NewLimiter(client.Limits{MaxProposalVoters: 10}, nil)
+	p, err = a.ProposalInfo(ctx, 5, 3)
+	require.NoError(err, "ProposalInfo")
+	require.Equal(rc.proposal.VoteOption, p.VoteOption)
+}
@@ -0,0 +1,211 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/iterator"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrProposalNotFound is the error returned when a queried proposal does not exist.
+var ErrProposalNotFound = client.NewClassifiedError(client.ErrNotFound, "accounts: proposal not found")
+
+// ProposalSource is a source of proposals, e.g. V1.
+type ProposalSource interface {
+	// ProposalInfo queries the given proposal.
+	ProposalInfo(ctx context.Context, round uint64, id uint32) (*ProposalOutput, error)
+}
+
+// ProposalsIter returns an iterator over the proposals with identifiers from fromID up to and
+// including toID (e.g. as returned by ProposalIDInfo), as seen at the given round.
+//
+// Pass e.g. iterator.WithRetries(3, client.IsTransient) to retry failed lookups.
+func ProposalsIter(
+	ctx context.Context,
+	src ProposalSource,
+	round uint64,
+	fromID, toID uint32,
+	opts ...iterator.Option,
+) *iterator.Iterator[*ProposalOutput] {
+	// Proposal identifiers start at 1.
+	if fromID == 0 {
+		fromID = 1
+	}
+	fetch := func(ctx context.Context, offset uint64) ([]*ProposalOutput, bool, error) {
+		id := uint64(fromID) + offset
+		if id > uint64(toID) {
+			return nil, false, nil
+		}
+		p, err := src.ProposalInfo(ctx, round, uint32(id))
+		if err != nil {
+			return nil, false, fmt.Errorf("accounts: failed to query proposal %d: %w", id, err)
+		}
+		return []*ProposalOutput{p}, id < uint64(toID), nil
+	}
+	return iterator.New(ctx, fetch, opts...)
+}
+
+// ProposalFilter selects proposals returned by Proposals.
+type ProposalFilter struct {
+	// State selects proposals in the given state (all states if nil).
+	State *types.ProposalState
+	// Submitter selects proposals submitted by the given address (all submitters if nil).
+	Submitter *types.Address
+	// Offset is the number of matching proposals to skip.
+	Offset uint64
+	// Limit is the maximum number of proposals to return (no limit if zero).
+	Limit uint64
+}
+
+// Matches checks whether the given proposal matches the state and submitter of the filter.
+func (f *ProposalFilter) Matches(p *ProposalOutput) bool {
+	if f.State != nil && p.State != *f.State {
+		return false
+	}
+	if f.Submitter != nil && !p.Submitter.Equal(*f.Submitter) {
+		return false
+	}
+	return true
+}
+
+// ProposalList is a page of proposals.
+type ProposalList struct {
+	// Round is the round the proposals were queried at.
+	Round uint64 `json:"round"`
+	// Proposals are the matching proposals in identifier order.
+	Proposals []*ProposalOutput `json:"proposals"`
+	// More is true iff more proposals match beyond the limit.
+	More bool `json:"more"`
+}
+
+// Implements V1.
+func (a *v1) Proposals(ctx context.Context, round uint64, filter *ProposalFilter) (*ProposalList, error) {
+	if filter == nil {
+		filter = &ProposalFilter{}
+	}
+
+	// Pin all queries to the same round, so that the list is consistent.
+	round, err := a.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	latestID, err := a.ProposalIDInfo(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query latest proposal ID: %w", err)
+	}
+
+	// The runtime has no proposal listing query, so proposals are filtered client-side.
+	list := ProposalList{Round: round}
+	var skipped uint64
+	for from := uint32(1); from <= latestID; from += ProposalQueryParallelism {
+		ids := make([]uint32, 0, ProposalQueryParallelism)
+		for id := from; id <= latestID && id-from < ProposalQueryParallelism; id++ {
+			ids = append(ids, id)
+		}
+		proposals, err := a.ProposalInfos(ctx, round, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			p := proposals[id]
+			switch {
+			case !filter.Matches(p):
+			case skipped < filter.Offset:
+				skipped++
+			case filter.Limit > 0 && uint64(len(list.Proposals)) == filter.Limit:
+				list.More = true
+				return &list, nil
+			default:
+				list.Proposals = append(list.Proposals, p)
+			}
+		}
+	}
+	return &list, nil
+}
+
+// ProposalQueryParallelism is the maximum number of concurrent queries made by ProposalInfos.
+const ProposalQueryParallelism = 16
+
+// Implements V1.
+func (a *v1) ProposalInfos(ctx context.Context, round uint64, ids []uint32) (map[uint32]*ProposalOutput, error) {
+	// Pin all queries to the same round, so that the proposals are consistent.
+	round, err := a.resolveRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		l         sync.Mutex
+		firstErr  error
+		proposals = make(map[uint32]*ProposalOutput, len(ids))
+	)
+	queue := make(chan uint32)
+	var wg sync.WaitGroup
+	for w := 0; w < ProposalQueryParallelism && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				p, err := a.ProposalInfo(ctx, round, id)
+				if err == nil && p.ID != id {
+					// The runtime returns an empty proposal for unknown identifiers.
+					err = ErrProposalNotFound
+				}
+
+				l.Lock()
+				switch {
+				case err != nil && firstErr == nil:
+					firstErr = fmt.Errorf("accounts: failed to query proposal %d: %w", id, err)
+					cancel()
+				case err == nil:
+					proposals[id] = p
+				}
+				l.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[uint32]bool, len(ids))
+enqueue:
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		select {
+		case queue <- id:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// resolveRound resolves RoundLatest to the latest round, so that subsequent queries are pinned to
+// the same round.
+func (a *v1) resolveRound(ctx context.Context, round uint64) (uint64, error) {
+	if round != client.RoundLatest {
+		return round, nil
+	}
+	blk, err := a.rc.GetBlock(ctx, round)
+	if err != nil {
+		return 0, fmt.Errorf("accounts: failed to fetch latest block: %w", err)
+	}
+	return blk.Header.Round, nil
+}
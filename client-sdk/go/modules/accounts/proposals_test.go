@@ -0,0 +1,154 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// proposalRuntimeClient serves proposals, recording the rounds they were queried at and the
+// maximum number of concurrent queries.
+type proposalRuntimeClient struct {
+	client.RuntimeClient
+
+	l           sync.Mutex
+	proposals   []*ProposalOutput
+	rounds      map[uint64]bool
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *proposalRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = 42
+	return &blk, nil
+}
+
+func (p *proposalRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	p.l.Lock()
+	p.rounds[round] = true
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	p.l.Unlock()
+	defer func() {
+		p.l.Lock()
+		p.inFlight--
+		p.l.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	var result interface{}
+	switch method {
+	case methodProposalID:
+		result = uint32(len(p.proposals))
+	case methodProposalInfo:
+		// Like the runtime, return an empty proposal for unknown identifiers.
+		result = &ProposalOutput{}
+		if id := *args.(*uint32); id > 0 && int(id) <= len(p.proposals) {
+			result = p.proposals[id-1]
+		}
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return cbor.Unmarshal(cbor.Marshal(result), rsp)
+}
+
+func TestProposals(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	alice, bob := sdkTesting.Alice.Address, sdkTesting.Bob.Address
+	rc := &proposalRuntimeClient{rounds: make(map[uint64]bool)}
+	for i, p := range []struct {
+		submitter types.Address
+		state     types.ProposalState
+	}{
+		{alice, types.Passed},
+		{bob, types.Active},
+		{alice, types.Active},
+		{alice, types.Rejected},
+		{bob, types.Active},
+		{alice, types.Active},
+	} {
+		rc.proposals = append(rc.proposals, &ProposalOutput{ID: uint32(i + 1), Submitter: p.submitter, State: p.state})
+	}
+	ids := func(list *ProposalList) (ids []uint32) {
+		for _, p := range list.Proposals {
+			ids = append(ids, p.ID)
+		}
+		return
+	}
+	a := NewV1(rc)
+
+	list, err := a.Proposals(ctx, client.RoundLatest, nil)
+	require.NoError(err, "Proposals")
+	require.EqualValues(42, list.Round)
+	require.Equal([]uint32{1, 2, 3, 4, 5, 6}, ids(list))
+	require.False(list.More)
+	require.Equal(map[uint64]bool{42: true}, rc.rounds, "all queries should be pinned to the same round")
+
+	active := types.Active
+	list, err = a.Proposals(ctx, client.RoundLatest, &ProposalFilter{State: &active})
+	require.NoError(err, "Proposals")
+	require.Equal([]uint32{2, 3, 5, 6}, ids(list))
+
+	list, err = a.Proposals(ctx, 10, &ProposalFilter{State: &active, Submitter: &alice})
+	require.NoError(err, "Proposals")
+	require.EqualValues(10, list.Round)
+	require.Equal([]uint32{3, 6}, ids(list))
+
+	list, err = a.Proposals(ctx, client.RoundLatest, &ProposalFilter{State: &active, Offset: 1, Limit: 2})
+	require.NoError(err, "Proposals")
+	require.Equal([]uint32{3, 5}, ids(list))
+	require.True(list.More)
+
+	list, err = a.Proposals(ctx, client.RoundLatest, &ProposalFilter{State: &active, Offset: 2, Limit: 2})
+	require.NoError(err, "Proposals")
+	require.Equal([]uint32{5, 6}, ids(list))
+	require.False(list.More)
+}
+
+func TestProposalInfos(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &proposalRuntimeClient{rounds: make(map[uint64]bool)}
+	var ids []uint32
+	for id := uint32(1); id <= 100; id++ {
+		rc.proposals = append(rc.proposals, &ProposalOutput{ID: id, Submitter: sdkTesting.Alice.Address})
+		ids = append(ids, id)
+	}
+	a := NewV1(rc)
+
+	proposals, err := a.ProposalInfos(ctx, client.RoundLatest, append(ids, 1, 2))
+	require.NoError(err, "ProposalInfos")
+	require.Len(proposals, 100)
+	for _, id := range ids {
+		require.Equal(id, proposals[id].ID)
+	}
+	require.Equal(map[uint64]bool{42: true}, rc.rounds, "all queries should be pinned to the same round")
+	require.Greater(rc.maxInFlight, 1, "proposals should be queried concurrently")
+	require.LessOrEqual(rc.maxInFlight, ProposalQueryParallelism, "concurrency should be bounded")
+
+	_, err = a.ProposalInfos(ctx, 10, []uint32{1, 2, 101, 3})
+	require.ErrorContains(err, "failed to query proposal 101")
+	require.ErrorIs(err, ErrProposalNotFound, "unknown proposals should be reported")
+	require.ErrorIs(err, client.ErrNotFound)
+
+	proposals, err = a.ProposalInfos(ctx, 10, nil)
+	require.NoError(err, "ProposalInfos")
+	require.Empty(proposals)
+}
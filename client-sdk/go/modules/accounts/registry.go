@@ -0,0 +1,92 @@
+package accounts
+
+import (
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func init() {
+	client.MustRegisterModule(&client.ModuleDescriptor{
+		Name: ModuleName,
+		Methods: map[string]interface{}{
+			methodTransfer:   Transfer{},
+			methodInitOwners: []RoleAddress{},
+			methodPropose:    ProposalContent{},
+			methodVoteST:     VoteProposal{},
+			methodMintST:     MintST{},
+			methodBurnST:     BurnST{},
+		},
+		MethodInfos: map[string]client.MethodInfo{
+			methodTransfer: {
+				Description: "Transfer tokens to another account.",
+				Events:      []uint32{TransferEventCode},
+			},
+			methodInitOwners: {
+				Description: "Assign the initial roles. Only the chain initiator may call it, once.",
+			},
+			methodPropose: {
+				Description: "Submit a governance proposal. Requires the proposer or voter role of the proposed action.",
+				BodyRoles:   proposeRoles,
+			},
+			methodVoteST: {
+				Description: "Vote on a governance proposal. Requires the voter role of the proposal's action. Executes the proposal once the quorum is reached.",
+				Events:      []uint32{MintEventCode, BurnEventCode},
+			},
+			methodMintST: {
+				Description: "Mint tokens to an account.",
+				Events:      []uint32{MintEventCode},
+			},
+			methodBurnST: {
+				Description: "Burn tokens of the caller. Only the chain initiator may call it.",
+				Events:      []uint32{BurnEventCode},
+			},
+		},
+		Queries: map[string]interface{}{
+			methodParameters:       nil,
+			methodNonce:            NonceQuery{},
+			methodRole:             RoleQuery{},
+			methodInit:             InitInfoQuery{},
+			methodQuorum:           QuorumsQuery{},
+			methodRoleAddresses:    RoleAddressesQuery{},
+			methodProposalID:       nil,
+			methodProposalInfo:     uint32(0),
+			methodBalances:         BalancesQuery{},
+			methodAddresses:        AddressesQuery{},
+			methodDenominationInfo: DenominationInfoQuery{},
+		},
+		Events: client.EventDecoderFunc(DecodeEvent),
+		EventTypes: map[uint32]interface{}{
+			TransferEventCode: TransferEvent{},
+			BurnEventCode:     BurnEvent{},
+			MintEventCode:     MintEvent{},
+		},
+		Errors: map[uint32]client.ModuleError{
+			1:  {Description: "invalid argument"},
+			2:  {Description: "insufficient balance"},
+			3:  {Description: "forbidden by policy", Class: client.ErrAuth},
+			4:  {Description: "not found", Class: client.ErrNotFound},
+			5:  {Description: "invalid role", Class: client.ErrAuth},
+			6:  {Description: "invalid proposal state"},
+			7:  {Description: "counter overflow"},
+			8:  {Description: "invalid proposal quorum"},
+			9:  {Description: "invalid proposal role no"},
+			10: {Description: "voted already"},
+		},
+	})
+}
+
+// proposeRoles returns the roles allowed to submit the given proposal.
+func proposeRoles(body interface{}) []types.Role {
+	pc, ok := body.(*ProposalContent)
+	if !ok {
+		return nil
+	}
+	var roles []types.Role
+	if role, ok := pc.Action.ProposerRole(); ok {
+		roles = append(roles, role)
+	}
+	if role, ok := pc.Action.VoterRole(); ok && (len(roles) == 0 || roles[0] != role) {
+		roles = append(roles, role)
+	}
+	return roles
+}
@@ -0,0 +1,127 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// TransactIssue is a reason a transfer can't be made.
+type TransactIssue string
+
+const (
+	// IssueSenderBlacklisted means that the sender is blacklisted (frozen), so the runtime will
+	// reject the transaction as the sender pays its fee.
+	IssueSenderBlacklisted TransactIssue = "sender_blacklisted"
+	// IssueRecipientBlacklisted means that the recipient is blacklisted (frozen).
+	//
+	// Unlike IssueSenderBlacklisted this is not enforced by the runtime, which accepts transfers to
+	// blacklisted accounts. It is reported so that gateways can refuse to fund frozen accounts as a
+	// matter of policy.
+	IssueRecipientBlacklisted TransactIssue = "recipient_blacklisted"
+	// IssueTransfersDisabled means that transfers are disabled by the module parameters.
+	IssueTransfersDisabled TransactIssue = "transfers_disabled"
+	// IssueInsufficientBalance means that the sender lacks the transferred amount.
+	IssueInsufficientBalance TransactIssue = "insufficient_balance"
+	// IssueInsufficientGas means that the sender lacks the funds to pay the transaction fee.
+	IssueInsufficientGas TransactIssue = "insufficient_gas"
+)
+
+// TransactVerdict is the result of checking whether a transfer can be made.
+type TransactVerdict struct {
+	// Round is the round the checks were performed at.
+	Round uint64 `json:"round"`
+	// Allowed is true iff no issues were found.
+	Allowed bool `json:"allowed"`
+	// Issues are the reasons the transfer can't be made.
+	Issues []TransactIssue `json:"issues,omitempty"`
+	// Fee is the estimated fee of the transfer at the minimum gas price.
+	Fee types.BaseUnits `json:"fee"`
+	// Balance is the balance of the sender in the transferred denomination.
+	Balance types.Quantity `json:"balance"`
+}
+
+// Has checks whether the verdict contains the given issue.
+func (v *TransactVerdict) Has(issue TransactIssue) bool {
+	for _, i := range v.Issues {
+		if i == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// Implements V1.
+func (a *v1) CanTransact(ctx context.Context, from, to types.Address, amount types.BaseUnits) (*TransactVerdict, error) {
+	// Pin all queries to the same round.
+	blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to fetch latest block: %w", err)
+	}
+	v := TransactVerdict{Round: blk.Header.Round}
+
+	params, err := a.Parameters(ctx, v.Round)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query parameters: %w", err)
+	}
+	if params.TransfersDisabled {
+		v.Issues = append(v.Issues, IssueTransfersDisabled)
+	}
+	for _, party := range []struct {
+		address types.Address
+		issue   TransactIssue
+	}{
+		{from, IssueSenderBlacklisted},
+		{to, IssueRecipientBlacklisted},
+	} {
+		role, err := a.Role(ctx, v.Round, party.address)
+		if err != nil {
+			return nil, fmt.Errorf("accounts: failed to query role of %s: %w", party.address, err)
+		}
+		if role == types.BlacklistedUser {
+			v.Issues = append(v.Issues, party.issue)
+		}
+	}
+
+	c := core.NewV1(a.rc)
+	tx := types.NewTransaction(nil, methodTransfer, &Transfer{To: to, Amount: amount})
+	gas, err := c.EstimateGasForCaller(ctx, v.Round, types.CallerAddress{Address: &from}, tx, false)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to estimate gas: %w", err)
+	}
+	mgp, err := c.MinGasPrice(ctx, v.Round)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query minimum gas price: %w", err)
+	}
+	fee, err := core.FeeForGas(mgp, types.GasDenomination, gas)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to compute fee: %w", err)
+	}
+	v.Fee = *fee
+
+	balances, err := a.Balances(ctx, v.Round, from)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query balances: %w", err)
+	}
+	v.Balance = balances.Balances[amount.Denomination]
+	if v.Balance.Cmp(&amount.Amount) < 0 {
+		v.Issues = append(v.Issues, IssueInsufficientBalance)
+	}
+	// The fee is paid from what is left in case the amount is transferred in the gas denomination.
+	available := balances.Balances[fee.Denomination]
+	if fee.Denomination == amount.Denomination {
+		available = *available.Clone()
+		if available.Sub(&amount.Amount) != nil {
+			available = types.Quantity{}
+		}
+	}
+	if available.Cmp(&fee.Amount) < 0 {
+		v.Issues = append(v.Issues, IssueInsufficientGas)
+	}
+
+	v.Allowed = len(v.Issues) == 0
+	return &v, nil
+}
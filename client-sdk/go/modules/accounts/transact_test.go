@@ -0,0 +1,108 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// paymentRuntimeClient serves the queries made by CanTransact.
+type paymentRuntimeClient struct {
+	client.RuntimeClient
+
+	params      Parameters
+	blacklisted map[types.Address]bool
+	balances    map[types.Address]map[types.Denomination]types.Quantity
+	rounds      []uint64
+}
+
+func (p *paymentRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = 42
+	return &blk, nil
+}
+
+func (p *paymentRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	var result interface{}
+	switch method {
+	case methodParameters:
+		p.rounds = append(p.rounds, round)
+		result = p.params
+	case methodRole:
+		p.rounds = append(p.rounds, round)
+		result = types.User
+		if p.blacklisted[args.(*RoleQuery).Address] {
+			result = types.BlacklistedUser
+		}
+	case methodBalances:
+		p.rounds = append(p.rounds, round)
+		result = AccountBalances{Balances: p.balances[args.(*BalancesQuery).Address]}
+	case "core.EstimateGas":
+		p.rounds = append(p.rounds, round)
+		result = uint64(1000)
+	case "core.MinGasPrice":
+		p.rounds = append(p.rounds, round)
+		result = map[types.Denomination]types.Quantity{types.NativeDenomination: *quantity.NewFromUint64(2)}
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return cbor.Unmarshal(cbor.Marshal(result), rsp)
+}
+
+func TestCanTransact(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob := sdkTesting.Alice.Address, sdkTesting.Bob.Address
+	hlusd := types.Denomination("HLUSD")
+	rc := &paymentRuntimeClient{
+		blacklisted: map[types.Address]bool{},
+		balances: map[types.Address]map[types.Denomination]types.Quantity{
+			alice: {
+				types.NativeDenomination: *quantity.NewFromUint64(2500),
+				hlusd:                    *quantity.NewFromUint64(100),
+			},
+		},
+	}
+	a := NewV1(rc)
+	ctx := context.Background()
+
+	v, err := a.CanTransact(ctx, alice, bob, types.NewBaseUnits(*quantity.NewFromUint64(100), hlusd))
+	require.NoError(err)
+	require.True(v.Allowed)
+	require.EqualValues(42, v.Round)
+	require.Equal(types.NewBaseUnits(*quantity.NewFromUint64(2000), types.NativeDenomination), v.Fee)
+	require.EqualValues(100, v.Balance.ToBigInt().Uint64())
+	require.Len(rc.rounds, 6)
+	for _, round := range rc.rounds {
+		require.EqualValues(42, round, "all queries should be pinned to the same round")
+	}
+
+	// The fee is paid from what is left after the transfer.
+	v, err = a.CanTransact(ctx, alice, bob, types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination))
+	require.NoError(err)
+	require.False(v.Allowed)
+	require.Equal([]TransactIssue{IssueInsufficientGas}, v.Issues)
+
+	rc.params.TransfersDisabled = true
+	rc.blacklisted[bob] = true
+	v, err = a.CanTransact(ctx, alice, bob, types.NewBaseUnits(*quantity.NewFromUint64(101), hlusd))
+	require.NoError(err)
+	require.False(v.Allowed)
+	require.Equal([]TransactIssue{IssueTransfersDisabled, IssueRecipientBlacklisted, IssueInsufficientBalance}, v.Issues)
+	require.True(v.Has(IssueRecipientBlacklisted))
+	require.False(v.Has(IssueSenderBlacklisted))
+
+	v, err = a.CanTransact(ctx, bob, alice, types.NewBaseUnits(*quantity.NewFromUint64(1), hlusd))
+	require.NoError(err)
+	require.Equal([]TransactIssue{IssueTransfersDisabled, IssueSenderBlacklisted, IssueInsufficientBalance, IssueInsufficientGas}, v.Issues)
+}
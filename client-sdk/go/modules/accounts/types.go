@@ -181,4 +181,6 @@ type Event struct {
 	Transfer *TransferEvent
 	Burn     *BurnEvent
 	Mint     *MintEvent
+
+	types.EventMeta
 }
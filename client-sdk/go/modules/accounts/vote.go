@@ -0,0 +1,228 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrProposalNotActive is the error returned when voting on a proposal which is not active.
+	ErrProposalNotActive = client.NewClassifiedError(client.ErrPermanent, "accounts: proposal is not active")
+	// ErrAlreadyVoted is the error returned when the voter has already voted on a proposal.
+	ErrAlreadyVoted = client.NewClassifiedError(client.ErrPermanent, "accounts: already voted on proposal")
+	// ErrSelfVote is the error returned when the voter is the submitter of a proposal and self
+	// votes are forbidden.
+	ErrSelfVote = client.NewClassifiedError(client.ErrPermanent, "accounts: voter is the proposal submitter")
+)
+
+// VoteGuardOptions are the options of the client-side vote checks.
+type VoteGuardOptions struct {
+	// ForbidSelfVote rejects votes by the submitter of the proposal. The runtime currently
+	// allows such votes so this is disabled by default.
+	ForbidSelfVote bool
+	// Force skips all checks.
+	Force bool
+}
+
+// CheckVote checks whether the given voter may vote on the given proposal, rejecting votes on
+// proposals that are no longer active and votes by voters that have already voted (which the
+// runtime would reject anyway, wasting the transaction fee).
+func CheckVote(proposal *ProposalOutput, voter types.Address, opts *VoteGuardOptions) error {
+	if opts == nil {
+		opts = &VoteGuardOptions{}
+	}
+	if opts.Force {
+		return nil
+	}
+
+	if proposal.State != types.Active {
+		return fmt.Errorf("%w: proposal %d is %s", ErrProposalNotActive, proposal.ID, proposal.State)
+	}
+	if vote, ok := proposal.VoteOption[voter]; ok {
+		return fmt.Errorf("%w: %s voted %s on proposal %d", ErrAlreadyVoted, voter, vote, proposal.ID)
+	}
+	if opts.ForbidSelfVote && proposal.Submitter.Equal(voter) {
+		return fmt.Errorf("%w: %s submitted proposal %d", ErrSelfVote, voter, proposal.ID)
+	}
+	return nil
+}
+
+// NewVoteBuilder returns a builder for an accounts.VoteST transaction by the given voter after
+// checking the latest state of the proposal with CheckVote.
+func NewVoteBuilder(
+	ctx context.Context,
+	rc client.RuntimeClient,
+	voter types.Address,
+	body *VoteProposal,
+	opts *VoteGuardOptions,
+) (*client.TransactionBuilder, error) {
+	if opts == nil || !opts.Force {
+		proposal, err := NewV1(rc).ProposalInfo(ctx, client.RoundLatest, body.ID)
+		if err != nil {
+			return nil, fmt.Errorf("accounts: failed to query proposal: %w", err)
+		}
+		if err = CheckVote(proposal, voter, opts); err != nil {
+			return nil, err
+		}
+	}
+	return client.NewTransactionBuilder(rc, methodVoteST, body), nil
+}
+
+// VoteResult is the result of a submitted vote.
+type VoteResult struct {
+	// Round is the round in which the vote was executed.
+	Round uint64
+	// Proposal is the proposal as of right after the vote.
+	Proposal *ProposalOutput
+	// Tally is the vote tally as of right after the vote.
+	Tally map[types.Vote]uint16
+	// Decided is true iff the proposal left the active state in the round the vote was executed
+	// in, normally because this vote made it reach the quorum.
+	Decided bool
+}
+
+// SubmitVote submits the given signed accounts.VoteST transaction, waits for it to be executed and
+// returns the updated tally.
+//
+// The proposal is queried at the exact round in which the vote was executed (and the one before
+// it) so the result is not affected by other votes landing in later blocks.
+func SubmitVote(ctx context.Context, rc client.RuntimeClient, tb *client.TransactionBuilder) (*VoteResult, error) {
+	result, _, err := submitVote(ctx, rc, tb)
+	return result, err
+}
+
+// submitVote submits the given signed vote transaction. It additionally returns whether the
+// transaction was executed and thus consumed the signer's nonce.
+func submitVote(ctx context.Context, rc client.RuntimeClient, tb *client.TransactionBuilder) (*VoteResult, bool, error) {
+	tx := tb.GetTransaction()
+	if tx.Call.Method != methodVoteST {
+		return nil, false, fmt.Errorf("accounts: not a vote transaction: %s", tx.Call.Method)
+	}
+	var vote VoteProposal
+	if err := cbor.Unmarshal(tx.Call.Body, &vote); err != nil {
+		return nil, false, fmt.Errorf("accounts: malformed vote: %w", err)
+	}
+
+	meta, err := tb.SubmitTxMeta(ctx, nil)
+	if err != nil {
+		// Failed calls were executed and thus consumed the nonce.
+		var failed *types.FailedCallResult
+		return nil, errors.As(err, &failed), err
+	}
+	if meta.CheckTxError != nil {
+		return nil, false, &checkTxError{client.Classify(types.FailedCallResult{
+			Module:  meta.CheckTxError.Module,
+			Code:    meta.CheckTxError.Code,
+			Message: meta.CheckTxError.Message,
+		})}
+	}
+
+	a := NewV1(rc)
+	proposal, err := a.ProposalInfo(ctx, meta.Round, vote.ID)
+	if err != nil {
+		return nil, true, fmt.Errorf("accounts: failed to query proposal after vote: %w", err)
+	}
+	result := VoteResult{
+		Round:    meta.Round,
+		Proposal: proposal,
+		Tally:    proposal.Results,
+	}
+	if proposal.State != types.Active && meta.Round > 0 {
+		prev, err := a.ProposalInfo(ctx, meta.Round-1, vote.ID)
+		if err != nil {
+			return nil, true, fmt.Errorf("accounts: failed to query proposal before vote: %w", err)
+		}
+		result.Decided = prev.State == types.Active
+	}
+	return &result, true, nil
+}
+
+// BatchVoteOutcome is the outcome of a single vote of a batch.
+type BatchVoteOutcome struct {
+	// ID is the proposal identifier.
+	ID uint32
+	// Result is the vote result in case the vote succeeded.
+	Result *VoteResult
+	// Err is the error in case the vote failed.
+	Err error
+}
+
+// SubmitVotes builds, signs and submits votes on multiple proposals by the same voter, one after
+// another in nonce order. Votes rejected by CheckVote or by the runtime do not stop the batch and
+// are reported in the corresponding outcome.
+//
+// In case the outcome of a submission cannot be determined (e.g. due to a network error) the
+// batch stops, as the next nonce is unknown, and the outcomes so far are returned together with
+// the error.
+func SubmitVotes(
+	ctx context.Context,
+	rc client.RuntimeClient,
+	signer signature.Signer,
+	spec types.SignatureAddressSpec,
+	fee *types.Fee,
+	votes []VoteProposal,
+	opts *VoteGuardOptions,
+) ([]*BatchVoteOutcome, error) {
+	voter := types.NewAddress(spec)
+	nonce, err := NewV1(rc).Nonce(ctx, client.RoundLatest, voter)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: failed to query nonce: %w", err)
+	}
+
+	outcomes := make([]*BatchVoteOutcome, 0, len(votes))
+	for i := range votes {
+		outcome := &BatchVoteOutcome{ID: votes[i].ID}
+		outcomes = append(outcomes, outcome)
+
+		tb, err := NewVoteBuilder(ctx, rc, voter, &votes[i], opts)
+		if err != nil {
+			outcome.Err = err
+			continue
+		}
+		if fee != nil {
+			tb.SetFeeAmount(fee.Amount)
+			tb.SetFeeGas(fee.Gas)
+		}
+		tb.AppendAuthSignature(spec, nonce)
+		if err = tb.AppendSign(ctx, signer); err != nil {
+			outcome.Err = err
+			continue
+		}
+
+		var consumed bool
+		outcome.Result, consumed, outcome.Err = submitVote(ctx, rc, tb)
+		switch {
+		case consumed:
+			nonce++
+		case outcome.Err != nil && !isCheckTxError(outcome.Err):
+			return outcomes, fmt.Errorf("accounts: failed to submit vote on proposal %d: %w", votes[i].ID, outcome.Err)
+		}
+	}
+	return outcomes, nil
+}
+
+// checkTxError is an error returned by transaction checks.
+type checkTxError struct {
+	err error
+}
+
+func (e *checkTxError) Error() string {
+	return e.err.Error()
+}
+
+func (e *checkTxError) Unwrap() error {
+	return e.err
+}
+
+// isCheckTxError checks whether the given error was returned by transaction checks.
+func isCheckTxError(err error) bool {
+	var ce *checkTxError
+	return errors.As(err, &ce)
+}
@@ -0,0 +1,279 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// voteRuntimeClient executes votes on in-memory proposals, one round per executed transaction.
+type voteRuntimeClient struct {
+	client.RuntimeClient
+
+	round     uint64
+	nonce     uint64
+	quorum    uint16
+	proposals map[uint32]*ProposalOutput
+	history   map[uint64]map[uint32]*ProposalOutput
+
+	// failures are the failures of votes on the given proposals, either a *types.FailedCallResult
+	// (executed), a *client.CheckTxError (rejected by transaction checks) or an error (not
+	// submitted).
+	failures map[uint32]interface{}
+	// nonces are the nonces of all submitted transactions.
+	nonces []uint64
+	// concurrent is called after each executed transaction to simulate other transactions.
+	concurrent func()
+}
+
+func newVoteRuntimeClient(quorum uint16, proposals ...*ProposalOutput) *voteRuntimeClient {
+	v := &voteRuntimeClient{
+		round:     10,
+		quorum:    quorum,
+		proposals: make(map[uint32]*ProposalOutput),
+		history:   make(map[uint64]map[uint32]*ProposalOutput),
+		failures:  make(map[uint32]interface{}),
+	}
+	for _, p := range proposals {
+		v.proposals[p.ID] = p
+	}
+	v.snapshot()
+	return v
+}
+
+// snapshot records the state of all proposals as of the current round.
+func (v *voteRuntimeClient) snapshot() {
+	state := make(map[uint32]*ProposalOutput)
+	for id, p := range v.proposals {
+		var cp ProposalOutput
+		_ = cbor.Unmarshal(cbor.Marshal(p), &cp)
+		state[id] = &cp
+	}
+	v.history[v.round] = state
+}
+
+func (v *voteRuntimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "vote test"}, nil
+}
+
+func (v *voteRuntimeClient) SubmitTxRawMeta(ctx context.Context, utx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	var tx types.Transaction
+	if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+		return nil, err
+	}
+	var vote VoteProposal
+	if err := cbor.Unmarshal(tx.Call.Body, &vote); err != nil {
+		return nil, err
+	}
+	si := tx.AuthInfo.SignerInfo[0]
+	v.nonces = append(v.nonces, si.Nonce)
+
+	var meta client.SubmitTxRawMeta
+	switch failure := v.failures[vote.ID].(type) {
+	case nil:
+		p := v.proposals[vote.ID]
+		p.VoteOption[types.NewAddress(*si.AddressSpec.Signature)] = vote.Option
+		p.Results[vote.Option]++
+		if p.Results[vote.Option] >= v.quorum {
+			p.State = types.Passed
+		}
+		meta.Result.Ok = cbor.Marshal(nil)
+	case *types.FailedCallResult:
+		meta.Result.Failed = failure
+	case *client.CheckTxError:
+		meta.CheckTxError = failure
+		return &meta, nil
+	case error:
+		return nil, failure
+	}
+	v.nonce++
+	v.round++
+	v.snapshot()
+	meta.Round = v.round
+
+	if v.concurrent != nil {
+		v.round++
+		v.concurrent()
+		v.snapshot()
+	}
+	return &meta, nil
+}
+
+func (v *voteRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	var result interface{}
+	switch method {
+	case methodNonce:
+		result = v.nonce
+	case methodProposalInfo:
+		if round == client.RoundLatest {
+			round = v.round
+		}
+		result = v.history[round][*args.(*uint32)]
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return cbor.Unmarshal(cbor.Marshal(result), rsp)
+}
+
+func newActiveProposal(id uint32, votes map[types.Address]types.Vote) *ProposalOutput {
+	p := &ProposalOutput{
+		ID:         id,
+		Submitter:  sdkTesting.Charlie.Address,
+		State:      types.Active,
+		Results:    make(map[types.Vote]uint16),
+		VoteOption: make(map[types.Address]types.Vote),
+	}
+	for voter, vote := range votes {
+		p.VoteOption[voter] = vote
+		p.Results[vote]++
+	}
+	return p
+}
+
+func TestCheckVote(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	proposal := &ProposalOutput{
+		ID:         1,
+		Submitter:  alice,
+		State:      types.Active,
+		VoteOption: map[types.Address]types.Vote{bob: types.VoteYes},
+	}
+
+	require.NoError(CheckVote(proposal, charlie, nil))
+	require.NoError(CheckVote(proposal, alice, nil), "self votes should be allowed by default")
+	require.True(errors.Is(CheckVote(proposal, alice, &VoteGuardOptions{ForbidSelfVote: true}), ErrSelfVote))
+	require.True(errors.Is(CheckVote(proposal, bob, nil), ErrAlreadyVoted))
+	require.NoError(CheckVote(proposal, bob, &VoteGuardOptions{Force: true}), "forced votes should skip checks")
+
+	proposal.State = types.Passed
+	require.True(errors.Is(CheckVote(proposal, charlie, nil), ErrProposalNotActive))
+}
+
+func TestSubmitVote(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rc := newVoteRuntimeClient(2,
+		newActiveProposal(1, map[types.Address]types.Vote{sdkTesting.Bob.Address: types.VoteYes}),
+		newActiveProposal(2, nil),
+	)
+	vote := func(key sdkTesting.TestKey, id uint32, option types.Vote) (*VoteResult, error) {
+		tb, err := NewVoteBuilder(ctx, rc, key.Address, &VoteProposal{ID: id, Option: option}, nil)
+		if err != nil {
+			return nil, err
+		}
+		tb.AppendAuthSignature(key.SigSpec, rc.nonce)
+		require.NoError(tb.AppendSign(ctx, key.Signer))
+		return SubmitVote(ctx, rc, tb)
+	}
+
+	tb, err := NewVoteBuilder(ctx, rc, sdkTesting.Alice.Address, &VoteProposal{ID: 2, Option: types.VoteNo}, nil)
+	require.NoError(err)
+	tx := tb.GetTransaction()
+	require.Equal(methodVoteST, tx.Call.Method)
+	var body VoteProposal
+	require.NoError(cbor.Unmarshal(tx.Call.Body, &body))
+	require.Equal(VoteProposal{ID: 2, Option: types.VoteNo}, body)
+
+	_, err = NewVoteBuilder(ctx, rc, sdkTesting.Bob.Address, &VoteProposal{ID: 1, Option: types.VoteNo}, nil)
+	require.ErrorIs(err, ErrAlreadyVoted, "votes should be checked against the latest state")
+
+	result, err := vote(sdkTesting.Alice, 2, types.VoteNo)
+	require.NoError(err)
+	require.EqualValues(11, result.Round)
+	require.Equal(map[types.Vote]uint16{types.VoteNo: 1}, result.Tally)
+	require.Equal(types.Active, result.Proposal.State)
+	require.False(result.Decided, "votes not reaching the quorum should not decide the proposal")
+
+	result, err = vote(sdkTesting.Alice, 1, types.VoteYes)
+	require.NoError(err)
+	require.EqualValues(12, result.Round)
+	require.Equal(map[types.Vote]uint16{types.VoteYes: 2}, result.Tally)
+	require.Equal(types.Passed, result.Proposal.State)
+	require.True(result.Decided, "the vote reaching the quorum should decide the proposal")
+
+	// Results should be as of the round of the vote, not affected by later votes.
+	rc.concurrent = func() {
+		rc.proposals[2].Results[types.VoteAbstain]++
+	}
+	result, err = vote(sdkTesting.Bob, 2, types.VoteNo)
+	require.NoError(err)
+	require.EqualValues(13, result.Round)
+	require.Equal(map[types.Vote]uint16{types.VoteNo: 2}, result.Tally)
+	require.True(result.Decided)
+	rc.concurrent = nil
+
+	_, err = vote(sdkTesting.Charlie, 1, types.VoteYes)
+	require.ErrorIs(err, ErrProposalNotActive)
+
+	rc.proposals[3] = newActiveProposal(3, nil)
+	rc.snapshot()
+	rc.failures[3] = &types.FailedCallResult{Module: ModuleName, Code: 1, Message: "failed"}
+	_, err = vote(sdkTesting.Charlie, 3, types.VoteAbstain)
+	var failed *types.FailedCallResult
+	require.True(errors.As(err, &failed), "failed votes should be reported")
+
+	_, err = SubmitVote(ctx, rc, client.NewTransactionBuilder(rc, methodMintST, nil))
+	require.ErrorContains(err, "not a vote transaction")
+}
+
+func TestSubmitVotes(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	alice := sdkTesting.Alice
+	rc := newVoteRuntimeClient(2,
+		newActiveProposal(1, nil),
+		newActiveProposal(2, map[types.Address]types.Vote{alice.Address: types.VoteYes}),
+		newActiveProposal(3, nil),
+		newActiveProposal(4, nil),
+		newActiveProposal(5, nil),
+		newActiveProposal(6, nil),
+		newActiveProposal(7, nil),
+	)
+	rc.nonce = 5
+	rc.failures[3] = &types.FailedCallResult{Module: ModuleName, Code: 1, Message: "failed"}
+	rc.failures[4] = &client.CheckTxError{Module: "core", Code: 2, Message: "insufficient balance"}
+
+	batch := func(ids ...uint32) []VoteProposal {
+		var votes []VoteProposal
+		for _, id := range ids {
+			votes = append(votes, VoteProposal{ID: id, Option: types.VoteYes})
+		}
+		return votes
+	}
+	outcomes, err := SubmitVotes(ctx, rc, alice.Signer, alice.SigSpec, nil, batch(1, 2, 3, 4, 5), nil)
+	require.NoError(err, "rejected votes should not stop the batch")
+	require.Len(outcomes, 5)
+	require.NoError(outcomes[0].Err)
+	require.EqualValues(1, outcomes[0].Result.Tally[types.VoteYes])
+	require.ErrorIs(outcomes[1].Err, ErrAlreadyVoted)
+	var failed *types.FailedCallResult
+	require.True(errors.As(outcomes[2].Err, &failed))
+	require.ErrorContains(outcomes[3].Err, "insufficient balance")
+	require.NoError(outcomes[4].Err)
+	require.Equal([]uint64{5, 6, 7, 7}, rc.nonces, "only executed transactions should advance the nonce")
+
+	// Submissions with an unknown outcome should stop the batch.
+	rc.nonces = nil
+	rc.failures[6] = errors.New("connection reset")
+	outcomes, err = SubmitVotes(ctx, rc, alice.Signer, alice.SigSpec, nil, batch(7, 6, 1), nil)
+	require.ErrorContains(err, "failed to submit vote on proposal 6")
+	require.Len(outcomes, 2, "outcomes so far should be returned")
+	require.NoError(outcomes[0].Err)
+	require.EqualValues(1, outcomes[0].Result.Tally[types.VoteYes])
+	require.Error(outcomes[1].Err)
+	require.Nil(outcomes[1].Result)
+	require.Equal([]uint64{8, 9}, rc.nonces)
+}
@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/features"
+)
+
+// FeatureWatchEvents is the experimental feature of watching accounts events (see WatchEvents).
+const FeatureWatchEvents features.Feature = "accounts.WatchEvents"
+
+func init() {
+	features.Register(FeatureWatchEvents, features.Spec{
+		Experimental: true,
+		Description:  "watch decoded accounts events of new blocks",
+	})
+}
+
+// RoundEvents are the accounts events emitted in a round.
+type RoundEvents struct {
+	// Round is the round the events were emitted in.
+	Round uint64
+	// Events are the emitted accounts events.
+	Events []*Event
+}
+
+// WatchEvents subscribes to new blocks and emits their accounts events. Rounds without accounts
+// events are skipped.
+//
+// The returned channel is closed when the context is cancelled or the underlying event watch
+// fails.
+//
+// This is an experimental feature which needs to be enabled first (see FeatureWatchEvents).
+func WatchEvents(ctx context.Context, rc client.RuntimeClient) (<-chan *RoundEvents, error) {
+	if err := features.Require(FeatureWatchEvents); err != nil {
+		return nil, err
+	}
+	evCh, err := rc.WatchEvents(ctx, []client.EventDecoder{NewV1(rc)}, false)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *RoundEvents)
+
+	go func() {
+		defer close(ch)
+
+		// Keep draining the underlying watch after cancellation so that it can terminate.
+		for bev := range evCh {
+			re := RoundEvents{Round: bev.Round}
+			for _, ev := range bev.Events {
+				if e, ok := ev.(*Event); ok {
+					re.Events = append(re.Events, e)
+				}
+			}
+			if len(re.Events) == 0 || ctx.Err() != nil {
+				continue
+			}
+			select {
+			case ch <- &re:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,56 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/features"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+)
+
+// eventsRuntimeClient serves the given decoded block events.
+type eventsRuntimeClient struct {
+	client.RuntimeClient
+
+	blocks []*client.BlockEvents
+}
+
+func (e *eventsRuntimeClient) WatchEvents(ctx context.Context, decoders []client.EventDecoder, includeUndecoded bool) (<-chan *client.BlockEvents, error) {
+	ch := make(chan *client.BlockEvents, len(e.blocks))
+	for _, blk := range e.blocks {
+		ch <- blk
+	}
+	close(ch)
+	return ch, nil
+}
+
+// otherEvent is a decoded event of another module.
+type otherEvent struct{}
+
+func TestWatchEvents(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	transfer := &Event{Transfer: &TransferEvent{From: sdkTesting.Alice.Address, To: sdkTesting.Bob.Address}}
+	rc := &eventsRuntimeClient{blocks: []*client.BlockEvents{
+		{Round: 1, Events: []client.DecodedEvent{transfer, &otherEvent{}}},
+		{Round: 2, Events: []client.DecodedEvent{&otherEvent{}}},
+		{Round: 3},
+	}}
+
+	_, err := WatchEvents(ctx, rc)
+	require.ErrorIs(err, features.ErrNotEnabled, "experimental features should need to be enabled")
+	require.NoError(features.EnableExperimental(FeatureWatchEvents))
+	defer features.DisableExperimental(FeatureWatchEvents)
+
+	ch, err := WatchEvents(ctx, rc)
+	require.NoError(err, "WatchEvents")
+	var rounds []*RoundEvents
+	for re := range ch {
+		rounds = append(rounds, re)
+	}
+	require.Equal([]*RoundEvents{{Round: 1, Events: []*Event{transfer}}}, rounds, "rounds without accounts events should be skipped")
+}
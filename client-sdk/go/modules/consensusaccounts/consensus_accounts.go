@@ -8,6 +8,7 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/consensus"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -43,6 +44,18 @@ type V1 interface {
 
 	// GetEvents returns all consensus accounts events emitted in a given block.
 	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
+
+	// ValidateWithdraw checks whether a withdrawal of the given amount from the given account into
+	// the given consensus account (the account itself if nil) would succeed in the consensus layer,
+	// returning a typed error (e.g. ErrUnderMinTransferAmount) in case it wouldn't.
+	ValidateWithdraw(
+		ctx context.Context,
+		cc *consensus.Client,
+		from types.Address,
+		to *types.Address,
+		amount types.BaseUnits,
+		opts ...ValidateOption,
+	) error
 }
 
 type v1 struct {
@@ -135,16 +148,16 @@ func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode consensus accounts deposit event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{Deposit: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{Deposit: ev, EventMeta: event.MetaAt(i)})
 		}
 	case WithdrawEventCode:
 		var evs []*WithdrawEvent
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode consensus accounts withdraw event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{Withdraw: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{Withdraw: ev, EventMeta: event.MetaAt(i)})
 		}
 	default:
 		return nil, fmt.Errorf("invalid consensus accounts event code: %v", event.Code)
@@ -92,4 +92,6 @@ func (we *WithdrawEvent) IsSuccess() bool {
 type Event struct {
 	Deposit  *DepositEvent
 	Withdraw *WithdrawEvent
+
+	types.EventMeta
 }
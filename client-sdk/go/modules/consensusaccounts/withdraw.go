@@ -0,0 +1,161 @@
+package consensusaccounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/consensus"
+	consensusModule "github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensus"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Errors returned by ValidateWithdraw. Withdrawals failing these checks would otherwise only fail
+// asynchronously, as reported by the Error field of the corresponding WithdrawEvent.
+var (
+	// ErrInvalidDenomination is the error returned when the withdrawn denomination is not the
+	// consensus denomination.
+	ErrInvalidDenomination = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: invalid denomination")
+	// ErrAmountNotRepresentable is the error returned when the withdrawn amount can't be
+	// represented in the consensus layer due to the consensus scaling factor.
+	ErrAmountNotRepresentable = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: amount not representable")
+	// ErrInsufficientBalance is the error returned when the withdrawing account lacks the amount.
+	ErrInsufficientBalance = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: insufficient balance")
+	// ErrUnderMinTransferAmount is the error returned when the amount is lower than the minimum
+	// transfer amount of the consensus layer.
+	ErrUnderMinTransferAmount = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: amount lower than the minimum transfer amount")
+	// ErrTransfersDisabled is the error returned when the consensus layer doesn't permit transfers
+	// from the runtime.
+	ErrTransfersDisabled = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: consensus transfers disabled")
+	// ErrReservedDestination is the error returned when the destination is a reserved consensus
+	// address.
+	ErrReservedDestination = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: reserved destination address")
+	// ErrDestinationNotFound is the error returned when the destination consensus account doesn't
+	// exist and RequireExistingDestination was given.
+	ErrDestinationNotFound = client.NewClassifiedError(client.ErrNotFound, "consensusaccounts: destination account not found")
+	// ErrInsufficientRuntimeBalance is the error returned when the consensus account of the runtime
+	// lacks the amount.
+	ErrInsufficientRuntimeBalance = client.NewClassifiedError(client.ErrPermanent, "consensusaccounts: insufficient runtime consensus balance")
+)
+
+// ValidateOption is an option for ValidateWithdraw.
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	requireExistingDestination bool
+}
+
+// RequireExistingDestination makes ValidateWithdraw reject withdrawals into consensus accounts
+// that don't exist yet, e.g. to catch mistyped destination addresses.
+func RequireExistingDestination() ValidateOption {
+	return func(o *validateOptions) {
+		o.requireExistingDestination = true
+	}
+}
+
+// Implements V1.
+func (a *v1) ValidateWithdraw(
+	ctx context.Context,
+	cc *consensus.Client,
+	from types.Address,
+	to *types.Address,
+	amount types.BaseUnits,
+	opts ...ValidateOption,
+) error {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if to == nil {
+		to = &from
+	}
+
+	// Pin all runtime queries to the same round.
+	blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("consensusaccounts: failed to fetch latest block: %w", err)
+	}
+	round := blk.Header.Round
+
+	params, err := consensusModule.NewV1(a.rc).Parameters(ctx, round)
+	if err != nil {
+		return fmt.Errorf("consensusaccounts: failed to query consensus parameters: %w", err)
+	}
+	if amount.Denomination != params.ConsensusDenomination {
+		return fmt.Errorf("%w: %s (expected: %s)", ErrInvalidDenomination, amount.Denomination, params.ConsensusDenomination)
+	}
+	consensusAmount, err := scaleToConsensus(&amount.Amount, params.ConsensusScalingFactor)
+	if err != nil {
+		return err
+	}
+
+	balance, err := a.Balance(ctx, round, &BalanceQuery{Address: from})
+	if err != nil {
+		return fmt.Errorf("consensusaccounts: failed to query balance: %w", err)
+	}
+	if balance.Balance.Cmp(&amount.Amount) < 0 {
+		return fmt.Errorf("%w: %s (balance: %s)", ErrInsufficientBalance, amount.Amount, balance.Balance)
+	}
+
+	stakingParams, err := cc.Backend().Staking().ConsensusParameters(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("consensusaccounts: failed to query staking parameters: %w", err)
+	}
+	if consensusAmount.Cmp(&stakingParams.MinTransferAmount) < 0 {
+		return fmt.Errorf("%w: %s (minimum: %s)", ErrUnderMinTransferAmount, consensusAmount, stakingParams.MinTransferAmount)
+	}
+
+	// The withdrawal is a transfer from the consensus account of the runtime.
+	info, err := a.rc.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("consensusaccounts: failed to query runtime info: %w", err)
+	}
+	runtimeAddr := staking.NewRuntimeAddress(info.ID)
+	if stakingParams.DisableTransfers && !stakingParams.UndisableTransfersFrom[runtimeAddr] {
+		return ErrTransfersDisabled
+	}
+
+	if to.ConsensusAddress().IsReserved() {
+		return fmt.Errorf("%w: %s", ErrReservedDestination, to)
+	}
+	if o.requireExistingDestination {
+		dst, err := cc.Account(ctx, consensus.HeightLatest, *to)
+		if err != nil {
+			return err
+		}
+		if dst.General.Nonce == 0 && dst.General.Balance.IsZero() &&
+			dst.Escrow.Active.Balance.IsZero() && dst.Escrow.Debonding.Balance.IsZero() {
+			return fmt.Errorf("%w: %s", ErrDestinationNotFound, to)
+		}
+	}
+
+	runtimeBalance, err := cc.Balance(ctx, consensus.HeightLatest, types.NewAddressFromConsensus(runtimeAddr))
+	if err != nil {
+		return err
+	}
+	if runtimeBalance.Cmp(consensusAmount) < 0 {
+		return fmt.Errorf("%w: %s (balance: %s)", ErrInsufficientRuntimeBalance, consensusAmount, runtimeBalance)
+	}
+
+	return nil
+}
+
+// scaleToConsensus converts the given runtime amount into a consensus amount.
+func scaleToConsensus(amount *types.Quantity, scalingFactor uint64) (*types.Quantity, error) {
+	if scalingFactor == 0 {
+		scalingFactor = 1
+	}
+	var scaled, rem big.Int
+	scaled.QuoRem(amount.ToBigInt(), new(big.Int).SetUint64(scalingFactor), &rem)
+	if rem.Sign() != 0 {
+		return nil, fmt.Errorf("%w: %s (scaling factor: %d)", ErrAmountNotRepresentable, amount, scalingFactor)
+	}
+	var q types.Quantity
+	if err := q.FromBigInt(&scaled); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
@@ -0,0 +1,140 @@
+package consensusaccounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	coreConsensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/consensus"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var testRuntimeID = common.NewTestNamespaceFromSeed([]byte("consensusaccounts test"), 0)
+
+// withdrawRuntimeClient serves the runtime queries made by ValidateWithdraw.
+type withdrawRuntimeClient struct {
+	client.RuntimeClient
+
+	balance uint64
+}
+
+func (w *withdrawRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = 42
+	return &blk, nil
+}
+
+func (w *withdrawRuntimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ID: testRuntimeID}, nil
+}
+
+func (w *withdrawRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	if round != 42 {
+		return fmt.Errorf("unexpected round: %d", round)
+	}
+	var result interface{}
+	switch method {
+	case "consensus.Parameters":
+		result = map[string]interface{}{
+			"consensus_denomination":   types.Denomination("TEST"),
+			"consensus_scaling_factor": uint64(1000),
+		}
+	case methodBalance:
+		result = AccountBalance{Balance: *quantity.NewFromUint64(w.balance)}
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return cbor.Unmarshal(cbor.Marshal(result), rsp)
+}
+
+type withdrawConsensus struct {
+	coreConsensus.ClientBackend
+
+	params   staking.ConsensusParameters
+	accounts map[staking.Address]*staking.Account
+}
+
+func (w *withdrawConsensus) Staking() staking.Backend {
+	return &withdrawStaking{w: w}
+}
+
+type withdrawStaking struct {
+	staking.Backend
+
+	w *withdrawConsensus
+}
+
+func (s *withdrawStaking) ConsensusParameters(ctx context.Context, height int64) (*staking.ConsensusParameters, error) {
+	return &s.w.params, nil
+}
+
+func (s *withdrawStaking) Account(ctx context.Context, query *staking.OwnerQuery) (*staking.Account, error) {
+	if acct, ok := s.w.accounts[query.Owner]; ok {
+		return acct, nil
+	}
+	return &staking.Account{}, nil
+}
+
+func TestValidateWithdraw(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob := sdkTesting.Alice.Address, sdkTesting.Bob.Address
+	runtimeAddr := staking.NewRuntimeAddress(testRuntimeID)
+	runtimeAcct := &staking.Account{}
+	runtimeAcct.General.Balance = *quantity.NewFromUint64(50)
+	backend := &withdrawConsensus{
+		params:   staking.ConsensusParameters{MinTransferAmount: *quantity.NewFromUint64(10)},
+		accounts: map[staking.Address]*staking.Account{runtimeAddr: runtimeAcct},
+	}
+	cc := consensus.New(backend)
+	rc := &withdrawRuntimeClient{balance: 100_000}
+	a := NewV1(rc)
+	ctx := context.Background()
+
+	amount := func(v uint64, denom types.Denomination) types.BaseUnits {
+		return types.NewBaseUnits(*quantity.NewFromUint64(v), denom)
+	}
+
+	require.NoError(a.ValidateWithdraw(ctx, cc, alice, &bob, amount(20_000, "TEST")))
+	require.NoError(a.ValidateWithdraw(ctx, cc, alice, nil, amount(20_000, "TEST")))
+
+	for _, tc := range []struct {
+		to     *types.Address
+		amount types.BaseUnits
+		opts   []ValidateOption
+		err    error
+	}{
+		{&bob, amount(20_000, types.NativeDenomination), nil, ErrInvalidDenomination},
+		{&bob, amount(20_500, "TEST"), nil, ErrAmountNotRepresentable},
+		{&bob, amount(200_000, "TEST"), nil, ErrInsufficientBalance},
+		{&bob, amount(5_000, "TEST"), nil, ErrUnderMinTransferAmount},
+		{&bob, amount(60_000, "TEST"), nil, ErrInsufficientRuntimeBalance},
+		{&bob, amount(20_000, "TEST"), []ValidateOption{RequireExistingDestination()}, ErrDestinationNotFound},
+	} {
+		err := a.ValidateWithdraw(ctx, cc, alice, tc.to, tc.amount, tc.opts...)
+		require.ErrorIs(err, tc.err, "amount %s", tc.amount)
+	}
+
+	// Existing destination.
+	bobAcct := &staking.Account{}
+	bobAcct.General.Nonce = 1
+	backend.accounts[bob.ConsensusAddress()] = bobAcct
+	require.NoError(a.ValidateWithdraw(ctx, cc, alice, &bob, amount(20_000, "TEST"), RequireExistingDestination()))
+
+	// Transfers disabled unless the runtime is exempt.
+	backend.params.DisableTransfers = true
+	require.ErrorIs(a.ValidateWithdraw(ctx, cc, alice, &bob, amount(20_000, "TEST")), ErrTransfersDisabled)
+	backend.params.UndisableTransfersFrom = map[staking.Address]bool{runtimeAddr: true}
+	require.NoError(a.ValidateWithdraw(ctx, cc, alice, &bob, amount(20_000, "TEST")))
+}
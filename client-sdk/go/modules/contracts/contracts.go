@@ -314,6 +314,7 @@ func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
 	}
 	events := make([]client.DecodedEvent, len(evs))
 	for i, ev := range evs {
+		ev.EventMeta = event.MetaAt(i)
 		events[i] = ev
 	}
 	return events, nil
@@ -151,7 +151,7 @@ func (ed *eventDecoder) DecodeEvent(event *types.Event) ([]client.DecodedEvent,
 	}
 
 	var events []client.DecodedEvent
-	for _, contractEvent := range contractEvents {
+	for i, contractEvent := range contractEvents {
 		if contractEvent.ID != ed.instanceID {
 			return nil, nil
 		}
@@ -162,43 +162,43 @@ func (ed *eventDecoder) DecodeEvent(event *types.Event) ([]client.DecodedEvent,
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 instantiated event value: %w", err)
 			}
-			events = append(events, &Event{Instantiated: &ev})
+			events = append(events, &Event{Instantiated: &ev, EventMeta: event.MetaAt(i)})
 		case TransferredEventCode:
 			var ev TransferredEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 transferred event value: %w", err)
 			}
-			events = append(events, &Event{Transferred: &ev})
+			events = append(events, &Event{Transferred: &ev, EventMeta: event.MetaAt(i)})
 		case SentEventCode:
 			var ev SentEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 sent event value: %w", err)
 			}
-			events = append(events, &Event{Sent: &ev})
+			events = append(events, &Event{Sent: &ev, EventMeta: event.MetaAt(i)})
 		case BurnedEventCode:
 			var ev BurnedEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 burned event value: %w", err)
 			}
-			events = append(events, &Event{Burned: &ev})
+			events = append(events, &Event{Burned: &ev, EventMeta: event.MetaAt(i)})
 		case AllowanceChangedEventCode:
 			var ev AllowanceChangedEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 allowance changed event value: %w", err)
 			}
-			events = append(events, &Event{AllowanceChanged: &ev})
+			events = append(events, &Event{AllowanceChanged: &ev, EventMeta: event.MetaAt(i)})
 		case WithdrewEventCode:
 			var ev WithdrewEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 withdew event value: %w", err)
 			}
-			events = append(events, &Event{Withdrew: &ev})
+			events = append(events, &Event{Withdrew: &ev, EventMeta: event.MetaAt(i)})
 		case MintedEventCode:
 			var ev MintedEvent
 			if err := cbor.Unmarshal(contractEvent.Data, &ev); err != nil {
 				return nil, fmt.Errorf("decode OAS20 minted event value: %w", err)
 			}
-			events = append(events, &Event{Minted: &ev})
+			events = append(events, &Event{Minted: &ev, EventMeta: event.MetaAt(i)})
 		default:
 			return nil, fmt.Errorf("invalid OAS20 event code: %v", event.Code)
 		}
@@ -279,4 +279,6 @@ type Event struct {
 	AllowanceChanged *AllowanceChangedEvent `json:"allowance_changed,omitempty"`
 	Withdrew         *WithdrewEvent         `json:"withdrew,omitempty"`
 	Minted           *MintedEvent           `json:"minted,omitempty"`
+
+	types.EventMeta
 }
@@ -353,4 +353,6 @@ type Event struct {
 	ID InstanceID `json:"id"`
 	// Data is the cbor serialized event data.
 	Data []byte `json:"data,omitempty"`
+
+	types.EventMeta `cbor:"-"`
 }
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// invalidMethodErrorCode is the core module error code returned for unknown methods.
+const invalidMethodErrorCode = 3
+
+// ErrUnsupported is the error returned when the runtime does not support a given method.
+var ErrUnsupported = client.NewClassifiedError(client.ErrPermanent, "core: not supported by runtime")
+
+// Capabilities are the capabilities of a runtime deployment.
+//
+// They allow a single client build to talk to both older and newer runtime deployments and skip
+// features that a given deployment does not support instead of failing on unknown methods.
+type Capabilities struct {
+	info    *RuntimeInfoResponse
+	methods map[string]MethodHandlerInfo
+}
+
+// NewCapabilities derives capabilities from the given runtime introspection information. Passing
+// nil results in unknown capabilities (see Known).
+func NewCapabilities(info *RuntimeInfoResponse) *Capabilities {
+	caps := Capabilities{info: info}
+	if info == nil {
+		return &caps
+	}
+
+	caps.methods = make(map[string]MethodHandlerInfo)
+	for _, mi := range info.Modules {
+		for _, mh := range mi.Methods {
+			caps.methods[mh.Name] = mh
+		}
+	}
+	return &caps
+}
+
+// DiscoverCapabilities discovers the capabilities of the runtime.
+//
+// Runtimes which predate the core.RuntimeInfo query result in unknown capabilities.
+func DiscoverCapabilities(ctx context.Context, c V1) (*Capabilities, error) {
+	info, err := c.RuntimeInfo(ctx)
+	switch {
+	case err == nil:
+		return NewCapabilities(info), nil
+	case isInvalidMethod(err):
+		return NewCapabilities(nil), nil
+	default:
+		return nil, fmt.Errorf("failed to discover runtime capabilities: %w", err)
+	}
+}
+
+func isInvalidMethod(err error) bool {
+	module, code, ok := client.ErrorCode(err)
+	return ok && module == ModuleName && code == invalidMethodErrorCode
+}
+
+// Known returns true iff the capabilities of the runtime are known. In case they are not, all
+// methods are assumed to be supported.
+func (c *Capabilities) Known() bool {
+	return c.info != nil
+}
+
+// Info returns the runtime introspection information (if known).
+func (c *Capabilities) Info() *RuntimeInfoResponse {
+	return c.info
+}
+
+// ModuleVersion returns the version of the given module.
+func (c *Capabilities) ModuleVersion(module string) (uint32, bool) {
+	if c.info == nil {
+		return 0, false
+	}
+	mi, ok := c.info.Modules[module]
+	return mi.Version, ok
+}
+
+// SupportsMethod checks whether the runtime supports the given method (e.g. "accounts.Quorum").
+func (c *Capabilities) SupportsMethod(method string) bool {
+	if c.info == nil {
+		return true
+	}
+	_, ok := c.methods[method]
+	return ok
+}
+
+// CheckMethod returns ErrUnsupported in case the runtime does not support the given method.
+func (c *Capabilities) CheckMethod(method string) error {
+	if !c.SupportsMethod(method) {
+		return fmt.Errorf("%w: %s", ErrUnsupported, method)
+	}
+	return nil
+}
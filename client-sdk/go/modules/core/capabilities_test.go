@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+func TestCapabilities(t *testing.T) {
+	require := require.New(t)
+
+	caps := NewCapabilities(&RuntimeInfoResponse{
+		Modules: map[string]ModuleInfo{
+			"accounts": {
+				Version: 2,
+				Methods: []MethodHandlerInfo{
+					{Name: "accounts.Transfer", Kind: MethodHandlerKindCall},
+					{Name: "accounts.Quorum", Kind: MethodHandlerKindQuery},
+				},
+			},
+		},
+	})
+	require.True(caps.Known())
+	require.True(caps.SupportsMethod("accounts.Quorum"))
+	require.False(caps.SupportsMethod("accounts.MintST"))
+	require.NoError(caps.CheckMethod("accounts.Transfer"))
+	require.True(errors.Is(caps.CheckMethod("accounts.MintST"), ErrUnsupported))
+	require.True(errors.Is(caps.CheckMethod("accounts.MintST"), client.ErrPermanent))
+	version, ok := caps.ModuleVersion("accounts")
+	require.True(ok)
+	require.EqualValues(2, version)
+	_, ok = caps.ModuleVersion("evm")
+	require.False(ok)
+
+	unknown := NewCapabilities(nil)
+	require.False(unknown.Known())
+	require.True(unknown.SupportsMethod("accounts.MintST"), "unknown capabilities should assume support")
+}
@@ -3,8 +3,10 @@ package core
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
@@ -33,11 +35,21 @@ type V1 interface {
 	EstimateGasForCaller(ctx context.Context, round uint64, caller types.CallerAddress, tx *types.Transaction, propagateFailures bool) (uint64, error)
 
 	// MinGasPrice returns the minimum gas price.
-	MinGasPrice(ctx context.Context) (map[types.Denomination]types.Quantity, error)
+	MinGasPrice(ctx context.Context, round uint64) (map[types.Denomination]types.Quantity, error)
+
+	// SuggestPriorityTip suggests a per-gas priority tip (see types.Fee.AddPriorityTip) based on
+	// the gas prices paid by transactions in the given number of most recent blocks. The tip is
+	// chosen so that the resulting gas price is at the given percentile (0-100) of recent gas
+	// prices, and is zero when recent blocks were not congested.
+	SuggestPriorityTip(ctx context.Context, denomination types.Denomination, blocks uint64, percentile uint8) (*types.Quantity, error)
 
 	// GetEvents returns all core events emitted in a given block.
 	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
 
+	// BlockSummary returns the number of transactions, the number of events per module and the
+	// total gas used in the given block, without decoding the transactions and events.
+	BlockSummary(ctx context.Context, round uint64) (*BlockSummary, error)
+
 	// RuntimeInfo returns basic info about the module and the containing runtime.
 	RuntimeInfo(ctx context.Context) (*RuntimeInfoResponse, error)
 
@@ -88,15 +100,73 @@ func (a *v1) EstimateGasForCaller(ctx context.Context, round uint64, caller type
 }
 
 // Implements V1.
-func (a *v1) MinGasPrice(ctx context.Context) (map[types.Denomination]types.Quantity, error) {
+func (a *v1) MinGasPrice(ctx context.Context, round uint64) (map[types.Denomination]types.Quantity, error) {
 	var mgp map[types.Denomination]types.Quantity
-	err := a.rc.Query(ctx, client.RoundLatest, methodMinGasPrice, nil, &mgp)
+	err := a.rc.Query(ctx, round, methodMinGasPrice, nil, &mgp)
 	if err != nil {
 		return nil, err
 	}
 	return mgp, nil
 }
 
+// Implements V1.
+func (a *v1) SuggestPriorityTip(ctx context.Context, denomination types.Denomination, blocks uint64, percentile uint8) (*types.Quantity, error) {
+	if percentile > 100 {
+		return nil, fmt.Errorf("invalid percentile: %d", percentile)
+	}
+
+	blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest block: %w", err)
+	}
+
+	mgp, err := a.MinGasPrice(ctx, blk.Header.Round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query minimum gas price: %w", err)
+	}
+	minPrice := mgp[denomination]
+
+	var prices []*types.Quantity
+	for i := uint64(0); i < blocks && i <= blk.Header.Round; i++ {
+		txs, err := a.rc.GetTransactions(ctx, blk.Header.Round-i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query transactions: %w", err)
+		}
+		for _, utx := range txs {
+			var tx types.Transaction
+			if err = cbor.Unmarshal(utx.Body, &tx); err != nil {
+				// Skip malformed transactions.
+				continue
+			}
+			fee := tx.AuthInfo.Fee
+			if fee.Amount.Denomination != denomination {
+				continue
+			}
+			prices = append(prices, fee.GasPrice())
+		}
+	}
+	return priorityTip(prices, &minPrice, percentile), nil
+}
+
+// priorityTip returns the tip over the minimum price needed to reach the given percentile of the
+// given prices.
+func priorityTip(prices []*types.Quantity, minPrice *types.Quantity, percentile uint8) *types.Quantity {
+	if len(prices) == 0 {
+		return quantity.NewQuantity()
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+	idx := (len(prices) - 1) * int(percentile) / 100
+	target := prices[idx]
+	if target.Cmp(minPrice) <= 0 {
+		return quantity.NewQuantity()
+	}
+	tip := target.Clone()
+	_ = tip.Sub(minPrice)
+	return tip
+}
+
 // Implements V1.
 func (a *v1) GetEvents(ctx context.Context, round uint64) ([]*Event, error) {
 	rawEvs, err := a.rc.GetEventsRaw(ctx, round)
@@ -138,8 +208,8 @@ func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
 		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
 			return nil, fmt.Errorf("decode core gas used event value: %w", err)
 		}
-		for _, ev := range evs {
-			events = append(events, &Event{GasUsed: ev})
+		for i, ev := range evs {
+			events = append(events, &Event{GasUsed: ev, EventMeta: event.MetaAt(i)})
 		}
 	default:
 		return nil, fmt.Errorf("invalid core event code: %v", event.Code)
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestPriorityTip(t *testing.T) {
+	require := require.New(t)
+
+	prices := func(ps ...uint64) []*types.Quantity {
+		var qs []*types.Quantity
+		for _, p := range ps {
+			qs = append(qs, quantity.NewFromUint64(p))
+		}
+		return qs
+	}
+	minPrice := quantity.NewFromUint64(10)
+
+	require.EqualValues(quantity.NewQuantity(), priorityTip(nil, minPrice, 90), "no transactions")
+	require.EqualValues(quantity.NewQuantity(), priorityTip(prices(10, 10, 10), minPrice, 90), "no congestion")
+	require.EqualValues(quantity.NewFromUint64(40), priorityTip(prices(50, 10, 30, 20, 40), minPrice, 100))
+	require.EqualValues(quantity.NewFromUint64(20), priorityTip(prices(50, 10, 30, 20, 40), minPrice, 50))
+	require.EqualValues(quantity.NewQuantity(), priorityTip(prices(50, 10, 30, 20, 40), minPrice, 0))
+}
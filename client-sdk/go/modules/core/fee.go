@@ -0,0 +1,32 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrNotGasDenomination is the error returned when a fee is to be paid in a denomination that
+// the runtime does not accept for paying gas.
+var ErrNotGasDenomination = client.NewClassifiedError(client.ErrPermanent, "core: denomination cannot be used to pay for gas")
+
+// FeeForGas computes the minimum fee for the given amount of gas paid in the given denomination,
+// based on the minimum gas prices as returned by MinGasPrice.
+//
+// Returns ErrNotGasDenomination in case the runtime does not accept the denomination for paying
+// gas, e.g. because it is a stable-token denomination.
+func FeeForGas(minGasPrice map[types.Denomination]types.Quantity, denomination types.Denomination, gas uint64) (*types.BaseUnits, error) {
+	price, ok := minGasPrice[denomination]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotGasDenomination, denomination)
+	}
+	amount := price.Clone()
+	if err := amount.Mul(quantity.NewFromUint64(gas)); err != nil {
+		return nil, err
+	}
+	fee := types.NewBaseUnits(*amount, denomination)
+	return &fee, nil
+}
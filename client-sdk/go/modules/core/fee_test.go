@@ -0,0 +1,28 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestFeeForGas(t *testing.T) {
+	require := require.New(t)
+
+	minGasPrice := map[types.Denomination]types.Quantity{
+		types.GasDenomination: *quantity.NewFromUint64(5),
+	}
+
+	fee, err := FeeForGas(minGasPrice, types.GasDenomination, 1000)
+	require.NoError(err, "FeeForGas")
+	require.EqualValues(quantity.NewFromUint64(5000), &fee.Amount)
+	require.True(fee.Denomination.IsNative())
+
+	_, err = FeeForGas(minGasPrice, types.Denomination("HLUSD"), 1000)
+	require.True(errors.Is(err, ErrNotGasDenomination), "stable-token denominations should not pay gas")
+}
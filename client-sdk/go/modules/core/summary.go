@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// summaryEventChunkSize is the number of events processed at once when summarizing a block.
+const summaryEventChunkSize = 1024
+
+// BlockSummary is a summary of a runtime block.
+type BlockSummary struct {
+	// Round is the round of the block.
+	Round uint64 `json:"round"`
+	// Transactions is the number of transactions in the block.
+	Transactions int `json:"transactions"`
+	// Events is the number of events emitted in the block per module.
+	Events map[string]int `json:"events"`
+	// GasUsed is the total amount of gas used by the transactions in the block.
+	GasUsed uint64 `json:"gas_used"`
+}
+
+// TotalEvents returns the total number of events emitted in the block.
+func (s *BlockSummary) TotalEvents() int {
+	var total int
+	for _, n := range s.Events {
+		total += n
+	}
+	return total
+}
+
+// add accounts for the given raw events. Each raw event holds an array of events with the same
+// tag, which are counted individually.
+func (s *BlockSummary) add(evs []*types.Event) error {
+	for _, ev := range evs {
+		if ev.Module == ModuleName && ev.Code == GasUsedEventCode {
+			var gasUsed []*GasUsedEvent
+			if err := cbor.Unmarshal(ev.Value, &gasUsed); err != nil {
+				return fmt.Errorf("decode core gas used event value: %w", err)
+			}
+			for _, gu := range gasUsed {
+				s.GasUsed += gu.Amount
+			}
+			s.Events[ev.Module] += len(gasUsed)
+			continue
+		}
+		var values []cbor.RawMessage
+		if err := cbor.Unmarshal(ev.Value, &values); err != nil {
+			return fmt.Errorf("decode %s event value: %w", ev.Module, err)
+		}
+		s.Events[ev.Module] += len(values)
+	}
+	return nil
+}
+
+// Implements V1.
+func (a *v1) BlockSummary(ctx context.Context, round uint64) (*BlockSummary, error) {
+	// Resolve the round first so that all data comes from the same block.
+	blk, err := a.rc.GetBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block: %w", err)
+	}
+	round = blk.Header.Round
+
+	txs, err := a.rc.GetTransactions(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	summary := BlockSummary{
+		Round:        round,
+		Transactions: len(txs),
+		Events:       make(map[string]int),
+	}
+	if err = a.rc.StreamEventsRaw(ctx, round, summaryEventChunkSize, summary.add); err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	return &summary, nil
+}
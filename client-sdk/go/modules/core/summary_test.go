@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestBlockSummary(t *testing.T) {
+	require := require.New(t)
+
+	summary := BlockSummary{Events: make(map[string]int)}
+	require.NoError(summary.add([]*types.Event{
+		{Module: "accounts", Code: 1, Value: cbor.Marshal([]string{"a", "b"})},
+		{Module: ModuleName, Code: GasUsedEventCode, Value: cbor.Marshal([]*GasUsedEvent{{Amount: 100}, {Amount: 20}})},
+		{Module: "accounts", Code: 2, Value: cbor.Marshal([]string{"c"})},
+	}))
+	require.NoError(summary.add([]*types.Event{
+		{Module: ModuleName, Code: GasUsedEventCode, Value: cbor.Marshal([]*GasUsedEvent{{Amount: 3}})},
+	}))
+	require.EqualValues(123, summary.GasUsed)
+	require.Equal(map[string]int{"accounts": 3, ModuleName: 3}, summary.Events, "all events of each raw event should be counted")
+	require.Equal(6, summary.TotalEvents())
+
+	err := summary.add([]*types.Event{{Module: ModuleName, Code: GasUsedEventCode, Value: []byte{0xff}}})
+	require.Error(err, "malformed gas used events should be rejected")
+	err = summary.add([]*types.Event{{Module: "accounts", Code: 1, Value: []byte{0xff}}})
+	require.Error(err, "malformed events should be rejected")
+}
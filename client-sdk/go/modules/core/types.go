@@ -57,6 +57,8 @@ type GasUsedEvent struct {
 // Event is a core module event.
 type Event struct {
 	GasUsed *GasUsedEvent
+
+	types.EventMeta
 }
 
 // RuntimeInfoResponse is the response of the core.RuntimeInfo query
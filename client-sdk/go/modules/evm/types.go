@@ -1,5 +1,7 @@
 package evm
 
+import "github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
 // The types in this file must match the types from the evm module types
 // in runtime-sdk/modules/evm/src/types.rs.
 
@@ -58,4 +60,6 @@ type Event struct {
 	Address []byte   `json:"address"`
 	Topics  [][]byte `json:"topics"`
 	Data    []byte   `json:"data"`
+
+	types.EventMeta `cbor:"-"`
 }
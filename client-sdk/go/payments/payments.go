@@ -0,0 +1,197 @@
+// Package payments defines signed payment requests (invoices), so that merchants and wallets
+// built on the SDK interoperate on a single format.
+//
+// A merchant creates a PaymentRequest, signs it and hands out its string encoding, e.g. as a
+// deep link or QR code. The encoding only uses characters of the QR code alphanumeric mode, which
+// keeps QR codes small. A wallet decodes the string, verifies the signature and expiry and pays the
+// requested amount to the recipient, e.g. with an accounts.Transfer.
+package payments
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+const (
+	// LatestRequestVersion is the latest payment request format version.
+	LatestRequestVersion = 1
+
+	// URIScheme is the scheme of encoded payment requests.
+	URIScheme = "HELAPAY"
+
+	// MaxMemoSize is the maximum size of the memo in bytes.
+	MaxMemoSize = 256
+)
+
+var (
+	// ErrExpired is the error returned when verifying an expired payment request.
+	ErrExpired = errors.New("payments: payment request expired")
+	// ErrInvalidSignature is the error returned when the signature of a payment request is invalid.
+	ErrInvalidSignature = errors.New("payments: invalid signature")
+	// ErrMalformed is the error returned when decoding a malformed payment request.
+	ErrMalformed = errors.New("payments: malformed payment request")
+)
+
+// encoding is the encoding of signed payment requests, which only uses characters of the QR code
+// alphanumeric mode.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// PaymentRequest is a request to pay an amount to a recipient.
+type PaymentRequest struct {
+	cbor.Versioned
+
+	// Recipient is the address the payment should be sent to.
+	Recipient types.Address `json:"recipient"`
+	// Amount is the requested amount, including its denomination.
+	Amount types.BaseUnits `json:"amount"`
+	// Memo is a free-form description of the payment, e.g. an invoice number.
+	Memo string `json:"memo,omitempty"`
+	// Expiry is the POSIX time after which the request must no longer be paid (zero if the request
+	// doesn't expire).
+	Expiry uint64 `json:"expiry,omitempty"`
+}
+
+// NewPaymentRequest creates a new payment request. A zero expiry creates a request that doesn't
+// expire.
+func NewPaymentRequest(recipient types.Address, amount types.BaseUnits, memo string, expiry time.Time) *PaymentRequest {
+	pr := &PaymentRequest{
+		Versioned: cbor.NewVersioned(LatestRequestVersion),
+		Recipient: recipient,
+		Amount:    amount,
+		Memo:      memo,
+	}
+	if !expiry.IsZero() {
+		pr.Expiry = uint64(expiry.Unix())
+	}
+	return pr
+}
+
+// ValidateBasic performs basic validation on the payment request.
+func (pr *PaymentRequest) ValidateBasic() error {
+	if pr.V != LatestRequestVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrMalformed, pr.V)
+	}
+	if pr.Amount.Amount.IsZero() {
+		return fmt.Errorf("%w: zero amount", ErrMalformed)
+	}
+	if len(pr.Memo) > MaxMemoSize {
+		return fmt.Errorf("%w: memo exceeds %d bytes", ErrMalformed, MaxMemoSize)
+	}
+	if !utf8.ValidString(pr.Memo) {
+		return fmt.Errorf("%w: memo is not valid UTF-8", ErrMalformed)
+	}
+	return nil
+}
+
+// Expired checks whether the payment request is expired at the given time.
+func (pr *PaymentRequest) Expired(now time.Time) bool {
+	return pr.Expiry != 0 && uint64(now.Unix()) > pr.Expiry
+}
+
+// Sign signs the payment request for the given chain domain separation context.
+func (pr *PaymentRequest) Sign(ctx signature.Context, signer signature.Signer) (*SignedPaymentRequest, error) {
+	if err := pr.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	body := cbor.Marshal(pr)
+	sig, err := signer.ContextSign(ctx.For(signature.ContextKindPaymentRequest), body)
+	if err != nil {
+		return nil, fmt.Errorf("payments: failed to sign payment request: %w", err)
+	}
+	return &SignedPaymentRequest{
+		Body:      body,
+		PublicKey: types.PublicKey{PublicKey: signer.Public()},
+		Signature: sig,
+	}, nil
+}
+
+// SignedPaymentRequest is a signed payment request.
+type SignedPaymentRequest struct {
+	// Body is the CBOR-encoded PaymentRequest.
+	Body []byte `json:"body"`
+	// PublicKey is the public key of the signer, e.g. the merchant.
+	PublicKey types.PublicKey `json:"public_key"`
+	// Signature is the signature over the body.
+	Signature []byte `json:"signature"`
+}
+
+// String returns the compact string encoding of the signed payment request, which can be used as
+// a deep link or QR code payload.
+func (sr *SignedPaymentRequest) String() string {
+	return URIScheme + ":" + encoding.EncodeToString(cbor.Marshal(sr))
+}
+
+// Decode decodes a signed payment request previously encoded using String. Decoding is case
+// insensitive.
+func Decode(s string) (*SignedPaymentRequest, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(s, URIScheme+":") {
+		return nil, fmt.Errorf("%w: missing %s scheme", ErrMalformed, URIScheme)
+	}
+	raw, err := encoding.DecodeString(strings.TrimPrefix(s, URIScheme+":"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	var sr SignedPaymentRequest
+	if err = cbor.Unmarshal(raw, &sr); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	return &sr, nil
+}
+
+// Open verifies the signature of the signed payment request for the given chain domain
+// separation context and returns the payment request. It doesn't check the expiry, see Verify.
+//
+// The caller is responsible for checking that the signer is trusted.
+func (sr *SignedPaymentRequest) Open(ctx signature.Context) (*PaymentRequest, error) {
+	if sr.PublicKey.PublicKey == nil {
+		return nil, fmt.Errorf("%w: missing public key", ErrMalformed)
+	}
+	if !sr.PublicKey.Verify(ctx.For(signature.ContextKindPaymentRequest), sr.Body, sr.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var pr PaymentRequest
+	if err := cbor.Unmarshal(sr.Body, &pr); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if err := pr.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// Verify opens the signed payment request (see Open) and checks that it is not expired at the
+// given time.
+func (sr *SignedPaymentRequest) Verify(ctx signature.Context, now time.Time) (*PaymentRequest, error) {
+	pr, err := sr.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Expired(now) {
+		return nil, fmt.Errorf("%w: at %s", ErrExpired, time.Unix(int64(pr.Expiry), 0).UTC())
+	}
+	return pr, nil
+}
+
+// Parse decodes and verifies an encoded signed payment request.
+func Parse(ctx signature.Context, s string, now time.Time) (*PaymentRequest, *SignedPaymentRequest, error) {
+	sr, err := Decode(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	pr, err := sr.Verify(ctx, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, sr, nil
+}
@@ -0,0 +1,62 @@
+package payments
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestPaymentRequest(t *testing.T) {
+	require := require.New(t)
+
+	chainCtx := signature.Context("test chain")
+	now := time.Unix(1_700_000_000, 0)
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(1500), types.Denomination("HLUSD"))
+	pr := NewPaymentRequest(sdkTesting.Bob.Address, amount, "invoice 42", now.Add(time.Hour))
+
+	sr, err := pr.Sign(chainCtx, sdkTesting.Bob.Signer)
+	require.NoError(err, "Sign")
+
+	encoded := sr.String()
+	require.Regexp(regexp.MustCompile(`^HELAPAY:[A-Z2-7]+$`), encoded, "encoding should be QR alphanumeric")
+
+	decoded, _, err := Parse(chainCtx, strings.ToLower(encoded), now)
+	require.NoError(err, "Parse")
+	require.Equal(pr, decoded)
+	require.True(decoded.Recipient.Equal(sdkTesting.Bob.Address))
+
+	_, err = sr.Verify(chainCtx, now.Add(2*time.Hour))
+	require.ErrorIs(err, ErrExpired)
+	_, err = sr.Verify(signature.Context("other chain"), now)
+	require.ErrorIs(err, ErrInvalidSignature)
+
+	tampered := *sr
+	tampered.Body = append([]byte{}, sr.Body...)
+	tampered.Body[len(tampered.Body)-1] ^= 0xff
+	_, err = tampered.Open(chainCtx)
+	require.ErrorIs(err, ErrInvalidSignature)
+
+	_, err = Decode("bitcoin:" + encoded)
+	require.ErrorIs(err, ErrMalformed)
+	_, err = Decode(URIScheme + ":!!!")
+	require.ErrorIs(err, ErrMalformed)
+
+	_, err = NewPaymentRequest(sdkTesting.Bob.Address, types.NewBaseUnits(*quantity.NewFromUint64(0), types.NativeDenomination), "", time.Time{}).
+		Sign(chainCtx, sdkTesting.Bob.Signer)
+	require.ErrorIs(err, ErrMalformed, "zero amounts should be rejected")
+	_, err = NewPaymentRequest(sdkTesting.Bob.Address, amount, strings.Repeat("x", MaxMemoSize+1), time.Time{}).
+		Sign(chainCtx, sdkTesting.Bob.Signer)
+	require.ErrorIs(err, ErrMalformed, "oversized memos should be rejected")
+
+	require.False(NewPaymentRequest(sdkTesting.Bob.Address, amount, "", time.Time{}).Expired(now.AddDate(100, 0, 0)),
+		"requests without expiry should never expire")
+}
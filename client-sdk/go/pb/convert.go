@@ -0,0 +1,316 @@
+package pb
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// FromAddress converts an address.
+func FromAddress(a types.Address) *Address {
+	raw, _ := a.MarshalBinary()
+	return &Address{Raw: raw}
+}
+
+// ToAddress converts the message into an address.
+func (x *Address) ToAddress() (types.Address, error) {
+	var a types.Address
+	if x == nil {
+		return a, fmt.Errorf("pb: missing address")
+	}
+	if err := a.UnmarshalBinary(x.Raw); err != nil {
+		return a, fmt.Errorf("pb: malformed address: %w", err)
+	}
+	return a, nil
+}
+
+// FromBaseUnits converts an amount.
+func FromBaseUnits(b *types.BaseUnits) *BaseUnits {
+	return &BaseUnits{
+		Amount:       b.Amount.String(),
+		Denomination: string(b.Denomination),
+	}
+}
+
+// ToBaseUnits converts the message into an amount.
+func (x *BaseUnits) ToBaseUnits() (types.BaseUnits, error) {
+	var b types.BaseUnits
+	if x == nil {
+		return b, fmt.Errorf("pb: missing amount")
+	}
+	if err := b.Amount.UnmarshalText([]byte(x.Amount)); err != nil {
+		return b, fmt.Errorf("pb: malformed amount: %w", err)
+	}
+	b.Denomination = types.Denomination(x.Denomination)
+	return b, nil
+}
+
+// FromTransfer converts an accounts.Transfer call body.
+func FromTransfer(t *accounts.Transfer) *Transfer {
+	return &Transfer{
+		To:     FromAddress(t.To),
+		Amount: FromBaseUnits(&t.Amount),
+	}
+}
+
+// ToTransfer converts the message into an accounts.Transfer call body.
+func (x *Transfer) ToTransfer() (*accounts.Transfer, error) {
+	var (
+		t   accounts.Transfer
+		err error
+	)
+	if t.To, err = x.GetTo().ToAddress(); err != nil {
+		return nil, err
+	}
+	if t.Amount, err = x.GetAmount().ToBaseUnits(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FromProposalOutput converts a governance proposal.
+func FromProposalOutput(p *accounts.ProposalOutput) *ProposalOutput {
+	x := ProposalOutput{
+		Id:        p.ID,
+		Submitter: FromAddress(p.Submitter),
+		State:     uint32(p.State),
+		Content: &ProposalContent{
+			Action: uint32(p.Content.Action),
+			Data:   fromProposalData(&p.Content.Data),
+		},
+		Results: make(map[uint32]uint32, len(p.Results)),
+	}
+	for vote, n := range p.Results {
+		x.Results[uint32(vote)] = uint32(n)
+	}
+	for voter, vote := range p.VoteOption {
+		x.VoteOptions = append(x.VoteOptions, &VoteOption{Voter: FromAddress(voter), Vote: uint32(vote)})
+	}
+	sort.Slice(x.VoteOptions, func(i, j int) bool {
+		return bytes.Compare(x.VoteOptions[i].Voter.Raw, x.VoteOptions[j].Voter.Raw) < 0
+	})
+	return &x
+}
+
+// ToProposalOutput converts the message into a governance proposal.
+func (x *ProposalOutput) ToProposalOutput() (*accounts.ProposalOutput, error) {
+	if x == nil {
+		return nil, fmt.Errorf("pb: missing proposal")
+	}
+	var (
+		p   accounts.ProposalOutput
+		err error
+	)
+	p.ID = x.Id
+	if p.Submitter, err = x.Submitter.ToAddress(); err != nil {
+		return nil, err
+	}
+	state, err := toUint8("proposal state", x.State)
+	if err != nil {
+		return nil, err
+	}
+	p.State = types.ProposalState(state)
+
+	action, err := toUint8("action", x.GetContent().GetAction())
+	if err != nil {
+		return nil, err
+	}
+	p.Content.Action = types.Action(action)
+	if err = toProposalData(x.GetContent().GetData(), &p.Content.Data); err != nil {
+		return nil, err
+	}
+
+	p.Results = make(map[types.Vote]uint16, len(x.Results))
+	for vote, n := range x.Results {
+		v, err := toUint8("vote", vote)
+		if err != nil {
+			return nil, err
+		}
+		if n > math.MaxUint16 {
+			return nil, fmt.Errorf("pb: vote count %d out of range", n)
+		}
+		p.Results[types.Vote(v)] = uint16(n)
+	}
+	p.VoteOption = make(map[types.Address]types.Vote, len(x.VoteOptions))
+	for _, vo := range x.VoteOptions {
+		voter, err := vo.GetVoter().ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		v, err := toUint8("vote", vo.GetVote())
+		if err != nil {
+			return nil, err
+		}
+		p.VoteOption[voter] = types.Vote(v)
+	}
+	return &p, nil
+}
+
+// fromProposalData converts proposal data.
+func fromProposalData(d *types.ProposalData) *ProposalData {
+	var x ProposalData
+	if d.Address != nil {
+		x.Address = FromAddress(*d.Address)
+	}
+	if d.Amount != nil {
+		x.Amount = FromBaseUnits(d.Amount)
+	}
+	if d.Meta != nil {
+		x.Meta = append([]byte{}, d.Meta[:]...)
+	}
+	if d.Role != nil {
+		role := uint32(*d.Role)
+		x.Role = &role
+	}
+	x.MintQuorum = fromQuorum(d.MintQuorum)
+	x.BurnQuorum = fromQuorum(d.BurnQuorum)
+	x.WhitelistQuorum = fromQuorum(d.WhitelistQuorum)
+	x.BlacklistQuorum = fromQuorum(d.BlacklistQuorum)
+	x.ConfigQuorum = fromQuorum(d.ConfigQuorum)
+	return &x
+}
+
+// toProposalData converts the message into proposal data.
+func toProposalData(x *ProposalData, d *types.ProposalData) error {
+	if x == nil {
+		return nil
+	}
+	if x.Address != nil {
+		addr, err := x.Address.ToAddress()
+		if err != nil {
+			return err
+		}
+		d.Address = &addr
+	}
+	if x.Amount != nil {
+		amount, err := x.Amount.ToBaseUnits()
+		if err != nil {
+			return err
+		}
+		d.Amount = &amount
+	}
+	if len(x.Meta) > 0 {
+		var meta types.Meta
+		if err := meta.UnmarshalBinary(x.Meta); err != nil {
+			return fmt.Errorf("pb: malformed meta: %w", err)
+		}
+		d.Meta = &meta
+	}
+	if x.Role != nil {
+		role, err := toUint8("role", *x.Role)
+		if err != nil {
+			return err
+		}
+		r := types.Role(role)
+		d.Role = &r
+	}
+	for _, q := range []struct {
+		what string
+		src  *uint32
+		dst  **uint8
+	}{
+		{"mint quorum", x.MintQuorum, &d.MintQuorum},
+		{"burn quorum", x.BurnQuorum, &d.BurnQuorum},
+		{"whitelist quorum", x.WhitelistQuorum, &d.WhitelistQuorum},
+		{"blacklist quorum", x.BlacklistQuorum, &d.BlacklistQuorum},
+		{"config quorum", x.ConfigQuorum, &d.ConfigQuorum},
+	} {
+		if q.src == nil {
+			continue
+		}
+		v, err := toUint8(q.what, *q.src)
+		if err != nil {
+			return err
+		}
+		*q.dst = &v
+	}
+	return nil
+}
+
+// FromAccountsEvent converts an accounts module event.
+func FromAccountsEvent(ev *accounts.Event) *AccountsEvent {
+	switch {
+	case ev.Transfer != nil:
+		return &AccountsEvent{Event: &AccountsEvent_Transfer{Transfer: &TransferEvent{
+			From:   FromAddress(ev.Transfer.From),
+			To:     FromAddress(ev.Transfer.To),
+			Amount: FromBaseUnits(&ev.Transfer.Amount),
+		}}}
+	case ev.Burn != nil:
+		return &AccountsEvent{Event: &AccountsEvent_Burn{Burn: &BurnEvent{
+			Owner:  FromAddress(ev.Burn.Owner),
+			Amount: FromBaseUnits(&ev.Burn.Amount),
+		}}}
+	case ev.Mint != nil:
+		return &AccountsEvent{Event: &AccountsEvent_Mint{Mint: &MintEvent{
+			Owner:  FromAddress(ev.Mint.Owner),
+			Amount: FromBaseUnits(&ev.Mint.Amount),
+		}}}
+	default:
+		return &AccountsEvent{}
+	}
+}
+
+// ToAccountsEvent converts the message into an accounts module event.
+func (x *AccountsEvent) ToAccountsEvent() (*accounts.Event, error) {
+	var (
+		ev  accounts.Event
+		err error
+	)
+	switch e := x.GetEvent().(type) {
+	case *AccountsEvent_Transfer:
+		var t accounts.TransferEvent
+		if t.From, err = e.Transfer.GetFrom().ToAddress(); err != nil {
+			return nil, err
+		}
+		if t.To, err = e.Transfer.GetTo().ToAddress(); err != nil {
+			return nil, err
+		}
+		if t.Amount, err = e.Transfer.GetAmount().ToBaseUnits(); err != nil {
+			return nil, err
+		}
+		ev.Transfer = &t
+	case *AccountsEvent_Burn:
+		var b accounts.BurnEvent
+		if b.Owner, err = e.Burn.GetOwner().ToAddress(); err != nil {
+			return nil, err
+		}
+		if b.Amount, err = e.Burn.GetAmount().ToBaseUnits(); err != nil {
+			return nil, err
+		}
+		ev.Burn = &b
+	case *AccountsEvent_Mint:
+		var m accounts.MintEvent
+		if m.Owner, err = e.Mint.GetOwner().ToAddress(); err != nil {
+			return nil, err
+		}
+		if m.Amount, err = e.Mint.GetAmount().ToBaseUnits(); err != nil {
+			return nil, err
+		}
+		ev.Mint = &m
+	default:
+		return nil, fmt.Errorf("pb: missing accounts event")
+	}
+	return &ev, nil
+}
+
+// fromQuorum converts an optional quorum.
+func fromQuorum(q *uint8) *uint32 {
+	if q == nil {
+		return nil
+	}
+	v := uint32(*q)
+	return &v
+}
+
+// toUint8 converts a protobuf integer into an 8-bit one.
+func toUint8(what string, v uint32) (uint8, error) {
+	if v > math.MaxUint8 {
+		return 0, fmt.Errorf("pb: %s %d out of range", what, v)
+	}
+	return uint8(v), nil
+}
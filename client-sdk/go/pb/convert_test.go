@@ -0,0 +1,102 @@
+package pb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// roundTrip encodes and decodes the given message.
+func roundTrip(t *testing.T, m, out proto.Message) {
+	data, err := proto.Marshal(m)
+	require.NoError(t, err, "Marshal")
+	require.NoError(t, proto.Unmarshal(data, out), "Unmarshal")
+}
+
+func TestTransfer(t *testing.T) {
+	require := require.New(t)
+
+	transfer := &accounts.Transfer{
+		To:     sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1_000_000), "USD"),
+	}
+	var decoded Transfer
+	roundTrip(t, FromTransfer(transfer), &decoded)
+	converted, err := decoded.ToTransfer()
+	require.NoError(err, "ToTransfer")
+	require.Equal(transfer, converted)
+
+	_, err = (&Transfer{Amount: decoded.Amount}).ToTransfer()
+	require.Error(err, "missing addresses should be rejected")
+	_, err = (&Transfer{To: decoded.To, Amount: &BaseUnits{Amount: "-1"}}).ToTransfer()
+	require.Error(err, "malformed amounts should be rejected")
+	_, err = (&Transfer{To: &Address{Raw: []byte{1, 2}}, Amount: decoded.Amount}).ToTransfer()
+	require.Error(err, "malformed addresses should be rejected")
+}
+
+func TestProposalOutput(t *testing.T) {
+	require := require.New(t)
+
+	role := types.Role(2)
+	quorum := uint8(3)
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(5), "USD")
+	meta, err := types.StringToMeta(&[]string{"payroll"}[0])
+	require.NoError(err)
+	proposal := &accounts.ProposalOutput{
+		ID:        7,
+		Submitter: sdkTesting.Alice.Address,
+		State:     types.Passed,
+		Content: accounts.ProposalContent{
+			Action: types.Mint,
+			Data: types.ProposalData{
+				Address:    &sdkTesting.Bob.Address,
+				Amount:     &amount,
+				Meta:       meta,
+				Role:       &role,
+				MintQuorum: &quorum,
+			},
+		},
+		Results: map[types.Vote]uint16{types.VoteYes: 2},
+		VoteOption: map[types.Address]types.Vote{
+			sdkTesting.Alice.Address: types.VoteYes,
+			sdkTesting.Bob.Address:   types.VoteYes,
+		},
+	}
+	var decoded ProposalOutput
+	roundTrip(t, FromProposalOutput(proposal), &decoded)
+	require.Len(decoded.VoteOptions, 2)
+	converted, err := decoded.ToProposalOutput()
+	require.NoError(err, "ToProposalOutput")
+	require.Equal(proposal, converted)
+
+	decoded.State = 256
+	_, err = decoded.ToProposalOutput()
+	require.Error(err, "out of range values should be rejected")
+}
+
+func TestAccountsEvent(t *testing.T) {
+	require := require.New(t)
+
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(5), types.NativeDenomination)
+	for _, ev := range []*accounts.Event{
+		{Transfer: &accounts.TransferEvent{From: sdkTesting.Alice.Address, To: sdkTesting.Bob.Address, Amount: amount}},
+		{Burn: &accounts.BurnEvent{Owner: sdkTesting.Alice.Address, Amount: amount}},
+		{Mint: &accounts.MintEvent{Owner: sdkTesting.Bob.Address, Amount: amount}},
+	} {
+		var decoded AccountsEvent
+		roundTrip(t, FromAccountsEvent(ev), &decoded)
+		converted, err := decoded.ToAccountsEvent()
+		require.NoError(err, "ToAccountsEvent")
+		require.Equal(ev, converted)
+	}
+
+	_, err := FromAccountsEvent(&accounts.Event{}).ToAccountsEvent()
+	require.Error(err, "empty events should be rejected")
+}
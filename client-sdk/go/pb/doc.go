@@ -0,0 +1,8 @@
+// Package pb contains protobuf mirrors of key SDK types together with converters from and to the
+// SDK types, so that services exchanging SDK data over protobuf-based APIs share one encoding.
+//
+// The messages are defined in sdk.proto. After changing it, regenerate sdk.pb.go using
+// protoc-gen-go v1.28.1 (go generate).
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative sdk.proto
@@ -0,0 +1,1113 @@
+// Protobuf mirrors of SDK types for use in service APIs.
+//
+// Regenerate sdk.pb.go after changing this file (see doc.go).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: sdk.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Address is an account address.
+type Address struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Raw is the versioned raw address (21 bytes).
+	Raw []byte `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Address) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+// BaseUnits is an amount of a denomination in base units.
+type BaseUnits struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Amount is the amount as a decimal string.
+	Amount string `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Denomination is the denomination, empty for the native denomination.
+	Denomination string `protobuf:"bytes,2,opt,name=denomination,proto3" json:"denomination,omitempty"`
+}
+
+func (x *BaseUnits) Reset() {
+	*x = BaseUnits{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BaseUnits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaseUnits) ProtoMessage() {}
+
+func (x *BaseUnits) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaseUnits.ProtoReflect.Descriptor instead.
+func (*BaseUnits) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BaseUnits) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *BaseUnits) GetDenomination() string {
+	if x != nil {
+		return x.Denomination
+	}
+	return ""
+}
+
+// Transfer is the body of the accounts.Transfer call.
+type Transfer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	To     *Address   `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	Amount *BaseUnits `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Transfer) GetTo() *Address {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Transfer) GetAmount() *BaseUnits {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+// ProposalData is the data of a governance proposal. The set fields depend on the action.
+type ProposalData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address *Address   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Amount  *BaseUnits `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Meta is the proposal metadata (64 bytes, empty if not set).
+	Meta            []byte  `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	Role            *uint32 `protobuf:"varint,4,opt,name=role,proto3,oneof" json:"role,omitempty"`
+	MintQuorum      *uint32 `protobuf:"varint,5,opt,name=mint_quorum,json=mintQuorum,proto3,oneof" json:"mint_quorum,omitempty"`
+	BurnQuorum      *uint32 `protobuf:"varint,6,opt,name=burn_quorum,json=burnQuorum,proto3,oneof" json:"burn_quorum,omitempty"`
+	WhitelistQuorum *uint32 `protobuf:"varint,7,opt,name=whitelist_quorum,json=whitelistQuorum,proto3,oneof" json:"whitelist_quorum,omitempty"`
+	BlacklistQuorum *uint32 `protobuf:"varint,8,opt,name=blacklist_quorum,json=blacklistQuorum,proto3,oneof" json:"blacklist_quorum,omitempty"`
+	ConfigQuorum    *uint32 `protobuf:"varint,9,opt,name=config_quorum,json=configQuorum,proto3,oneof" json:"config_quorum,omitempty"`
+}
+
+func (x *ProposalData) Reset() {
+	*x = ProposalData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProposalData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalData) ProtoMessage() {}
+
+func (x *ProposalData) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalData.ProtoReflect.Descriptor instead.
+func (*ProposalData) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProposalData) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ProposalData) GetAmount() *BaseUnits {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *ProposalData) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *ProposalData) GetRole() uint32 {
+	if x != nil && x.Role != nil {
+		return *x.Role
+	}
+	return 0
+}
+
+func (x *ProposalData) GetMintQuorum() uint32 {
+	if x != nil && x.MintQuorum != nil {
+		return *x.MintQuorum
+	}
+	return 0
+}
+
+func (x *ProposalData) GetBurnQuorum() uint32 {
+	if x != nil && x.BurnQuorum != nil {
+		return *x.BurnQuorum
+	}
+	return 0
+}
+
+func (x *ProposalData) GetWhitelistQuorum() uint32 {
+	if x != nil && x.WhitelistQuorum != nil {
+		return *x.WhitelistQuorum
+	}
+	return 0
+}
+
+func (x *ProposalData) GetBlacklistQuorum() uint32 {
+	if x != nil && x.BlacklistQuorum != nil {
+		return *x.BlacklistQuorum
+	}
+	return 0
+}
+
+func (x *ProposalData) GetConfigQuorum() uint32 {
+	if x != nil && x.ConfigQuorum != nil {
+		return *x.ConfigQuorum
+	}
+	return 0
+}
+
+// ProposalContent is the content of a governance proposal.
+type ProposalContent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action uint32        `protobuf:"varint,1,opt,name=action,proto3" json:"action,omitempty"`
+	Data   *ProposalData `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ProposalContent) Reset() {
+	*x = ProposalContent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProposalContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalContent) ProtoMessage() {}
+
+func (x *ProposalContent) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalContent.ProtoReflect.Descriptor instead.
+func (*ProposalContent) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProposalContent) GetAction() uint32 {
+	if x != nil {
+		return x.Action
+	}
+	return 0
+}
+
+func (x *ProposalContent) GetData() *ProposalData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// VoteOption is the vote cast by a voter.
+type VoteOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Voter *Address `protobuf:"bytes,1,opt,name=voter,proto3" json:"voter,omitempty"`
+	Vote  uint32   `protobuf:"varint,2,opt,name=vote,proto3" json:"vote,omitempty"`
+}
+
+func (x *VoteOption) Reset() {
+	*x = VoteOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoteOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteOption) ProtoMessage() {}
+
+func (x *VoteOption) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteOption.ProtoReflect.Descriptor instead.
+func (*VoteOption) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VoteOption) GetVoter() *Address {
+	if x != nil {
+		return x.Voter
+	}
+	return nil
+}
+
+func (x *VoteOption) GetVote() uint32 {
+	if x != nil {
+		return x.Vote
+	}
+	return 0
+}
+
+// ProposalOutput is a governance proposal as returned by the accounts.ProposalInfo query.
+type ProposalOutput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        uint32           `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Submitter *Address         `protobuf:"bytes,2,opt,name=submitter,proto3" json:"submitter,omitempty"`
+	State     uint32           `protobuf:"varint,3,opt,name=state,proto3" json:"state,omitempty"`
+	Content   *ProposalContent `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	// Results are the number of votes per vote option.
+	Results map[uint32]uint32 `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// VoteOptions are the votes cast, ordered by voter address.
+	VoteOptions []*VoteOption `protobuf:"bytes,6,rep,name=vote_options,json=voteOptions,proto3" json:"vote_options,omitempty"`
+}
+
+func (x *ProposalOutput) Reset() {
+	*x = ProposalOutput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProposalOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalOutput) ProtoMessage() {}
+
+func (x *ProposalOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalOutput.ProtoReflect.Descriptor instead.
+func (*ProposalOutput) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProposalOutput) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProposalOutput) GetSubmitter() *Address {
+	if x != nil {
+		return x.Submitter
+	}
+	return nil
+}
+
+func (x *ProposalOutput) GetState() uint32 {
+	if x != nil {
+		return x.State
+	}
+	return 0
+}
+
+func (x *ProposalOutput) GetContent() *ProposalContent {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ProposalOutput) GetResults() map[uint32]uint32 {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ProposalOutput) GetVoteOptions() []*VoteOption {
+	if x != nil {
+		return x.VoteOptions
+	}
+	return nil
+}
+
+// TransferEvent is the accounts transfer event.
+type TransferEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From   *Address   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To     *Address   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount *BaseUnits `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *TransferEvent) Reset() {
+	*x = TransferEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferEvent) ProtoMessage() {}
+
+func (x *TransferEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferEvent.ProtoReflect.Descriptor instead.
+func (*TransferEvent) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TransferEvent) GetFrom() *Address {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetTo() *Address {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetAmount() *BaseUnits {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+// BurnEvent is the accounts burn event.
+type BurnEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner  *Address   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Amount *BaseUnits `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *BurnEvent) Reset() {
+	*x = BurnEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BurnEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnEvent) ProtoMessage() {}
+
+func (x *BurnEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BurnEvent.ProtoReflect.Descriptor instead.
+func (*BurnEvent) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BurnEvent) GetOwner() *Address {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *BurnEvent) GetAmount() *BaseUnits {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+// MintEvent is the accounts mint event.
+type MintEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner  *Address   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Amount *BaseUnits `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *MintEvent) Reset() {
+	*x = MintEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MintEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintEvent) ProtoMessage() {}
+
+func (x *MintEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintEvent.ProtoReflect.Descriptor instead.
+func (*MintEvent) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MintEvent) GetOwner() *Address {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *MintEvent) GetAmount() *BaseUnits {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+// AccountsEvent is an accounts module event.
+type AccountsEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*AccountsEvent_Transfer
+	//	*AccountsEvent_Burn
+	//	*AccountsEvent_Mint
+	Event isAccountsEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *AccountsEvent) Reset() {
+	*x = AccountsEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sdk_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccountsEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountsEvent) ProtoMessage() {}
+
+func (x *AccountsEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sdk_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountsEvent.ProtoReflect.Descriptor instead.
+func (*AccountsEvent) Descriptor() ([]byte, []int) {
+	return file_sdk_proto_rawDescGZIP(), []int{10}
+}
+
+func (m *AccountsEvent) GetEvent() isAccountsEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *AccountsEvent) GetTransfer() *TransferEvent {
+	if x, ok := x.GetEvent().(*AccountsEvent_Transfer); ok {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *AccountsEvent) GetBurn() *BurnEvent {
+	if x, ok := x.GetEvent().(*AccountsEvent_Burn); ok {
+		return x.Burn
+	}
+	return nil
+}
+
+func (x *AccountsEvent) GetMint() *MintEvent {
+	if x, ok := x.GetEvent().(*AccountsEvent_Mint); ok {
+		return x.Mint
+	}
+	return nil
+}
+
+type isAccountsEvent_Event interface {
+	isAccountsEvent_Event()
+}
+
+type AccountsEvent_Transfer struct {
+	Transfer *TransferEvent `protobuf:"bytes,1,opt,name=transfer,proto3,oneof"`
+}
+
+type AccountsEvent_Burn struct {
+	Burn *BurnEvent `protobuf:"bytes,2,opt,name=burn,proto3,oneof"`
+}
+
+type AccountsEvent_Mint struct {
+	Mint *MintEvent `protobuf:"bytes,3,opt,name=mint,proto3,oneof"`
+}
+
+func (*AccountsEvent_Transfer) isAccountsEvent_Event() {}
+
+func (*AccountsEvent_Burn) isAccountsEvent_Event() {}
+
+func (*AccountsEvent_Mint) isAccountsEvent_Event() {}
+
+var File_sdk_proto protoreflect.FileDescriptor
+
+var file_sdk_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x73, 0x64, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x68, 0x65, 0x6c,
+	0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x22, 0x1b, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x72, 0x61, 0x77, 0x22, 0x47, 0x0a, 0x09, 0x42, 0x61, 0x73, 0x65, 0x55, 0x6e, 0x69,
+	0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x60,
+	0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x02, 0x74, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x02, 0x74, 0x6f,
+	0x12, 0x2e, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x61, 0x73, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0xd6, 0x03, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x2e, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x61, 0x73, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24,
+	0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x74, 0x51, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x71, 0x75, 0x6f,
+	0x72, 0x75, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0a, 0x62, 0x75, 0x72,
+	0x6e, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x77, 0x68,
+	0x69, 0x74, 0x65, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0f, 0x77, 0x68, 0x69, 0x74, 0x65, 0x6c, 0x69, 0x73,
+	0x74, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x62, 0x6c,
+	0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0f, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73,
+	0x74, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x05, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x51, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x42, 0x0e, 0x0a,
+	0x0c, 0x5f, 0x6d, 0x69, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x42, 0x0e, 0x0a,
+	0x0c, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x42, 0x13, 0x0a,
+	0x11, 0x5f, 0x77, 0x68, 0x69, 0x74, 0x65, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x72,
+	0x75, 0x6d, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74,
+	0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x22, 0x58, 0x0a, 0x0f, 0x50, 0x72, 0x6f,
+	0x70, 0x6f, 0x73, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x4c, 0x0a, 0x0a, 0x56, 0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x76, 0x6f, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x76, 0x6f, 0x74,
+	0x65, 0x22, 0xde, 0x02, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x32, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73,
+	0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x09, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x36,
+	0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73,
+	0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x0c, 0x76, 0x6f,
+	0x74, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x76, 0x6f, 0x74, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x8f, 0x01, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x24,
+	0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c,
+	0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x02, 0x74, 0x6f, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x61, 0x73, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x67, 0x0a, 0x09, 0x42, 0x75, 0x72, 0x6e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x2a, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x2e, 0x0a,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x73, 0x65,
+	0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x67, 0x0a,
+	0x09, 0x4d, 0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65, 0x6c, 0x61,
+	0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52,
+	0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x73, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xae, 0x01, 0x0a, 0x0d, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x6c,
+	0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x12, 0x2c, 0x0a, 0x04, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x75, 0x72, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x62, 0x75, 0x72, 0x6e,
+	0x12, 0x2c, 0x0a, 0x04, 0x6d, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x68, 0x65, 0x6c, 0x61, 0x2e, 0x73, 0x64, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e,
+	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x69, 0x6e, 0x74, 0x42, 0x07,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x61, 0x73, 0x69, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2f, 0x6f, 0x61, 0x73, 0x69, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x2f, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x2d, 0x73, 0x64, 0x6b, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_sdk_proto_rawDescOnce sync.Once
+	file_sdk_proto_rawDescData = file_sdk_proto_rawDesc
+)
+
+func file_sdk_proto_rawDescGZIP() []byte {
+	file_sdk_proto_rawDescOnce.Do(func() {
+		file_sdk_proto_rawDescData = protoimpl.X.CompressGZIP(file_sdk_proto_rawDescData)
+	})
+	return file_sdk_proto_rawDescData
+}
+
+var file_sdk_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_sdk_proto_goTypes = []interface{}{
+	(*Address)(nil),         // 0: hela.sdk.v1.Address
+	(*BaseUnits)(nil),       // 1: hela.sdk.v1.BaseUnits
+	(*Transfer)(nil),        // 2: hela.sdk.v1.Transfer
+	(*ProposalData)(nil),    // 3: hela.sdk.v1.ProposalData
+	(*ProposalContent)(nil), // 4: hela.sdk.v1.ProposalContent
+	(*VoteOption)(nil),      // 5: hela.sdk.v1.VoteOption
+	(*ProposalOutput)(nil),  // 6: hela.sdk.v1.ProposalOutput
+	(*TransferEvent)(nil),   // 7: hela.sdk.v1.TransferEvent
+	(*BurnEvent)(nil),       // 8: hela.sdk.v1.BurnEvent
+	(*MintEvent)(nil),       // 9: hela.sdk.v1.MintEvent
+	(*AccountsEvent)(nil),   // 10: hela.sdk.v1.AccountsEvent
+	nil,                     // 11: hela.sdk.v1.ProposalOutput.ResultsEntry
+}
+var file_sdk_proto_depIdxs = []int32{
+	0,  // 0: hela.sdk.v1.Transfer.to:type_name -> hela.sdk.v1.Address
+	1,  // 1: hela.sdk.v1.Transfer.amount:type_name -> hela.sdk.v1.BaseUnits
+	0,  // 2: hela.sdk.v1.ProposalData.address:type_name -> hela.sdk.v1.Address
+	1,  // 3: hela.sdk.v1.ProposalData.amount:type_name -> hela.sdk.v1.BaseUnits
+	3,  // 4: hela.sdk.v1.ProposalContent.data:type_name -> hela.sdk.v1.ProposalData
+	0,  // 5: hela.sdk.v1.VoteOption.voter:type_name -> hela.sdk.v1.Address
+	0,  // 6: hela.sdk.v1.ProposalOutput.submitter:type_name -> hela.sdk.v1.Address
+	4,  // 7: hela.sdk.v1.ProposalOutput.content:type_name -> hela.sdk.v1.ProposalContent
+	11, // 8: hela.sdk.v1.ProposalOutput.results:type_name -> hela.sdk.v1.ProposalOutput.ResultsEntry
+	5,  // 9: hela.sdk.v1.ProposalOutput.vote_options:type_name -> hela.sdk.v1.VoteOption
+	0,  // 10: hela.sdk.v1.TransferEvent.from:type_name -> hela.sdk.v1.Address
+	0,  // 11: hela.sdk.v1.TransferEvent.to:type_name -> hela.sdk.v1.Address
+	1,  // 12: hela.sdk.v1.TransferEvent.amount:type_name -> hela.sdk.v1.BaseUnits
+	0,  // 13: hela.sdk.v1.BurnEvent.owner:type_name -> hela.sdk.v1.Address
+	1,  // 14: hela.sdk.v1.BurnEvent.amount:type_name -> hela.sdk.v1.BaseUnits
+	0,  // 15: hela.sdk.v1.MintEvent.owner:type_name -> hela.sdk.v1.Address
+	1,  // 16: hela.sdk.v1.MintEvent.amount:type_name -> hela.sdk.v1.BaseUnits
+	7,  // 17: hela.sdk.v1.AccountsEvent.transfer:type_name -> hela.sdk.v1.TransferEvent
+	8,  // 18: hela.sdk.v1.AccountsEvent.burn:type_name -> hela.sdk.v1.BurnEvent
+	9,  // 19: hela.sdk.v1.AccountsEvent.mint:type_name -> hela.sdk.v1.MintEvent
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_sdk_proto_init() }
+func file_sdk_proto_init() {
+	if File_sdk_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_sdk_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Address); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BaseUnits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Transfer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProposalData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProposalContent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoteOption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProposalOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BurnEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MintEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sdk_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AccountsEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_sdk_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_sdk_proto_msgTypes[10].OneofWrappers = []interface{}{
+		(*AccountsEvent_Transfer)(nil),
+		(*AccountsEvent_Burn)(nil),
+		(*AccountsEvent_Mint)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_sdk_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_sdk_proto_goTypes,
+		DependencyIndexes: file_sdk_proto_depIdxs,
+		MessageInfos:      file_sdk_proto_msgTypes,
+	}.Build()
+	File_sdk_proto = out.File
+	file_sdk_proto_rawDesc = nil
+	file_sdk_proto_goTypes = nil
+	file_sdk_proto_depIdxs = nil
+}
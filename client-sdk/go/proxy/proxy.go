@@ -0,0 +1,336 @@
+// Package proxy implements a caching runtime client proxy for read-heavy deployments.
+//
+// The proxy sits between many SDK clients and a single node. It serves the node's runtime client
+// gRPC API, caching responses to queries about historical rounds, coalescing identical
+// concurrent requests and fanning out block subscriptions over a single upstream subscription.
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// DefaultCacheSize is the default maximum number of cached responses.
+const DefaultCacheSize = 10_000
+
+// DefaultUpstreamTimeout is the default timeout of upstream requests made on behalf of clients.
+const DefaultUpstreamTimeout = 30 * time.Second
+
+// watchRetryInterval is the interval after which a failed upstream block subscription is retried.
+const watchRetryInterval = time.Second
+
+// Option is a proxy option.
+type Option func(*options)
+
+type options struct {
+	cacheSize       int
+	upstreamTimeout time.Duration
+}
+
+// WithCacheSize sets the maximum number of cached responses. A size of zero disables caching.
+func WithCacheSize(size int) Option {
+	return func(o *options) {
+		o.cacheSize = size
+	}
+}
+
+// WithUpstreamTimeout sets the timeout of upstream requests made on behalf of clients.
+//
+// As identical concurrent requests share a single upstream request, it is not bound to the
+// context of any client and needs its own timeout.
+func WithUpstreamTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.upstreamTimeout = timeout
+	}
+}
+
+// Proxy is a caching runtime client proxy.
+//
+// It implements the Oasis Core runtime client interface so it can be served over gRPC (see
+// Register) or used in-process.
+type Proxy struct {
+	upstream coreClient.RuntimeClient
+
+	cache           *lruCache
+	inflight        singleflight.Group
+	upstreamTimeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	watchLock sync.Mutex
+	watchers  map[common.Namespace]*watcher
+}
+
+// watcher is a shared upstream block subscription for a single runtime.
+type watcher struct {
+	broker      *pubsub.Broker
+	subscribers int
+	cancel      context.CancelFunc
+}
+
+var _ coreClient.RuntimeClient = (*Proxy)(nil)
+
+// New creates a new proxy in front of the given upstream runtime client.
+func New(upstream coreClient.RuntimeClient, opts ...Option) *Proxy {
+	o := options{cacheSize: DefaultCacheSize, upstreamTimeout: DefaultUpstreamTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Proxy{
+		upstream:        upstream,
+		cache:           newLRUCache(o.cacheSize),
+		upstreamTimeout: o.upstreamTimeout,
+		ctx:             ctx,
+		cancel:          cancel,
+		watchers:        make(map[common.Namespace]*watcher),
+	}
+}
+
+// Register registers the proxy as the runtime client service of the given gRPC server.
+func (p *Proxy) Register(server *grpc.Server) {
+	coreClient.RegisterService(server, p)
+}
+
+// Close stops all upstream subscriptions.
+func (p *Proxy) Close() {
+	p.cancel()
+}
+
+// CacheLen returns the number of cached responses.
+func (p *Proxy) CacheLen() int {
+	return p.cache.len()
+}
+
+// isHistorical returns true iff the given round refers to a specific (and thus immutable) round.
+func isHistorical(round uint64) bool {
+	return round != coreClient.RoundLatest
+}
+
+// do performs the given upstream request, coalescing identical concurrent requests and caching
+// the response in case the request is about a historical round.
+//
+// The upstream request is bound to the lifetime of the proxy and the upstream timeout rather than
+// to the context of any of the callers sharing it.
+func (p *Proxy) do(ctx context.Context, method string, request interface{}, round uint64, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	key := method + ":" + string(cbor.Marshal(request))
+	cacheable := isHistorical(round)
+	if cacheable {
+		if rsp, ok := p.cache.get(key); ok {
+			return rsp, nil
+		}
+	}
+
+	ch := p.inflight.DoChan(key, func() (interface{}, error) {
+		upstreamCtx, cancel := context.WithTimeout(p.ctx, p.upstreamTimeout)
+		defer cancel()
+
+		rsp, err := fn(upstreamCtx)
+		if err == nil && cacheable {
+			p.cache.put(key, rsp)
+		}
+		return rsp, err
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SubmitTx implements coreClient.RuntimeClient.
+func (p *Proxy) SubmitTx(ctx context.Context, request *coreClient.SubmitTxRequest) ([]byte, error) {
+	return p.upstream.SubmitTx(ctx, request)
+}
+
+// SubmitTxMeta implements coreClient.RuntimeClient.
+func (p *Proxy) SubmitTxMeta(ctx context.Context, request *coreClient.SubmitTxRequest) (*coreClient.SubmitTxMetaResponse, error) {
+	return p.upstream.SubmitTxMeta(ctx, request)
+}
+
+// SubmitTxNoWait implements coreClient.RuntimeClient.
+func (p *Proxy) SubmitTxNoWait(ctx context.Context, request *coreClient.SubmitTxRequest) error {
+	return p.upstream.SubmitTxNoWait(ctx, request)
+}
+
+// CheckTx implements coreClient.RuntimeClient.
+func (p *Proxy) CheckTx(ctx context.Context, request *coreClient.CheckTxRequest) error {
+	return p.upstream.CheckTx(ctx, request)
+}
+
+// GetGenesisBlock implements coreClient.RuntimeClient.
+func (p *Proxy) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
+	rsp, err := p.do(ctx, "GetGenesisBlock", runtimeID, 0, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.GetGenesisBlock(ctx, runtimeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*block.Block), nil
+}
+
+// GetBlock implements coreClient.RuntimeClient.
+func (p *Proxy) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	rsp, err := p.do(ctx, "GetBlock", request, request.Round, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.GetBlock(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*block.Block), nil
+}
+
+// GetLastRetainedBlock implements coreClient.RuntimeClient.
+func (p *Proxy) GetLastRetainedBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
+	// The last retained block changes as the node prunes old rounds, so it is never cached.
+	return p.upstream.GetLastRetainedBlock(ctx, runtimeID)
+}
+
+// GetTransactions implements coreClient.RuntimeClient.
+func (p *Proxy) GetTransactions(ctx context.Context, request *coreClient.GetTransactionsRequest) ([][]byte, error) {
+	rsp, err := p.do(ctx, "GetTransactions", request, request.Round, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.GetTransactions(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.([][]byte), nil
+}
+
+// GetTransactionsWithResults implements coreClient.RuntimeClient.
+func (p *Proxy) GetTransactionsWithResults(ctx context.Context, request *coreClient.GetTransactionsRequest) ([]*coreClient.TransactionWithResults, error) {
+	rsp, err := p.do(ctx, "GetTransactionsWithResults", request, request.Round, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.GetTransactionsWithResults(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.([]*coreClient.TransactionWithResults), nil
+}
+
+// GetEvents implements coreClient.RuntimeClient.
+func (p *Proxy) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	rsp, err := p.do(ctx, "GetEvents", request, request.Round, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.GetEvents(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.([]*coreClient.Event), nil
+}
+
+// Query implements coreClient.RuntimeClient.
+func (p *Proxy) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	rsp, err := p.do(ctx, "Query", request, request.Round, func(ctx context.Context) (interface{}, error) {
+		return p.upstream.Query(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*coreClient.QueryResponse), nil
+}
+
+// WatchBlocks implements coreClient.RuntimeClient.
+//
+// All subscribers for the same runtime share a single upstream subscription, which is closed
+// once its last subscriber goes away.
+func (p *Proxy) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	p.watchLock.Lock()
+	w, ok := p.watchers[runtimeID]
+	if !ok {
+		watchCtx, cancel := context.WithCancel(p.ctx)
+		w = &watcher{
+			broker: pubsub.NewBroker(false),
+			cancel: cancel,
+		}
+		p.watchers[runtimeID] = w
+		go p.watchUpstream(watchCtx, runtimeID, w.broker)
+	}
+	w.subscribers++
+	p.watchLock.Unlock()
+
+	ch := make(chan *roothash.AnnotatedBlock)
+	sub := w.broker.Subscribe()
+	sub.Unwrap(ch)
+	return ch, &watchSubscription{p: p, runtimeID: runtimeID, w: w, sub: sub}, nil
+}
+
+// unsubscribe drops a subscriber of the given watcher, stopping its upstream subscription when
+// there are no subscribers left.
+func (p *Proxy) unsubscribe(runtimeID common.Namespace, w *watcher) {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+
+	w.subscribers--
+	if w.subscribers > 0 {
+		return
+	}
+	w.cancel()
+	if p.watchers[runtimeID] == w {
+		delete(p.watchers, runtimeID)
+	}
+}
+
+// watchSubscription is a block subscription that releases its watcher when closed.
+type watchSubscription struct {
+	p         *Proxy
+	runtimeID common.Namespace
+	w         *watcher
+	sub       *pubsub.Subscription
+
+	closeOnce sync.Once
+}
+
+// Close implements pubsub.ClosableSubscription.
+func (s *watchSubscription) Close() {
+	s.closeOnce.Do(func() {
+		s.sub.Close()
+		s.p.unsubscribe(s.runtimeID, s.w)
+	})
+}
+
+// watchUpstream forwards blocks from the upstream subscription to the given broker, resubscribing
+// in case the upstream subscription fails, until the given context is canceled.
+func (p *Proxy) watchUpstream(ctx context.Context, runtimeID common.Namespace, broker *pubsub.Broker) {
+	for {
+		ch, sub, err := p.upstream.WatchBlocks(ctx, runtimeID)
+		if err == nil {
+			forwardBlocks(ctx, ch, broker)
+			sub.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+func forwardBlocks(ctx context.Context, ch <-chan *roothash.AnnotatedBlock, broker *pubsub.Broker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blk, ok := <-ch:
+			if !ok {
+				return
+			}
+			broker.Broadcast(blk)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+type countingRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	queries uint64
+	release chan struct{}
+}
+
+func (c *countingRuntimeClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	atomic.AddUint64(&c.queries, 1)
+	if c.release != nil {
+		select {
+		case <-c.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &coreClient.QueryResponse{Data: []byte(request.Method)}, nil
+}
+
+// watchingRuntimeClient counts the active upstream block subscriptions.
+type watchingRuntimeClient struct {
+	coreClient.RuntimeClient
+
+	active int64
+}
+
+func (c *watchingRuntimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	atomic.AddInt64(&c.active, 1)
+	ch := make(chan *roothash.AnnotatedBlock)
+	return ch, &countingSubscription{active: &c.active}, nil
+}
+
+type countingSubscription struct {
+	active *int64
+}
+
+func (s *countingSubscription) Close() {
+	atomic.AddInt64(s.active, -1)
+}
+
+// waitingContext counts the callers waiting on it.
+type waitingContext struct {
+	context.Context
+
+	waiting int64
+}
+
+func (c *waitingContext) Done() <-chan struct{} {
+	atomic.AddInt64(&c.waiting, 1)
+	return c.Context.Done()
+}
+
+func TestProxyCaching(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &countingRuntimeClient{}
+	p := New(upstream)
+	defer p.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		rsp, err := p.Query(ctx, &coreClient.QueryRequest{Round: 10, Method: "accounts.Balances"})
+		require.NoError(err, "Query")
+		require.Equal([]byte("accounts.Balances"), rsp.Data)
+	}
+	require.EqualValues(1, atomic.LoadUint64(&upstream.queries), "historical queries should be cached")
+	require.Equal(1, p.CacheLen())
+
+	for i := 0; i < 3; i++ {
+		_, err := p.Query(ctx, &coreClient.QueryRequest{Round: coreClient.RoundLatest, Method: "accounts.Balances"})
+		require.NoError(err, "Query")
+	}
+	require.EqualValues(4, atomic.LoadUint64(&upstream.queries), "queries for the latest round should not be cached")
+	require.Equal(1, p.CacheLen())
+
+	_, err := p.Query(ctx, &coreClient.QueryRequest{Round: 11, Method: "accounts.Balances"})
+	require.NoError(err, "Query")
+	require.EqualValues(5, atomic.LoadUint64(&upstream.queries), "different requests should not share a cache entry")
+}
+
+func TestProxyDeduplication(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &countingRuntimeClient{release: make(chan struct{})}
+	p := New(upstream, WithCacheSize(0))
+	defer p.Close()
+
+	ctx := &waitingContext{Context: context.Background()}
+	var wg sync.WaitGroup
+	query := func() {
+		defer wg.Done()
+		_, err := p.Query(ctx, &coreClient.QueryRequest{Round: coreClient.RoundLatest, Method: "core.Parameters"})
+		require.NoError(err, "Query")
+	}
+	wg.Add(1)
+	go query()
+	require.Eventually(func() bool { return atomic.LoadUint64(&upstream.queries) == 1 }, time.Second, time.Millisecond)
+
+	// Identical requests issued while the first one is in flight should share its response.
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go query()
+	}
+	require.Eventually(func() bool { return atomic.LoadInt64(&ctx.waiting) == 5 }, time.Second, time.Millisecond)
+	close(upstream.release)
+	wg.Wait()
+
+	require.EqualValues(1, atomic.LoadUint64(&upstream.queries), "concurrent requests should be coalesced")
+	require.Equal(0, p.CacheLen(), "caching should be disabled")
+}
+
+func TestProxyUpstreamTimeout(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &countingRuntimeClient{release: make(chan struct{})}
+	p := New(upstream, WithUpstreamTimeout(10*time.Millisecond))
+	defer p.Close()
+
+	// A hanging upstream request should not block clients without a deadline forever.
+	_, err := p.Query(context.Background(), &coreClient.QueryRequest{Round: 10, Method: "core.Parameters"})
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.Equal(0, p.CacheLen(), "failed requests should not be cached")
+
+	close(upstream.release)
+	_, err = p.Query(context.Background(), &coreClient.QueryRequest{Round: 10, Method: "core.Parameters"})
+	require.NoError(err, "requests should be retried upstream after a timeout")
+	require.EqualValues(2, atomic.LoadUint64(&upstream.queries))
+}
+
+func TestProxyWatchBlocks(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &watchingRuntimeClient{}
+	p := New(upstream)
+	defer p.Close()
+
+	ctx := context.Background()
+	var runtimeID common.Namespace
+	_, sub1, err := p.WatchBlocks(ctx, runtimeID)
+	require.NoError(err, "WatchBlocks")
+	_, sub2, err := p.WatchBlocks(ctx, runtimeID)
+	require.NoError(err, "WatchBlocks")
+	require.Eventually(func() bool { return atomic.LoadInt64(&upstream.active) == 1 }, time.Second, time.Millisecond,
+		"subscribers should share a single upstream subscription")
+
+	sub1.Close()
+	sub1.Close()
+	require.EqualValues(1, atomic.LoadInt64(&upstream.active), "upstream subscription should be kept while in use")
+
+	sub2.Close()
+	require.Eventually(func() bool { return atomic.LoadInt64(&upstream.active) == 0 }, time.Second, time.Millisecond,
+		"upstream subscription should be closed after the last subscriber leaves")
+	p.watchLock.Lock()
+	require.Empty(p.watchers)
+	p.watchLock.Unlock()
+
+	// A new subscriber should start a new upstream subscription.
+	_, sub3, err := p.WatchBlocks(ctx, runtimeID)
+	require.NoError(err, "WatchBlocks")
+	require.Eventually(func() bool { return atomic.LoadInt64(&upstream.active) == 1 }, time.Second, time.Millisecond)
+	sub3.Close()
+	require.Eventually(func() bool { return atomic.LoadInt64(&upstream.active) == 0 }, time.Second, time.Millisecond)
+}
+
+func TestLRUCache(t *testing.T) {
+	require := require.New(t)
+
+	c := newLRUCache(2)
+	c.put("a", 1)
+	c.put("b", 2)
+	_, ok := c.get("a")
+	require.True(ok)
+	c.put("c", 3)
+
+	_, ok = c.get("b")
+	require.False(ok, "least recently used entry should be evicted")
+	v, ok := c.get("a")
+	require.True(ok)
+	require.Equal(1, v)
+	require.Equal(2, c.len())
+}
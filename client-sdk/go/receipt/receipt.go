@@ -0,0 +1,205 @@
+// Package receipt implements signed query receipts, which capture a node's response to a query
+// together with the block it was served at, so that the response can later be presented to and
+// verified by a third party (e.g. an auditor resolving a dispute about a reported balance).
+//
+// Runtime queries are not accompanied by storage proofs, so a receipt instead commits to the hash
+// and state root of the block the query was executed at. An auditor can check those against the
+// consensus layer and re-execute the query against a node of their choosing (see Verify).
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// LatestReceiptVersion is the latest receipt format version.
+const LatestReceiptVersion = 1
+
+// ErrMismatch is the error returned by Verify in case the chain disagrees with the receipt.
+var ErrMismatch = errors.New("receipt: mismatch")
+
+// Receipt is a captured query response.
+type Receipt struct {
+	cbor.Versioned
+
+	// RuntimeID is the identifier of the queried runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Round is the round the query was executed at.
+	Round uint64 `json:"round"`
+	// BlockHash is the hash of the header of the block at Round.
+	BlockHash hash.Hash `json:"block_hash"`
+	// StateRoot is the state root of the block at Round.
+	StateRoot hash.Hash `json:"state_root"`
+
+	// Method is the query method.
+	Method string `json:"method"`
+	// Args are the CBOR-encoded query arguments.
+	Args cbor.RawMessage `json:"args,omitempty"`
+	// Response is the CBOR-encoded response returned by the node.
+	Response cbor.RawMessage `json:"response"`
+
+	// CapturedAt is the POSIX time at which the receipt was captured.
+	CapturedAt uint64 `json:"captured_at"`
+}
+
+// Capture executes the given query at the given round and captures the response into a receipt.
+// Passing client.RoundLatest pins the query to the latest round.
+func Capture(ctx context.Context, rc client.RuntimeClient, round uint64, method string, args interface{}) (*Receipt, error) {
+	info, err := rc.GetInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("receipt: failed to query runtime info: %w", err)
+	}
+	blk, err := rc.GetBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("receipt: failed to fetch block: %w", err)
+	}
+	round = blk.Header.Round
+	rsp, err := rc.QueryRaw(ctx, round, method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Receipt{
+		Versioned:  cbor.NewVersioned(LatestReceiptVersion),
+		RuntimeID:  info.ID,
+		Round:      round,
+		BlockHash:  blk.Header.EncodedHash(),
+		StateRoot:  blk.Header.StateRoot,
+		Method:     method,
+		Response:   rsp,
+		CapturedAt: uint64(time.Now().Unix()),
+	}
+	if args != nil {
+		r.Args = cbor.Marshal(args)
+	}
+	return r, nil
+}
+
+// ValidateBasic performs basic validation on the receipt.
+func (r *Receipt) ValidateBasic() error {
+	if r.V != LatestReceiptVersion {
+		return fmt.Errorf("receipt: unsupported version")
+	}
+	if r.Method == "" {
+		return fmt.Errorf("receipt: missing method")
+	}
+	if len(r.Response) == 0 {
+		return fmt.Errorf("receipt: missing response")
+	}
+	return nil
+}
+
+// DecodeResponse decodes the captured response into the given value.
+func (r *Receipt) DecodeResponse(dst interface{}) error {
+	return cbor.Unmarshal(r.Response, dst)
+}
+
+// Sign signs the receipt for the given chain domain separation context.
+func (r *Receipt) Sign(ctx signature.Context, signer signature.Signer) (*SignedReceipt, error) {
+	if err := r.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	body := cbor.Marshal(r)
+	sig, err := signer.ContextSign(ctx.For(signature.ContextKindReceipt), body)
+	if err != nil {
+		return nil, fmt.Errorf("receipt: failed to sign receipt: %w", err)
+	}
+	return &SignedReceipt{
+		Body:      body,
+		PublicKey: types.PublicKey{PublicKey: signer.Public()},
+		Signature: sig,
+	}, nil
+}
+
+// Verify re-executes the query of the receipt and checks that the chain agrees with the receipt,
+// i.e. that the block at the receipt's round and the response to the query are the same. It fails
+// with ErrMismatch in case they differ.
+//
+// Note that the state of the given round must still be available to the queried node.
+func (r *Receipt) Verify(ctx context.Context, rc client.RuntimeClient) error {
+	info, err := rc.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("receipt: failed to query runtime info: %w", err)
+	}
+	if !info.ID.Equal(&r.RuntimeID) {
+		return fmt.Errorf("%w: runtime %s (expected: %s)", ErrMismatch, info.ID, r.RuntimeID)
+	}
+	blk, err := rc.GetBlock(ctx, r.Round)
+	if err != nil {
+		return fmt.Errorf("receipt: failed to fetch block: %w", err)
+	}
+	if h := blk.Header.EncodedHash(); !h.Equal(&r.BlockHash) {
+		return fmt.Errorf("%w: block hash %s (expected: %s)", ErrMismatch, h, r.BlockHash)
+	}
+
+	var args interface{}
+	if len(r.Args) > 0 {
+		args = r.Args
+	}
+	rsp, err := rc.QueryRaw(ctx, r.Round, r.Method, args)
+	if err != nil {
+		return fmt.Errorf("receipt: failed to re-execute query: %w", err)
+	}
+	if !bytes.Equal(rsp, r.Response) {
+		return fmt.Errorf("%w: response to %s", ErrMismatch, r.Method)
+	}
+	return nil
+}
+
+// SignedReceipt is a signed receipt, suitable for handing out to third parties.
+type SignedReceipt struct {
+	// Body is the CBOR-encoded Receipt.
+	Body []byte `json:"body"`
+	// PublicKey is the public key of the signer.
+	PublicKey types.PublicKey `json:"public_key"`
+	// Signature is the signature over the body.
+	Signature []byte `json:"signature"`
+}
+
+// Encode returns the portable encoding of the signed receipt.
+func (sr *SignedReceipt) Encode() []byte {
+	return cbor.Marshal(sr)
+}
+
+// Decode decodes a signed receipt previously encoded using Encode.
+func Decode(data []byte) (*SignedReceipt, error) {
+	var sr SignedReceipt
+	if err := cbor.Unmarshal(data, &sr); err != nil {
+		return nil, fmt.Errorf("receipt: malformed signed receipt: %w", err)
+	}
+	return &sr, nil
+}
+
+// Open verifies the signature of the signed receipt for the given chain domain separation context
+// and returns the receipt.
+//
+// The caller is responsible for checking that the signer is trusted and may use Receipt.Verify to
+// check the receipt against the chain.
+func (sr *SignedReceipt) Open(ctx signature.Context) (*Receipt, error) {
+	if sr.PublicKey.PublicKey == nil {
+		return nil, fmt.Errorf("receipt: missing public key")
+	}
+	if !sr.PublicKey.Verify(ctx.For(signature.ContextKindReceipt), sr.Body, sr.Signature) {
+		return nil, fmt.Errorf("receipt: signature verification failed")
+	}
+
+	var r Receipt
+	if err := cbor.Unmarshal(sr.Body, &r); err != nil {
+		return nil, fmt.Errorf("receipt: malformed body: %w", err)
+	}
+	if err := r.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,91 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+const latestRound = 10
+
+// balanceRuntimeClient serves balances that changed at the given round.
+type balanceRuntimeClient struct {
+	client.RuntimeClient
+
+	changedAt uint64
+}
+
+func (rc *balanceRuntimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ID: common.NewTestNamespaceFromSeed([]byte("receipt"), 0)}, nil
+}
+
+func (rc *balanceRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if round == client.RoundLatest {
+		round = latestRound
+	}
+	var blk block.Block
+	blk.Header.Round = round
+	blk.Header.StateRoot.FromBytes(cbor.Marshal(round))
+	return &blk, nil
+}
+
+func (rc *balanceRuntimeClient) QueryRaw(ctx context.Context, round uint64, method string, args interface{}) (cbor.RawMessage, error) {
+	if method != "accounts.Balances" {
+		return nil, errors.New("unknown method")
+	}
+	balance := uint64(100)
+	if round >= rc.changedAt {
+		balance = 50
+	}
+	return cbor.Marshal(map[string]interface{}{"balance": balance, "args": args}), nil
+}
+
+func TestReceipt(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	chainCtx := signature.Context("test")
+	rc := &balanceRuntimeClient{changedAt: latestRound + 1}
+
+	args := map[string]string{"address": sdkTesting.Alice.Address.String()}
+	r, err := Capture(ctx, rc, client.RoundLatest, "accounts.Balances", args)
+	require.NoError(err)
+	require.EqualValues(latestRound, r.Round, "the receipt should be pinned to the latest round")
+	var rsp struct {
+		Balance uint64            `json:"balance"`
+		Args    map[string]string `json:"args"`
+	}
+	require.NoError(r.DecodeResponse(&rsp))
+	require.EqualValues(100, rsp.Balance)
+	require.Equal(args, rsp.Args)
+
+	sr, err := r.Sign(chainCtx, sdkTesting.Alice.Signer)
+	require.NoError(err)
+	sr, err = Decode(sr.Encode())
+	require.NoError(err)
+	opened, err := sr.Open(chainCtx)
+	require.NoError(err)
+	require.Equal(r, opened)
+	_, err = sr.Open(signature.Context("other"))
+	require.Error(err, "receipts should be bound to the chain")
+
+	require.NoError(opened.Verify(ctx, rc))
+	rc.changedAt = latestRound
+	require.ErrorIs(opened.Verify(ctx, rc), ErrMismatch, "changed responses should be detected")
+
+	tampered := *opened
+	tampered.BlockHash = hash.NewFromBytes([]byte("other"))
+	require.ErrorIs(tampered.Verify(ctx, rc), ErrMismatch, "changed blocks should be detected")
+}
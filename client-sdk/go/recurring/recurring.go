@@ -0,0 +1,379 @@
+// Package recurring implements a scheduler executing recurring transfers from an account, e.g. for
+// payroll and subscriptions.
+//
+// Payments follow cron-like schedules (see ParseSpec) and may have an end date. When the account
+// lacks the funds for a run, the run is either skipped or retried until the next run is due,
+// depending on the payment's policy. The scheduler state is journaled in a file, so that it
+// survives restarts. Skipped and failed runs are reported as alerts.
+//
+// Runs missed while the scheduler was not running (e.g. during downtime) are collapsed into a
+// single run. Each run is recorded before its transfer is submitted, so that a crash can't cause a
+// run to be paid twice; a crash during the submission is instead reported as an alert with an
+// unknown outcome.
+package recurring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/account"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// DefaultInterval is the default interval at which Run checks for due payments.
+const DefaultInterval = time.Minute
+
+var (
+	// ErrDuplicate is the error returned when adding a payment with an existing identifier.
+	ErrDuplicate = errors.New("recurring: duplicate payment")
+	// ErrNotFound is the error returned when removing an unknown payment.
+	ErrNotFound = errors.New("recurring: payment not found")
+)
+
+// Policy is the policy applied to runs the account lacks the funds for.
+type Policy string
+
+const (
+	// SkipIfInsufficient skips the run.
+	SkipIfInsufficient Policy = "skip"
+	// RetryIfInsufficient retries the run until the funds suffice or the next run is due.
+	RetryIfInsufficient Policy = "retry"
+)
+
+// Payment is a recurring transfer.
+type Payment struct {
+	// ID is the identifier of the payment.
+	ID string `json:"id"`
+	// To is the recipient of the transfers.
+	To types.Address `json:"to"`
+	// Amount is the amount transferred on each run.
+	Amount types.BaseUnits `json:"amount"`
+	// Schedule is the schedule of the runs.
+	Schedule *Spec `json:"schedule"`
+	// Start is the time of the earliest run (the time the payment is added if zero).
+	Start time.Time `json:"start,omitempty"`
+	// End is the time after which no more runs are made (never if zero).
+	End time.Time `json:"end,omitempty"`
+	// Policy is the policy applied to runs the account lacks the funds for (SkipIfInsufficient
+	// if empty).
+	Policy Policy `json:"policy,omitempty"`
+}
+
+// State is the state of a payment.
+type State struct {
+	// Next is the time of the next run (zero if the payment ended).
+	Next time.Time `json:"next"`
+	// Runs is the number of successful runs.
+	Runs uint64 `json:"runs"`
+	// Skipped is the number of skipped runs.
+	Skipped uint64 `json:"skipped"`
+	// Failures is the number of failed runs.
+	Failures uint64 `json:"failures"`
+	// LastRound is the round the last successful transfer was executed in.
+	LastRound uint64 `json:"last_round,omitempty"`
+	// LastError is the reason the last run failed or was skipped.
+	LastError string `json:"last_error,omitempty"`
+	// InFlight is true while the transfer of a run is being submitted.
+	InFlight bool `json:"in_flight,omitempty"`
+}
+
+// Ended checks whether the payment ended.
+func (s *State) Ended() bool {
+	return s.Next.IsZero()
+}
+
+// AlertKind is the kind of an alert.
+type AlertKind string
+
+const (
+	// AlertSkipped means that a run was skipped because the account lacked the funds.
+	AlertSkipped AlertKind = "skipped"
+	// AlertRetrying means that a run is being retried because the account lacks the funds.
+	AlertRetrying AlertKind = "retrying"
+	// AlertFailed means that the transfer of a run failed.
+	AlertFailed AlertKind = "failed"
+	// AlertUnknownOutcome means that the scheduler stopped while submitting the transfer of a run,
+	// so the run may or may not have been paid.
+	AlertUnknownOutcome AlertKind = "unknown_outcome"
+)
+
+// Alert reports a problem with a run.
+type Alert struct {
+	// Payment is the identifier of the payment.
+	Payment string
+	// Kind is the kind of the alert.
+	Kind AlertKind
+	// Due is the time the run was due.
+	Due time.Time
+	// Err is the underlying error (if any).
+	Err error
+}
+
+// Option is an option for configuring the scheduler.
+type Option func(*options)
+
+type options struct {
+	interval time.Duration
+	onAlert  func(*Alert)
+	now      func() time.Time
+}
+
+// WithInterval sets the interval at which Run checks for due payments.
+func WithInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.interval = interval
+	}
+}
+
+// WithAlerts sets the function called with each alert.
+func WithAlerts(fn func(*Alert)) Option {
+	return func(opts *options) {
+		opts.onAlert = fn
+	}
+}
+
+// entry is a payment together with its state, as stored in the journal.
+type entry struct {
+	Payment Payment `json:"payment"`
+	State   State   `json:"state"`
+}
+
+// Scheduler executes recurring payments from an account.
+type Scheduler struct {
+	acct *account.Account
+	path string
+	opts options
+
+	l       sync.Mutex
+	entries map[string]*entry
+}
+
+// New creates a new scheduler paying from the given account, journaled in the given file. Payments
+// recorded in an existing journal are loaded.
+func New(acct *account.Account, path string, opts ...Option) (*Scheduler, error) {
+	s := &Scheduler{
+		acct: acct,
+		path: path,
+		opts: options{
+			interval: DefaultInterval,
+			now:      time.Now,
+		},
+		entries: make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("recurring: failed to read journal: %w", err)
+	}
+	var entries []*entry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("recurring: malformed journal: %w", err)
+	}
+	var interrupted bool
+	for _, e := range entries {
+		s.entries[e.Payment.ID] = e
+		if e.State.InFlight {
+			e.State.InFlight = false
+			interrupted = true
+			s.alert(&Alert{Payment: e.Payment.ID, Kind: AlertUnknownOutcome})
+		}
+	}
+	if interrupted {
+		if err = s.save(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds a new payment.
+func (s *Scheduler) Add(p Payment) error {
+	if p.Schedule == nil {
+		return fmt.Errorf("recurring: payment %s has no schedule", p.ID)
+	}
+	if p.Amount.Amount.IsZero() {
+		return fmt.Errorf("recurring: payment %s has a zero amount", p.ID)
+	}
+	switch p.Policy {
+	case "":
+		p.Policy = SkipIfInsufficient
+	case SkipIfInsufficient, RetryIfInsufficient:
+	default:
+		return fmt.Errorf("recurring: unknown policy %s", p.Policy)
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	if _, ok := s.entries[p.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicate, p.ID)
+	}
+	start := p.Start
+	if start.IsZero() {
+		start = s.opts.now()
+	}
+	e := &entry{Payment: p}
+	e.State.Next = s.next(&e.Payment, start.Add(-time.Nanosecond))
+	s.entries[p.ID] = e
+	return s.save()
+}
+
+// Remove removes the given payment.
+func (s *Scheduler) Remove(id string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if _, ok := s.entries[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(s.entries, id)
+	return s.save()
+}
+
+// State returns the state of the given payment.
+func (s *Scheduler) State(id string) (*State, bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, false
+	}
+	state := e.State
+	return &state, true
+}
+
+// Run executes due payments at the configured interval until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Tick(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick executes all payments that are due. Failed runs are reported as alerts, an error is only
+// returned in case the journal can't be stored.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	now := s.opts.now()
+	ids := make([]string, 0, len(s.entries))
+	for id, e := range s.entries {
+		if !e.State.Ended() && !e.State.Next.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := s.execute(ctx, s.entries[id], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute executes a due run of the given payment.
+func (s *Scheduler) execute(ctx context.Context, e *entry, now time.Time) error {
+	p, state := &e.Payment, &e.State
+	due := state.Next
+
+	balance, err := s.acct.Balance(ctx, p.Amount.Denomination)
+	switch {
+	case err != nil:
+		// Try again on the next tick.
+		state.LastError = err.Error()
+		return nil
+	case balance.Cmp(&p.Amount.Amount) < 0:
+		err = fmt.Errorf("recurring: insufficient balance %s for payment of %s", balance, p.Amount)
+		if p.Policy == RetryIfInsufficient && s.next(p, now).Equal(s.next(p, due)) {
+			// Retry on the next tick, alerting only once.
+			if state.LastError != err.Error() {
+				state.LastError = err.Error()
+				s.alert(&Alert{Payment: p.ID, Kind: AlertRetrying, Due: due, Err: err})
+			}
+			return s.save()
+		}
+		state.Next = s.next(p, now)
+		state.Skipped++
+		state.LastError = err.Error()
+		s.alert(&Alert{Payment: p.ID, Kind: AlertSkipped, Due: due, Err: err})
+		return s.save()
+	}
+
+	// Record the run before submitting it, so that it is never paid twice.
+	state.Next = s.next(p, now)
+	state.InFlight = true
+	if err = s.save(); err != nil {
+		return err
+	}
+
+	meta, err := s.acct.Transfer(ctx, p.To, p.Amount)
+	state.InFlight = false
+	if err != nil {
+		state.Failures++
+		state.LastError = err.Error()
+		s.alert(&Alert{Payment: p.ID, Kind: AlertFailed, Due: due, Err: err})
+	} else {
+		state.Runs++
+		state.LastRound = meta.Round
+		state.LastError = ""
+	}
+	return s.save()
+}
+
+// next returns the time of the first run of the given payment after the given time, or the zero
+// time in case the payment ends before.
+func (s *Scheduler) next(p *Payment, after time.Time) time.Time {
+	next := p.Schedule.Next(after)
+	if !p.End.IsZero() && next.After(p.End) {
+		return time.Time{}
+	}
+	return next
+}
+
+func (s *Scheduler) alert(a *Alert) {
+	if s.opts.onAlert != nil {
+		s.opts.onAlert(a)
+	}
+}
+
+// save atomically stores the journal.
+func (s *Scheduler) save() error {
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Payment.ID < entries[j].Payment.ID })
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("recurring: failed to marshal journal: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("recurring: failed to write journal: %w", err)
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("recurring: failed to write journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package recurring
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/account"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSpec(t *testing.T) {
+	require := require.New(t)
+
+	for _, s := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseSpec(s)
+		require.Error(err, "malformed schedule '%s' should be rejected", s)
+	}
+
+	at := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		require.NoError(err)
+		return tm
+	}
+	for _, tc := range []struct {
+		spec, after, next string
+	}{
+		{"* * * * *", "2024-01-01T10:00:30Z", "2024-01-01T10:01:00Z"},
+		{"* * * * *", "2024-01-01T10:00:00Z", "2024-01-01T10:01:00Z"},
+		{"*/15 * * * *", "2024-01-01T10:14:00Z", "2024-01-01T10:15:00Z"},
+		{"@hourly", "2024-01-01T23:30:00Z", "2024-01-02T00:00:00Z"},
+		{"0 9 1,15 * *", "2024-01-01T09:00:00Z", "2024-01-15T09:00:00Z"},
+		{"0 9 * * 1-5", "2024-01-05T10:00:00Z", "2024-01-08T09:00:00Z"},
+		{"0 0 * * 7", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"0 0 13 * 5", "2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z"},
+		{"@monthly", "2024-01-31T12:00:00Z", "2024-02-01T00:00:00Z"},
+		{"0 0 29 2 *", "2023-01-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"@yearly", "2024-06-01T00:00:00+02:00", "2025-01-01T00:00:00+02:00"},
+	} {
+		spec, err := ParseSpec(tc.spec)
+		require.NoError(err, "ParseSpec(%s)", tc.spec)
+		require.Equal(at(tc.next), spec.Next(at(tc.after)), "Next(%s) of '%s'", tc.after, tc.spec)
+	}
+
+	spec, err := ParseSpec("0 0 31 2 *")
+	require.NoError(err, "ParseSpec")
+	require.True(spec.Next(at("2024-01-01T00:00:00Z")).IsZero(), "schedules without activations should never activate")
+
+	var decoded Spec
+	require.NoError(decoded.UnmarshalText([]byte("@daily")), "UnmarshalText")
+	require.Equal("@daily", decoded.String())
+	require.Equal(at("2024-01-02T00:00:00Z"), decoded.Next(at("2024-01-01T00:00:00Z")))
+}
+
+// fakeChain executes transfers from an account with the given balance.
+type fakeChain struct {
+	client.RuntimeClient
+
+	nonce     uint64
+	round     uint64
+	balance   uint64
+	transfers []uint64
+}
+
+func (c *fakeChain) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: signature.Context("test chain")}, nil
+}
+
+func (c *fakeChain) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	switch method {
+	case "accounts.Nonce":
+		*rsp.(*uint64) = c.nonce
+	case "accounts.Balances":
+		rsp.(*accounts.AccountBalances).Balances = map[types.Denomination]types.Quantity{
+			types.NativeDenomination: *quantity.NewFromUint64(c.balance),
+		}
+	}
+	return nil
+}
+
+func (c *fakeChain) SubmitTxRawMeta(ctx context.Context, utx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	tx, err := utx.Verify(signature.Context("test chain"))
+	if err != nil {
+		return nil, err
+	}
+	var body accounts.Transfer
+	if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+		return nil, err
+	}
+	amount := body.Amount.Amount.ToBigInt().Uint64()
+
+	c.nonce++
+	c.round++
+	c.balance -= amount
+	c.transfers = append(c.transfers, amount)
+
+	var meta client.SubmitTxRawMeta
+	meta.Round = c.round
+	meta.Result.Ok = cbor.Marshal(nil)
+	return &meta, nil
+}
+
+func TestScheduler(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	chain := &fakeChain{round: 10, balance: 250}
+	acct := account.New(chain, sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec)
+	path := filepath.Join(t.TempDir(), "recurring.json")
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var alerts []*Alert
+	newScheduler := func() *Scheduler {
+		s, err := New(acct, path, WithAlerts(func(a *Alert) { alerts = append(alerts, a) }))
+		require.NoError(err, "New")
+		s.opts.now = func() time.Time { return now }
+		return s
+	}
+	s := newScheduler()
+
+	daily, err := ParseSpec("@daily")
+	require.NoError(err, "ParseSpec")
+	amount := func(v uint64) types.BaseUnits {
+		return types.NewBaseUnits(*quantity.NewFromUint64(v), types.NativeDenomination)
+	}
+	require.NoError(s.Add(Payment{
+		ID:       "salary",
+		To:       sdkTesting.Bob.Address,
+		Amount:   amount(100),
+		Schedule: daily,
+		End:      time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+	}), "Add")
+	require.NoError(s.Add(Payment{
+		ID:       "rent",
+		To:       sdkTesting.Charlie.Address,
+		Amount:   amount(100),
+		Schedule: daily,
+		Start:    now.Add(time.Hour),
+		Policy:   RetryIfInsufficient,
+	}), "Add")
+	require.ErrorIs(s.Add(Payment{ID: "rent", Amount: amount(1), Schedule: daily}), ErrDuplicate)
+
+	// Only the salary is due at the start.
+	require.NoError(s.Tick(ctx), "Tick")
+	require.Equal([]uint64{100}, chain.transfers)
+	state, ok := s.State("salary")
+	require.True(ok)
+	require.EqualValues(1, state.Runs)
+	require.EqualValues(11, state.LastRound)
+	require.Equal(now.AddDate(0, 0, 1), state.Next)
+
+	// Both are due the next day, when the balance doesn't suffice.
+	chain.balance = 50
+	now = now.AddDate(0, 0, 1)
+	require.NoError(s.Tick(ctx), "Tick")
+	require.Len(chain.transfers, 1)
+	require.Len(alerts, 2)
+	require.Equal("rent", alerts[0].Payment)
+	require.Equal(AlertRetrying, alerts[0].Kind)
+	require.Equal("salary", alerts[1].Payment)
+	require.Equal(AlertSkipped, alerts[1].Kind)
+	state, _ = s.State("salary")
+	require.EqualValues(1, state.Skipped)
+	require.Equal(now.AddDate(0, 0, 1), state.Next)
+	require.NoError(s.Tick(ctx), "Tick")
+	require.Len(alerts, 2, "retries should only be alerted once")
+
+	// The rent is retried once the balance suffices, also after a restart.
+	now = now.Add(time.Hour)
+	chain.balance += 100
+	s = newScheduler()
+	require.NoError(s.Tick(ctx), "Tick")
+	require.Equal([]uint64{100, 100}, chain.transfers)
+	state, _ = s.State("rent")
+	require.EqualValues(1, state.Runs)
+	require.Empty(state.LastError)
+
+	// Missed runs are collapsed and the salary ends.
+	chain.balance = 1000
+	now = now.AddDate(0, 0, 5)
+	require.NoError(s.Tick(ctx), "Tick")
+	require.Equal([]uint64{100, 100, 100, 100}, chain.transfers)
+	state, _ = s.State("salary")
+	require.True(state.Ended())
+	require.EqualValues(2, state.Runs)
+	state, _ = s.State("rent")
+	require.EqualValues(2, state.Runs)
+	require.False(state.Ended())
+
+	require.NoError(s.Remove("rent"), "Remove")
+	require.ErrorIs(s.Remove("rent"), ErrNotFound)
+}
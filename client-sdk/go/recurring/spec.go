@@ -0,0 +1,177 @@
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds the search for the next activation of a schedule.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// aliases are the predefined schedules.
+var aliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Spec is a cron-like schedule.
+type Spec struct {
+	raw string
+
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are true iff the day of month or day of week fields are "*".
+	anyDay, anyWeekday bool
+}
+
+// ParseSpec parses a schedule in the standard five field cron format (minute, hour, day of month,
+// month and day of week), e.g. "0 9 1,15 * *" for 9:00 on the 1st and 15th of every month. Fields
+// support lists, ranges and steps (e.g. "1-5", "*/15" and "0-30/10"). The aliases @hourly, @daily,
+// @weekly, @monthly and @yearly are also supported.
+//
+// As in cron, a schedule restricting both the day of month and the day of week activates on days
+// matching either of them.
+func ParseSpec(spec string) (*Spec, error) {
+	raw := strings.TrimSpace(spec)
+	if alias, ok := aliases[raw]; ok {
+		raw = alias
+	}
+	fields := strings.Fields(raw)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("recurring: schedule '%s' must have 5 fields", spec)
+	}
+
+	s := Spec{
+		raw:        spec,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+	for _, f := range []struct {
+		field    string
+		min, max int
+		bits     *uint64
+	}{
+		{fields[0], 0, 59, &s.minutes},
+		{fields[1], 0, 23, &s.hours},
+		{fields[2], 1, 31, &s.days},
+		{fields[3], 1, 12, &s.months},
+		{fields[4], 0, 7, &s.weekdays},
+	} {
+		bits, err := parseField(f.field, f.min, f.max)
+		if err != nil {
+			return nil, fmt.Errorf("recurring: malformed schedule '%s': %w", spec, err)
+		}
+		*f.bits = bits
+	}
+	// Both 0 and 7 are Sunday.
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	return &s, nil
+}
+
+// parseField parses a single schedule field into a bit set of the matching values.
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			rng = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range '%s'", rng)
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", rng)
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("'%s' out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String returns the schedule as it was given to ParseSpec.
+func (s *Spec) String() string {
+	return s.raw
+}
+
+// MarshalText encodes the schedule as text.
+func (s *Spec) MarshalText() ([]byte, error) {
+	return []byte(s.raw), nil
+}
+
+// UnmarshalText decodes a text-encoded schedule.
+func (s *Spec) UnmarshalText(text []byte) error {
+	parsed, err := ParseSpec(string(text))
+	if err != nil {
+		return err
+	}
+	*s = *parsed
+	return nil
+}
+
+// Next returns the first activation of the schedule strictly after the given time, in the time's
+// location. It returns the zero time in case there is none within the next five years (e.g. for
+// "0 0 31 2 *").
+func (s *Spec) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for end := after.Add(maxSearch); t.Before(end); {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay checks whether the day of the given time matches the schedule.
+func (s *Spec) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
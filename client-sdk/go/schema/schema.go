@@ -0,0 +1,264 @@
+// Package schema emits JSON Schema definitions of the JSON encoding of SDK types, so that REST
+// facades and non-Go consumers can validate payloads against the shapes used by the SDK.
+//
+// Schemas are derived from the Go type definitions using the rules of encoding/json and follow
+// JSON Schema draft 2020-12, which can also be used for OpenAPI 3.1 component schemas. Named
+// struct types are emitted as definitions (e.g. "#/$defs/accounts.Transfer").
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// Draft is the JSON Schema version of the emitted schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type            string   `json:"type,omitempty"`
+	Pattern         string   `json:"pattern,omitempty"`
+	ContentEncoding string   `json:"contentEncoding,omitempty"`
+	Minimum         *int64   `json:"minimum,omitempty"`
+	Maximum         *uint64  `json:"maximum,omitempty"`
+	Items           *Schema  `json:"items,omitempty"`
+	MinItems        *int     `json:"minItems,omitempty"`
+	MaxItems        *int     `json:"maxItems,omitempty"`
+	Required        []string `json:"required,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Document is a schema document describing the call bodies, query arguments and events of the
+// registered runtime modules (see client.RegisterModule).
+//
+// The document itself is a JSON Schema holding all definitions. Calls, Queries and Events index
+// the definitions by method name and event, e.g. a consumer can validate accounts.Transfer call
+// bodies against Calls["accounts.Transfer"].
+type Document struct {
+	Schema string             `json:"$schema"`
+	Defs   map[string]*Schema `json:"$defs"`
+
+	// Calls are the schemas of call bodies by method name.
+	Calls map[string]*Schema `json:"calls"`
+	// Queries are the schemas of query arguments by method name. Queries without arguments are
+	// omitted.
+	Queries map[string]*Schema `json:"queries"`
+	// Events are the schemas of individual events by module and event code.
+	Events map[string]map[uint32]*Schema `json:"events"`
+}
+
+// known are the schemas of types whose JSON encoding is not derived from their definition.
+var known = map[reflect.Type]*Schema{
+	reflect.TypeOf(quantity.Quantity{}): {Type: "string", Pattern: "^[0-9]+$"},
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator generates schemas, collecting the definitions of named struct types.
+type generator struct {
+	defs     map[string]*Schema
+	defTypes map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		defs:     make(map[string]*Schema),
+		defTypes: make(map[reflect.Type]string),
+	}
+}
+
+// For returns a standalone schema of the JSON encoding of values of the type of v.
+func For(v interface{}) *Schema {
+	g := newGenerator()
+	s := g.schemaFor(reflect.TypeOf(v))
+	s.Schema = Draft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return s
+}
+
+// Registered returns the schema document of all modules registered with the client. Modules are
+// registered by importing their client packages.
+func Registered() *Document {
+	g := newGenerator()
+	doc := Document{
+		Schema:  Draft,
+		Calls:   make(map[string]*Schema),
+		Queries: make(map[string]*Schema),
+		Events:  make(map[string]map[uint32]*Schema),
+	}
+	for _, name := range client.RegisteredModules() {
+		desc, _ := client.LookupModule(name)
+		for method, body := range desc.Methods {
+			doc.Calls[method] = g.schemaFor(reflect.TypeOf(body))
+			doc.Calls[method].Description = desc.MethodInfos[method].Description
+		}
+		for method, args := range desc.Queries {
+			if args == nil {
+				continue
+			}
+			doc.Queries[method] = g.schemaFor(reflect.TypeOf(args))
+		}
+		if len(desc.EventTypes) > 0 {
+			doc.Events[name] = make(map[uint32]*Schema, len(desc.EventTypes))
+		}
+		for code, ev := range desc.EventTypes {
+			doc.Events[name][code] = g.schemaFor(reflect.TypeOf(ev))
+		}
+	}
+	doc.Defs = g.defs
+	return &doc
+}
+
+// schemaFor returns the schema of the given type.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		// Methods without a body.
+		return &Schema{Type: "null"}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if s, ok := known[t]; ok {
+		c := *s
+		return &c
+	}
+
+	ptr := reflect.PtrTo(t)
+	switch {
+	case t.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType):
+		// Custom encoding that can't be derived, so accept anything.
+		return &Schema{Description: fmt.Sprintf("custom JSON encoding of %s", t)}
+	case t.Implements(textMarshalerType) || ptr.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var min int64
+		max := ^uint64(0) >> (64 - t.Bits())
+		return &Schema{Type: "integer", Minimum: &min, Maximum: &max}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Array:
+		n := t.Len()
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem()), MinItems: &n, MaxItems: &n}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t)
+	default:
+		// Interfaces can hold anything.
+		return &Schema{}
+	}
+}
+
+// ref returns a reference to the definition of the given named struct type, generating the
+// definition if needed.
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, ok := g.defTypes[t]
+	if !ok {
+		name = path.Base(t.PkgPath()) + "." + t.Name()
+		if _, taken := g.defs[name]; taken {
+			// Different packages with the same name.
+			name = t.PkgPath() + "." + t.Name()
+		}
+		g.defTypes[t] = name
+		// Reserve the name before generating the definition to support recursive types.
+		g.defs[name] = nil
+		g.defs[name] = g.structSchema(t)
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+// structSchema returns the schema of the given struct type.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	g.addFields(&s, t)
+	sort.Strings(s.Required)
+	return &s
+}
+
+// addFields adds the properties of the fields of the given struct type, including the fields of
+// embedded structs.
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(s, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schemaFor(f.Type)
+		if hasOption(opts, "string") {
+			fs = &Schema{Type: "string"}
+		}
+		s.Properties[name] = fs
+		if !hasOption(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// hasOption checks whether the given comma-separated JSON tag options contain the given option.
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
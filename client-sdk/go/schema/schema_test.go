@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+	Ignored  bool    `json:"-"`
+	Count    uint64  `json:"count,string"`
+	Data     []byte  `json:"data,omitempty"`
+	Hash     [2]byte `json:"hash"`
+	private  bool    //nolint: unused
+}
+
+func TestFor(t *testing.T) {
+	require := require.New(t)
+
+	s := For(&node{})
+	require.Equal(Draft, s.Schema)
+	require.Equal("#/$defs/schema.node", s.Ref)
+	def := s.Defs["schema.node"]
+	require.NotNil(def)
+	require.Equal([]string{"count", "hash", "name"}, def.Required)
+	require.Len(def.Properties, 5, "ignored and unexported fields should be skipped")
+	require.Equal("#/$defs/schema.node", def.Properties["children"].Items.Ref, "recursive types should be referenced")
+	require.Equal("string", def.Properties["count"].Type)
+	require.Equal("base64", def.Properties["data"].ContentEncoding)
+	require.Equal("array", def.Properties["hash"].Type)
+	require.EqualValues(2, *def.Properties["hash"].MaxItems)
+
+	s = For(uint8(0))
+	require.Equal("integer", s.Type)
+	require.EqualValues(255, *s.Maximum)
+}
+
+func TestRegistered(t *testing.T) {
+	require := require.New(t)
+
+	doc := Registered()
+	require.Equal("#/$defs/accounts.Transfer", doc.Calls["accounts.Transfer"].Ref)
+	require.Equal("Transfer tokens to another account.", doc.Calls["accounts.Transfer"].Description)
+	require.Equal("#/$defs/accounts.NonceQuery", doc.Queries["accounts.Nonce"].Ref)
+	require.NotContains(doc.Queries, "accounts.Parameters", "queries without arguments should be omitted")
+	require.Equal("#/$defs/accounts.MintEvent", doc.Events[accounts.ModuleName][accounts.MintEventCode].Ref)
+
+	transfer := doc.Defs["accounts.Transfer"]
+	require.Equal("string", transfer.Properties["to"].Type, "addresses are encoded as text")
+	require.Equal("#/$defs/types.BaseUnits", transfer.Properties["amount"].Ref)
+	baseUnits := doc.Defs["types.BaseUnits"]
+	require.Equal([]string{"Amount", "Denomination"}, baseUnits.Required)
+	require.Equal("^[0-9]+$", baseUnits.Properties["Amount"].Pattern)
+
+	// The schema should describe the actual encoding.
+	data, err := json.Marshal(&accounts.Transfer{Amount: types.NewBaseUnits(*quantity.NewQuantity(), types.NativeDenomination)})
+	require.NoError(err)
+	var encoded map[string]interface{}
+	require.NoError(json.Unmarshal(data, &encoded))
+	for field := range encoded {
+		require.Contains(transfer.Properties, field)
+	}
+
+	_, err = json.Marshal(doc)
+	require.NoError(err, "the document should be serializable")
+}
@@ -0,0 +1,194 @@
+// Package session implements short-lived session keys that sign a restricted set of methods on
+// behalf of a cold key for a limited time.
+//
+// The runtime has no on-chain delegation, so the authorization is an off-chain grant signed by
+// the cold key. Transactions signed with a session key are sent from the session key's own
+// account; services that accept them (e.g. relayers or policy services) verify the grant using
+// Grant.Authorizes. The session key itself refuses to sign anything its grant does not cover, so
+// a web service holding it can't be tricked into signing other transactions.
+package session
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrNotAuthorized is the error returned when a session key is used outside of its grant.
+var ErrNotAuthorized = errors.New("session: not authorized")
+
+// Grant is the authorization of a session key by its grantor.
+type Grant struct {
+	// SessionKey is the signature address specification of the session key.
+	SessionKey types.SignatureAddressSpec `json:"session_key"`
+	// Methods are the methods the session key may sign transactions for.
+	Methods []string `json:"methods"`
+	// NotAfter is the POSIX timestamp after which the grant expires.
+	NotAfter uint64 `json:"not_after"`
+}
+
+// Expiry returns the time at which the grant expires.
+func (g *Grant) Expiry() time.Time {
+	return time.Unix(int64(g.NotAfter), 0)
+}
+
+// allows checks whether the grant allows signing a call to the given method at the given time.
+func (g *Grant) allows(method string, now time.Time) error {
+	if now.After(g.Expiry()) {
+		return fmt.Errorf("%w: grant expired at %s", ErrNotAuthorized, g.Expiry())
+	}
+	for _, m := range g.Methods {
+		if m == method {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: method '%s' not granted", ErrNotAuthorized, method)
+}
+
+// Authorizes checks whether the grant authorizes the given verified transaction at the given time.
+// The transaction must have been signed by the session key alone.
+func (g *Grant) Authorizes(tx *types.Transaction, now time.Time) error {
+	if len(tx.AuthInfo.SignerInfo) != 1 {
+		return fmt.Errorf("%w: transaction must have a single signer", ErrNotAuthorized)
+	}
+	spec := tx.AuthInfo.SignerInfo[0].AddressSpec.Signature
+	if spec == nil || !types.NewAddress(*spec).Equal(types.NewAddress(g.SessionKey)) {
+		return fmt.Errorf("%w: transaction not signed by the session key", ErrNotAuthorized)
+	}
+	if tx.Call.Format != types.CallFormatPlain {
+		return fmt.Errorf("%w: confidential calls are not supported", ErrNotAuthorized)
+	}
+	return g.allows(tx.Call.Method, now)
+}
+
+// SignedGrant is a grant signed by the grantor.
+type SignedGrant struct {
+	// Body is the CBOR-encoded Grant.
+	Body []byte `json:"body"`
+	// PublicKey is the public key of the grantor.
+	PublicKey types.PublicKey `json:"public_key"`
+	// Signature is the grantor's signature over the body.
+	Signature []byte `json:"signature"`
+}
+
+// Open verifies the signed grant for the given chain domain separation context and returns the
+// grant.
+func (sg *SignedGrant) Open(ctx signature.Context) (*Grant, error) {
+	if sg.PublicKey.PublicKey == nil {
+		return nil, fmt.Errorf("session: missing grantor public key")
+	}
+	if err := types.VerifyMessage(ctx, sg.PublicKey.PublicKey, sg.Body, sg.Signature); err != nil {
+		return nil, fmt.Errorf("session: grant signature verification failed: %w", err)
+	}
+	var grant Grant
+	if err := cbor.Unmarshal(sg.Body, &grant); err != nil {
+		return nil, fmt.Errorf("session: malformed grant: %w", err)
+	}
+	return &grant, nil
+}
+
+// Key is a session key. It implements signature.Signer, but only signs transactions allowed by
+// its grant.
+type Key struct {
+	signer signature.Signer
+	grant  Grant
+	signed SignedGrant
+
+	now func() time.Time
+}
+
+var _ signature.Signer = (*Key)(nil)
+
+// New generates a new session key allowed to sign transactions calling the given methods during
+// the given period, and has the grantor sign the grant for the given chain domain separation
+// context.
+func New(ctx signature.Context, grantor signature.Signer, methods []string, ttl time.Duration) (*Key, error) {
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("session: no methods granted")
+	}
+	coreSigner, err := memorySigner.NewSigner(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("session: failed to generate key: %w", err)
+	}
+	signer := ed25519.WrapSigner(coreSigner)
+
+	grant := Grant{
+		SessionKey: types.NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey)),
+		Methods:    methods,
+		NotAfter:   uint64(time.Now().Add(ttl).Unix()),
+	}
+	body := cbor.Marshal(&grant)
+	sig, err := types.SignMessage(ctx, grantor, body)
+	if err != nil {
+		signer.Reset()
+		return nil, fmt.Errorf("session: failed to sign grant: %w", err)
+	}
+	return &Key{
+		signer: signer,
+		grant:  grant,
+		signed: SignedGrant{
+			Body:      body,
+			PublicKey: types.PublicKey{PublicKey: grantor.Public()},
+			Signature: sig,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Grant returns the signed grant, to be presented to services accepting the session key.
+func (k *Key) Grant() *SignedGrant {
+	return &k.signed
+}
+
+// SigSpec returns the signature address specification of the session key.
+func (k *Key) SigSpec() types.SignatureAddressSpec {
+	return k.grant.SessionKey
+}
+
+// Public implements signature.Signer.
+func (k *Key) Public() signature.PublicKey {
+	return k.signer.Public()
+}
+
+// ContextSign implements signature.Signer. Only transactions calling a granted method are signed
+// and only until the grant expires.
+func (k *Key) ContextSign(context, message []byte) ([]byte, error) {
+	if !bytes.HasPrefix(context, types.SignatureContextBase) {
+		return nil, fmt.Errorf("%w: only transactions may be signed", ErrNotAuthorized)
+	}
+	var tx types.Transaction
+	if err := cbor.Unmarshal(message, &tx); err != nil {
+		return nil, fmt.Errorf("session: malformed transaction: %w", err)
+	}
+	if tx.Call.Format != types.CallFormatPlain {
+		return nil, fmt.Errorf("%w: confidential calls are not supported", ErrNotAuthorized)
+	}
+	if err := k.grant.allows(tx.Call.Method, k.now()); err != nil {
+		return nil, err
+	}
+	return k.signer.ContextSign(context, message)
+}
+
+// Sign implements signature.Signer. Signing without a context is never allowed.
+func (k *Key) Sign(message []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%w: signing without context", ErrNotAuthorized)
+}
+
+// String implements signature.Signer.
+func (k *Key) String() string {
+	return fmt.Sprintf("session key %s (expires %s)", k.signer.Public(), k.grant.Expiry())
+}
+
+// Reset implements signature.Signer.
+func (k *Key) Reset() {
+	k.signer.Reset()
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSessionKey(t *testing.T) {
+	require := require.New(t)
+
+	chainCtx := signature.Context("test chain")
+	_, err := New(chainCtx, sdkTesting.Alice.Signer, nil, time.Hour)
+	require.Error(err, "session keys without methods should be rejected")
+
+	key, err := New(chainCtx, sdkTesting.Alice.Signer, []string{"accounts.Transfer"}, time.Hour)
+	require.NoError(err, "New")
+
+	grant, err := key.Grant().Open(chainCtx)
+	require.NoError(err, "Open")
+	require.True(key.Grant().PublicKey.PublicKey.Equal(sdkTesting.Alice.Signer.Public()))
+	_, err = key.Grant().Open(signature.Context("other chain"))
+	require.Error(err, "grants for other chains should not verify")
+
+	sign := func(method string) (*types.UnverifiedTransaction, *types.Transaction, error) {
+		tx := types.NewTransaction(nil, method, nil)
+		tx.AppendAuthSignature(key.SigSpec(), 0)
+		ts := tx.PrepareForSigning()
+		if err := ts.AppendSign(chainCtx, key); err != nil {
+			return nil, nil, err
+		}
+		return ts.UnverifiedTransaction(), tx, nil
+	}
+
+	ut, _, err := sign("accounts.Transfer")
+	require.NoError(err, "granted methods should be signed")
+	verified, err := ut.Verify(chainCtx)
+	require.NoError(err)
+	require.NoError(grant.Authorizes(verified, time.Now()))
+	require.ErrorIs(grant.Authorizes(verified, time.Now().Add(2*time.Hour)), ErrNotAuthorized, "expired grants should not authorize")
+
+	_, _, err = sign("accounts.MintST")
+	require.ErrorIs(err, ErrNotAuthorized, "other methods should not be signed")
+
+	_, err = types.SignMessage(chainCtx, key, []byte("hello"))
+	require.True(errors.Is(err, ErrNotAuthorized), "off-chain messages should not be signed")
+
+	key.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, _, err = sign("accounts.Transfer")
+	require.ErrorIs(err, ErrNotAuthorized, "expired session keys should not sign")
+
+	other := types.NewTransaction(nil, "accounts.Transfer", nil)
+	other.AppendAuthSignature(sdkTesting.Bob.SigSpec, 0)
+	require.ErrorIs(grant.Authorizes(other, time.Now()), ErrNotAuthorized, "transactions signed by other keys should not be authorized")
+}
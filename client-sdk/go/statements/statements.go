@@ -0,0 +1,264 @@
+// Package statements assembles periodic account statements (opening and closing balances,
+// transfers, mints and burns) from accounts events, so that report renderers can produce customer
+// statements, e.g. as PDF or CSV.
+package statements
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// IndexedEvent is an accounts event together with the block it was emitted in.
+type IndexedEvent struct {
+	// Round is the round in which the event was emitted.
+	Round uint64
+	// Timestamp is the timestamp of the block in which the event was emitted.
+	Timestamp time.Time
+	// Event is the event.
+	Event *accounts.Event
+}
+
+// Source is the source of the data statements are assembled from, e.g. an indexer.
+type Source interface {
+	// Balances returns the balances of the given account at the given round.
+	Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error)
+	// Timestamp returns the timestamp of the block at the given round.
+	Timestamp(ctx context.Context, round uint64) (time.Time, error)
+	// ForEachEvent calls the given function for all accounts events emitted in the given range of
+	// rounds (inclusive), in order. Iteration stops at the first error.
+	ForEachEvent(ctx context.Context, from, to uint64, fn func(*IndexedEvent) error) error
+}
+
+// nodeSource is a source querying a node directly.
+type nodeSource struct {
+	rc client.RuntimeClient
+	ac accounts.V1
+}
+
+// NewNodeSource creates a new source querying the given node directly instead of an indexer. As
+// this fetches the events of each round in the statement period, it is only suitable for short
+// periods.
+func NewNodeSource(rc client.RuntimeClient) Source {
+	return &nodeSource{
+		rc: rc,
+		ac: accounts.NewV1(rc),
+	}
+}
+
+func (s *nodeSource) Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error) {
+	return s.ac.Balances(ctx, round, address)
+}
+
+func (s *nodeSource) Timestamp(ctx context.Context, round uint64) (time.Time, error) {
+	blk, err := s.rc.GetBlock(ctx, round)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(blk.Header.Timestamp), 0).UTC(), nil
+}
+
+func (s *nodeSource) ForEachEvent(ctx context.Context, from, to uint64, fn func(*IndexedEvent) error) error {
+	for round := from; round <= to; round++ {
+		evs, err := s.ac.GetEvents(ctx, round)
+		if err != nil {
+			return fmt.Errorf("statements: failed to fetch events at round %d: %w", round, err)
+		}
+		if len(evs) == 0 {
+			continue
+		}
+		ts, err := s.Timestamp(ctx, round)
+		if err != nil {
+			return fmt.Errorf("statements: failed to fetch block %d: %w", round, err)
+		}
+		for _, ev := range evs {
+			if err = fn(&IndexedEvent{Round: round, Timestamp: ts, Event: ev}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EntryKind is the kind of a statement entry.
+type EntryKind string
+
+const (
+	// EntryTransferIn is a transfer received from another account.
+	EntryTransferIn EntryKind = "transfer_in"
+	// EntryTransferOut is a transfer sent to another account.
+	EntryTransferOut EntryKind = "transfer_out"
+	// EntryMint is a mint to the account.
+	EntryMint EntryKind = "mint"
+	// EntryBurn is a burn from the account.
+	EntryBurn EntryKind = "burn"
+)
+
+// IsCredit checks whether entries of the given kind increase the balance.
+func (k EntryKind) IsCredit() bool {
+	return k == EntryTransferIn || k == EntryMint
+}
+
+// Entry is a single balance change on a statement.
+type Entry struct {
+	// Round is the round in which the change happened.
+	Round uint64 `json:"round"`
+	// Timestamp is the timestamp of the block in which the change happened.
+	Timestamp time.Time `json:"timestamp"`
+	// Kind is the kind of the change.
+	Kind EntryKind `json:"kind"`
+	// Counterparty is the other account of transfers.
+	Counterparty *types.Address `json:"counterparty,omitempty"`
+	// Amount is the amount by which the balance changed.
+	Amount types.Quantity `json:"amount"`
+	// Event is the metadata of the event the entry was derived from.
+	Event types.EventMeta `json:"event"`
+}
+
+// Statement is an account statement for a period of rounds.
+type Statement struct {
+	// Address is the address of the account.
+	Address types.Address `json:"address"`
+	// Denomination is the denomination the statement is for.
+	Denomination types.Denomination `json:"denomination"`
+
+	// FromRound is the first round of the period.
+	FromRound uint64 `json:"from_round"`
+	// ToRound is the last round of the period.
+	ToRound uint64 `json:"to_round"`
+	// From is the timestamp of the block at FromRound.
+	From time.Time `json:"from"`
+	// To is the timestamp of the block at ToRound.
+	To time.Time `json:"to"`
+
+	// OpeningBalance is the balance before the first round of the period.
+	OpeningBalance types.Quantity `json:"opening_balance"`
+	// ClosingBalance is the balance after the last round of the period.
+	ClosingBalance types.Quantity `json:"closing_balance"`
+	// TotalIn is the sum of all credits.
+	TotalIn types.Quantity `json:"total_in"`
+	// TotalOut is the sum of all debits.
+	TotalOut types.Quantity `json:"total_out"`
+
+	// Entries are the balance changes in order.
+	Entries []Entry `json:"entries"`
+
+	// Reconciled is true iff the opening balance plus credits minus debits equals the closing
+	// balance. Otherwise the balance also changed in ways not covered by events, e.g. by paying
+	// transaction fees.
+	Reconciled bool `json:"reconciled"`
+}
+
+// Build assembles the statement of the given account in the given denomination for the given
+// range of rounds (inclusive).
+func Build(
+	ctx context.Context,
+	src Source,
+	address types.Address,
+	denomination types.Denomination,
+	from, to uint64,
+) (*Statement, error) {
+	if from > to {
+		return nil, fmt.Errorf("statements: invalid period %d-%d", from, to)
+	}
+
+	s := Statement{
+		Address:      address,
+		Denomination: denomination,
+		FromRound:    from,
+		ToRound:      to,
+		Entries:      []Entry{},
+	}
+	var err error
+	if s.From, err = src.Timestamp(ctx, from); err != nil {
+		return nil, fmt.Errorf("statements: failed to fetch block %d: %w", from, err)
+	}
+	if s.To, err = src.Timestamp(ctx, to); err != nil {
+		return nil, fmt.Errorf("statements: failed to fetch block %d: %w", to, err)
+	}
+	if from > 0 {
+		if s.OpeningBalance, err = balance(ctx, src, from-1, address, denomination); err != nil {
+			return nil, err
+		}
+	}
+	if s.ClosingBalance, err = balance(ctx, src, to, address, denomination); err != nil {
+		return nil, err
+	}
+
+	err = src.ForEachEvent(ctx, from, to, func(ev *IndexedEvent) error {
+		for _, entry := range entriesOf(ev, address, denomination) {
+			total := &s.TotalOut
+			if entry.Kind.IsCredit() {
+				total = &s.TotalIn
+			}
+			if err := total.Add(&entry.Amount); err != nil {
+				return fmt.Errorf("statements: failed to sum amounts: %w", err)
+			}
+			s.Entries = append(s.Entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expected := s.OpeningBalance.Clone()
+	if err = expected.Add(&s.TotalIn); err == nil {
+		err = expected.Sub(&s.TotalOut)
+	}
+	s.Reconciled = err == nil && expected.Cmp(&s.ClosingBalance) == 0
+	return &s, nil
+}
+
+// balance returns the balance of the given account in the given denomination.
+func balance(ctx context.Context, src Source, round uint64, address types.Address, denomination types.Denomination) (types.Quantity, error) {
+	balances, err := src.Balances(ctx, round, address)
+	if err != nil {
+		return types.Quantity{}, fmt.Errorf("statements: failed to query balances at round %d: %w", round, err)
+	}
+	return balances.Balances[denomination], nil
+}
+
+// entriesOf returns the statement entries of the given account derived from the given event.
+func entriesOf(ev *IndexedEvent, address types.Address, denomination types.Denomination) []Entry {
+	newEntry := func(kind EntryKind, counterparty *types.Address, amount types.BaseUnits) Entry {
+		return Entry{
+			Round:        ev.Round,
+			Timestamp:    ev.Timestamp,
+			Kind:         kind,
+			Counterparty: counterparty,
+			Amount:       amount.Amount,
+			Event:        ev.Event.Metadata(),
+		}
+	}
+
+	var entries []Entry
+	switch {
+	case ev.Event.Transfer != nil:
+		t := ev.Event.Transfer
+		if t.Amount.Denomination != denomination {
+			break
+		}
+		if t.From.Equal(address) {
+			to := t.To
+			entries = append(entries, newEntry(EntryTransferOut, &to, t.Amount))
+		}
+		if t.To.Equal(address) {
+			from := t.From
+			entries = append(entries, newEntry(EntryTransferIn, &from, t.Amount))
+		}
+	case ev.Event.Mint != nil:
+		if ev.Event.Mint.Owner.Equal(address) && ev.Event.Mint.Amount.Denomination == denomination {
+			entries = append(entries, newEntry(EntryMint, nil, ev.Event.Mint.Amount))
+		}
+	case ev.Event.Burn != nil:
+		if ev.Event.Burn.Owner.Equal(address) && ev.Event.Burn.Amount.Denomination == denomination {
+			entries = append(entries, newEntry(EntryBurn, nil, ev.Event.Burn.Amount))
+		}
+	}
+	return entries
+}
@@ -0,0 +1,101 @@
+package statements
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	alice = sdkTesting.Alice.Address
+	bob   = sdkTesting.Bob.Address
+)
+
+func amount(v uint64) types.BaseUnits {
+	return types.NewBaseUnits(*quantity.NewFromUint64(v), types.NativeDenomination)
+}
+
+// fakeSource serves the given balances of alice and events.
+type fakeSource struct {
+	balances map[uint64]uint64
+	events   map[uint64][]*accounts.Event
+}
+
+func (s *fakeSource) Balances(ctx context.Context, round uint64, address types.Address) (*accounts.AccountBalances, error) {
+	return &accounts.AccountBalances{Balances: map[types.Denomination]types.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(s.balances[round]),
+	}}, nil
+}
+
+func (s *fakeSource) Timestamp(ctx context.Context, round uint64) (time.Time, error) {
+	return time.Unix(int64(1000+round), 0).UTC(), nil
+}
+
+func (s *fakeSource) ForEachEvent(ctx context.Context, from, to uint64, fn func(*IndexedEvent) error) error {
+	for round := from; round <= to; round++ {
+		ts, _ := s.Timestamp(ctx, round)
+		for _, ev := range s.events[round] {
+			if err := fn(&IndexedEvent{Round: round, Timestamp: ts, Event: ev}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestBuild(t *testing.T) {
+	require := require.New(t)
+
+	other := types.NewBaseUnits(*quantity.NewFromUint64(1000), "OTHER")
+	src := &fakeSource{
+		balances: map[uint64]uint64{9: 100, 12: 170},
+		events: map[uint64][]*accounts.Event{
+			9:  {{Mint: &accounts.MintEvent{Owner: alice, Amount: amount(100)}}},
+			10: {{Transfer: &accounts.TransferEvent{From: bob, To: alice, Amount: amount(50)}}},
+			11: {
+				{Transfer: &accounts.TransferEvent{From: alice, To: bob, Amount: amount(30)}},
+				{Transfer: &accounts.TransferEvent{From: alice, To: bob, Amount: other}},
+				{Transfer: &accounts.TransferEvent{From: bob, To: bob, Amount: amount(5)}},
+			},
+			12: {
+				{Mint: &accounts.MintEvent{Owner: alice, Amount: amount(70)}},
+				{Burn: &accounts.BurnEvent{Owner: alice, Amount: amount(20)}},
+			},
+		},
+	}
+
+	s, err := Build(context.Background(), src, alice, types.NativeDenomination, 10, 12)
+	require.NoError(err)
+	require.Equal(time.Unix(1010, 0).UTC(), s.From)
+	require.Equal(time.Unix(1012, 0).UTC(), s.To)
+	require.EqualValues(100, s.OpeningBalance.ToBigInt().Uint64())
+	require.EqualValues(170, s.ClosingBalance.ToBigInt().Uint64())
+	require.EqualValues(120, s.TotalIn.ToBigInt().Uint64())
+	require.EqualValues(50, s.TotalOut.ToBigInt().Uint64())
+	require.True(s.Reconciled)
+
+	require.Len(s.Entries, 4, "only entries of the account in the denomination should be included")
+	require.Equal(EntryTransferIn, s.Entries[0].Kind)
+	require.Equal(&bob, s.Entries[0].Counterparty)
+	require.EqualValues(10, s.Entries[0].Round)
+	require.Equal(EntryTransferOut, s.Entries[1].Kind)
+	require.Equal(EntryMint, s.Entries[2].Kind)
+	require.Nil(s.Entries[2].Counterparty)
+	require.Equal(EntryBurn, s.Entries[3].Kind)
+
+	src.balances[12] = 169
+	s, err = Build(context.Background(), src, alice, types.NativeDenomination, 10, 12)
+	require.NoError(err)
+	require.False(s.Reconciled, "balance changes without events should be detected")
+
+	_, err = Build(context.Background(), src, alice, types.NativeDenomination, 12, 10)
+	require.Error(err)
+}
@@ -0,0 +1,104 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// NewTestKey derives a reproducible Ed25519 test key from the given seed.
+func NewTestKey(seed string) TestKey {
+	return newEd25519TestKey("oasis-runtime-sdk/test-keys: " + seed)
+}
+
+// Committee is a reproducible set of test keys with preassigned governance roles.
+type Committee struct {
+	name    string
+	members map[types.Role][]TestKey
+}
+
+// NewCommittee creates a new committee with the given number of members for each role. The keys
+// are derived from the committee name so the same name always yields the same committee.
+func NewCommittee(name string, sizes map[types.Role]int) *Committee {
+	c := &Committee{
+		name:    name,
+		members: make(map[types.Role][]TestKey),
+	}
+	for role, n := range sizes {
+		for i := 0; i < n; i++ {
+			c.members[role] = append(c.members[role], NewTestKey(fmt.Sprintf("%s/%s/%d", name, role, i)))
+		}
+	}
+	return c
+}
+
+// NewGovernanceCommittee creates a new committee with one admin, one proposer for each action and
+// the given number of voters for each action, enough to reach any quorum.
+func NewGovernanceCommittee(name string, voters int) *Committee {
+	return NewCommittee(name, map[types.Role]int{
+		types.Admin:             1,
+		types.MintProposer:      1,
+		types.MintVoter:         voters,
+		types.BurnProposer:      1,
+		types.BurnVoter:         voters,
+		types.WhitelistProposer: 1,
+		types.WhitelistVoter:    voters,
+		types.BlacklistProposer: 1,
+		types.BlacklistVoter:    voters,
+	})
+}
+
+// Members returns the members holding the given role.
+func (c *Committee) Members(role types.Role) []TestKey {
+	return c.members[role]
+}
+
+// Member returns the i-th member holding the given role.
+//
+// Panics if there is no such member.
+func (c *Committee) Member(role types.Role, i int) TestKey {
+	members := c.members[role]
+	if i >= len(members) {
+		panic(fmt.Sprintf("testing: committee %s has no %s member %d", c.name, role, i))
+	}
+	return members[i]
+}
+
+// Roles returns the roles of all members, e.g. for use in an InitOwners transaction.
+func (c *Committee) Roles() map[types.Address]types.Role {
+	roles := make(map[types.Address]types.Role)
+	for role, members := range c.members {
+		for _, m := range members {
+			roles[m.Address] = role
+		}
+	}
+	return roles
+}
+
+// Keys returns the keys of all members ordered by role and index.
+func (c *Committee) Keys() []TestKey {
+	roles := make([]types.Role, 0, len(c.members))
+	for role := range c.members {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	var keys []TestKey
+	for _, role := range roles {
+		keys = append(keys, c.members[role]...)
+	}
+	return keys
+}
+
+// Fund calls the given funding function (e.g. a faucet request) for all members in the order
+// returned by Keys, stopping at the first error.
+func (c *Committee) Fund(ctx context.Context, fund func(context.Context, types.Address) error) error {
+	for _, k := range c.Keys() {
+		if err := fund(ctx, k.Address); err != nil {
+			return fmt.Errorf("testing: failed to fund %s: %w", k.Address, err)
+		}
+	}
+	return nil
+}
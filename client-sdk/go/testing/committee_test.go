@@ -0,0 +1,37 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestCommittee(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(Alice.Address, NewTestKey("alice").Address, "NewTestKey should match the predefined keys")
+
+	c1 := NewGovernanceCommittee("gov", 3)
+	c2 := NewGovernanceCommittee("gov", 3)
+	c3 := NewGovernanceCommittee("other", 3)
+	require.Equal(c1.Roles(), c2.Roles(), "committees should be reproducible")
+	require.NotEqual(c1.Member(types.Admin, 0).Address, c3.Member(types.Admin, 0).Address)
+
+	require.Len(c1.Members(types.MintVoter), 3)
+	require.Len(c1.Roles(), 4*(1+3)+1)
+	require.Panics(func() { c1.Member(types.MintProposer, 1) })
+
+	keys := c1.Keys()
+	require.Equal(c1.Member(types.Admin, 0).Address, keys[0].Address, "admin should be first")
+
+	var funded []types.Address
+	err := c1.Fund(context.Background(), func(ctx context.Context, addr types.Address) error {
+		funded = append(funded, addr)
+		return nil
+	})
+	require.NoError(err, "Fund")
+	require.Len(funded, len(keys))
+}
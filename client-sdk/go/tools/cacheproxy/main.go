@@ -0,0 +1,79 @@
+// cacheproxy serves a caching runtime client proxy in front of a single node.
+//
+// The proxy listens on a local socket (and optionally on an unauthenticated TCP port) and exposes
+// the node's runtime client and consensus APIs, so SDK clients can connect to it exactly as they
+// would connect to the node itself. See the proxy package for details on what is cached.
+//
+// Usage:
+//
+//	cacheproxy -upstream unix:/node/internal.sock -socket /tmp/cacheproxy.sock
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/proxy"
+)
+
+func main() {
+	upstream := flag.String("upstream", "", "gRPC address of the upstream node (e.g. unix:/node/internal.sock)")
+	socket := flag.String("socket", "cacheproxy.sock", "path of the local socket to listen on")
+	port := flag.Uint("port", 0, "additional unauthenticated TCP port to listen on (0 to disable)")
+	cacheSize := flag.Int("cache-size", proxy.DefaultCacheSize, "maximum number of cached responses (0 to disable caching)")
+	flag.Parse()
+
+	if err := run(*upstream, *socket, uint16(*port), *cacheSize); err != nil {
+		fmt.Fprintf(os.Stderr, "cacheproxy: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(upstream, socket string, port uint16, cacheSize int) error {
+	if upstream == "" {
+		return fmt.Errorf("missing upstream address")
+	}
+
+	// The upstream node is expected to be local, so no TLS is used.
+	conn, err := cmnGrpc.Dial(upstream, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to dial upstream: %w", err)
+	}
+	defer conn.Close()
+
+	server, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "cacheproxy",
+		Path: socket,
+		Port: port,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	p := proxy.New(coreClient.NewRuntimeClient(conn), proxy.WithCacheSize(cacheSize))
+	defer p.Close()
+	p.Register(server.Server())
+	consensus.RegisterService(server.Server(), consensus.NewConsensusClient(conn))
+
+	if err = server.Start(); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	defer server.Cleanup()
+	defer server.Stop()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"comment": func(indent, doc string) string {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			return ""
+		}
+		var sb strings.Builder
+		for _, line := range strings.Split(doc, "\n") {
+			sb.WriteString(indent + "// " + strings.TrimSpace(line) + "\n")
+		}
+		return sb.String()
+	},
+	"tag": func(f Field) string {
+		if f.Optional {
+			return fmt.Sprintf("`json:\"%s,omitempty\"`", f.Key)
+		}
+		return fmt.Sprintf("`json:\"%s\"`", f.Key)
+	},
+}
+
+var tmpl = template.Must(template.New("module").Funcs(funcs).Parse(`// Code generated by modgen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+{{- if .Events }}
+	"fmt"
+{{- end }}
+
+{{- if .Events }}
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+{{- end }}
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ModuleName is the {{ .Module }} module name.
+const ModuleName = "{{ .Module }}"
+
+{{- if .Methods }}
+
+const (
+{{- range .Methods }}
+	method{{ .Name }} = "{{ $.Module }}.{{ .Name }}"
+{{- end }}
+)
+{{- end }}
+
+{{- range .Types }}
+
+{{ comment "" .Doc -}}
+type {{ .Name }} struct {
+{{- range .Fields }}
+{{ comment "\t" .Doc -}}
+	{{ .Name }} {{ .Type }} {{ tag . }}
+{{- end }}
+}
+{{- end }}
+
+{{- if .Events }}
+
+const (
+{{- range .Events }}
+	// {{ .Name }}EventCode is the event code for the {{ .Name }} event.
+	{{ .Name }}EventCode = {{ .Code }}
+{{- end }}
+)
+
+// Event is a {{ .Module }} module event.
+type Event struct {
+{{- range .Events }}
+	{{ .Name }} *{{ .Type }}
+{{- end }}
+
+	types.EventMeta
+}
+{{- end }}
+
+// V1 is the v1 {{ .Module }} module interface.
+type V1 interface {
+	client.EventDecoder
+{{- range .Methods }}
+
+{{- if eq .Kind "call" }}
+
+{{ comment "\t" .Doc -}}
+	{{ .Name }}(body *{{ .Body }}) *client.TransactionBuilder
+{{- else }}
+
+{{ comment "\t" .Doc -}}
+	{{ .Name }}(ctx context.Context, round uint64{{ if .Body }}, args *{{ .Body }}{{ end }}) (*{{ .Response }}, error)
+{{- end }}
+{{- end }}
+
+	// GetEvents returns all {{ .Module }} events emitted in a given block.
+	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
+}
+
+type v1 struct {
+	rc client.RuntimeClient
+}
+{{- range .Methods }}
+
+// Implements V1.
+{{- if eq .Kind "call" }}
+func (a *v1) {{ .Name }}(body *{{ .Body }}) *client.TransactionBuilder {
+	return client.NewTransactionBuilder(a.rc, method{{ .Name }}, body)
+}
+{{- else }}
+func (a *v1) {{ .Name }}(ctx context.Context, round uint64{{ if .Body }}, args *{{ .Body }}{{ end }}) (*{{ .Response }}, error) {
+	var rsp {{ .Response }}
+	err := a.rc.Query(ctx, round, method{{ .Name }}, {{ if .Body }}args{{ else }}nil{{ end }}, &rsp)
+	if err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+{{- end }}
+{{- end }}
+
+// Implements V1.
+func (a *v1) GetEvents(ctx context.Context, round uint64) ([]*Event, error) {
+	rawEvs, err := a.rc.GetEventsRaw(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := make([]*Event, 0)
+	for _, rawEv := range rawEvs {
+		ev, err := a.DecodeEvent(rawEv)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range ev {
+			evs = append(evs, e.(*Event))
+		}
+	}
+	return evs, nil
+}
+
+// Implements client.EventDecoder.
+func (a *v1) DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
+	return DecodeEvent(event)
+}
+
+// DecodeEvent decodes a {{ .Module }} event.
+func DecodeEvent(event *types.Event) ([]client.DecodedEvent, error) {
+	if event.Module != ModuleName {
+		return nil, nil
+	}
+{{- if .Events }}
+	var events []client.DecodedEvent
+	switch event.Code {
+{{- range .Events }}
+	case {{ .Name }}EventCode:
+		var evs []*{{ .Type }}
+		if err := cbor.Unmarshal(event.Value, &evs); err != nil {
+			return nil, fmt.Errorf("decode {{ $.Module }} {{ .Name }} event value: %w", err)
+		}
+		for i, ev := range evs {
+			events = append(events, &Event{ {{- .Name }}: ev, EventMeta: event.MetaAt(i)})
+		}
+{{- end }}
+	default:
+		return nil, fmt.Errorf("invalid {{ .Module }} event code: %v", event.Code)
+	}
+	return events, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+
+// NewV1 generates a V1 client helper for the {{ .Module }} module.
+func NewV1(rc client.RuntimeClient) V1 {
+	return &v1{rc: rc}
+}
+{{- range .Methods }}
+{{- if eq .Kind "call" }}
+
+// New{{ .Name }}Tx generates a new {{ $.Module }}.{{ .Name }} transaction.
+func New{{ .Name }}Tx(fee *types.Fee, body *{{ .Body }}) *types.Transaction {
+	return types.NewTransaction(fee, method{{ .Name }}, body)
+}
+{{- end }}
+{{- end }}
+
+func init() {
+	client.MustRegisterModule(&client.ModuleDescriptor{
+		Name: ModuleName,
+		Methods: map[string]interface{}{
+{{- range .Methods }}
+{{- if eq .Kind "call" }}
+			method{{ .Name }}: {{ .Body }}{},
+{{- end }}
+{{- end }}
+		},
+		Queries: map[string]interface{}{
+{{- range .Methods }}
+{{- if eq .Kind "query" }}
+			method{{ .Name }}: {{ if .Body }}{{ .Body }}{}{{ else }}nil{{ end }},
+{{- end }}
+{{- end }}
+		},
+		Events: client.EventDecoderFunc(DecodeEvent),
+		EventTypes: map[uint32]interface{}{
+{{- range .Events }}
+			{{ .Name }}EventCode: {{ .Type }}{},
+{{- end }}
+		},
+	})
+}
+`))
+
+// Generate generates the module client source code for the given schema.
+func Generate(s *Schema) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, s); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "example.yaml"))
+	require.NoError(err, "ReadFile")
+	schema, err := ParseSchema(data)
+	require.NoError(err, "ParseSchema")
+
+	src, err := Generate(schema)
+	require.NoError(err, "Generate")
+	golden, err := os.ReadFile(filepath.Join("testdata", "example.go.golden"))
+	require.NoError(err, "ReadFile")
+	require.Equal(string(golden), string(src), "generated code should match the golden file")
+}
+
+func TestParseSchema(t *testing.T) {
+	require := require.New(t)
+
+	_, err := ParseSchema([]byte(`{"module": "example", "package": "example"}`))
+	require.NoError(err, "JSON schemas should be accepted")
+
+	for _, tc := range []struct {
+		schema string
+		msg    string
+	}{
+		{`package: example`, "missing module name"},
+		{`{module: example, package: "ex-ample"}`, "malformed package name"},
+		{`{module: example, package: example, unknown: true}`, "unknown field"},
+		{`{module: example, package: example, methods: [{name: Do, kind: call}]}`, "missing body type"},
+		{`{module: example, package: example, methods: [{name: Do, kind: query}]}`, "missing response type"},
+		{`{module: example, package: example, methods: [{name: Do, kind: other, body: X}]}`, "unknown kind"},
+		{`{module: example, package: example, methods: [{name: do, kind: call, body: X}]}`, "unexported name"},
+		{`{module: example, package: example, events: [{name: A, code: 1, type: X}, {name: B, code: 1, type: X}]}`, "duplicate code"},
+		{`{module: example, package: example, types: [{name: A}, {name: A}]}`, "duplicate type"},
+	} {
+		_, err := ParseSchema([]byte(tc.schema))
+		require.Error(err, tc.msg)
+	}
+}
@@ -0,0 +1,43 @@
+// modgen generates runtime module client boilerplate from a module schema.
+//
+// The schema is a YAML or JSON document describing the module's methods, body types and events
+// (see Schema). The generated file contains the V1 interface and its implementation, the method
+// body and event types, event decoding and the module's registration with the client registry.
+//
+// Usage from a module client package:
+//
+//	//go:generate go run github.com/oasisprotocol/oasis-sdk/client-sdk/go/tools/modgen -schema schema.yaml -out module_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema.yaml", "path to the module schema (YAML or JSON)")
+	outPath := flag.String("out", "module_gen.go", "path to the generated file")
+	flag.Parse()
+
+	if err := run(*schemaPath, *outPath); err != nil {
+		fmt.Fprintf(os.Stderr, "modgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaPath, outPath string) error {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return err
+	}
+	schema, err := ParseSchema(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", schemaPath, err)
+	}
+	src, err := Generate(schema)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, src, 0o644) //nolint: gosec
+}
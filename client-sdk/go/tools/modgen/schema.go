@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Method kinds.
+const (
+	KindCall  = "call"
+	KindQuery = "query"
+)
+
+// Schema describes a runtime module.
+type Schema struct {
+	// Module is the runtime module name, e.g. "accounts".
+	Module string `yaml:"module"`
+	// Package is the name of the generated Go package.
+	Package string `yaml:"package"`
+	// Types are the method body, response and event types to generate.
+	Types []Type `yaml:"types"`
+	// Methods are the module's callable methods and queries.
+	Methods []Method `yaml:"methods"`
+	// Events are the events emitted by the module.
+	Events []Event `yaml:"events"`
+}
+
+// Type is a struct type.
+type Type struct {
+	Name   string  `yaml:"name"`
+	Doc    string  `yaml:"doc"`
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a struct field.
+type Field struct {
+	// Name is the Go field name.
+	Name string `yaml:"name"`
+	// Type is the Go type of the field, e.g. "types.Address" or "uint64".
+	Type string `yaml:"type"`
+	// Key is the encoded field name.
+	Key string `yaml:"key"`
+	// Optional marks fields that are omitted when empty.
+	Optional bool   `yaml:"optional"`
+	Doc      string `yaml:"doc"`
+}
+
+// Method is a callable method or query.
+type Method struct {
+	// Name is the method name without the module prefix, e.g. "Transfer".
+	Name string `yaml:"name"`
+	// Kind is either "call" or "query".
+	Kind string `yaml:"kind"`
+	Doc  string `yaml:"doc"`
+	// Body is the Go type of the method body. It is optional for queries.
+	Body string `yaml:"body"`
+	// Response is the Go type of the query response. It is required for queries.
+	Response string `yaml:"response"`
+}
+
+// Event is an event.
+type Event struct {
+	// Name is the event name, e.g. "Transfer".
+	Name string `yaml:"name"`
+	Code uint32 `yaml:"code"`
+	// Type is the Go type of the event value.
+	Type string `yaml:"type"`
+	Doc  string `yaml:"doc"`
+}
+
+// ParseSchema parses a YAML or JSON encoded module schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("malformed schema: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate performs basic validation of the schema.
+func (s *Schema) Validate() error {
+	if s.Module == "" {
+		return fmt.Errorf("missing module name")
+	}
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("malformed package name '%s'", s.Package)
+	}
+
+	names := make(map[string]bool)
+	checkName := func(what, name string) error {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("malformed %s name '%s'", what, name)
+		}
+		if names[what+":"+name] {
+			return fmt.Errorf("duplicate %s '%s'", what, name)
+		}
+		names[what+":"+name] = true
+		return nil
+	}
+	for _, t := range s.Types {
+		if err := checkName("type", t.Name); err != nil {
+			return err
+		}
+		for _, f := range t.Fields {
+			if !token.IsIdentifier(f.Name) || f.Type == "" || f.Key == "" {
+				return fmt.Errorf("type '%s': malformed field '%s'", t.Name, f.Name)
+			}
+		}
+	}
+	for _, m := range s.Methods {
+		if err := checkName("method", m.Name); err != nil {
+			return err
+		}
+		switch m.Kind {
+		case KindCall:
+			if m.Body == "" {
+				return fmt.Errorf("method '%s': missing body type", m.Name)
+			}
+		case KindQuery:
+			if m.Response == "" {
+				return fmt.Errorf("method '%s': missing response type", m.Name)
+			}
+		default:
+			return fmt.Errorf("method '%s': unknown kind '%s'", m.Name, m.Kind)
+		}
+	}
+	codes := make(map[uint32]bool)
+	for _, ev := range s.Events {
+		if err := checkName("event", ev.Name); err != nil {
+			return err
+		}
+		if ev.Type == "" {
+			return fmt.Errorf("event '%s': missing type", ev.Name)
+		}
+		if codes[ev.Code] {
+			return fmt.Errorf("event '%s': duplicate code %d", ev.Name, ev.Code)
+		}
+		codes[ev.Code] = true
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+var (
+	// ErrNegativeAmount is the error returned when converting a negative amount to base units.
+	ErrNegativeAmount = errors.New("amount is negative")
+	// ErrDenominationMismatch is the error returned when combining amounts of different
+	// denominations.
+	ErrDenominationMismatch = errors.New("denomination mismatch")
+)
+
+// Amount is a signed token amount of given denomination in base units, e.g. a balance change
+// where debits are negative and credits are positive. Unlike Quantity it never fails on underflow.
+//
+// The zero value is a zero amount of the native denomination. As with Quantity, use Clone to copy
+// amounts that are going to be modified.
+type Amount struct {
+	value big.Int
+
+	Denomination Denomination
+}
+
+// Credit returns a positive amount for the given base units.
+func Credit(bu BaseUnits) Amount {
+	var a Amount
+	a.value.Set(bu.Amount.ToBigInt())
+	a.Denomination = bu.Denomination
+	return a
+}
+
+// Debit returns a negative amount for the given base units.
+func Debit(bu BaseUnits) Amount {
+	a := Credit(bu)
+	a.value.Neg(&a.value)
+	return a
+}
+
+// Clone returns a copy of the amount.
+func (a *Amount) Clone() Amount {
+	c := Amount{Denomination: a.Denomination}
+	c.value.Set(&a.value)
+	return c
+}
+
+// Sign returns -1, 0 or 1 depending on whether the amount is negative, zero or positive.
+func (a *Amount) Sign() int {
+	return a.value.Sign()
+}
+
+// IsZero checks whether the amount is zero.
+func (a *Amount) IsZero() bool {
+	return a.value.Sign() == 0
+}
+
+// Cmp compares the amount with another amount of the same denomination, returning -1, 0 or 1.
+func (a *Amount) Cmp(other *Amount) (int, error) {
+	if a.Denomination != other.Denomination {
+		return 0, fmt.Errorf("%w: %s vs. %s", ErrDenominationMismatch, a.Denomination, other.Denomination)
+	}
+	return a.value.Cmp(&other.value), nil
+}
+
+// Add adds another amount of the same denomination to the amount.
+func (a *Amount) Add(other *Amount) error {
+	if a.Denomination != other.Denomination {
+		return fmt.Errorf("%w: %s vs. %s", ErrDenominationMismatch, a.Denomination, other.Denomination)
+	}
+	a.value.Add(&a.value, &other.value)
+	return nil
+}
+
+// Sub subtracts another amount of the same denomination from the amount.
+func (a *Amount) Sub(other *Amount) error {
+	if a.Denomination != other.Denomination {
+		return fmt.Errorf("%w: %s vs. %s", ErrDenominationMismatch, a.Denomination, other.Denomination)
+	}
+	a.value.Sub(&a.value, &other.value)
+	return nil
+}
+
+// Neg negates the amount.
+func (a *Amount) Neg() {
+	a.value.Neg(&a.value)
+}
+
+// Abs returns the magnitude of the amount in base units.
+func (a *Amount) Abs() BaseUnits {
+	var q quantity.Quantity
+	// The absolute value is never negative, so the conversion can't fail.
+	_ = q.FromBigInt(new(big.Int).Abs(&a.value))
+	return NewBaseUnits(q, a.Denomination)
+}
+
+// ToBaseUnits converts the amount to base units, failing with ErrNegativeAmount in case the
+// amount is negative.
+func (a *Amount) ToBaseUnits() (BaseUnits, error) {
+	if a.value.Sign() < 0 {
+		return BaseUnits{}, fmt.Errorf("%w: %s", ErrNegativeAmount, a)
+	}
+	return a.Abs(), nil
+}
+
+// String returns a string representation of this amount.
+func (a Amount) String() string {
+	return fmt.Sprintf("%s %s", a.value.String(), a.Denomination.String())
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+func TestAmount(t *testing.T) {
+	require := require.New(t)
+
+	bu := func(v uint64) BaseUnits {
+		return NewBaseUnits(*quantity.NewFromUint64(v), "USD")
+	}
+
+	balance := Credit(bu(100))
+	debit := Debit(bu(150))
+	require.Equal(-1, debit.Sign())
+	require.Equal("-150 USD", debit.String())
+
+	require.NoError(balance.Add(&debit))
+	require.Equal(-1, balance.Sign(), "amounts should go negative instead of underflowing")
+	_, err := balance.ToBaseUnits()
+	require.True(errors.Is(err, ErrNegativeAmount))
+	require.Equal(bu(50), balance.Abs())
+
+	snapshot := balance.Clone()
+	credit := Credit(bu(70))
+	require.NoError(balance.Add(&credit))
+	require.Equal("-50 USD", snapshot.String(), "clones should not be affected by changes")
+	converted, err := balance.ToBaseUnits()
+	require.NoError(err)
+	require.Equal(bu(20), converted)
+
+	require.NoError(balance.Sub(&credit))
+	cmp, err := balance.Cmp(&snapshot)
+	require.NoError(err)
+	require.Equal(0, cmp)
+	balance.Neg()
+	require.Equal("50 USD", balance.String())
+
+	native := Credit(NewBaseUnits(*quantity.NewFromUint64(1), NativeDenomination))
+	require.True(errors.Is(balance.Add(&native), ErrDenominationMismatch))
+	require.True(errors.Is(balance.Sub(&native), ErrDenominationMismatch))
+	_, err = balance.Cmp(&native)
+	require.True(errors.Is(err, ErrDenominationMismatch))
+
+	var zero Amount
+	require.True(zero.IsZero())
+	require.NoError(zero.Add(&native), "the zero value should be of the native denomination")
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	coreSignature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+)
+
+// verifyBatchSize is the maximum number of transactions whose signatures are verified in a
+// single batch.
+const verifyBatchSize = 128
+
+// VerifyResult is the result of verifying a transaction using VerifyBatch.
+type VerifyResult struct {
+	// Tx is the verified transaction (nil in case verification failed).
+	Tx *Transaction
+	// Err is the verification error (if any).
+	Err error
+}
+
+// VerifyBatch verifies and deserializes the given unverified transactions (e.g. all transactions
+// of a block) using the given number of concurrent workers (GOMAXPROCS if not positive). The
+// results are in the same order as the transactions.
+//
+// Ed25519 signatures are verified in batches, which is substantially faster than verifying them
+// one by one. Other signatures are verified individually.
+func VerifyBatch(ctx signature.Context, uts []*UnverifiedTransaction, workers int) []VerifyResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make([]VerifyResult, len(uts))
+	txCtx := ctx.New(SignatureContextBase)
+
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + verifyBatchSize
+				if end > len(uts) {
+					end = len(uts)
+				}
+				verifyChunk(txCtx, uts[start:end], results[start:end])
+			}
+		}()
+	}
+	for start := 0; start < len(uts); start += verifyBatchSize {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+
+	return results
+}
+
+// verifyChunk verifies the given transactions, storing the outcomes into the given results.
+func verifyChunk(txCtx []byte, uts []*UnverifiedTransaction, results []VerifyResult) {
+	type batchEntry struct {
+		tx  int
+		sig int
+	}
+	var entries []batchEntry
+	bv := coreSignature.NewBatchVerifierWithCapacity(len(uts))
+
+	txs := make([]*Transaction, len(uts))
+	for i, ut := range uts {
+		tx, publicKeys, signatures, err := ut.decodeSigned()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		txs[i] = tx
+
+		for j, pk := range publicKeys {
+			if edPk, ok := pk.PublicKey.(ed25519.PublicKey); ok {
+				bv.Add(coreSignature.PublicKey(edPk), coreSignature.Context(txCtx), ut.Body, signatures[j])
+				entries = append(entries, batchEntry{tx: i, sig: j})
+				continue
+			}
+			if results[i].Err == nil && !pk.Verify(txCtx, ut.Body, signatures[j]) {
+				results[i].Err = fmt.Errorf("transaction: signature %d verification failed", j)
+			}
+		}
+	}
+
+	if len(entries) > 0 {
+		if ok, errs := bv.Verify(); !ok {
+			for k, err := range errs {
+				entry := entries[k]
+				if err != nil && results[entry.tx].Err == nil {
+					results[entry.tx].Err = fmt.Errorf("transaction: signature %d verification failed", entry.sig)
+				}
+			}
+		}
+	}
+
+	for i, tx := range txs {
+		if results[i].Err == nil {
+			results[i].Tx = tx
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+import "fmt"
+
+// DecodeError is the error returned when decoding chain data (e.g. events, transactions or query
+// responses) panicked, e.g. because a custom decoder choked on malformed data.
+type DecodeError struct {
+	// What describes the data that failed to decode, e.g. "accounts event 1".
+	What string
+	// Panic is the value the decoder panicked with.
+	Panic interface{}
+}
+
+// Error returns the string representation of the decode error. Payloads the decoder panicked with
+// are redacted (see RedactPayload).
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s: decoder panicked: %v", e.What, redactValue(e.Panic))
+}
+
+// RecoverDecode converts a panic during decoding into a DecodeError stored in the given error. It
+// must be called directly via defer from a function with a named error result:
+//
+//	func decode(data []byte) (v *Value, err error) {
+//		defer types.RecoverDecode(&err, "value")
+//		...
+//	}
+func RecoverDecode(err *error, what string) {
+	if r := recover(); r != nil {
+		*err = &DecodeError{What: what, Panic: r}
+	}
+}
@@ -0,0 +1,277 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+var (
+	// ErrBodyTooLarge is the error returned when an encoded call body exceeds the configured
+	// maximum size.
+	ErrBodyTooLarge = errors.New("encoded body too large")
+	// ErrArrayTooLarge is the error returned when a decoded array has more elements than the
+	// configured maximum.
+	ErrArrayTooLarge = errors.New("array too large")
+)
+
+// sizeLimitWriter is a writer that fails once more than limit bytes have been written.
+type sizeLimitWriter struct {
+	w       io.Writer
+	limit   uint64
+	written uint64
+}
+
+func (lw *sizeLimitWriter) Write(p []byte) (int, error) {
+	if lw.limit > 0 && lw.written+uint64(len(p)) > lw.limit {
+		return 0, fmt.Errorf("%w: exceeds limit of %d bytes", ErrBodyTooLarge, lw.limit)
+	}
+	n, err := lw.w.Write(p)
+	lw.written += uint64(n)
+	return n, err
+}
+
+// ArrayEncoder incrementally encodes a CBOR array of a known length, one element at a time, so
+// that large call bodies (e.g. bulk transfers or role assignments) can be produced without first
+// materializing all elements in memory.
+//
+// The produced encoding is identical to the one produced by cbor.Marshal for the equivalent slice.
+type ArrayEncoder struct {
+	w         *sizeLimitWriter
+	remaining uint64
+}
+
+// NewArrayEncoder creates a new encoder writing an array of n elements to the given writer. In
+// case maxSize is non-zero, encoding fails with ErrBodyTooLarge as soon as the encoded array
+// would exceed maxSize bytes.
+func NewArrayEncoder(w io.Writer, n uint64, maxSize uint64) (*ArrayEncoder, error) {
+	lw := &sizeLimitWriter{w: w, limit: maxSize}
+	if _, err := lw.Write(arrayHeader(n)); err != nil {
+		return nil, err
+	}
+	return &ArrayEncoder{
+		w:         lw,
+		remaining: n,
+	}, nil
+}
+
+// Encode encodes the next array element.
+func (e *ArrayEncoder) Encode(v interface{}) error {
+	if e.remaining == 0 {
+		return fmt.Errorf("array encoder: too many elements")
+	}
+	if _, err := e.w.Write(cbor.Marshal(v)); err != nil {
+		return err
+	}
+	e.remaining--
+	return nil
+}
+
+// Written returns the number of bytes written so far.
+func (e *ArrayEncoder) Written() uint64 {
+	return e.w.written
+}
+
+// Close finishes the encoding, making sure that all elements have been encoded.
+func (e *ArrayEncoder) Close() error {
+	if e.remaining != 0 {
+		return fmt.Errorf("array encoder: %d elements missing", e.remaining)
+	}
+	return nil
+}
+
+// EncodeArray encodes an array of n elements returned by the given function into a raw CBOR
+// message that can be used as a call body (e.g. passed to NewTransaction). In case maxSize is
+// non-zero, encoding fails with ErrBodyTooLarge as soon as the body would exceed maxSize bytes.
+func EncodeArray(n uint64, maxSize uint64, elem func(i uint64) (interface{}, error)) (cbor.RawMessage, error) {
+	var buf bytes.Buffer
+	enc, err := NewArrayEncoder(&buf, n, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < n; i++ {
+		v, err := elem(i)
+		if err != nil {
+			return nil, err
+		}
+		if err = enc.Encode(v); err != nil {
+			return nil, fmt.Errorf("failed to encode element %d: %w", i, err)
+		}
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ArrayDecoder incrementally decodes a CBOR array, one element at a time, so that large responses
+// (e.g. Addresses) can be processed without first materializing all elements in memory.
+type ArrayDecoder struct {
+	dec       interface{ Decode(v interface{}) error }
+	n         uint64
+	remaining uint64
+}
+
+// NewArrayDecoder creates a new decoder of the array encoded in data. In case maxLen is non-zero,
+// decoding fails with ErrArrayTooLarge in case the array has more than maxLen elements.
+func NewArrayDecoder(data []byte, maxLen uint64) (*ArrayDecoder, error) {
+	n, hdrLen, err := parseArrayHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if maxLen > 0 && n > maxLen {
+		return nil, fmt.Errorf("%w: %d elements exceed limit of %d", ErrArrayTooLarge, n, maxLen)
+	}
+	// Each element takes at least one byte, so reject bogus lengths early.
+	if n > uint64(len(data)-hdrLen) {
+		return nil, fmt.Errorf("array decoder: truncated array of %d elements", n)
+	}
+	return &ArrayDecoder{
+		dec:       cbor.NewDecoder(bytes.NewReader(data[hdrLen:])),
+		n:         n,
+		remaining: n,
+	}, nil
+}
+
+// Len returns the total number of array elements.
+func (d *ArrayDecoder) Len() uint64 {
+	return d.n
+}
+
+// Remaining returns the number of elements that have not been decoded yet.
+func (d *ArrayDecoder) Remaining() uint64 {
+	return d.remaining
+}
+
+// Decode decodes the next array element into v. It returns io.EOF once all elements have been
+// decoded and fails in case the array is followed by trailing data.
+func (d *ArrayDecoder) Decode(v interface{}) error {
+	if d.remaining == 0 {
+		var trailing cbor.RawMessage
+		if err := d.dec.Decode(&trailing); err != io.EOF { //nolint: errorlint
+			return fmt.Errorf("array decoder: trailing data after array")
+		}
+		return io.EOF
+	}
+	var raw cbor.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return fmt.Errorf("array decoder: malformed element %d: %w", d.n-d.remaining, err)
+	}
+	if err := cbor.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("array decoder: malformed element %d: %w", d.n-d.remaining, err)
+	}
+	d.remaining--
+	return nil
+}
+
+// MapDecoder incrementally decodes a CBOR map, one entry at a time, so that large responses can
+// be processed (or truncated) without first materializing all entries in memory.
+type MapDecoder struct {
+	dec       interface{ Decode(v interface{}) error }
+	n         uint64
+	remaining uint64
+}
+
+// NewMapDecoder creates a new decoder of the map encoded in data.
+func NewMapDecoder(data []byte) (*MapDecoder, error) {
+	n, hdrLen, err := parseHeader(data, majorMap, "map")
+	if err != nil {
+		return nil, err
+	}
+	// Each entry takes at least two bytes, so reject bogus lengths early.
+	if n > uint64(len(data)-hdrLen)/2 {
+		return nil, fmt.Errorf("map decoder: truncated map of %d entries", n)
+	}
+	return &MapDecoder{
+		dec:       cbor.NewDecoder(bytes.NewReader(data[hdrLen:])),
+		n:         n,
+		remaining: n,
+	}, nil
+}
+
+// Len returns the total number of map entries.
+func (d *MapDecoder) Len() uint64 {
+	return d.n
+}
+
+// Decode decodes the next map entry into key and value. It returns io.EOF once all entries have
+// been decoded.
+func (d *MapDecoder) Decode(key, value interface{}) error {
+	if d.remaining == 0 {
+		return io.EOF
+	}
+	for _, v := range []interface{}{key, value} {
+		var raw cbor.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return fmt.Errorf("map decoder: malformed entry %d: %w", d.n-d.remaining, err)
+		}
+		if err := cbor.Unmarshal(raw, v); err != nil {
+			return fmt.Errorf("map decoder: malformed entry %d: %w", d.n-d.remaining, err)
+		}
+	}
+	d.remaining--
+	return nil
+}
+
+const (
+	majorArray = 0x80
+	majorMap   = 0xa0
+)
+
+// parseArrayHeader parses a definite-length CBOR array header, returning the number of elements
+// and the header length.
+func parseArrayHeader(data []byte) (uint64, int, error) {
+	return parseHeader(data, majorArray, "array")
+}
+
+// parseHeader parses a definite-length CBOR header of the given major type, returning the number
+// of items and the header length.
+func parseHeader(data []byte, major byte, name string) (uint64, int, error) {
+	if len(data) == 0 || data[0]&0xe0 != major {
+		return 0, 0, fmt.Errorf("%s decoder: not a CBOR %s", name, name)
+	}
+	info := data[0] & 0x1f
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < 1+size {
+			return 0, 0, fmt.Errorf("%s decoder: truncated %s header", name, name)
+		}
+		var n uint64
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		return n, 1 + size, nil
+	default:
+		return 0, 0, fmt.Errorf("%s decoder: unsupported %s encoding", name, name)
+	}
+}
+
+// arrayHeader returns the canonical CBOR header of an array with n elements.
+func arrayHeader(n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{majorArray | byte(n)}
+	case n <= 0xff:
+		return []byte{majorArray | 24, byte(n)}
+	case n <= 0xffff:
+		hdr := []byte{majorArray | 25, 0, 0}
+		binary.BigEndian.PutUint16(hdr[1:], uint16(n))
+		return hdr
+	case n <= 0xffffffff:
+		hdr := []byte{majorArray | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(hdr[1:], uint32(n))
+		return hdr
+	default:
+		hdr := make([]byte, 9)
+		hdr[0] = majorArray | 27
+		binary.BigEndian.PutUint64(hdr[1:], n)
+		return hdr
+	}
+}
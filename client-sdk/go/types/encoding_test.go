@@ -0,0 +1,127 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+func TestEncodeArray(t *testing.T) {
+	require := require.New(t)
+
+	type entry struct {
+		Key   string `json:"key"`
+		Value uint64 `json:"value"`
+	}
+
+	for _, n := range []uint64{0, 1, 23, 24, 255, 256, 70_000} {
+		entries := make([]entry, n)
+		for i := range entries {
+			entries[i] = entry{Key: "k", Value: uint64(i)}
+		}
+
+		raw, err := EncodeArray(n, 0, func(i uint64) (interface{}, error) {
+			return &entries[i], nil
+		})
+		require.NoError(err, "EncodeArray(%d)", n)
+		require.EqualValues(cbor.Marshal(entries), raw, "encoding should match cbor.Marshal (%d)", n)
+	}
+
+	_, err := EncodeArray(100, 64, func(i uint64) (interface{}, error) {
+		return entry{Key: "k", Value: i}, nil
+	})
+	require.True(errors.Is(err, ErrBodyTooLarge), "EncodeArray should fail with ErrBodyTooLarge")
+}
+
+func TestArrayEncoderLength(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	enc, err := NewArrayEncoder(&buf, 1, 0)
+	require.NoError(err, "NewArrayEncoder")
+	require.Error(enc.Close(), "Close should fail with missing elements")
+	require.NoError(enc.Encode(uint64(1)), "Encode")
+	require.Error(enc.Encode(uint64(2)), "Encode should fail with too many elements")
+	require.NoError(enc.Close(), "Close")
+	require.EqualValues(2, enc.Written())
+	require.EqualValues(cbor.Marshal([]uint64{1}), buf.Bytes())
+}
+
+func TestArrayDecoder(t *testing.T) {
+	require := require.New(t)
+
+	for _, n := range []uint64{0, 1, 23, 24, 255, 256, 70_000} {
+		values := make([]uint64, n)
+		for i := range values {
+			values[i] = uint64(i) * 1000
+		}
+		dec, err := NewArrayDecoder(cbor.Marshal(values), 0)
+		require.NoError(err, "NewArrayDecoder(%d)", n)
+		require.Equal(n, dec.Len())
+
+		var decoded []uint64
+		for {
+			var v uint64
+			err = dec.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(err, "Decode")
+			decoded = append(decoded, v)
+		}
+		require.EqualValues(n, len(decoded))
+		require.Zero(dec.Remaining())
+		if n > 0 {
+			require.Equal(values, decoded)
+		}
+	}
+
+	_, err := NewArrayDecoder(cbor.Marshal([]uint64{1, 2, 3}), 2)
+	require.ErrorIs(err, ErrArrayTooLarge)
+	_, err = NewArrayDecoder([]byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0)
+	require.Error(err, "bogus lengths should be rejected")
+	_, err = NewArrayDecoder(cbor.Marshal(map[string]uint64{"a": 1}), 0)
+	require.Error(err, "non-arrays should be rejected")
+
+	dec, err := NewArrayDecoder(append(cbor.Marshal([]uint64{1}), 0x01), 0)
+	require.NoError(err)
+	var v uint64
+	require.NoError(dec.Decode(&v))
+	err = dec.Decode(&v)
+	require.Error(err)
+	require.False(errors.Is(err, io.EOF), "trailing data should be rejected")
+}
+
+func TestMapDecoder(t *testing.T) {
+	require := require.New(t)
+
+	values := map[uint64]string{1: "a", 2: "b", 3: "c"}
+	dec, err := NewMapDecoder(cbor.Marshal(values))
+	require.NoError(err, "NewMapDecoder")
+	require.EqualValues(3, dec.Len())
+
+	decoded := make(map[uint64]string)
+	for {
+		var (
+			k uint64
+			v string
+		)
+		err = dec.Decode(&k, &v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(err, "Decode")
+		decoded[k] = v
+	}
+	require.Equal(values, decoded)
+
+	_, err = NewMapDecoder(cbor.Marshal([]uint64{1}))
+	require.Error(err, "non-maps should be rejected")
+	_, err = NewMapDecoder([]byte{0xbb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	require.Error(err, "bogus lengths should be rejected")
+}
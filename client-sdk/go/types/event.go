@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"strconv"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 )
@@ -16,6 +17,16 @@ type Event struct {
 	Code   uint32
 	Value  []byte
 	TxHash *hash.Hash
+	// TxIndex is the index of the transaction that emitted the event within its block (if known).
+	TxIndex *uint32
+
+	// Round is the round the event was emitted in.
+	Round uint64
+	// Index is the position of the event among all events emitted in the round.
+	//
+	// Round and Index are only set for events obtained from the block event queries of the
+	// runtime client (e.g. GetEventsRaw).
+	Index uint32
 }
 
 // UnmarshalRaw decodes the event from a raw key/value pair.
@@ -31,6 +42,35 @@ func (ev *Event) UnmarshalRaw(key, value []byte, txHash *hash.Hash) error {
 	return nil
 }
 
+// ID returns the identity of the sub-th event contained in the event value.
+func (ev *Event) ID(sub int) EventID {
+	return EventID{
+		Round:  ev.Round,
+		Index:  ev.Index,
+		Sub:    uint32(sub),
+		Module: ev.Module,
+		Code:   ev.Code,
+	}
+}
+
+// MetaAt returns the metadata of the sub-th event decoded from the event value.
+func (ev *Event) MetaAt(sub int) EventMeta {
+	meta := EventMeta{
+		ID:      ev.ID(sub),
+		TxIndex: ev.TxIndex,
+	}
+	// Events emitted by the block itself rather than a transaction use an all-zero hash.
+	if ev.TxHash != nil && *ev.TxHash != (hash.Hash{}) {
+		meta.TxHash = ev.TxHash
+	}
+	return meta
+}
+
+// Metadata returns the metadata of the (undecoded) event.
+func (ev *Event) Metadata() EventMeta {
+	return ev.MetaAt(0)
+}
+
 // Key returns the event key.
 func (ev *Event) Key() EventKey {
 	return NewEventKey(ev.Module, ev.Code)
@@ -51,3 +91,46 @@ func NewEventKey(module string, code uint32) EventKey {
 	binary.BigEndian.PutUint32(key[len(module):], code)
 	return key
 }
+
+// EventID is the deterministic identity of a decoded event, suitable for de-duplicating events
+// delivered more than once (e.g. after a watcher restart).
+//
+// Events are identified by their position in the block: the round, the index of the raw event
+// among all events of the round and the index of the decoded event among the events contained in
+// the raw event value. The module and code are included for convenience.
+type EventID struct {
+	Round  uint64 `json:"round"`
+	Index  uint32 `json:"index"`
+	Sub    uint32 `json:"sub"`
+	Module string `json:"module"`
+	Code   uint32 `json:"code"`
+}
+
+// String returns a string representation of the event identity that can be used as a key, e.g.
+// "1234/5/0/accounts/1".
+func (id EventID) String() string {
+	return strconv.FormatUint(id.Round, 10) + "/" +
+		strconv.FormatUint(uint64(id.Index), 10) + "/" +
+		strconv.FormatUint(uint64(id.Sub), 10) + "/" +
+		id.Module + "/" +
+		strconv.FormatUint(uint64(id.Code), 10)
+}
+
+// EventMeta is the metadata of a decoded event. It is embedded in the decoded events of the
+// runtime modules.
+type EventMeta struct {
+	// ID is the identity of the event.
+	ID EventID `json:"event_id"`
+	// TxHash is the hash of the transaction that emitted the event. It is nil for events emitted
+	// by the block itself (e.g. at the end of the block).
+	TxHash *hash.Hash `json:"tx_hash,omitempty"`
+	// TxIndex is the index of the transaction that emitted the event within its block. It is only
+	// known in case the runtime client is configured to resolve it (see client.WithTxIndices) or
+	// the event was obtained together with its transaction.
+	TxIndex *uint32 `json:"tx_index,omitempty"`
+}
+
+// Metadata returns the event metadata.
+func (m EventMeta) Metadata() EventMeta {
+	return m
+}
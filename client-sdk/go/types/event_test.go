@@ -44,3 +44,17 @@ func TestEventUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestEventID(t *testing.T) {
+	require := require.New(t)
+
+	ev := Event{Module: "accounts", Code: 1, Round: 1234, Index: 5}
+	require.Equal("1234/5/0/accounts/1", ev.Metadata().ID.String())
+	require.Equal("1234/5/2/accounts/1", ev.MetaAt(2).ID.String())
+	require.Equal(ev.MetaAt(2), ev.MetaAt(2), "identities should be deterministic")
+	require.NotEqual(ev.MetaAt(1).ID, ev.MetaAt(2).ID)
+
+	var m EventMeta
+	m = ev.MetaAt(1)
+	require.Equal(m, m.Metadata())
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+)
+
+func TestMessageSigning(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: msg signing"))
+	chainCtx := signature.Context("test chain")
+	msg := []byte("login to example.com")
+
+	sig, err := SignMessage(chainCtx, signer, msg)
+	require.NoError(err, "SignMessage")
+	require.NoError(VerifyMessage(chainCtx, signer.Public(), msg, sig), "VerifyMessage")
+
+	require.Error(VerifyMessage(chainCtx, signer.Public(), []byte("other message"), sig), "VerifyMessage should fail for a different message")
+	require.Error(VerifyMessage(signature.Context("other chain"), signer.Public(), msg, sig), "VerifyMessage should fail for a different chain")
+
+	// Message signatures must not be valid transaction signatures.
+	require.False(signer.Public().Verify(chainCtx.New(SignatureContextBase), msg, sig), "message signature should not verify as a transaction signature")
+}
+
+func TestTypedMessageSigning(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: msg signing"))
+	chainCtx := signature.Context("test chain")
+	now := time.Unix(1_600_000_000, 0)
+
+	type login struct {
+		Account string `json:"account"`
+	}
+	msg := NewTypedMessage("example.com", []byte("nonce"), now.Add(time.Minute), "login", &login{Account: "alice"})
+	signed, err := msg.Sign(chainCtx, signer)
+	require.NoError(err, "Sign")
+
+	opened, err := signed.Open(chainCtx, "example.com", now)
+	require.NoError(err, "Open")
+	require.EqualValues(msg, opened, "opened message should be equal to the signed one")
+	var payload login
+	require.NoError(opened.DecodePayload(&payload), "DecodePayload")
+	require.Equal("alice", payload.Account)
+
+	_, err = signed.Open(chainCtx, "evil.com", now)
+	require.Error(err, "Open should fail for a different domain")
+	_, err = signed.Open(chainCtx, "example.com", now.Add(time.Hour))
+	require.Error(err, "Open should fail for an expired message")
+	_, err = signed.Open(signature.Context("other chain"), "example.com", now)
+	require.Error(err, "Open should fail for a different chain")
+
+	// Typed message signatures must not verify as plain message signatures.
+	require.Error(VerifyMessage(chainCtx, signer.Public(), signed.Body, signed.Signature), "VerifyMessage should fail for a typed message signature")
+
+	_, err = NewTypedMessage("example.com", nil, now, "login", nil).Sign(chainCtx, signer)
+	require.Error(err, "Sign should fail for a message without a nonce")
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// showPayloads is non-zero iff payloads are included verbatim in errors.
+var showPayloads int32
+
+// SetPayloadRedaction configures whether payloads (e.g. unsigned transaction bodies, events or
+// call results) included in errors produced by the SDK are redacted, which is the default.
+//
+// Disabling redaction helps when debugging against a local network, but must not be used in
+// production as payloads may carry sensitive data. Private keys are always redacted.
+func SetPayloadRedaction(enabled bool) {
+	var v int32
+	if !enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&showPayloads, v)
+}
+
+// RedactPayload returns a representation of the given payload suitable for errors and logs. When
+// redaction is enabled (the default), only the size and hash of the payload are included so that
+// it can still be correlated with the original data.
+func RedactPayload(data []byte) string {
+	if atomic.LoadInt32(&showPayloads) != 0 {
+		return hex.EncodeToString(data)
+	}
+	h := hash.NewFromBytes(data)
+	return fmt.Sprintf("[redacted %d bytes, hash %s]", len(data), hex.EncodeToString(h[:8]))
+}
+
+// Sensitive is a secret (e.g. a private key or a mnemonic) that is always redacted when formatted,
+// even if it ends up in an error, a log line or a panic.
+type Sensitive []byte
+
+// String returns a redacted representation of the secret.
+func (s Sensitive) String() string {
+	return fmt.Sprintf("[redacted %d bytes]", len(s))
+}
+
+// GoString returns a redacted representation of the secret.
+func (s Sensitive) GoString() string {
+	return s.String()
+}
+
+// Format formats the secret in redacted form regardless of the verb.
+func (s Sensitive) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(s.String()))
+}
+
+// redactValue redacts the given value in case it is a payload (any byte slice).
+func redactValue(v interface{}) interface{} {
+	if _, ok := v.(Sensitive); ok {
+		return v
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		return RedactPayload(rv.Bytes())
+	}
+	return v
+}
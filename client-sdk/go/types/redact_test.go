@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedaction(t *testing.T) {
+	require := require.New(t)
+
+	payload := []byte("unsigned transaction body")
+	redacted := RedactPayload(payload)
+	require.NotContains(redacted, hex.EncodeToString(payload))
+	require.Contains(redacted, "25 bytes")
+	require.Equal(redacted, RedactPayload(payload), "redacted payloads should be correlatable")
+
+	err := &DecodeError{What: "test event 1", Panic: payload}
+	require.NotContains(err.Error(), string(payload))
+	require.NotContains(err.Error(), hex.EncodeToString(payload))
+	err = &DecodeError{What: "test event 1", Panic: "malformed event"}
+	require.Contains(err.Error(), "malformed event", "non-payload panics should be kept")
+
+	secret := Sensitive("correct horse battery staple")
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%x", "%q"} {
+		formatted := fmt.Sprintf(verb, secret)
+		require.Equal("[redacted 28 bytes]", formatted, verb)
+	}
+	require.NotContains(fmt.Sprintf("%+v", struct{ Key Sensitive }{secret}), string(secret), "nested secrets should be redacted")
+
+	SetPayloadRedaction(false)
+	defer SetPayloadRedaction(true)
+	require.Equal(hex.EncodeToString(payload), RedactPayload(payload))
+	require.Equal("[redacted 28 bytes]", secret.String(), "secrets should always be redacted")
+}
@@ -184,3 +184,39 @@ func (a Action) String() string {
 		return fmt.Sprintf("Unknown action: %d", a)
 	}
 }
+
+// VoterRole returns the role required to vote on proposals with the given action.
+func (a Action) VoterRole() (Role, bool) {
+	switch a {
+	case SetRoles, Config:
+		return Admin, true
+	case Mint:
+		return MintVoter, true
+	case Burn:
+		return BurnVoter, true
+	case Whitelist:
+		return WhitelistVoter, true
+	case Blacklist:
+		return BlacklistVoter, true
+	default:
+		return 0, false
+	}
+}
+
+// ProposerRole returns the role required to submit proposals with the given action.
+func (a Action) ProposerRole() (Role, bool) {
+	switch a {
+	case SetRoles, Config:
+		return Admin, true
+	case Mint:
+		return MintProposer, true
+	case Burn:
+		return BurnProposer, true
+	case Whitelist:
+		return WhitelistProposer, true
+	case Blacklist:
+		return BlacklistProposer, true
+	default:
+		return 0, false
+	}
+}
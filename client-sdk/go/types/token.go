@@ -12,6 +12,11 @@ type Quantity = quantity.Quantity
 // NativeDenomination is the denomination in native token (now is HELA).
 var NativeDenomination = Denomination([]byte{})
 
+// GasDenomination is the denomination used to pay transaction fees. Stable-token denominations
+// (e.g. HLUSD) cannot be used to pay for gas unless the core module is configured with a minimum
+// gas price for them.
+var GasDenomination = NativeDenomination
+
 // MaxDenominationSize is the maximum length of a denomination.
 const MaxDenominationSize = 32
 
@@ -12,7 +12,7 @@ import (
 )
 
 // SignatureContextBase is the transaction signature domain separation context base.
-var SignatureContextBase = []byte("oasis-runtime-sdk/tx: v0")
+var SignatureContextBase = signature.ContextBase(signature.ContextKindTransaction)
 
 // LatestTransactionVersion is the latest transaction format version.
 const LatestTransactionVersion = 1
@@ -37,32 +37,61 @@ type UnverifiedTransaction struct {
 }
 
 // Hash returns the cryptographic hash of the encoded transaction.
+//
+// This is the same hash that nodes and explorers report for the transaction. Note that an unsigned
+// Transaction has no such hash as the hash also covers the authentication proofs.
 func (ut *UnverifiedTransaction) Hash() hash.Hash {
 	return hash.NewFrom(ut)
 }
 
 // Verify verifies and deserializes the unverified transaction.
-func (ut *UnverifiedTransaction) Verify(ctx signature.Context) (*Transaction, error) {
+//
+// Panics caused by malformed transactions are reported as a *DecodeError.
+func (ut *UnverifiedTransaction) Verify(ctx signature.Context) (_ *Transaction, err error) {
+	defer RecoverDecode(&err, "transaction")
+
+	tx, publicKeys, signatures, err := ut.decodeSigned()
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify all signatures.
+	txCtx := ctx.New(SignatureContextBase)
+	for i, pk := range publicKeys {
+		if !pk.Verify(txCtx, ut.Body, signatures[i]) {
+			// If you're looking at the below error message: the numbering doesn't match up with the auth proof indices
+			// if the transaction has multisig auth proofs. You have to count up the included signatures inside the
+			// multisig auth proofs to find which one (first) failed.
+			return nil, fmt.Errorf("transaction: signature %d verification failed", i)
+		}
+	}
+
+	return tx, nil
+}
+
+// decodeSigned deserializes the unverified transaction and returns the public keys and signatures
+// that need to be verified.
+func (ut *UnverifiedTransaction) decodeSigned() (_ *Transaction, _ []PublicKey, _ [][]byte, err error) {
+	defer RecoverDecode(&err, "transaction")
+
 	if len(ut.AuthProofs) == 1 && ut.AuthProofs[0].Module != "" {
-		return nil, fmt.Errorf("module-controlled decoding (scheme %q) not supported", ut.AuthProofs[0].Module)
+		return nil, nil, nil, fmt.Errorf("module-controlled decoding (scheme %q) not supported", ut.AuthProofs[0].Module)
 	}
 
 	// Deserialize the inner body.
 	var tx Transaction
-	if err := cbor.Unmarshal(ut.Body, &tx); err != nil {
-		return nil, fmt.Errorf("transaction: malformed transaction body: %w", err)
+	if err = cbor.Unmarshal(ut.Body, &tx); err != nil {
+		return nil, nil, nil, fmt.Errorf("transaction: malformed transaction body: %w", err)
 	}
-	if err := tx.ValidateBasic(); err != nil {
-		return nil, err
+	if err = tx.ValidateBasic(); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// Basic structure validation.
 	if len(ut.AuthProofs) != len(tx.AuthInfo.SignerInfo) {
-		return nil, fmt.Errorf("transaction: inconsistent number of auth proofs")
+		return nil, nil, nil, fmt.Errorf("transaction: inconsistent number of auth proofs")
 	}
 
-	// Verify all signatures.
-	txCtx := ctx.New(SignatureContextBase)
 	// We'll need at least one signature per proof, so we might as well preallocate that.
 	// Could be more though.
 	publicKeys := make([]PublicKey, 0, len(ut.AuthProofs))
@@ -70,21 +99,12 @@ func (ut *UnverifiedTransaction) Verify(ctx signature.Context) (*Transaction, er
 	for i, ap := range ut.AuthProofs {
 		pks, sigs, err := tx.AuthInfo.SignerInfo[i].AddressSpec.Batch(ap)
 		if err != nil {
-			return nil, fmt.Errorf("transaction: auth proof %d batch: %w", i, err)
+			return nil, nil, nil, fmt.Errorf("transaction: auth proof %d batch: %w", i, err)
 		}
 		publicKeys = append(publicKeys, pks...)
 		signatures = append(signatures, sigs...)
 	}
-	for i, pk := range publicKeys {
-		if !pk.Verify(txCtx, ut.Body, signatures[i]) {
-			// If you're looking at the below error message: the numbering doesn't match up with the auth proof indices
-			// if the transaction has multisig auth proofs. You have to count up the included signatures inside the
-			// multisig auth proofs to find which one (first) failed.
-			return nil, fmt.Errorf("transaction: signature %d verification failed", i)
-		}
-	}
-
-	return &tx, nil
+	return &tx, publicKeys, signatures, nil
 }
 
 type TransactionSigner struct {
@@ -332,6 +352,26 @@ func (f *Fee) GasPrice() *quantity.Quantity {
 	return amt
 }
 
+// AddPriorityTip increases the fee amount so that the implied gas price is raised by the given
+// per-gas tip. As transactions are prioritized by their gas price, this makes the transaction
+// more likely to be scheduled ahead of others during busy periods.
+//
+// The gas limit must be configured before adding a tip.
+func (f *Fee) AddPriorityTip(tip *quantity.Quantity) error {
+	if f.Gas == 0 {
+		return fmt.Errorf("fee: gas limit must be set before adding a priority tip")
+	}
+
+	var total quantity.Quantity
+	if err := total.FromUint64(f.Gas); err != nil {
+		return err
+	}
+	if err := total.Mul(tip); err != nil {
+		return err
+	}
+	return f.Amount.Amount.Add(&total)
+}
+
 // CallerAddress is a caller address.
 type CallerAddress struct {
 	// Address is an oasis address.
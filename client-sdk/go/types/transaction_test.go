@@ -7,7 +7,9 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
@@ -77,3 +79,74 @@ func TestTransactionSigning(t *testing.T) {
 	err = tx.ValidateBasic()
 	require.NoError(err, "ValidateBasic")
 }
+
+func TestTransactionHash(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing"))
+	chainCtx := signature.Context("test chain")
+
+	tx := NewTransaction(nil, "hello.World", nil)
+	tx.AppendAuthSignature(NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey)), 42)
+	ts := tx.PrepareForSigning()
+	require.NoError(ts.AppendSign(chainCtx, signer), "AppendSign")
+	ut := ts.UnverifiedTransaction()
+
+	// Nodes hash the raw submitted transaction bytes.
+	raw := cbor.Marshal(ut)
+	require.Equal(hash.NewFromBytes(raw), ut.Hash(), "hash should match the hash of the raw transaction")
+
+	var decoded UnverifiedTransaction
+	require.NoError(cbor.Unmarshal(raw, &decoded), "Unmarshal")
+	require.Equal(ut.Hash(), decoded.Hash(), "hash should survive decoding")
+}
+
+func TestFeePriorityTip(t *testing.T) {
+	require := require.New(t)
+
+	fee := Fee{Amount: NewBaseUnits(*quantity.NewFromUint64(1_000), NativeDenomination)}
+	require.Error(fee.AddPriorityTip(quantity.NewFromUint64(5)), "AddPriorityTip should fail without gas")
+
+	fee.Gas = 100
+	require.EqualValues(quantity.NewFromUint64(10), fee.GasPrice())
+	require.NoError(fee.AddPriorityTip(quantity.NewFromUint64(5)), "AddPriorityTip")
+	require.EqualValues(quantity.NewFromUint64(1_500), &fee.Amount.Amount)
+	require.EqualValues(quantity.NewFromUint64(15), fee.GasPrice())
+}
+
+func TestVerifyBatch(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing"))
+	chainCtx := signature.Context("test chain")
+
+	var uts []*UnverifiedTransaction
+	for nonce := uint64(0); nonce < 300; nonce++ {
+		tx := NewTransaction(nil, "hello.World", nil)
+		tx.AppendAuthSignature(NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey)), nonce)
+		ts := tx.PrepareForSigning()
+		require.NoError(ts.AppendSign(chainCtx, signer), "AppendSign")
+		uts = append(uts, ts.UnverifiedTransaction())
+	}
+	// Corrupt a signature and a body.
+	uts[150].AuthProofs[0].Signature = append([]byte{}, uts[151].AuthProofs[0].Signature...)
+	uts[200] = &UnverifiedTransaction{Body: []byte("garbage"), AuthProofs: uts[200].AuthProofs}
+
+	results := VerifyBatch(chainCtx, uts, 3)
+	require.Len(results, len(uts))
+	for i, result := range results {
+		switch i {
+		case 150:
+			require.ErrorContains(result.Err, "signature 0 verification failed")
+			require.Nil(result.Tx)
+		case 200:
+			require.ErrorContains(result.Err, "malformed transaction body")
+			require.Nil(result.Tx)
+		default:
+			require.NoError(result.Err, "transaction %d", i)
+			require.EqualValues(i, result.Tx.AuthInfo.SignerInfo[0].Nonce)
+		}
+	}
+
+	require.Empty(VerifyBatch(chainCtx, nil, 0))
+}
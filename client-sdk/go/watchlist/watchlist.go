@@ -0,0 +1,355 @@
+// Package watchlist implements a list of watched addresses with alert rules.
+//
+// Watchlists are meant to be maintained alongside the on-chain blacklist (e.g. by compliance
+// teams tracking sanction lists) and can be imported from and exported to JSON or YAML snapshots.
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Event kinds that alert rules can be restricted to.
+const (
+	EventTransfer = "transfer"
+	EventMint     = "mint"
+	EventBurn     = "burn"
+)
+
+// Format is a snapshot serialization format.
+type Format string
+
+const (
+	// FormatJSON is the JSON snapshot format.
+	FormatJSON Format = "json"
+	// FormatYAML is the YAML snapshot format.
+	FormatYAML Format = "yaml"
+)
+
+// FormatFromPath determines the snapshot format from the file extension of the given path.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("watchlist: unsupported snapshot file extension: '%s'", filepath.Ext(path))
+	}
+}
+
+// Rule is an alert rule.
+type Rule struct {
+	// Events are the kinds of events the rule applies to. An empty list matches all events.
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+	// Denomination restricts the rule to the given denomination (if set).
+	Denomination *types.Denomination `json:"denomination,omitempty" yaml:"denomination,omitempty"`
+	// MinAmount is the minimum amount which triggers the rule.
+	MinAmount quantity.Quantity `json:"min_amount" yaml:"min_amount"`
+}
+
+// Validate performs basic validation on the rule.
+func (r *Rule) Validate() error {
+	for _, ev := range r.Events {
+		switch ev {
+		case EventTransfer, EventMint, EventBurn:
+		default:
+			return fmt.Errorf("unknown event kind '%s'", ev)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(kind string, amount *types.BaseUnits) bool {
+	if len(r.Events) > 0 {
+		var found bool
+		for _, ev := range r.Events {
+			if ev == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Denomination != nil && *r.Denomination != amount.Denomination {
+		return false
+	}
+	return amount.Amount.Cmp(&r.MinAmount) >= 0
+}
+
+// Entry is a watched address.
+type Entry struct {
+	// Address is the watched address.
+	Address types.Address `json:"address" yaml:"address"`
+	// Label is a human readable label of the address.
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+	// Rules are the alert rules of the address. An address without any rules triggers an alert
+	// on any event it is involved in.
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// Validate performs basic validation on the entry.
+func (e *Entry) Validate() error {
+	for i := range e.Rules {
+		if err := e.Rules[i].Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Snapshot is a serializable snapshot of the watchlist.
+type Snapshot struct {
+	Entries []Entry `json:"entries" yaml:"entries"`
+}
+
+// Alert is an alert triggered by an event involving a watched address.
+type Alert struct {
+	// Entry is the watchlist entry of the involved address.
+	Entry *Entry
+	// Rule is the rule that triggered the alert or nil in case the entry has no rules.
+	Rule *Rule
+	// Kind is the kind of the event.
+	Kind string
+	// Event is the event that triggered the alert.
+	Event *accounts.Event
+}
+
+// Watchlist is a list of watched addresses. It is safe for concurrent use.
+type Watchlist struct {
+	l       sync.RWMutex
+	entries map[types.Address]*Entry
+}
+
+// New creates a new empty watchlist.
+func New() *Watchlist {
+	return &Watchlist{
+		entries: make(map[types.Address]*Entry),
+	}
+}
+
+// Add adds or replaces a watchlist entry.
+func (w *Watchlist) Add(entry Entry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("watchlist: entry %s: %w", entry.Address, err)
+	}
+
+	w.l.Lock()
+	defer w.l.Unlock()
+	w.entries[entry.Address] = &entry
+	return nil
+}
+
+// Remove removes the given address from the watchlist.
+func (w *Watchlist) Remove(address types.Address) {
+	w.l.Lock()
+	defer w.l.Unlock()
+	delete(w.entries, address)
+}
+
+// Lookup returns the watchlist entry for the given address (if any).
+func (w *Watchlist) Lookup(address types.Address) (*Entry, bool) {
+	w.l.RLock()
+	defer w.l.RUnlock()
+	entry, ok := w.entries[address]
+	return entry, ok
+}
+
+// Len returns the number of watched addresses.
+func (w *Watchlist) Len() int {
+	w.l.RLock()
+	defer w.l.RUnlock()
+	return len(w.entries)
+}
+
+// Snapshot returns a snapshot of the watchlist, sorted by address.
+func (w *Watchlist) Snapshot() *Snapshot {
+	w.l.RLock()
+	defer w.l.RUnlock()
+
+	snapshot := Snapshot{Entries: make([]Entry, 0, len(w.entries))}
+	for _, entry := range w.entries {
+		snapshot.Entries = append(snapshot.Entries, *entry)
+	}
+	sort.Slice(snapshot.Entries, func(i, j int) bool {
+		return snapshot.Entries[i].Address.String() < snapshot.Entries[j].Address.String()
+	})
+	return &snapshot
+}
+
+// Restore replaces the content of the watchlist with the given snapshot.
+func (w *Watchlist) Restore(snapshot *Snapshot) error {
+	entries := make(map[types.Address]*Entry, len(snapshot.Entries))
+	for i := range snapshot.Entries {
+		entry := snapshot.Entries[i]
+		if err := entry.Validate(); err != nil {
+			return fmt.Errorf("watchlist: entry %s: %w", entry.Address, err)
+		}
+		if _, exists := entries[entry.Address]; exists {
+			return fmt.Errorf("watchlist: duplicate entry %s", entry.Address)
+		}
+		entries[entry.Address] = &entry
+	}
+
+	w.l.Lock()
+	defer w.l.Unlock()
+	w.entries = entries
+	return nil
+}
+
+// Export serializes a snapshot of the watchlist in the given format.
+func (w *Watchlist) Export(format Format) ([]byte, error) {
+	snapshot := w.Snapshot()
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(snapshot, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(snapshot)
+	default:
+		return nil, fmt.Errorf("watchlist: unsupported snapshot format: '%s'", format)
+	}
+}
+
+// Import replaces the content of the watchlist with the given serialized snapshot.
+func (w *Watchlist) Import(data []byte, format Format) error {
+	var snapshot Snapshot
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("watchlist: malformed JSON snapshot: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("watchlist: malformed YAML snapshot: %w", err)
+		}
+	default:
+		return fmt.Errorf("watchlist: unsupported snapshot format: '%s'", format)
+	}
+	return w.Restore(&snapshot)
+}
+
+// Load replaces the content of the watchlist with the snapshot stored in the given file. The
+// format is determined from the file extension.
+func (w *Watchlist) Load(path string) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("watchlist: failed to read snapshot: %w", err)
+	}
+	return w.Import(data, format)
+}
+
+// Save stores a snapshot of the watchlist into the given file. The format is determined from the
+// file extension.
+func (w *Watchlist) Save(path string) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := w.Export(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// WatchFile loads the snapshot stored in the given file and then keeps reloading it whenever it
+// changes until the context is cancelled.
+//
+// Failed reloads keep the previous content and are reported via onError (if non-nil).
+func (w *Watchlist) WatchFile(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("watchlist: failed to stat snapshot: %w", err)
+	}
+	if err = w.Load(path); err != nil {
+		return err
+	}
+	lastMod := fi.ModTime()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		fi, err = os.Stat(path)
+		if err == nil && fi.ModTime().Equal(lastMod) {
+			continue
+		}
+		if err == nil {
+			lastMod = fi.ModTime()
+			err = w.Load(path)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Match returns the alerts triggered by the given event.
+func (w *Watchlist) Match(ev *accounts.Event) []Alert {
+	var (
+		kind    string
+		amount  *types.BaseUnits
+		parties []types.Address
+	)
+	switch {
+	case ev.Transfer != nil:
+		kind, amount, parties = EventTransfer, &ev.Transfer.Amount, []types.Address{ev.Transfer.From, ev.Transfer.To}
+	case ev.Mint != nil:
+		kind, amount, parties = EventMint, &ev.Mint.Amount, []types.Address{ev.Mint.Owner}
+	case ev.Burn != nil:
+		kind, amount, parties = EventBurn, &ev.Burn.Amount, []types.Address{ev.Burn.Owner}
+	default:
+		return nil
+	}
+
+	w.l.RLock()
+	defer w.l.RUnlock()
+
+	var alerts []Alert
+	for i, addr := range parties {
+		if i > 0 && addr == parties[0] {
+			// Do not alert twice for self-transfers.
+			continue
+		}
+		entry, ok := w.entries[addr]
+		if !ok {
+			continue
+		}
+		if len(entry.Rules) == 0 {
+			alerts = append(alerts, Alert{Entry: entry, Kind: kind, Event: ev})
+			continue
+		}
+		for j := range entry.Rules {
+			if entry.Rules[j].matches(kind, amount) {
+				alerts = append(alerts, Alert{Entry: entry, Rule: &entry.Rules[j], Kind: kind, Event: ev})
+			}
+		}
+	}
+	return alerts
+}
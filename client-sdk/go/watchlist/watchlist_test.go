@@ -0,0 +1,78 @@
+package watchlist
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestImportExport(t *testing.T) {
+	require := require.New(t)
+
+	w := New()
+	require.NoError(w.Add(Entry{Address: sdkTesting.Alice.Address, Label: "alice"}))
+	require.NoError(w.Add(Entry{
+		Address: sdkTesting.Bob.Address,
+		Label:   "bob",
+		Rules:   []Rule{{Events: []string{EventTransfer}, MinAmount: *quantity.NewFromUint64(100)}},
+	}))
+	require.Error(w.Add(Entry{Address: sdkTesting.Charlie.Address, Rules: []Rule{{Events: []string{"bogus"}}}}))
+
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		data, err := w.Export(format)
+		require.NoError(err, "Export(%s)", format)
+
+		w2 := New()
+		require.NoError(w2.Import(data, format), "Import(%s)", format)
+		require.EqualValues(w.Snapshot(), w2.Snapshot(), "imported snapshot should be equal (%s)", format)
+	}
+
+	path := filepath.Join(t.TempDir(), "watchlist.yaml")
+	require.NoError(w.Save(path), "Save")
+	w2 := New()
+	require.NoError(w2.Load(path), "Load")
+	require.Equal(2, w2.Len())
+
+	require.Error(w2.Save(filepath.Join(t.TempDir(), "watchlist.txt")), "Save should fail for unknown formats")
+}
+
+func TestMatch(t *testing.T) {
+	require := require.New(t)
+
+	w := New()
+	require.NoError(w.Add(Entry{Address: sdkTesting.Alice.Address}))
+	require.NoError(w.Add(Entry{
+		Address: sdkTesting.Bob.Address,
+		Rules:   []Rule{{Events: []string{EventTransfer}, MinAmount: *quantity.NewFromUint64(100)}},
+	}))
+
+	transfer := func(from, to types.Address, amount uint64) *accounts.Event {
+		return &accounts.Event{Transfer: &accounts.TransferEvent{
+			From:   from,
+			To:     to,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination),
+		}}
+	}
+
+	require.Len(w.Match(transfer(sdkTesting.Alice.Address, sdkTesting.Charlie.Address, 1)), 1, "alice has no rules")
+	require.Len(w.Match(transfer(sdkTesting.Alice.Address, sdkTesting.Alice.Address, 1)), 1, "self-transfers alert once")
+	require.Len(w.Match(transfer(sdkTesting.Charlie.Address, sdkTesting.Bob.Address, 10)), 0, "amount below threshold")
+	alerts := w.Match(transfer(sdkTesting.Charlie.Address, sdkTesting.Bob.Address, 100))
+	require.Len(alerts, 1)
+	require.Equal(EventTransfer, alerts[0].Kind)
+	require.NotNil(alerts[0].Rule)
+	require.Len(w.Match(&accounts.Event{Mint: &accounts.MintEvent{
+		Owner:  sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	}}), 0, "rule restricted to transfers")
+
+	w.Remove(sdkTesting.Alice.Address)
+	require.Len(w.Match(transfer(sdkTesting.Alice.Address, sdkTesting.Charlie.Address, 1)), 0)
+}
@@ -0,0 +1,133 @@
+import * as oasis from '@oasisprotocol/client';
+import * as fs from 'fs';
+import * as path from 'path';
+
+import * as oasisRT from './../src';
+
+// Test vectors generated by the Go SDK, see client-sdk/go/compat.
+const vectors = JSON.parse(
+    fs.readFileSync(path.join(__dirname, '../../../go/compat/testdata/vectors.json'), 'utf8'),
+);
+
+function baseUnits(amount: string, denomination: string): oasisRT.types.BaseUnits {
+    return [oasis.quantity.fromBigInt(BigInt(amount)), oasis.misc.fromString(denomination)];
+}
+
+function expectBaseUnits(actual: oasisRT.types.BaseUnits, amount: string, denomination: string) {
+    expect(oasis.quantity.toBigInt(actual[0])).toEqual(BigInt(amount));
+    expect(oasis.misc.toStringUTF8(actual[1])).toEqual(denomination);
+}
+
+describe('compat', () => {
+    describe('addresses', () => {
+        it('Should derive the same addresses as the Go SDK', async () => {
+            for (const v of vectors.addresses) {
+                const pk = oasis.misc.fromBase64(v.public_key);
+                const address = await oasisRT.address.fromSigspec({[v.kind]: pk});
+                expect(oasis.misc.toHex(address)).toEqual(v.address);
+            }
+        });
+    });
+
+    describe('transactions', () => {
+        it('Should encode transfers byte-for-byte like the Go SDK', () => {
+            for (const v of vectors.transfers) {
+                const tx: oasisRT.types.Transaction = {
+                    v: oasisRT.transaction.LATEST_TRANSACTION_VERSION,
+                    call: {
+                        method: oasisRT.accounts.METHOD_TRANSFER,
+                        body: {
+                            to: oasis.misc.fromHex(v.to),
+                            amount: baseUnits(v.amount, v.denomination),
+                        } as oasisRT.types.AccountsTransfer,
+                    },
+                    ai: {
+                        si: [
+                            {
+                                address_spec: {
+                                    signature: {ed25519: oasis.misc.fromBase64(v.signer_public_key)},
+                                },
+                                nonce: BigInt(v.nonce),
+                            },
+                        ],
+                        fee: {
+                            amount: baseUnits(v.fee_amount, v.fee_denomination),
+                            gas: BigInt(v.fee_gas),
+                            consensus_messages: v.consensus_messages,
+                        },
+                    },
+                };
+                if (v.consensus_messages === 0) {
+                    // The Go SDK omits a zero consensus_messages field while the TypeScript SDK
+                    // always encodes it, so the encodings only match without the field.
+                    expect(oasis.misc.toHex(oasis.misc.toCBOR(tx))).not.toEqual(v.encoded);
+                    const {consensus_messages, ...fee} = tx.ai.fee;
+                    expect(consensus_messages).toEqual(0);
+                    const goTx = {...tx, ai: {...tx.ai, fee}};
+                    expect(oasis.misc.toHex(oasis.misc.toCBOR(goTx))).toEqual(v.encoded);
+                    continue;
+                }
+                expect(oasis.misc.toHex(oasis.misc.toCBOR(tx))).toEqual(v.encoded);
+            }
+        });
+
+        it('Should decode Go SDK transactions without consensus messages', () => {
+            let checked = 0;
+            for (const v of vectors.transfers) {
+                if (v.consensus_messages !== 0) {
+                    continue;
+                }
+                const decoded = oasis.misc.fromCBOR(
+                    oasis.misc.fromHex(v.encoded),
+                ) as oasisRT.types.Transaction;
+                expect(decoded.ai.fee.consensus_messages).toBeUndefined();
+                checked++;
+            }
+            expect(checked).toBeGreaterThan(0);
+        });
+
+        it('Should re-encode Go SDK transactions without changes', () => {
+            for (const v of vectors.transfers) {
+                const decoded = oasis.misc.fromCBOR(
+                    oasis.misc.fromHex(v.encoded),
+                ) as oasisRT.types.Transaction;
+                expect(decoded.call.method).toEqual(oasisRT.accounts.METHOD_TRANSFER);
+                expect(oasis.misc.toHex(oasis.misc.toCBOR(decoded))).toEqual(v.encoded);
+            }
+        });
+    });
+
+    describe('events', () => {
+        it('Should decode Go SDK events', () => {
+            for (const v of vectors.events) {
+                expect(v.module).toEqual(oasisRT.accounts.MODULE_NAME);
+                const value = oasis.misc.fromCBOR(oasis.misc.fromHex(v.value)) as unknown[];
+                expect(value.length).toEqual(1);
+                switch (v.code) {
+                    case oasisRT.accounts.EVENT_TRANSFER_CODE: {
+                        const ev = value[0] as oasisRT.types.AccountsTransferEvent;
+                        expect(oasis.misc.toHex(ev.from)).toEqual(v.from);
+                        expect(oasis.misc.toHex(ev.to)).toEqual(v.to);
+                        expectBaseUnits(ev.amount, v.amount, v.denomination);
+                        break;
+                    }
+                    case oasisRT.accounts.EVENT_MINT_CODE: {
+                        const ev = value[0] as oasisRT.types.AccountsMintEvent;
+                        expect(oasis.misc.toHex(ev.owner)).toEqual(v.owner);
+                        expectBaseUnits(ev.amount, v.amount, v.denomination);
+                        break;
+                    }
+                    case oasisRT.accounts.EVENT_BURN_CODE: {
+                        const ev = value[0] as oasisRT.types.AccountsBurnEvent;
+                        expect(oasis.misc.toHex(ev.owner)).toEqual(v.owner);
+                        expectBaseUnits(ev.amount, v.amount, v.denomination);
+                        break;
+                    }
+                    default:
+                        throw new Error(`unexpected event code ${v.code}`);
+                }
+                expect(oasis.misc.toHex(oasis.misc.toCBOR(value))).toEqual(v.value);
+            }
+        });
+    });
+});
@@ -414,7 +414,7 @@ func evmTest(log *logging.Logger, rtc client.RuntimeClient, c10l c10lity) error
 	gasPrice := uint64(1)
 
 	// Check min gas price.
-	mgp, err := c.MinGasPrice(ctx)
+	mgp, err := c.MinGasPrice(ctx, client.RoundLatest)
 	if err != nil {
 		return err
 	}
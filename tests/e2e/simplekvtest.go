@@ -460,6 +460,10 @@ func TransactionsQueryTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.
 	tb.SetFeeGas(2 * defaultGasAmount)
 	tb.AppendAuthSignature(sigspecForSigner(signer), nonce)
 	_ = tb.AppendSign(ctx, signer)
+	txHash, err := tb.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to compute transaction hash: %w", err)
+	}
 	var meta *client.TransactionMeta
 	if meta, err = tb.SubmitTxMeta(ctx, nil); err != nil {
 		return fmt.Errorf("failed to submit transaction: %w", err)
@@ -479,6 +483,9 @@ func TransactionsQueryTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.
 	}
 
 	tx := txs[meta.BatchOrder]
+	if h := tx.Tx.Hash(); !h.Equal(&txHash) {
+		return fmt.Errorf("transaction hash mismatch (expected: %s got: %s)", txHash, h)
+	}
 	if len(tx.Events) != 2 {
 		return fmt.Errorf("expected 2 events got %d events", len(tx.Events))
 	}